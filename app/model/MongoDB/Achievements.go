@@ -60,8 +60,12 @@ type Period struct {
 }
 
 type Attachment struct {
+	ID         string    `bson:"id,omitempty" json:"id,omitempty"`
 	FileName   string    `bson:"fileName" json:"fileName"`
 	FileUrl    string    `bson:"fileUrl" json:"fileUrl"`
 	FileType   string    `bson:"fileType" json:"fileType"`
+	Size       int64     `bson:"size,omitempty" json:"size,omitempty"`
+	Checksum   string    `bson:"checksum,omitempty" json:"checksum,omitempty"` // SHA-256 (hex)
+	StorageKey string    `bson:"storageKey,omitempty" json:"-"`
 	UploadedAt time.Time `bson:"uploadedAt" json:"uploadedAt"`
 }
@@ -0,0 +1,25 @@
+package model
+
+// BulkAchievementActionRequest untuk verifikasi/penolakan banyak prestasi sekaligus (dosen wali).
+// Note dipakai bersama oleh semua item; wajib untuk penolakan.
+type BulkAchievementActionRequest struct {
+	AchievementIDs []string `json:"achievement_ids" validate:"required"`
+	Note           string   `json:"note"`
+}
+
+// BulkAchievementItemResult adalah hasil satu item pada aksi massal
+type BulkAchievementItemResult struct {
+	AchievementID string  `json:"achievement_id"`
+	Success       bool    `json:"success"`
+	Status        *string `json:"status,omitempty"` // status prestasi setelah aksi berhasil
+	Error         *string `json:"error,omitempty"`
+	Code          int     `json:"code"` // HTTP status code yang setara untuk item ini
+}
+
+// BulkAchievementActionResponse merangkum hasil aksi massal; item yang gagal tidak membatalkan item yang berhasil
+type BulkAchievementActionResponse struct {
+	Total     int                         `json:"total"`
+	Succeeded int                         `json:"succeeded"`
+	Failed    int                         `json:"failed"`
+	Results   []BulkAchievementItemResult `json:"results"`
+}
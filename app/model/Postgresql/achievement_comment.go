@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AchievementComment adalah komentar review pada prestasi (achievement_comments).
+// Komentar utama membuka thread; balasan menunjuk komentar utama lewat ParentID.
+// FieldPath memakai format path yang sama dengan diff versi (mis. "details.competitionName").
+type AchievementComment struct {
+	ID            uuid.UUID  `json:"id"`
+	AchievementID uuid.UUID  `json:"achievement_id"`
+	ParentID      *uuid.UUID `json:"parent_id"`
+	AuthorID      uuid.UUID  `json:"author_id"`
+	AuthorName    string     `json:"author_name"`
+	AuthorRole    string     `json:"author_role"`
+	Body          string     `json:"body"`
+	FieldPath     *string    `json:"field_path"`
+	AttachmentID  *string    `json:"attachment_id"`
+	IsResolved    bool       `json:"is_resolved"`
+	ResolvedBy    *uuid.UUID `json:"resolved_by"`
+	ResolvedAt    *time.Time `json:"resolved_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	Replies []AchievementComment `json:"replies,omitempty"`
+}
+
+// CreateAchievementCommentRequest untuk menulis komentar baru atau balasan thread
+type CreateAchievementCommentRequest struct {
+	Body         string  `json:"body" validate:"required"`
+	ParentID     *string `json:"parent_id,omitempty"`
+	FieldPath    *string `json:"field_path,omitempty"`
+	AttachmentID *string `json:"attachment_id,omitempty"`
+}
+
+// AchievementCommentsResponse berisi thread komentar prestasi, terurut dari yang paling lama
+type AchievementCommentsResponse struct {
+	AchievementID uuid.UUID            `json:"achievement_id"`
+	Unresolved    int                  `json:"unresolved"`
+	Threads       []AchievementComment `json:"threads"`
+}
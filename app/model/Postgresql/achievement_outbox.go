@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Operasi dokumen MongoDB yang dicatat di achievement_outbox
+const (
+	OutboxOpUpsertDocument     = "upsert_document"      // tulis isi prestasi (payload BSON)
+	OutboxOpSoftDeleteDocument = "soft_delete_document" // set deleted_at
+	OutboxOpRestoreDocument    = "restore_document"     // unset deleted_at
+	OutboxOpAddAttachment      = "add_attachment"       // tambah satu attachment (payload BSON attachment)
+	OutboxOpRemoveAttachment   = "remove_attachment"    // hapus satu attachment (payload BSON {id})
+	OutboxOpSetPoints          = "set_points"           // set poin hasil scoring (payload BSON {points})
+)
+
+// AchievementOutboxEvent adalah perubahan dokumen MongoDB yang dicatat dalam transaksi PostgreSQL yang sama
+// dengan perubahan reference-nya, lalu diterapkan ke MongoDB oleh relay (berurutan per prestasi)
+type AchievementOutboxEvent struct {
+	ID                 int64      `json:"id"`
+	AchievementID      uuid.UUID  `json:"achievement_id"`
+	MongoAchievementID string     `json:"mongo_achievement_id"`
+	Operation          string     `json:"operation"`
+	Payload            []byte     `json:"-"`
+	Attempts           int        `json:"attempts"`
+	LastError          *string    `json:"last_error"`
+	NextAttemptAt      time.Time  `json:"next_attempt_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	ProcessedAt        *time.Time `json:"processed_at,omitempty"`
+}
+
+// OutboxRelayResult merangkum satu kali jalan relay outbox
+type OutboxRelayResult struct {
+	Processed int `json:"processed"`
+	Failed    int `json:"failed"`
+	Deferred  int `json:"deferred"` // menunggu jadwal retry atau event sebelumnya untuk prestasi yang sama
+}
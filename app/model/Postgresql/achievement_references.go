@@ -16,6 +16,10 @@ type AchievementReference struct {
 	VerifiedAt         *time.Time `json:"verified_at"`
 	VerifiedBy         *uuid.UUID `json:"verified_by"`
 	RejectionNote      *string    `json:"rejection_note"`
+	Revision           int        `json:"revision"` // jumlah resubmission setelah ditolak
+	ReviewDueAt        *time.Time `json:"review_due_at"`
+	EscalatedAt        *time.Time `json:"escalated_at"`
+	EscalatedTo        *string    `json:"escalated_to"` // role yang boleh memutuskan setelah batas waktu review terlewati
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
 }
@@ -32,8 +36,15 @@ type AchievementWithStudent struct {
 	VerifiedAt         *time.Time           `json:"verified_at"`
 	VerifiedBy         *uuid.UUID           `json:"verified_by"`
 	RejectionNote      *string              `json:"rejection_note"`
+	Revision           int                  `json:"revision"`
 	CreatedAt          time.Time            `json:"created_at"`
 	UpdatedAt          time.Time            `json:"updated_at"`
+	Title              *string              `json:"title"` // field read model, nil jika belum tersinkron
+	AchievementType    *string              `json:"achievement_type"`
+	CompetitionLevel   *string              `json:"competition_level"`
+	MedalType          *string              `json:"medal_type"`
+	Tags               []string             `json:"tags"`
+	Points             int                  `json:"points"` // dari achievement_scores
 	Details            *mongodb.Achievement `json:"details,omitempty"`
 }
 
@@ -49,16 +60,65 @@ type PaginationMetadata struct {
 type AchievementListResponse struct {
 	Achievements []AchievementWithStudent `json:"achievements"`
 	Pagination   PaginationMetadata       `json:"pagination"`
+	Facets       *AchievementFacets       `json:"facets,omitempty"` // hanya listing admin
+}
+
+// AchievementListFilters for filtering and sorting achievement listings (read model)
+type AchievementListFilters struct {
+	Status           string `json:"status"`
+	AchievementType  string `json:"achievement_type"`
+	CompetitionLevel string `json:"competition_level"`
+	MedalType        string `json:"medal_type"`
+	Tag              string `json:"tag"`
+	MinPoints        *int   `json:"min_points"`
+	MaxPoints        *int   `json:"max_points"`
+	SortBy           string `json:"sort_by"`    // created_at, updated_at, status, title, achievement_type, competition_level, points, student_name, event_date
+	SortOrder        string `json:"sort_order"` // asc, desc
 }
 
 // AdminAchievementFilters for filtering achievements in admin view
 type AdminAchievementFilters struct {
-	Status    string     `json:"status"`
-	StudentID *uuid.UUID `json:"student_id"`
-	DateFrom  *time.Time `json:"date_from"`
-	DateTo    *time.Time `json:"date_to"`
-	SortBy    string     `json:"sort_by"`    // created_at, updated_at, status
-	SortOrder string     `json:"sort_order"` // asc, desc
+	AchievementListFilters
+	StudentID    *uuid.UUID `json:"student_id"`
+	DateFrom     *time.Time `json:"date_from"`
+	DateTo       *time.Time `json:"date_to"`
+	ProgramStudy string     `json:"program_study"`
+	AcademicYear string     `json:"academic_year"`
+	AdvisorID    *uuid.UUID `json:"advisor_id"` // dosen wali mahasiswa
+}
+
+// Dimensi facet listing admin
+const (
+	FacetStatus           = "status"
+	FacetAchievementType  = "achievement_type"
+	FacetCompetitionLevel = "competition_level"
+	FacetMedalType        = "medal_type"
+	FacetProgramStudy     = "program_study"
+	FacetAcademicYear     = "academic_year"
+	FacetAdvisor          = "advisor"
+	FacetTag              = "tag"
+	FacetPoints           = "points"
+)
+
+// FacetCount adalah jumlah prestasi untuk satu nilai facet
+type FacetCount struct {
+	Value string  `json:"value"`
+	Label *string `json:"label,omitempty"` // nama tampilan jika value berupa ID (advisor)
+	Count int     `json:"count"`
+}
+
+// AchievementFacets berisi jumlah prestasi per nilai setiap dimensi filter. Setiap dimensi dihitung dengan
+// semua filter aktif kecuali filter dimensi itu sendiri, sehingga pilihan lain tetap terlihat beserta jumlahnya.
+type AchievementFacets struct {
+	Status           []FacetCount `json:"status"`
+	AchievementType  []FacetCount `json:"achievement_type"`
+	CompetitionLevel []FacetCount `json:"competition_level"`
+	MedalType        []FacetCount `json:"medal_type"`
+	ProgramStudy     []FacetCount `json:"program_study"`
+	AcademicYear     []FacetCount `json:"academic_year"`
+	Advisor          []FacetCount `json:"advisor"`
+	Tag              []FacetCount `json:"tag"`    // 50 tag terbanyak
+	Points           []FacetCount `json:"points"` // rentang poin: 0, 1-24, 25-49, 50-99, 100+
 }
 
 // AchievementStatistics represents overall achievement statistics
@@ -90,6 +150,7 @@ type TopStudent struct {
 	StudentName  string    `json:"student_name"`
 	ProgramStudy string    `json:"program_study"`
 	Count        int       `json:"count"`
+	TotalPoints  int       `json:"total_points"`
 }
 
 // LevelDistribution represents distribution by competition level
@@ -114,13 +175,20 @@ type StatisticsFilters struct {
 
 // AchievementStatusLog represents a log entry for achievement status changes
 type AchievementStatusLog struct {
-	ID            uuid.UUID  `json:"id"`
-	AchievementID uuid.UUID  `json:"achievement_id"`
-	Status        string     `json:"status"`
-	ChangedBy     *uuid.UUID `json:"changed_by"`
-	ChangedByName *string    `json:"changed_by_name"`
-	RejectionNote *string    `json:"rejection_note"`
-	CreatedAt     time.Time  `json:"created_at"`
+	ID             uuid.UUID  `json:"id"`
+	AchievementID  uuid.UUID  `json:"achievement_id"`
+	Status         string     `json:"status"`
+	PreviousStatus *string    `json:"previous_status"`
+	ChangedBy      *uuid.UUID `json:"changed_by"`
+	ChangedByName  *string    `json:"changed_by_name"`
+	Note           *string    `json:"note"`
+	RejectionNote  *string    `json:"rejection_note"`
+	OnBehalfOf     *uuid.UUID `json:"on_behalf_of"` // dosen wali yang diwakili delegasi/pengganti
+	OnBehalfOfName *string    `json:"on_behalf_of_name"`
+	DelegationID   *uuid.UUID `json:"delegation_id"`
+	StageOrder     *int       `json:"stage_order"` // tahap persetujuan yang diputuskan, jika ada
+	StageName      *string    `json:"stage_name"`
+	CreatedAt      time.Time  `json:"created_at"`
 }
 
 // AchievementHistoryResponse represents the timeline response
@@ -151,3 +219,27 @@ type StudentWithUser struct {
 	Email       string `json:"email"`
 	AdvisorName string `json:"advisor_name"`
 }
+
+// TrashedAchievement adalah prestasi berstatus 'deleted' yang masih dapat dipulihkan sebelum PurgeAfter
+type TrashedAchievement struct {
+	AchievementWithStudent
+	DeletedAt     time.Time  `json:"deleted_at"`
+	DeletedBy     *uuid.UUID `json:"deleted_by"`
+	DeletedByName *string    `json:"deleted_by_name"`
+	PurgeAfter    time.Time  `json:"purge_after"`
+}
+
+// TrashListResponse berisi daftar prestasi di trash (admin)
+type TrashListResponse struct {
+	Achievements  []TrashedAchievement `json:"achievements"`
+	RetentionDays int                  `json:"retention_days"`
+	Pagination    PaginationMetadata   `json:"pagination"`
+}
+
+// TrashPurgeResult merangkum satu kali jalan retention job
+type TrashPurgeResult struct {
+	Purged       int `json:"purged"`
+	Skipped      int `json:"skipped"` // dipulihkan setelah terpilih untuk dihapus
+	Failed       int `json:"failed"`
+	FilesDeleted int `json:"files_deleted"`
+}
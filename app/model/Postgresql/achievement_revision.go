@@ -0,0 +1,38 @@
+package model
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AchievementRejection menyimpan isi prestasi saat ditolak (achievement_rejections),
+// dipakai sebagai pembanding ketika mahasiswa mengirim ulang revisinya
+type AchievementRejection struct {
+	ID            uuid.UUID           `json:"id"`
+	AchievementID uuid.UUID           `json:"achievement_id"`
+	Revision      int                 `json:"revision"`
+	RejectionNote string              `json:"rejection_note"`
+	Snapshot      mongodb.Achievement `json:"snapshot"`
+	RejectedBy    *uuid.UUID          `json:"rejected_by"`
+	RejectedAt    time.Time           `json:"rejected_at"`
+}
+
+// FieldChange adalah satu perubahan field prestasi (path dot-notation, mis. "details.rank")
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AchievementChangesResponse berisi perubahan prestasi sejak penolakan terakhir
+type AchievementChangesResponse struct {
+	AchievementID uuid.UUID     `json:"achievement_id"`
+	Status        string        `json:"status"`
+	Revision      int           `json:"revision"`
+	RejectionNote string        `json:"rejection_note"`
+	RejectedBy    *uuid.UUID    `json:"rejected_by"`
+	RejectedAt    time.Time     `json:"rejected_at"`
+	Changes       []FieldChange `json:"changes"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+
+	"github.com/google/uuid"
+)
+
+// AchievementSearchMatch adalah dokumen prestasi hasil $text search beserta skor relevansinya
+type AchievementSearchMatch struct {
+	Achievement mongodb.Achievement
+	Score       float64
+}
+
+// SearchHighlight adalah cuplikan satu field yang cocok dengan query; term ditandai <mark>
+type SearchHighlight struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+}
+
+// AchievementSearchResult adalah satu hasil pencarian prestasi, urut relevansi
+type AchievementSearchResult struct {
+	AchievementID      uuid.UUID         `json:"achievement_id"`
+	MongoAchievementID string            `json:"mongo_achievement_id"`
+	StudentID          uuid.UUID         `json:"student_id"`
+	StudentNIM         string            `json:"student_nim"`
+	StudentName        string            `json:"student_name"`
+	Status             string            `json:"status"`
+	Title              string            `json:"title"`
+	AchievementType    string            `json:"achievement_type"`
+	Tags               []string          `json:"tags"`
+	Score              float64           `json:"score"`
+	Highlights         []SearchHighlight `json:"highlights"`
+}
+
+// AchievementSearchResponse is the response structure for GET /achievements/search
+type AchievementSearchResponse struct {
+	Query      string                    `json:"query"`
+	Results    []AchievementSearchResult `json:"results"`
+	Pagination PaginationMetadata        `json:"pagination"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// AchievementType is an entry of the achievement type catalog (achievement_types)
+type AchievementType struct {
+	Code          string    `json:"code"`
+	NameID        string    `json:"name_id"`
+	NameEN        string    `json:"name_en"`
+	IsActive      bool      `json:"is_active"`
+	DefaultPoints int       `json:"default_points"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// CreateAchievementTypeRequest untuk menambah tipe prestasi baru (admin)
+type CreateAchievementTypeRequest struct {
+	Code          string `json:"code" validate:"required"`
+	NameID        string `json:"name_id" validate:"required"`
+	NameEN        string `json:"name_en" validate:"required"`
+	IsActive      *bool  `json:"is_active,omitempty"`
+	DefaultPoints int    `json:"default_points"`
+}
+
+// UpdateAchievementTypeRequest untuk mengubah tipe prestasi (admin); field kosong tidak diubah
+type UpdateAchievementTypeRequest struct {
+	NameID        string `json:"name_id,omitempty"`
+	NameEN        string `json:"name_en,omitempty"`
+	IsActive      *bool  `json:"is_active,omitempty"`
+	DefaultPoints *int   `json:"default_points,omitempty"`
+}
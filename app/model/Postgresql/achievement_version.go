@@ -0,0 +1,33 @@
+package model
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AchievementVersion adalah snapshot immutable isi prestasi setiap kali dokumen MongoDB berubah (achievement_versions)
+type AchievementVersion struct {
+	ID            uuid.UUID            `json:"id"`
+	AchievementID uuid.UUID            `json:"achievement_id"`
+	Version       int                  `json:"version"`
+	Snapshot      *mongodb.Achievement `json:"snapshot,omitempty"`
+	ChangedBy     *uuid.UUID           `json:"changed_by"`
+	ChangedByName *string              `json:"changed_by_name"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+// AchievementVersionsResponse berisi daftar versi prestasi (tanpa snapshot)
+type AchievementVersionsResponse struct {
+	AchievementID uuid.UUID            `json:"achievement_id"`
+	Versions      []AchievementVersion `json:"versions"`
+}
+
+// AchievementVersionDiff berisi perubahan field antara dua versi prestasi
+type AchievementVersionDiff struct {
+	AchievementID uuid.UUID     `json:"achievement_id"`
+	FromVersion   int           `json:"from_version"`
+	ToVersion     int           `json:"to_version"`
+	Changes       []FieldChange `json:"changes"`
+}
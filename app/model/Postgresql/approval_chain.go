@@ -0,0 +1,85 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApproverRoleAdvisor adalah approver_role khusus untuk dosen wali mahasiswa pemilik prestasi;
+// approver_role lain dicocokkan dengan nama role user (tabel roles)
+const ApproverRoleAdvisor = "advisor"
+
+// ApprovalStage adalah satu tahap persetujuan dalam approval chain
+type ApprovalStage struct {
+	Name         string `json:"name"`
+	ApproverRole string `json:"approver_role"`
+}
+
+// ApprovalChain mendefinisikan urutan tahap persetujuan untuk tipe dan/atau tingkat prestasi (approval_chains).
+// AchievementType/CompetitionLevel kosong berarti berlaku untuk semua tipe/tingkat.
+type ApprovalChain struct {
+	ID               uuid.UUID       `json:"id"`
+	Name             string          `json:"name"`
+	AchievementType  *string         `json:"achievement_type"`
+	CompetitionLevel *string         `json:"competition_level"`
+	Stages           []ApprovalStage `json:"stages"`
+	IsActive         bool            `json:"is_active"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// ApprovalChainRequest untuk membuat atau mengganti approval chain (admin)
+type ApprovalChainRequest struct {
+	Name             string          `json:"name" validate:"required"`
+	AchievementType  *string         `json:"achievement_type,omitempty"`
+	CompetitionLevel *string         `json:"competition_level,omitempty"`
+	Stages           []ApprovalStage `json:"stages" validate:"required"`
+	IsActive         *bool           `json:"is_active,omitempty"`
+}
+
+// AchievementApproval adalah status satu tahap persetujuan untuk satu revision prestasi (achievement_approvals).
+// Tahap disalin dari approval chain saat prestasi disubmit sehingga perubahan chain tidak memengaruhi proses berjalan.
+type AchievementApproval struct {
+	ID            uuid.UUID  `json:"id"`
+	AchievementID uuid.UUID  `json:"achievement_id"`
+	Revision      int        `json:"revision"`
+	StageOrder    int        `json:"stage_order"`
+	StageName     string     `json:"stage_name"`
+	ApproverRole  string     `json:"approver_role"`
+	Status        string     `json:"status"` // pending, approved, rejected
+	DecidedBy     *uuid.UUID `json:"decided_by"`
+	DecidedByName *string    `json:"decided_by_name"`
+	Note          *string    `json:"note"`
+	DecidedAt     *time.Time `json:"decided_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	// Lama keputusan dalam detik, dihitung sejak tahap sebelumnya diputuskan
+	DecisionSeconds *int64 `json:"decision_seconds"`
+
+	// Terisi jika tahap diputuskan oleh delegasi/pengganti dosen wali
+	OnBehalfOf     *uuid.UUID `json:"on_behalf_of"`
+	OnBehalfOfName *string    `json:"on_behalf_of_name"`
+	DelegationID   *uuid.UUID `json:"delegation_id"`
+}
+
+// ApprovalDecision adalah keputusan approver atas satu tahap persetujuan.
+// OnBehalfOf (user ID dosen wali) dan DelegationID terisi jika approver adalah delegasi/pengganti.
+type ApprovalDecision struct {
+	ApprovalID   uuid.UUID
+	Status       string // approved, rejected
+	DecidedBy    uuid.UUID
+	Note         *string
+	DecidedAt    time.Time
+	OnBehalfOf   *uuid.UUID
+	DelegationID *uuid.UUID
+}
+
+// AchievementApprovalsResponse berisi tahap persetujuan revision prestasi yang sedang berjalan
+type AchievementApprovalsResponse struct {
+	AchievementID uuid.UUID             `json:"achievement_id"`
+	Status        string                `json:"status"`
+	Revision      int                   `json:"revision"`
+	CurrentStage  *int                  `json:"current_stage"`
+	Stages        []AchievementApproval `json:"stages"`
+}
@@ -18,5 +18,6 @@ type UserResponse struct {
 type LoginResponse struct {
 	Token        string       `json:"token"`
 	RefreshToken string       `json:"refreshToken"`
+	ExpiresIn    int64        `json:"expiresIn"` // masa berlaku access token (detik)
 	User         UserResponse `json:"user"`
 }
\ No newline at end of file
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipe notifikasi
+const (
+	NotificationAchievementWithdrawn = "achievement_withdrawn"
+)
+
+// Notification adalah pemberitahuan untuk satu user (notifications), mis. mahasiswa menarik prestasi dari review
+type Notification struct {
+	ID            uuid.UUID  `json:"id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	Type          string     `json:"type"`
+	AchievementID *uuid.UUID `json:"achievement_id"`
+	Message       string     `json:"message"`
+	ReadAt        *time.Time `json:"read_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// NotificationsResponse berisi notifikasi terbaru user dan jumlah yang belum dibaca
+type NotificationsResponse struct {
+	Unread        int            `json:"unread"`
+	Notifications []Notification `json:"notifications"`
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Jenis drift antara achievement_references (PostgreSQL) dan koleksi achievements (MongoDB)
+const (
+	DriftMissingDocument   = "missing_document"   // reference aktif tanpa dokumen
+	DriftOrphanDocument    = "orphan_document"    // dokumen tanpa reference
+	DriftDeletedDocument   = "deleted_document"   // reference aktif menunjuk dokumen yang soft-deleted
+	DriftUndeletedDocument = "undeleted_document" // reference 'deleted' tetapi dokumennya masih aktif
+	DriftStudentMismatch   = "student_mismatch"   // student_id reference berbeda dengan studentId dokumen
+)
+
+// Tindakan perbaikan; PostgreSQL dianggap sumber kebenaran
+const (
+	RepairRecreateDocument     = "recreate_document_from_version"
+	RepairMoveReferenceToTrash = "move_reference_to_trash"
+	RepairDeleteDocument       = "delete_document"
+	RepairRestoreDocument      = "restore_document"
+	RepairSoftDeleteDocument   = "soft_delete_document"
+	RepairSetDocumentStudent   = "set_document_student"
+)
+
+// ReconciliationReference adalah data reference yang dibutuhkan untuk rekonsiliasi
+type ReconciliationReference struct {
+	ID                 uuid.UUID
+	StudentID          uuid.UUID
+	MongoAchievementID string
+	Status             string
+	HasPendingOutbox   bool // dokumen masih menunggu relay outbox, belum bisa dibandingkan
+}
+
+// ReconciliationDocument adalah ringkasan dokumen MongoDB yang dibutuhkan untuk rekonsiliasi
+type ReconciliationDocument struct {
+	MongoAchievementID string
+	StudentID          uuid.UUID
+	Deleted            bool
+	CreatedAt          time.Time
+}
+
+// ReconciliationIssue adalah satu drift yang ditemukan beserta tindakan perbaikannya
+type ReconciliationIssue struct {
+	Type               string     `json:"type"`
+	AchievementID      *uuid.UUID `json:"achievement_id,omitempty"`
+	MongoAchievementID string     `json:"mongo_achievement_id"`
+	ReferenceStatus    *string    `json:"reference_status,omitempty"`
+	ReferenceStudentID *uuid.UUID `json:"reference_student_id,omitempty"`
+	DocumentStudentID  *uuid.UUID `json:"document_student_id,omitempty"`
+	Repair             string     `json:"repair"`          // tindakan yang (akan) dilakukan
+	Repaired           bool       `json:"repaired"`        // selalu false pada dry-run
+	Error              *string    `json:"error,omitempty"` // alasan perbaikan gagal
+	// LostAttachments berisi attachment versi terakhir yang tidak dapat ditautkan ulang saat dokumen dibuat ulang
+	LostAttachments []LostAttachment `json:"lost_attachments,omitempty"`
+}
+
+// LostAttachment adalah attachment yang hilang dari dokumen yang dibuat ulang; StorageKey kosong jika versinya
+// disimpan sebelum storage key ikut dicatat, selain itu file-nya sudah tidak ada di storage
+type LostAttachment struct {
+	AttachmentID string `json:"attachment_id"`
+	FileName     string `json:"file_name"`
+	StorageKey   string `json:"storage_key,omitempty"`
+}
+
+// ReconciliationReport adalah hasil satu kali rekonsiliasi
+type ReconciliationReport struct {
+	DryRun            bool                  `json:"dry_run"`
+	StartedAt         time.Time             `json:"started_at"`
+	FinishedAt        time.Time             `json:"finished_at"`
+	ReferencesScanned int                   `json:"references_scanned"`
+	DocumentsScanned  int                   `json:"documents_scanned"`
+	SkippedPending    int                   `json:"skipped_pending"` // reference dengan event outbox tertunda
+	Summary           map[string]int        `json:"summary"`         // jumlah issue per jenis drift
+	Repaired          int                   `json:"repaired"`
+	RepairFailed      int                   `json:"repair_failed"`
+	AttachmentsLost   int                   `json:"attachments_lost"` // attachment yang tidak ikut dokumen yang dibuat ulang
+	Issues            []ReconciliationIssue `json:"issues"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewSLALevelDefault adalah level SLA untuk prestasi tanpa competitionLevel atau tanpa SLA khusus
+const ReviewSLALevelDefault = "default"
+
+// ReviewSLA adalah batas waktu review prestasi berstatus 'submitted' per tingkat (review_slas).
+// Prestasi yang melewati batas dieskalasi ke EscalateToRole.
+type ReviewSLA struct {
+	Level          string    `json:"level"` // competitionLevel atau "default"
+	DeadlineHours  int       `json:"deadline_hours"`
+	EscalateToRole string    `json:"escalate_to_role"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ReviewSLARequest untuk membuat atau mengganti SLA satu level (admin)
+type ReviewSLARequest struct {
+	DeadlineHours  int    `json:"deadline_hours" validate:"required"`
+	EscalateToRole string `json:"escalate_to_role" validate:"required"`
+}
+
+// AchievementEscalation adalah prestasi yang baru saja dieskalasi oleh scheduler
+type AchievementEscalation struct {
+	AchievementID uuid.UUID `json:"achievement_id"`
+	EscalatedTo   string    `json:"escalated_to"`
+	ReviewDueAt   time.Time `json:"review_due_at"`
+}
+
+// OverdueAchievement adalah prestasi 'submitted' yang melewati batas waktu review
+type OverdueAchievement struct {
+	ID                  uuid.UUID  `json:"id"`
+	StudentID           uuid.UUID  `json:"student_id"`
+	StudentNIM          string     `json:"student_nim"`
+	StudentName         string     `json:"student_name"`
+	Revision            int        `json:"revision"`
+	ReviewLevel         *string    `json:"review_level"`
+	SubmittedAt         *time.Time `json:"submitted_at"`
+	ReviewDueAt         time.Time  `json:"review_due_at"`
+	OverdueHours        float64    `json:"overdue_hours"`
+	CurrentStageName    *string    `json:"current_stage_name"`
+	CurrentApproverRole *string    `json:"current_approver_role"`
+	EscalatedAt         *time.Time `json:"escalated_at"`
+	EscalatedTo         *string    `json:"escalated_to"`
+}
+
+// ReviewerThroughput merangkum keputusan tahap persetujuan per reviewer
+type ReviewerThroughput struct {
+	ReviewerID       uuid.UUID `json:"reviewer_id"`
+	ReviewerName     string    `json:"reviewer_name"`
+	Decisions        int       `json:"decisions"`
+	Approved         int       `json:"approved"`
+	Rejected         int       `json:"rejected"`
+	AvgDecisionHours float64   `json:"avg_decision_hours"`
+	MaxDecisionHours float64   `json:"max_decision_hours"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScoringRules adalah isi satu versi aturan skor (kolom rules JSONB di scoring_rule_sets).
+// Key map selalu lowercase; poin komponen dijumlahkan dengan poin dasar tipe prestasi.
+type ScoringRules struct {
+	// TypePoints adalah poin dasar per tipe prestasi untuk versi ini; saat versi dibuat, tipe yang tidak
+	// di-override diisi dari default_points katalog achievement_types
+	TypePoints             map[string]int `json:"type_points,omitempty"`
+	CompetitionLevelPoints map[string]int `json:"competition_level_points,omitempty"`
+	// RankPoints memakai key peringkat ("1", "2", ...); digabung dengan MedalTypePoints, diambil yang terbesar
+	RankPoints            map[string]int `json:"rank_points,omitempty"`
+	MedalTypePoints       map[string]int `json:"medal_type_points,omitempty"`
+	PublicationTypePoints map[string]int `json:"publication_type_points,omitempty"`
+	PositionPoints        map[string]int `json:"position_points,omitempty"`
+}
+
+// ScoringRuleSet adalah satu versi aturan skor; versi lama tidak pernah diubah agar bisa dipakai ulang saat recalculation
+type ScoringRuleSet struct {
+	Version     int          `json:"version"`
+	Description string       `json:"description"`
+	Rules       ScoringRules `json:"rules"`
+	IsActive    bool         `json:"is_active"`
+	CreatedBy   *uuid.UUID   `json:"created_by"`
+	CreatedAt   time.Time    `json:"created_at"`
+	ActivatedAt *time.Time   `json:"activated_at"`
+}
+
+// CreateScoringRuleSetRequest untuk membuat versi aturan skor baru (admin)
+type CreateScoringRuleSetRequest struct {
+	Description string       `json:"description"`
+	Rules       ScoringRules `json:"rules"`
+	Activate    bool         `json:"activate"`
+}
+
+// ScoreComponent adalah satu baris breakdown perhitungan skor
+type ScoreComponent struct {
+	Component string `json:"component"` // type, competitionLevel, rank, medalType, publicationType, position
+	Value     string `json:"value"`
+	Points    int    `json:"points"`
+}
+
+// AchievementScore adalah skor terakhir sebuah prestasi beserta breakdown-nya (achievement_scores)
+type AchievementScore struct {
+	AchievementID uuid.UUID        `json:"achievement_id"`
+	RuleVersion   int              `json:"rule_version"`
+	Points        int              `json:"points"`
+	Breakdown     []ScoreComponent `json:"breakdown"`
+	CalculatedBy  *uuid.UUID       `json:"calculated_by"`
+	CalculatedAt  time.Time        `json:"calculated_at"`
+}
+
+// RecalculateScoresRequest untuk menghitung ulang skor prestasi verified; version kosong = versi aktif
+type RecalculateScoresRequest struct {
+	Version *int `json:"version,omitempty"`
+}
+
+// RecalculateScoresResult adalah ringkasan hasil recalculation
+type RecalculateScoresResult struct {
+	RuleVersion int         `json:"rule_version"`
+	Processed   int         `json:"processed"`
+	Updated     int         `json:"updated"`
+	Failed      int         `json:"failed"`
+	FailedIDs   []uuid.UUID `json:"failed_ids,omitempty"`
+}
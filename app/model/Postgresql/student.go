@@ -15,3 +15,16 @@ type Student struct {
 	AdvisorID     uuid.UUID `json:"advisor_id"`
 	Created_at    time.Time `json:"created_at"`
 }
+
+// StudentStorageQuota represents attachment storage quota and usage of a student
+type StudentStorageQuota struct {
+	StudentID  uuid.UUID `json:"student_id"`
+	QuotaBytes int64     `json:"quota_bytes"`
+	UsedBytes  int64     `json:"used_bytes"`
+	IsDefault  bool      `json:"is_default"`
+}
+
+// UpdateStorageQuotaRequest for admin quota override
+type UpdateStorageQuotaRequest struct {
+	QuotaBytes int64 `json:"quota_bytes"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Jenis delegasi verifikasi
+const (
+	DelegationKindDelegation = "delegation" // dibuat sendiri oleh dosen wali
+	DelegationKindSubstitute = "substitute" // reviewer pengganti yang ditetapkan admin
+)
+
+// VerificationDelegation memberi hak verifikasi dosen wali (delegator) kepada dosen lain (delegate)
+// selama rentang tanggal tertentu (verification_delegations). StartDate dan EndDate inklusif.
+type VerificationDelegation struct {
+	ID              uuid.UUID  `json:"id"`
+	DelegatorID     uuid.UUID  `json:"delegator_id"`
+	DelegatorUserID uuid.UUID  `json:"delegator_user_id"`
+	DelegatorName   string     `json:"delegator_name"`
+	DelegateID      uuid.UUID  `json:"delegate_id"`
+	DelegateName    string     `json:"delegate_name"`
+	Kind            string     `json:"kind"`
+	StartDate       time.Time  `json:"start_date"`
+	EndDate         time.Time  `json:"end_date"`
+	Reason          *string    `json:"reason"`
+	CreatedBy       *uuid.UUID `json:"created_by"`
+	RevokedAt       *time.Time `json:"revoked_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// VerificationDelegationRequest untuk membuat delegasi (dosen) atau reviewer pengganti (admin).
+// DelegatorID hanya dipakai admin; dosen selalu mendelegasikan haknya sendiri.
+type VerificationDelegationRequest struct {
+	DelegatorID *uuid.UUID `json:"delegator_id,omitempty"`
+	DelegateID  uuid.UUID  `json:"delegate_id" validate:"required"`
+	StartDate   string     `json:"start_date" validate:"required"` // Format: YYYY-MM-DD
+	EndDate     string     `json:"end_date" validate:"required"`   // Format: YYYY-MM-DD
+	Reason      *string    `json:"reason,omitempty"`
+}
+
+// LecturerDelegationsResponse berisi delegasi yang diberikan dan diterima seorang dosen
+type LecturerDelegationsResponse struct {
+	Given    []VerificationDelegation `json:"given"`
+	Received []VerificationDelegation `json:"received"`
+}
@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AchievementOutboxRepository membaca event achievement_outbox dan menerapkannya ke MongoDB.
+// Event ditulis oleh AchievementRepository di dalam transaksi perubahan reference.
+type AchievementOutboxRepository interface {
+	GetPendingOutboxEvents(ctx context.Context, limit int) ([]model.AchievementOutboxEvent, error)
+	GetPendingOutboxEventsByAchievement(ctx context.Context, achievementID uuid.UUID) ([]model.AchievementOutboxEvent, error)
+	ApplyOutboxEvent(ctx context.Context, event model.AchievementOutboxEvent) error
+	MarkOutboxEventProcessed(ctx context.Context, eventID int64, processedAt time.Time) error
+	MarkOutboxEventFailed(ctx context.Context, eventID int64, lastError string, nextAttemptAt time.Time) error
+	DeleteProcessedOutboxEvents(ctx context.Context, before time.Time) (int64, error)
+	WithAchievementOutboxLock(ctx context.Context, achievementID uuid.UUID, fn func() error) (bool, error)
+	CountAttachmentsByStorageKey(ctx context.Context, storageKey string) (int64, error)
+}
+
+// removeAttachmentPayload adalah payload event remove_attachment; storage key dibawa agar file dapat dihapus
+// setelah attachment benar-benar hilang dari dokumen MongoDB
+type removeAttachmentPayload struct {
+	ID         string `bson:"id"`
+	StorageKey string `bson:"storageKey,omitempty"`
+}
+
+// RemovedAttachmentStorageKey mengambil storage key file dari event remove_attachment; kosong untuk event lain
+func RemovedAttachmentStorageKey(event model.AchievementOutboxEvent) string {
+	if event.Operation != model.OutboxOpRemoveAttachment {
+		return ""
+	}
+	var payload removeAttachmentPayload
+	if err := bson.Unmarshal(event.Payload, &payload); err != nil {
+		return ""
+	}
+	return payload.StorageKey
+}
+
+// ErrUnknownOutboxOperation dikembalikan untuk event dengan operation yang tidak dikenal
+var ErrUnknownOutboxOperation = errors.New("unknown outbox operation")
+
+type achievementOutboxRepo struct {
+	pgDB      *pgxpool.Pool
+	mongoColl *mongo.Collection
+}
+
+func NewAchievementOutboxRepository(pgDB *pgxpool.Pool, mongoColl *mongo.Collection) AchievementOutboxRepository {
+	return &achievementOutboxRepo{pgDB: pgDB, mongoColl: mongoColl}
+}
+
+const outboxEventColumns = `id, achievement_id, mongo_achievement_id, operation, payload, attempts, last_error,
+              next_attempt_at, created_at, processed_at`
+
+// GetPendingOutboxEvents mengambil event yang belum diterapkan, urut sesuai waktu dicatat
+func (r *achievementOutboxRepo) GetPendingOutboxEvents(ctx context.Context, limit int) ([]model.AchievementOutboxEvent, error) {
+	query := `SELECT ` + outboxEventColumns + ` FROM achievement_outbox
+              WHERE processed_at IS NULL ORDER BY id LIMIT $1`
+	return r.queryOutboxEvents(ctx, query, limit)
+}
+
+// GetPendingOutboxEventsByAchievement mengambil event yang belum diterapkan untuk satu prestasi
+func (r *achievementOutboxRepo) GetPendingOutboxEventsByAchievement(ctx context.Context, achievementID uuid.UUID) ([]model.AchievementOutboxEvent, error) {
+	query := `SELECT ` + outboxEventColumns + ` FROM achievement_outbox
+              WHERE achievement_id = $1 AND processed_at IS NULL ORDER BY id`
+	return r.queryOutboxEvents(ctx, query, achievementID)
+}
+
+func (r *achievementOutboxRepo) queryOutboxEvents(ctx context.Context, query string, args ...interface{}) ([]model.AchievementOutboxEvent, error) {
+	rows, err := r.pgDB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []model.AchievementOutboxEvent{}
+	for rows.Next() {
+		var e model.AchievementOutboxEvent
+		err := rows.Scan(&e.ID, &e.AchievementID, &e.MongoAchievementID, &e.Operation, &e.Payload, &e.Attempts,
+			&e.LastError, &e.NextAttemptAt, &e.CreatedAt, &e.ProcessedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// ApplyOutboxEvent menerapkan satu event ke MongoDB. Semua operasi idempotent sehingga
+// event yang sudah diterapkan tetapi gagal ditandai processed aman diterapkan ulang.
+func (r *achievementOutboxRepo) ApplyOutboxEvent(ctx context.Context, event model.AchievementOutboxEvent) error {
+	objectID, err := primitive.ObjectIDFromHex(event.MongoAchievementID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": objectID}
+
+	switch event.Operation {
+	case model.OutboxOpUpsertDocument:
+		var doc bson.D
+		if err := bson.Unmarshal(event.Payload, &doc); err != nil {
+			return err
+		}
+
+		// attachments dikelola endpoint attachment; hanya diisi saat dokumen pertama kali dibuat
+		// agar event yang tertunda tidak menimpa attachment yang ditambahkan sesudahnya
+		set := bson.D{}
+		setOnInsert := bson.D{}
+		for _, el := range doc {
+			switch el.Key {
+			case "_id":
+			case "attachments":
+				setOnInsert = append(setOnInsert, el)
+			default:
+				set = append(set, el)
+			}
+		}
+		update := bson.M{"$set": set}
+		if len(setOnInsert) > 0 {
+			update["$setOnInsert"] = setOnInsert
+		}
+		_, err = r.mongoColl.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		return err
+
+	case model.OutboxOpSoftDeleteDocument:
+		update := bson.M{"$set": bson.M{"deleted_at": event.CreatedAt, "updated_at": event.CreatedAt}}
+		_, err = r.mongoColl.UpdateOne(ctx, filter, update)
+		return err
+
+	case model.OutboxOpRestoreDocument:
+		update := bson.M{
+			"$unset": bson.M{"deleted_at": ""},
+			"$set":   bson.M{"updated_at": event.CreatedAt},
+		}
+		_, err = r.mongoColl.UpdateOne(ctx, filter, update)
+		return err
+
+	case model.OutboxOpAddAttachment:
+		var attachment mongodb.Attachment
+		if err := bson.Unmarshal(event.Payload, &attachment); err != nil {
+			return err
+		}
+		// Attachment yang sudah ada (event diterapkan ulang) tidak ditambahkan lagi
+		filter["attachments.id"] = bson.M{"$ne": attachment.ID}
+		update := bson.M{
+			"$push": bson.M{"attachments": attachment},
+			"$set":  bson.M{"updatedAt": event.CreatedAt},
+		}
+		_, err = r.mongoColl.UpdateOne(ctx, filter, update)
+		return err
+
+	case model.OutboxOpRemoveAttachment:
+		var payload removeAttachmentPayload
+		if err := bson.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		update := bson.M{
+			"$pull": bson.M{"attachments": bson.M{"id": payload.ID}},
+			"$set":  bson.M{"updatedAt": event.CreatedAt},
+		}
+		_, err = r.mongoColl.UpdateOne(ctx, filter, update)
+		return err
+
+	case model.OutboxOpSetPoints:
+		var payload struct {
+			Points int `bson:"points"`
+		}
+		if err := bson.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		_, err = r.mongoColl.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"points": payload.Points}})
+		return err
+	}
+
+	return ErrUnknownOutboxOperation
+}
+
+// MarkOutboxEventProcessed menandai event sudah diterapkan ke MongoDB
+func (r *achievementOutboxRepo) MarkOutboxEventProcessed(ctx context.Context, eventID int64, processedAt time.Time) error {
+	_, err := r.pgDB.Exec(ctx, `UPDATE achievement_outbox SET processed_at = $1, last_error = NULL WHERE id = $2`, processedAt, eventID)
+	return err
+}
+
+// MarkOutboxEventFailed mencatat kegagalan penerapan event dan jadwal percobaan berikutnya
+func (r *achievementOutboxRepo) MarkOutboxEventFailed(ctx context.Context, eventID int64, lastError string, nextAttemptAt time.Time) error {
+	query := `UPDATE achievement_outbox SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2
+              WHERE id = $3 AND processed_at IS NULL`
+	_, err := r.pgDB.Exec(ctx, query, lastError, nextAttemptAt, eventID)
+	return err
+}
+
+// DeleteProcessedOutboxEvents menghapus event yang sudah diterapkan sebelum batas waktu
+func (r *achievementOutboxRepo) DeleteProcessedOutboxEvents(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pgDB.Exec(ctx, `DELETE FROM achievement_outbox WHERE processed_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// outboxLockNamespace adalah key pertama advisory lock outbox, memisahkannya dari advisory lock lain
+const outboxLockNamespace = 20
+
+// WithAchievementOutboxLock menjalankan fn selama memegang advisory lock PostgreSQL untuk event satu prestasi,
+// sehingga hanya satu worker (di instance mana pun) yang menerapkan event prestasi tersebut, berurutan.
+// Mengembalikan false tanpa menjalankan fn jika lock sedang dipegang worker lain. Lock dilepas saat transaksi selesai.
+func (r *achievementOutboxRepo) WithAchievementOutboxLock(ctx context.Context, achievementID uuid.UUID, fn func() error) (bool, error) {
+	tx, err := r.pgDB.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// hashtext bisa bertabrakan antar prestasi; akibatnya hanya event keduanya diterapkan bergantian
+	var locked bool
+	err = tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1, hashtext($2))`, outboxLockNamespace, achievementID.String()).Scan(&locked)
+	if err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+
+	if err := fn(); err != nil {
+		return true, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// insertOutboxEvent mencatat event untuk dokumen MongoDB milik achievementID di dalam transaksi yang sedang berjalan
+func insertOutboxEvent(ctx context.Context, tx pgx.Tx, achievementID uuid.UUID, operation string, payload []byte, at time.Time) error {
+	query := `INSERT INTO achievement_outbox (achievement_id, mongo_achievement_id, operation, payload, next_attempt_at, created_at)
+              SELECT id, mongo_achievement_id, $2, $3, $4, $4 FROM achievement_references WHERE id = $1`
+
+	tag, err := tx.Exec(ctx, query, achievementID, operation, payload, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// insertOutboxUpsert mencatat isi prestasi (BSON, termasuk field json:"-" seperti storage_key) untuk ditulis ke MongoDB,
+// sekaligus memperbarui read model listing di transaksi yang sama
+func insertOutboxUpsert(ctx context.Context, tx pgx.Tx, achievementID uuid.UUID, achievement mongodb.Achievement, at time.Time) error {
+	payload, err := bson.Marshal(achievement)
+	if err != nil {
+		return err
+	}
+	if err := insertOutboxEvent(ctx, tx, achievementID, model.OutboxOpUpsertDocument, payload, at); err != nil {
+		return err
+	}
+	return upsertAchievementReadModel(ctx, tx, achievementID, achievement, at)
+}
+
+// insertOutboxSetPoints mencatat poin hasil scoring untuk ditulis ke dokumen MongoDB, di transaksi yang sama dengan skornya
+func insertOutboxSetPoints(ctx context.Context, tx pgx.Tx, achievementID uuid.UUID, points int, at time.Time) error {
+	payload, err := bson.Marshal(bson.M{"points": points})
+	if err != nil {
+		return err
+	}
+	return insertOutboxEvent(ctx, tx, achievementID, model.OutboxOpSetPoints, payload, at)
+}
+
+// CountAttachmentsByStorageKey menghitung dokumen yang masih memakai file dengan storage key tertentu
+func (r *achievementOutboxRepo) CountAttachmentsByStorageKey(ctx context.Context, storageKey string) (int64, error) {
+	return r.mongoColl.CountDocuments(ctx, bson.M{"attachments.storageKey": storageKey})
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AchievementReadModelRepository mengisi achievement_read_model untuk reference yang belum punya baris,
+// misalnya data yang dibuat sebelum read model ada. Penulisan normal disinkronkan lewat insertOutboxUpsert.
+type AchievementReadModelRepository interface {
+	GetReferencesWithoutReadModel(ctx context.Context, limit int) ([]model.AchievementReference, error)
+	SaveReadModelFromDocuments(ctx context.Context, refs []model.AchievementReference) error
+}
+
+type achievementReadModelRepo struct {
+	pgDB      *pgxpool.Pool
+	mongoColl *mongo.Collection
+}
+
+func NewAchievementReadModelRepository(pgDB *pgxpool.Pool, mongoColl *mongo.Collection) AchievementReadModelRepository {
+	return &achievementReadModelRepo{pgDB: pgDB, mongoColl: mongoColl}
+}
+
+// GetReferencesWithoutReadModel mengambil reference (status apa pun) yang belum punya baris read model
+func (r *achievementReadModelRepo) GetReferencesWithoutReadModel(ctx context.Context, limit int) ([]model.AchievementReference, error) {
+	query := `SELECT ar.id, ar.mongo_achievement_id
+              FROM achievement_references ar
+              WHERE NOT EXISTS (SELECT 1 FROM achievement_read_model rm WHERE rm.achievement_id = ar.id)
+              ORDER BY ar.id
+              LIMIT $1`
+
+	rows, err := r.pgDB.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refs := []model.AchievementReference{}
+	for rows.Next() {
+		var ref model.AchievementReference
+		if err := rows.Scan(&ref.ID, &ref.MongoAchievementID); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
+
+// SaveReadModelFromDocuments membaca dokumen MongoDB untuk refs lalu menyimpan baris read model-nya.
+// Reference tanpa dokumen tetap mendapat baris kosong agar tidak dipindai ulang; dokumen yang dibuat ulang
+// rekonsiliasi akan mengisinya lewat outbox. Baris yang sudah ditulis transaksi lain tidak ditimpa.
+func (r *achievementReadModelRepo) SaveReadModelFromDocuments(ctx context.Context, refs []model.AchievementReference) error {
+	objectIDs := make([]primitive.ObjectID, 0, len(refs))
+	for _, ref := range refs {
+		if objectID, err := primitive.ObjectIDFromHex(ref.MongoAchievementID); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+
+	docs := map[string]mongodb.Achievement{}
+	if len(objectIDs) > 0 {
+		cursor, err := r.mongoColl.Find(ctx, bson.M{"_id": bson.M{"$in": objectIDs}})
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			var achievement mongodb.Achievement
+			if err := cursor.Decode(&achievement); err != nil {
+				return err
+			}
+			docs[achievement.ID.Hex()] = achievement
+		}
+		if err := cursor.Err(); err != nil {
+			return err
+		}
+	}
+
+	batch := &pgx.Batch{}
+	now := time.Now()
+	for _, ref := range refs {
+		if achievement, found := docs[ref.MongoAchievementID]; found {
+			query, args := readModelUpsert(ref.ID, achievement, now, "DO NOTHING")
+			batch.Queue(query, args...)
+			continue
+		}
+		batch.Queue(`INSERT INTO achievement_read_model (achievement_id, synced_at) VALUES ($1, $2) ON CONFLICT (achievement_id) DO NOTHING`, ref.ID, now)
+	}
+
+	return r.pgDB.SendBatch(ctx, batch).Close()
+}
+
+// upsertAchievementReadModel menulis field detail prestasi ke read model dalam transaksi yang sama
+// dengan event outbox-nya, sehingga listing langsung mencerminkan isi terbaru
+func upsertAchievementReadModel(ctx context.Context, tx pgx.Tx, achievementID uuid.UUID, achievement mongodb.Achievement, at time.Time) error {
+	query, args := readModelUpsert(achievementID, achievement, at, `DO UPDATE SET
+		title = EXCLUDED.title, achievement_type = EXCLUDED.achievement_type,
+		competition_level = EXCLUDED.competition_level, medal_type = EXCLUDED.medal_type,
+		tags = EXCLUDED.tags, event_date = EXCLUDED.event_date, synced_at = EXCLUDED.synced_at`)
+	_, err := tx.Exec(ctx, query, args...)
+	return err
+}
+
+// readModelUpsert menyusun INSERT baris read model dari dokumen; onConflict menentukan perilaku jika baris sudah ada
+func readModelUpsert(achievementID uuid.UUID, achievement mongodb.Achievement, at time.Time, onConflict string) (string, []interface{}) {
+	query := fmt.Sprintf(`INSERT INTO achievement_read_model (
+		achievement_id, title, achievement_type, competition_level, medal_type, tags, event_date, synced_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (achievement_id) %s`, onConflict)
+
+	return query, []interface{}{
+		achievementID, achievement.Title, utils.NormalizeAchievementType(achievement.AchievementType),
+		normalizeReadModelValue(achievement.Details.CompetitionLevel), normalizeReadModelValue(achievement.Details.MedalType),
+		utils.NormalizeAchievementTags(achievement.Tags), achievement.Details.EventDate, at,
+	}
+}
+
+// normalizeReadModelValue menyeragamkan nilai field read model ("Gold " -> "gold") agar filter dan facet
+// tidak memecah nilai yang sama; nilai kosong disimpan sebagai NULL
+func normalizeReadModelValue(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	normalized := strings.ToLower(strings.TrimSpace(*value))
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
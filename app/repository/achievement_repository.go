@@ -2,52 +2,87 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	mongodb "UASBE/app/model/MongoDB"
 	model "UASBE/app/model/Postgresql"
+	"UASBE/utils"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/lib/pq"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type AchievementRepository interface {
 	GetStudentByUserID(ctx context.Context, userID uuid.UUID) (*model.Student, error)
-	SaveAchievementMongo(ctx context.Context, achievement mongodb.Achievement) (string, error)
-	SaveAchievementReference(ctx context.Context, ref model.AchievementReference) error
+	SaveAchievementReference(ctx context.Context, ref model.AchievementReference, snapshot mongodb.Achievement, changedBy uuid.UUID) error
 	GetAchievementReferenceByID(ctx context.Context, achievementID uuid.UUID) (*model.AchievementReference, error)
-	UpdateAchievementStatusToSubmitted(ctx context.Context, achievementID uuid.UUID) error
+	UpdateAchievementStatusToSubmitted(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID, stages []model.ApprovalStage, reviewLevel string) error
 	GetAdvisorIDByStudentID(ctx context.Context, studentID uuid.UUID) (uuid.UUID, error)
-	SoftDeleteAchievementMongo(ctx context.Context, mongoAchievementID string) error
-	UpdateAchievementReferenceToDeleted(ctx context.Context, achievementID uuid.UUID) error
+	UpdateAchievementReferenceToDeleted(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID) error
 	GetLecturerByUserID(ctx context.Context, userID uuid.UUID) (*model.Lecturers, error)
 	GetStudentIDsByAdvisorID(ctx context.Context, advisorID uuid.UUID) ([]uuid.UUID, error)
-	GetAchievementsWithStudentInfo(ctx context.Context, studentIDs []uuid.UUID, status string, page, limit int) ([]model.AchievementWithStudent, int, error)
+	GetAchievementsWithStudentInfo(ctx context.Context, studentIDs []uuid.UUID, filters model.AchievementListFilters, page, limit int) ([]model.AchievementWithStudent, int, error)
 	GetAchievementDetailFromMongo(ctx context.Context, mongoAchievementID string) (*mongodb.Achievement, error)
-	UpdateAchievementStatusToVerified(ctx context.Context, achievementID uuid.UUID, lecturerID uuid.UUID) error
+	UpdateAchievementStatusToVerified(ctx context.Context, achievementID uuid.UUID, lecturerID uuid.UUID, changedBy uuid.UUID, score model.AchievementScore, decision model.ApprovalDecision) error
 	GetStudentByID(ctx context.Context, studentID uuid.UUID) (*model.Student, error)
-	UpdateAchievementStatusToRejected(ctx context.Context, achievementID uuid.UUID, rejectionNote string) error
+	UpdateAchievementStatusToRejected(ctx context.Context, achievementID uuid.UUID, rejectionNote string, changedBy uuid.UUID, snapshot mongodb.Achievement, decision model.ApprovalDecision) error
+	UpdateAchievementStatusToRevised(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID) error
+	UpdateAchievementStatusToResubmitted(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID, stages []model.ApprovalStage, reviewLevel string) error
+	UpdateAchievementStatusToWithdrawn(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID, note string, advisorMessage string) error
+	GetDeletedAchievements(ctx context.Context, page, limit int) ([]model.TrashedAchievement, int, error)
+	UpdateAchievementReferenceToRestored(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID) error
+	GetAchievementsDeletedBefore(ctx context.Context, before time.Time, limit int) ([]model.AchievementReference, error)
+	PurgeAchievementMongo(ctx context.Context, mongoAchievementID string) error
+	PurgeAchievementReference(ctx context.Context, achievementID uuid.UUID) error
+	GetAchievementApprovals(ctx context.Context, achievementID uuid.UUID, revision int) ([]model.AchievementApproval, error)
+	ApproveAchievementStage(ctx context.Context, decision model.ApprovalDecision) error
+	GetPendingApprovalsByRole(ctx context.Context, approverRole, department string) ([]model.AchievementApproval, error)
+	GetUserRoleName(ctx context.Context, userID uuid.UUID) (string, error)
+	GetLatestAchievementRejection(ctx context.Context, achievementID uuid.UUID) (*model.AchievementRejection, error)
+	SaveAchievementVersion(ctx context.Context, achievementID uuid.UUID, snapshot mongodb.Achievement, changedBy uuid.UUID) (int, error)
+	GetAchievementVersions(ctx context.Context, achievementID uuid.UUID) ([]model.AchievementVersion, error)
+	GetAchievementVersion(ctx context.Context, achievementID uuid.UUID, version int) (*model.AchievementVersion, error)
+	GetLatestAchievementSnapshot(ctx context.Context, achievementID uuid.UUID) (*mongodb.Achievement, error)
+	GetAchievementsWithoutVersions(ctx context.Context, afterID uuid.UUID, limit int) ([]model.AchievementReference, error)
+	SaveBaselineVersionsFromDocuments(ctx context.Context, refs []model.AchievementReference) (int, error)
 	GetAchievementStatusHistory(ctx context.Context, achievementID uuid.UUID) ([]model.AchievementStatusLog, error)
 	LogAchievementStatusChange(ctx context.Context, log model.AchievementStatusLog) error
 	GetAllAchievementsForAdmin(ctx context.Context, filters model.AdminAchievementFilters, page, limit int) ([]model.AchievementWithStudent, int, error)
-	GetStatisticsByType(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters, mongoColl *mongo.Collection) ([]model.StatsByType, error)
+	GetAdminAchievementFacets(ctx context.Context, filters model.AdminAchievementFilters) (*model.AchievementFacets, error)
+	GetStatisticsByType(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters) ([]model.StatsByType, error)
 	GetStatisticsByPeriod(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters) ([]model.StatsByPeriod, error)
 	GetTopStudents(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters, limit int) ([]model.TopStudent, error)
 	GetAchievementsByID(ctx context.Context, userID uuid.UUID) (*model.Users, error)
-	UpdateAchievementInMongo(ctx context.Context, achievement mongodb.Achievement) error
-	UpdateAchievementTimestamp(ctx context.Context, achievementID uuid.UUID) error
-	GetLevelDistribution(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters, mongoColl *mongo.Collection) ([]model.LevelDistribution, error)
+	UpdateAchievementContent(ctx context.Context, achievementID uuid.UUID, snapshot mongodb.Achievement, changedBy uuid.UUID, revise bool) error
+	GetLevelDistribution(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters) ([]model.LevelDistribution, error)
 	GetStatusDistribution(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters) ([]model.StatusDistribution, error)
+	SearchAchievementDocuments(ctx context.Context, query string, studentIDs []uuid.UUID, skip, limit int) ([]model.AchievementSearchMatch, int64, error)
+	GetAchievementsByMongoIDs(ctx context.Context, mongoIDs []string) (map[string]model.AchievementWithStudent, error)
 	GetTotalAchievements(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters) (int, error)
-	AddAttachmentToAchievement(ctx context.Context, mongoAchievementID, fileName, fileURL, fileType string) error
+	AddAttachmentToAchievement(ctx context.Context, achievementID uuid.UUID, attachment mongodb.Attachment, snapshot mongodb.Achievement, changedBy uuid.UUID, revise bool) error
+	RemoveAttachmentFromAchievement(ctx context.Context, achievementID uuid.UUID, attachment mongodb.Attachment, snapshot mongodb.Achievement, changedBy uuid.UUID, revise bool) error
+	CountAttachmentsByStorageKey(ctx context.Context, storageKey string) (int64, error)
+	CountAchievementsByType(ctx context.Context, achievementType string) (int64, error)
+	GetAchievementReferencesByStatus(ctx context.Context, status string) ([]model.AchievementReference, error)
+	GetStudentAttachmentUsage(ctx context.Context, studentID uuid.UUID) (int64, error)
+	GetStudentStorageQuota(ctx context.Context, studentID uuid.UUID) (*int64, error)
+	SetStudentStorageQuota(ctx context.Context, studentID uuid.UUID, quotaBytes int64) error
 	GetStudentWithUserByID(ctx context.Context, studentID uuid.UUID) (*model.StudentWithUser, error)
 	GetStudentAchievements(ctx context.Context, studentID uuid.UUID, page, limit int) ([]model.AchievementWithStudent, int, error)
 	GetAllStudentIDs(ctx context.Context) ([]uuid.UUID, error)
+	GetAchievementComments(ctx context.Context, achievementID uuid.UUID) ([]model.AchievementComment, error)
+	GetAchievementCommentByID(ctx context.Context, commentID uuid.UUID) (*model.AchievementComment, error)
+	CreateAchievementComment(ctx context.Context, comment model.AchievementComment) error
+	SetAchievementCommentResolved(ctx context.Context, commentID uuid.UUID, resolved bool, resolvedBy uuid.UUID, resolvedAt time.Time) error
 }
 
 type achievementRepo struct {
@@ -78,39 +113,59 @@ func (r *achievementRepo) GetStudentByUserID(ctx context.Context, userID uuid.UU
 	return &s, nil
 }
 
-// SaveAchievementMongo menyimpan detail lengkap prestasi ke MongoDB
-func (r *achievementRepo) SaveAchievementMongo(ctx context.Context, achievement mongodb.Achievement) (string, error) {
-	res, err := r.mongoColl.InsertOne(ctx, achievement)
+// SaveAchievementReference menyimpan referensi status ke PostgreSQL beserta log status awal, versi 1,
+// dan event outbox untuk dokumen MongoDB-nya dalam satu transaksi
+func (r *achievementRepo) SaveAchievementReference(ctx context.Context, ref model.AchievementReference, snapshot mongodb.Achievement, changedBy uuid.UUID) error {
+	tx, err := r.pgDB.Begin(ctx)
 	if err != nil {
-		return "", err
+		return err
 	}
-	// Mengembalikan ID Mongo dalam bentuk Hex String
-	return res.InsertedID.(primitive.ObjectID).Hex(), nil
-}
+	defer tx.Rollback(ctx)
 
-// SaveAchievementReference menyimpan referensi status ke PostgreSQL
-func (r *achievementRepo) SaveAchievementReference(ctx context.Context, ref model.AchievementReference) error {
 	query := `INSERT INTO achievement_references (
 		id, student_id, mongo_achievement_id, status, created_at, updated_at
 	) VALUES ($1, $2, $3, $4, $5, $6)`
 
-	_, err := r.pgDB.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		ref.ID, ref.StudentID, ref.MongoAchievementID, ref.Status, ref.CreatedAt, ref.UpdatedAt,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	err = insertStatusLog(ctx, tx, model.AchievementStatusLog{
+		ID:            uuid.New(),
+		AchievementID: ref.ID,
+		Status:        ref.Status,
+		ChangedBy:     &changedBy,
+		CreatedAt:     ref.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := insertAchievementVersion(ctx, tx, ref.ID, snapshot, changedBy, ref.CreatedAt); err != nil {
+		return err
+	}
+
+	if err := insertOutboxUpsert(ctx, tx, ref.ID, snapshot, ref.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 // GetAchievementReferenceByID mengambil data achievement reference berdasarkan ID
 func (r *achievementRepo) GetAchievementReferenceByID(ctx context.Context, achievementID uuid.UUID) (*model.AchievementReference, error) {
 	query := `SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, 
-              verified_by, rejection_note, created_at, updated_at 
+              verified_by, rejection_note, revision, review_due_at, escalated_at, escalated_to, created_at, updated_at 
               FROM achievement_references WHERE id = $1`
 
 	var ref model.AchievementReference
 	err := r.pgDB.QueryRow(ctx, query, achievementID).Scan(
 		&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status,
 		&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote,
-		&ref.CreatedAt, &ref.UpdatedAt,
+		&ref.Revision, &ref.ReviewDueAt, &ref.EscalatedAt, &ref.EscalatedTo, &ref.CreatedAt, &ref.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -119,14 +174,68 @@ func (r *achievementRepo) GetAchievementReferenceByID(ctx context.Context, achie
 }
 
 // UpdateAchievementStatusToSubmitted mengupdate status achievement menjadi 'submitted'
-func (r *achievementRepo) UpdateAchievementStatusToSubmitted(ctx context.Context, achievementID uuid.UUID) error {
+// dan membuat tahap persetujuannya dalam transaksi yang sama
+func (r *achievementRepo) UpdateAchievementStatusToSubmitted(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID, stages []model.ApprovalStage, reviewLevel string) error {
 	query := `UPDATE achievement_references 
-              SET status = 'submitted', submitted_at = $1, updated_at = $2 
-              WHERE id = $3`
+              SET status = 'submitted', submitted_at = $1, updated_at = $2, ` + reviewDeadlineSet + `
+              WHERE id = $4`
 
 	now := time.Now()
-	_, err := r.pgDB.Exec(ctx, query, now, now, achievementID)
-	return err
+	createApprovals := func(tx pgx.Tx) error {
+		return insertApprovalStages(ctx, tx, achievementID, stages, now)
+	}
+	return r.updateStatusWithLogTx(ctx, achievementID, "submitted", changedBy, nil, now, nil, createApprovals, query, now, now, reviewLevel, achievementID)
+}
+
+// reviewDeadlineSet mengisi batas waktu review dari SLA level $3 (atau 'default') terhitung $1,
+// dan mengosongkan eskalasi dari submission sebelumnya
+const reviewDeadlineSet = `review_level = NULLIF($3::text, ''),
+              review_due_at = $1 + make_interval(hours => (
+                  SELECT deadline_hours FROM review_slas WHERE level IN ($3::text, 'default')
+                  ORDER BY level = 'default' LIMIT 1
+              )),
+              escalated_at = NULL, escalated_to = NULL`
+
+// ErrAchievementNotSubmitted dikembalikan jika status prestasi sudah berubah (mis. diverifikasi) sebelum ditarik
+var ErrAchievementNotSubmitted = errors.New("achievement is no longer submitted")
+
+// UpdateAchievementStatusToWithdrawn mengembalikan prestasi 'submitted' ke 'draft' atas permintaan mahasiswa.
+// Tahap persetujuan revision berjalan dibuang (submit berikutnya memulai chain dari awal) dan
+// dosen wali diberi notifikasi dalam transaksi yang sama.
+func (r *achievementRepo) UpdateAchievementStatusToWithdrawn(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID, note string, advisorMessage string) error {
+	query := `UPDATE achievement_references
+              SET status = 'draft', submitted_at = NULL, review_level = NULL, review_due_at = NULL,
+                  escalated_at = NULL, escalated_to = NULL, updated_at = $1
+              WHERE id = $2 AND status = 'submitted'`
+
+	now := time.Now()
+	afterUpdate := func(tx pgx.Tx) error {
+		if err := expectAchievementStatus(ctx, tx, achievementID, "draft", ErrAchievementNotSubmitted); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx, `DELETE FROM achievement_approvals
+			WHERE achievement_id = $1 AND revision = (SELECT revision FROM achievement_references WHERE id = $1)`, achievementID)
+		if err != nil {
+			return err
+		}
+
+		return insertAdvisorNotification(ctx, tx, achievementID, model.NotificationAchievementWithdrawn, advisorMessage, now)
+	}
+	return r.updateStatusWithLogTx(ctx, achievementID, "draft", changedBy, &note, now, nil, afterUpdate, query, now, achievementID)
+}
+
+// expectAchievementStatus memastikan update bersyarat status (WHERE status = ...) benar-benar terjadi;
+// dipanggil dari afterUpdate karena baris sudah dikunci oleh updateStatusWithLogTx
+func expectAchievementStatus(ctx context.Context, tx pgx.Tx, achievementID uuid.UUID, status string, errMismatch error) error {
+	var current string
+	if err := tx.QueryRow(ctx, `SELECT status FROM achievement_references WHERE id = $1`, achievementID).Scan(&current); err != nil {
+		return err
+	}
+	if current != status {
+		return errMismatch
+	}
+	return nil
 }
 
 // GetAdvisorIDByStudentID mengambil advisor_id dari student
@@ -141,34 +250,24 @@ func (r *achievementRepo) GetAdvisorIDByStudentID(ctx context.Context, studentID
 	return advisorID, nil
 }
 
-// SoftDeleteAchievementMongo melakukan soft delete di MongoDB dengan menambahkan field deleted_at
-func (r *achievementRepo) SoftDeleteAchievementMongo(ctx context.Context, mongoAchievementID string) error {
-	objectID, err := primitive.ObjectIDFromHex(mongoAchievementID)
-	if err != nil {
-		return err
-	}
-
-	filter := bson.M{"_id": objectID}
-	update := bson.M{
-		"$set": bson.M{
-			"deleted_at": time.Now(),
-			"updated_at": time.Now(),
-		},
-	}
-
-	_, err = r.mongoColl.UpdateOne(ctx, filter, update)
-	return err
-}
+// ErrAchievementNotDraft dikembalikan jika prestasi sudah disubmit sebelum sempat dihapus
+var ErrAchievementNotDraft = errors.New("achievement is no longer a draft")
 
-// UpdateAchievementReferenceToDeleted mengupdate status achievement reference menjadi 'deleted'
-func (r *achievementRepo) UpdateAchievementReferenceToDeleted(ctx context.Context, achievementID uuid.UUID) error {
+// UpdateAchievementReferenceToDeleted mengupdate status achievement reference 'draft' menjadi 'deleted'
+// dan mencatat soft delete dokumen MongoDB di outbox
+func (r *achievementRepo) UpdateAchievementReferenceToDeleted(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID) error {
 	query := `UPDATE achievement_references 
-              SET status = 'deleted', updated_at = $1 
-              WHERE id = $2`
+              SET status = 'deleted', deleted_at = $1, updated_at = $1 
+              WHERE id = $2 AND status = 'draft'`
 
 	now := time.Now()
-	_, err := r.pgDB.Exec(ctx, query, now, achievementID)
-	return err
+	softDeleteDocument := func(tx pgx.Tx) error {
+		if err := expectAchievementStatus(ctx, tx, achievementID, "deleted", ErrAchievementNotDraft); err != nil {
+			return err
+		}
+		return insertOutboxEvent(ctx, tx, achievementID, model.OutboxOpSoftDeleteDocument, nil, now)
+	}
+	return r.updateStatusWithLogTx(ctx, achievementID, "deleted", changedBy, nil, now, nil, softDeleteDocument, query, now, achievementID)
 }
 
 // GetLecturerByUserID mengambil data lecturer dari Postgres berdasarkan user_id
@@ -212,81 +311,17 @@ func (r *achievementRepo) GetStudentIDsByAdvisorID(ctx context.Context, advisorI
 	return studentIDs, nil
 }
 
-// GetAchievementsWithStudentInfo mengambil achievements dengan info student, dengan pagination
-func (r *achievementRepo) GetAchievementsWithStudentInfo(ctx context.Context, studentIDs []uuid.UUID, status string, page, limit int) ([]model.AchievementWithStudent, int, error) {
+// GetAchievementsWithStudentInfo mengambil achievements dengan info student, dengan filter, sorting dan pagination
+func (r *achievementRepo) GetAchievementsWithStudentInfo(ctx context.Context, studentIDs []uuid.UUID, filters model.AchievementListFilters, page, limit int) ([]model.AchievementWithStudent, int, error) {
 	if len(studentIDs) == 0 {
 		return []model.AchievementWithStudent{}, 0, nil
 	}
 
-	// Build query dengan filter
-	baseQuery := `
-		SELECT 
-			ar.id, ar.student_id, ar.mongo_achievement_id, ar.status,
-			ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note,
-			ar.created_at, ar.updated_at,
-			s.student_id as student_nim, u.full_name as student_name, s.program_study
-		FROM achievement_references ar
-		JOIN students s ON ar.student_id = s.id
-		JOIN users u ON s.user_id = u.id
-		WHERE ar.student_id = ANY($1)
-	`
-
-	countQuery := `
-		SELECT COUNT(*)
-		FROM achievement_references ar
-		WHERE ar.student_id = ANY($1)
-	`
-
+	where := ` WHERE ar.student_id = ANY($1)`
 	args := []interface{}{pq.Array(studentIDs)}
-	argCount := 2
+	where, args = appendAchievementListFilters(where, args, filters)
 
-	// Add status filter if provided
-	if status != "" {
-		baseQuery += ` AND ar.status = $` + fmt.Sprintf("%d", argCount)
-		countQuery += ` AND ar.status = $` + fmt.Sprintf("%d", argCount)
-		args = append(args, status)
-		argCount++
-	}
-
-	// Get total count
-	var total int
-	err := r.pgDB.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// Add ordering and pagination
-	baseQuery += ` ORDER BY ar.created_at DESC LIMIT $` + fmt.Sprintf("%d", argCount) + ` OFFSET $` + fmt.Sprintf("%d", argCount+1)
-	offset := (page - 1) * limit
-	args = append(args, limit, offset)
-
-	// Execute query
-	rows, err := r.pgDB.Query(ctx, baseQuery, args...)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer rows.Close()
-
-	var achievements []model.AchievementWithStudent
-	for rows.Next() {
-		var a model.AchievementWithStudent
-		err := rows.Scan(
-			&a.ID, &a.StudentID, &a.MongoAchievementID, &a.Status,
-			&a.SubmittedAt, &a.VerifiedAt, &a.VerifiedBy, &a.RejectionNote,
-			&a.CreatedAt, &a.UpdatedAt,
-			&a.StudentNIM, &a.StudentName, &a.ProgramStudy,
-		)
-		if err != nil {
-			return nil, 0, err
-		}
-		achievements = append(achievements, a)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, 0, err
-	}
-
-	return achievements, total, nil
+	return r.queryAchievementList(ctx, where, args, filters, page, limit)
 }
 
 // GetAchievementDetailFromMongo mengambil detail achievement dari MongoDB
@@ -308,14 +343,22 @@ func (r *achievementRepo) GetAchievementDetailFromMongo(ctx context.Context, mon
 }
 
 // UpdateAchievementStatusToVerified mengupdate status achievement menjadi 'verified'
-func (r *achievementRepo) UpdateAchievementStatusToVerified(ctx context.Context, achievementID uuid.UUID, lecturerID uuid.UUID) error {
+// dan menyimpan skornya dalam transaksi yang sama,
+// bersama keputusan tahap persetujuan terakhir
+func (r *achievementRepo) UpdateAchievementStatusToVerified(ctx context.Context, achievementID uuid.UUID, lecturerID uuid.UUID, changedBy uuid.UUID, score model.AchievementScore, decision model.ApprovalDecision) error {
 	query := `UPDATE achievement_references 
               SET status = 'verified', verified_by = $1, verified_at = $2, updated_at = $3 
               WHERE id = $4`
 
 	now := time.Now()
-	_, err := r.pgDB.Exec(ctx, query, lecturerID, now, now, achievementID)
-	return err
+	// Skor dan event outbox poinnya ikut transaksi verifikasi; MongoDB baru ditulis setelah commit
+	saveScore := func(tx pgx.Tx) error {
+		if err := upsertAchievementScore(ctx, tx, score); err != nil {
+			return err
+		}
+		return insertOutboxSetPoints(ctx, tx, achievementID, score.Points, now)
+	}
+	return r.updateStatusWithLogTx(ctx, achievementID, "verified", changedBy, nil, now, &decision, saveScore, query, lecturerID, now, now, achievementID)
 }
 
 // GetStudentByID mengambil data student dari Postgres berdasarkan student ID
@@ -335,13 +378,149 @@ func (r *achievementRepo) GetStudentByID(ctx context.Context, studentID uuid.UUI
 }
 
 // UpdateAchievementStatusToRejected mengupdate status achievement menjadi 'rejected' dengan rejection note
-func (r *achievementRepo) UpdateAchievementStatusToRejected(ctx context.Context, achievementID uuid.UUID, rejectionNote string) error {
+// dan menyimpan snapshot isi prestasi saat ditolak serta keputusan tahap persetujuannya dalam transaksi yang sama
+func (r *achievementRepo) UpdateAchievementStatusToRejected(ctx context.Context, achievementID uuid.UUID, rejectionNote string, changedBy uuid.UUID, snapshot mongodb.Achievement, decision model.ApprovalDecision) error {
 	query := `UPDATE achievement_references 
               SET status = 'rejected', rejection_note = $1, updated_at = $2 
               WHERE id = $3`
 
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	saveRejection := func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `INSERT INTO achievement_rejections (id, achievement_id, revision, rejection_note, snapshot, rejected_by, rejected_at)
+			SELECT $1, id, revision, $2, $3, $4, $5 FROM achievement_references WHERE id = $6`,
+			uuid.New(), rejectionNote, snapshotJSON, changedBy, now, achievementID)
+		return err
+	}
+	return r.updateStatusWithLogTx(ctx, achievementID, "rejected", changedBy, &rejectionNote, now, &decision, saveRejection, query, rejectionNote, now, achievementID)
+}
+
+// UpdateAchievementStatusToRevised menandai prestasi yang ditolak sedang direvisi oleh mahasiswa
+func (r *achievementRepo) UpdateAchievementStatusToRevised(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID) error {
+	query := `UPDATE achievement_references SET status = 'revised', updated_at = $1 WHERE id = $2`
+
+	now := time.Now()
+	return r.updateStatusWithLog(ctx, achievementID, "revised", changedBy, nil, now, query, now, achievementID)
+}
+
+// UpdateAchievementStatusToResubmitted mengirim ulang prestasi hasil revisi ('submitted') dan menaikkan revision,
+// beserta tahap persetujuan untuk revision yang baru
+func (r *achievementRepo) UpdateAchievementStatusToResubmitted(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID, stages []model.ApprovalStage, reviewLevel string) error {
+	query := `UPDATE achievement_references 
+              SET status = 'submitted', submitted_at = $1, revision = revision + 1, updated_at = $2, ` + reviewDeadlineSet + `
+              WHERE id = $4`
+
 	now := time.Now()
-	_, err := r.pgDB.Exec(ctx, query, rejectionNote, now, achievementID)
+	createApprovals := func(tx pgx.Tx) error {
+		return insertApprovalStages(ctx, tx, achievementID, stages, now)
+	}
+	return r.updateStatusWithLogTx(ctx, achievementID, "submitted", changedBy, nil, now, nil, createApprovals, query, now, now, reviewLevel, achievementID)
+}
+
+// GetLatestAchievementRejection mengambil penolakan terakhir beserta snapshot-nya; nil jika belum pernah ditolak
+func (r *achievementRepo) GetLatestAchievementRejection(ctx context.Context, achievementID uuid.UUID) (*model.AchievementRejection, error) {
+	query := `SELECT id, achievement_id, revision, rejection_note, snapshot, rejected_by, rejected_at
+              FROM achievement_rejections WHERE achievement_id = $1
+              ORDER BY rejected_at DESC LIMIT 1`
+
+	var rejection model.AchievementRejection
+	var snapshot []byte
+	err := r.pgDB.QueryRow(ctx, query, achievementID).Scan(
+		&rejection.ID, &rejection.AchievementID, &rejection.Revision, &rejection.RejectionNote,
+		&snapshot, &rejection.RejectedBy, &rejection.RejectedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(snapshot, &rejection.Snapshot); err != nil {
+		return nil, err
+	}
+	return &rejection, nil
+}
+
+// updateStatusWithLog menjalankan update status di achievement_references dan mencatat
+// log perubahan status dalam satu transaksi, sehingga riwayat tidak pernah tertinggal.
+func (r *achievementRepo) updateStatusWithLog(ctx context.Context, achievementID uuid.UUID, status string, changedBy uuid.UUID, note *string, changedAt time.Time, updateQuery string, args ...interface{}) error {
+	return r.updateStatusWithLogTx(ctx, achievementID, status, changedBy, note, changedAt, nil, nil, updateQuery, args...)
+}
+
+// updateStatusWithLogTx sama dengan updateStatusWithLog, dengan afterUpdate (opsional) yang
+// dijalankan di transaksi yang sama untuk data turunan perubahan status (mis. skor).
+// decision (opsional) menutup tahap persetujuan yang sedang berjalan; dosen wali yang diwakili
+// delegasi ikut dicatat di log.
+func (r *achievementRepo) updateStatusWithLogTx(ctx context.Context, achievementID uuid.UUID, status string, changedBy uuid.UUID, note *string, changedAt time.Time, decision *model.ApprovalDecision, afterUpdate func(tx pgx.Tx) error, updateQuery string, args ...interface{}) error {
+	tx, err := r.pgDB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock baris reference agar previous_status konsisten dengan update
+	var previousStatus string
+	err = tx.QueryRow(ctx, `SELECT status FROM achievement_references WHERE id = $1 FOR UPDATE`, achievementID).Scan(&previousStatus)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, updateQuery, args...); err != nil {
+		return err
+	}
+
+	log := model.AchievementStatusLog{
+		ID:             uuid.New(),
+		AchievementID:  achievementID,
+		Status:         status,
+		PreviousStatus: &previousStatus,
+		ChangedBy:      &changedBy,
+		Note:           note,
+		CreatedAt:      changedAt,
+	}
+	if status == "rejected" {
+		log.RejectionNote = note
+	}
+
+	if decision != nil {
+		stageOrder, stageName, err := decideApproval(ctx, tx, *decision)
+		if err != nil {
+			return err
+		}
+		log.OnBehalfOf = decision.OnBehalfOf
+		log.DelegationID = decision.DelegationID
+		log.StageOrder = &stageOrder
+		log.StageName = &stageName
+	}
+
+	if err := insertStatusLog(ctx, tx, log); err != nil {
+		return err
+	}
+
+	if afterUpdate != nil {
+		if err := afterUpdate(tx); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// insertStatusLog menyimpan satu baris achievement_status_logs di dalam transaksi yang sedang berjalan
+func insertStatusLog(ctx context.Context, tx pgx.Tx, log model.AchievementStatusLog) error {
+	query := `INSERT INTO achievement_status_logs (
+		id, achievement_id, status, previous_status, changed_by, note, rejection_note, on_behalf_of, delegation_id,
+		stage_order, stage_name, created_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err := tx.Exec(ctx, query,
+		log.ID, log.AchievementID, log.Status, log.PreviousStatus, log.ChangedBy, log.Note, log.RejectionNote,
+		log.OnBehalfOf, log.DelegationID, log.StageOrder, log.StageName, log.CreatedAt,
+	)
 	return err
 }
 
@@ -349,12 +528,15 @@ func (r *achievementRepo) UpdateAchievementStatusToRejected(ctx context.Context,
 func (r *achievementRepo) GetAchievementStatusHistory(ctx context.Context, achievementID uuid.UUID) ([]model.AchievementStatusLog, error) {
 	query := `
 		SELECT 
-			asl.id, asl.achievement_id, asl.status, asl.changed_by, 
-			u.full_name as changed_by_name, asl.rejection_note, asl.created_at
+			asl.id, asl.achievement_id, asl.status, asl.previous_status, asl.changed_by, 
+			u.full_name as changed_by_name, asl.note, asl.rejection_note,
+			asl.on_behalf_of, ob.full_name as on_behalf_of_name, asl.delegation_id,
+			asl.stage_order, asl.stage_name, asl.created_at
 		FROM achievement_status_logs asl
 		LEFT JOIN users u ON asl.changed_by = u.id
+		LEFT JOIN users ob ON asl.on_behalf_of = ob.id
 		WHERE asl.achievement_id = $1
-		ORDER BY asl.created_at ASC
+		ORDER BY asl.created_at ASC, asl.id ASC
 	`
 
 	rows, err := r.pgDB.Query(ctx, query, achievementID)
@@ -367,8 +549,10 @@ func (r *achievementRepo) GetAchievementStatusHistory(ctx context.Context, achie
 	for rows.Next() {
 		var log model.AchievementStatusLog
 		err := rows.Scan(
-			&log.ID, &log.AchievementID, &log.Status, &log.ChangedBy,
-			&log.ChangedByName, &log.RejectionNote, &log.CreatedAt,
+			&log.ID, &log.AchievementID, &log.Status, &log.PreviousStatus, &log.ChangedBy,
+			&log.ChangedByName, &log.Note, &log.RejectionNote,
+			&log.OnBehalfOf, &log.OnBehalfOfName, &log.DelegationID,
+			&log.StageOrder, &log.StageName, &log.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -386,87 +570,229 @@ func (r *achievementRepo) GetAchievementStatusHistory(ctx context.Context, achie
 // LogAchievementStatusChange mencatat perubahan status achievement ke log table
 func (r *achievementRepo) LogAchievementStatusChange(ctx context.Context, log model.AchievementStatusLog) error {
 	query := `INSERT INTO achievement_status_logs (
-		id, achievement_id, status, changed_by, rejection_note, created_at
-	) VALUES ($1, $2, $3, $4, $5, $6)`
+		id, achievement_id, status, previous_status, changed_by, note, rejection_note, on_behalf_of, delegation_id,
+		stage_order, stage_name, created_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
 
 	_, err := r.pgDB.Exec(ctx, query,
-		log.ID, log.AchievementID, log.Status, log.ChangedBy, log.RejectionNote, log.CreatedAt,
+		log.ID, log.AchievementID, log.Status, log.PreviousStatus, log.ChangedBy, log.Note, log.RejectionNote,
+		log.OnBehalfOf, log.DelegationID, log.StageOrder, log.StageName, log.CreatedAt,
 	)
 	return err
 }
 
 // GetAllAchievementsForAdmin mengambil semua achievements untuk admin dengan filters
 func (r *achievementRepo) GetAllAchievementsForAdmin(ctx context.Context, filters model.AdminAchievementFilters, page, limit int) ([]model.AchievementWithStudent, int, error) {
-	// Build query dengan filters
-	baseQuery := `
-		SELECT 
-			ar.id, ar.student_id, ar.mongo_achievement_id, ar.status,
-			ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note,
-			ar.created_at, ar.updated_at,
-			s.student_id as student_nim, u.full_name as student_name, s.program_study
-		FROM achievement_references ar
-		JOIN students s ON ar.student_id = s.id
-		JOIN users u ON s.user_id = u.id
-		WHERE 1=1
-	`
-
-	countQuery := `
-		SELECT COUNT(*)
-		FROM achievement_references ar
-		JOIN students s ON ar.student_id = s.id
-		WHERE 1=1
-	`
+	where, args := adminAchievementWhere(filters)
+	return r.queryAchievementList(ctx, where, args, filters.AchievementListFilters, page, limit)
+}
 
+// adminAchievementWhere menyusun klausa WHERE listing admin dari semua filter
+func adminAchievementWhere(filters model.AdminAchievementFilters) (string, []interface{}) {
+	where := ` WHERE 1=1`
 	args := []interface{}{}
-	argCount := 1
-
-	// Apply filters
-	if filters.Status != "" {
-		baseQuery += fmt.Sprintf(" AND ar.status = $%d", argCount)
-		countQuery += fmt.Sprintf(" AND ar.status = $%d", argCount)
-		args = append(args, filters.Status)
-		argCount++
-	}
+	where, args = appendAchievementListFilters(where, args, filters.AchievementListFilters)
 
 	if filters.StudentID != nil {
-		baseQuery += fmt.Sprintf(" AND ar.student_id = $%d", argCount)
-		countQuery += fmt.Sprintf(" AND ar.student_id = $%d", argCount)
 		args = append(args, *filters.StudentID)
-		argCount++
+		where += fmt.Sprintf(" AND ar.student_id = $%d", len(args))
 	}
 
 	if filters.DateFrom != nil {
-		baseQuery += fmt.Sprintf(" AND ar.created_at >= $%d", argCount)
-		countQuery += fmt.Sprintf(" AND ar.created_at >= $%d", argCount)
 		args = append(args, *filters.DateFrom)
-		argCount++
+		where += fmt.Sprintf(" AND ar.created_at >= $%d", len(args))
 	}
 
 	if filters.DateTo != nil {
-		baseQuery += fmt.Sprintf(" AND ar.created_at <= $%d", argCount)
-		countQuery += fmt.Sprintf(" AND ar.created_at <= $%d", argCount)
 		args = append(args, *filters.DateTo)
-		argCount++
+		where += fmt.Sprintf(" AND ar.created_at <= $%d", len(args))
+	}
+
+	if filters.ProgramStudy != "" {
+		args = append(args, filters.ProgramStudy)
+		where += fmt.Sprintf(" AND s.program_study = $%d", len(args))
+	}
+
+	if filters.AcademicYear != "" {
+		args = append(args, filters.AcademicYear)
+		where += fmt.Sprintf(" AND s.academic_year = $%d", len(args))
+	}
+
+	if filters.AdvisorID != nil {
+		args = append(args, *filters.AdvisorID)
+		where += fmt.Sprintf(" AND s.advisor_id = $%d", len(args))
+	}
+
+	return where, args
+}
+
+// withoutFacetFilter mengosongkan filter milik satu dimensi facet
+func withoutFacetFilter(filters model.AdminAchievementFilters, facet string) model.AdminAchievementFilters {
+	switch facet {
+	case model.FacetStatus:
+		filters.Status = ""
+	case model.FacetAchievementType:
+		filters.AchievementType = ""
+	case model.FacetCompetitionLevel:
+		filters.CompetitionLevel = ""
+	case model.FacetMedalType:
+		filters.MedalType = ""
+	case model.FacetProgramStudy:
+		filters.ProgramStudy = ""
+	case model.FacetAcademicYear:
+		filters.AcademicYear = ""
+	case model.FacetAdvisor:
+		filters.AdvisorID = nil
+	case model.FacetTag:
+		filters.Tag = ""
+	case model.FacetPoints:
+		filters.MinPoints, filters.MaxPoints = nil, nil
+	}
+	return filters
+}
+
+// pointsBucketExpr mengelompokkan poin ke rentang facet
+const pointsBucketExpr = `CASE
+		WHEN COALESCE(sc.points, 0) = 0 THEN '0'
+		WHEN sc.points < 25 THEN '1-24'
+		WHEN sc.points < 50 THEN '25-49'
+		WHEN sc.points < 100 THEN '50-99'
+		ELSE '100+' END`
+
+// GetAdminAchievementFacets menghitung facet listing admin; semua dimensi dikirim dalam satu batch query
+func (r *achievementRepo) GetAdminAchievementFacets(ctx context.Context, filters model.AdminAchievementFilters) (*model.AchievementFacets, error) {
+	facets := &model.AchievementFacets{}
+	dimensions := []struct {
+		name   string
+		value  string
+		label  string
+		join   string
+		order  string
+		target *[]model.FacetCount
+	}{
+		{model.FacetStatus, "ar.status", "NULL", "", "3 DESC, 1", &facets.Status},
+		{model.FacetAchievementType, "rm.achievement_type", "NULL", "", "3 DESC, 1", &facets.AchievementType},
+		{model.FacetCompetitionLevel, "rm.competition_level", "NULL", "", "3 DESC, 1", &facets.CompetitionLevel},
+		{model.FacetMedalType, "rm.medal_type", "NULL", "", "3 DESC, 1", &facets.MedalType},
+		{model.FacetProgramStudy, "s.program_study", "NULL", "", "3 DESC, 1", &facets.ProgramStudy},
+		{model.FacetAcademicYear, "s.academic_year", "NULL", "", "1 DESC", &facets.AcademicYear},
+		{model.FacetAdvisor, "s.advisor_id::text", "lu.full_name",
+			" LEFT JOIN lecturers l ON l.id = s.advisor_id LEFT JOIN users lu ON lu.id = l.user_id", "3 DESC, 2", &facets.Advisor},
+		{model.FacetTag, "t.tag", "NULL", " CROSS JOIN LATERAL unnest(rm.tags) AS t(tag)", "3 DESC, 1 LIMIT 50", &facets.Tag},
+		{model.FacetPoints, pointsBucketExpr, "NULL", "", "MIN(COALESCE(sc.points, 0))", &facets.Points},
+	}
+
+	batch := &pgx.Batch{}
+	for _, d := range dimensions {
+		where, args := adminAchievementWhere(withoutFacetFilter(filters, d.name))
+		query := fmt.Sprintf(`SELECT %s AS value, %s::text AS label, COUNT(*)%s%s%s AND %s IS NOT NULL GROUP BY 1, 2 ORDER BY %s`,
+			d.value, d.label, achievementListFrom, d.join, where, d.value, d.order)
+		batch.Queue(query, args...)
+	}
+
+	results := r.pgDB.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for _, d := range dimensions {
+		rows, err := results.Query()
+		if err != nil {
+			return nil, err
+		}
+
+		counts := []model.FacetCount{}
+		for rows.Next() {
+			var fc model.FacetCount
+			if err := rows.Scan(&fc.Value, &fc.Label, &fc.Count); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			counts = append(counts, fc)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		*d.target = counts
+	}
+
+	return facets, nil
+}
+
+// achievementListFrom menggabungkan reference, mahasiswa, read model dan skor untuk listing prestasi
+const achievementListFrom = `
+	FROM achievement_references ar
+	JOIN students s ON ar.student_id = s.id
+	JOIN users u ON s.user_id = u.id
+	LEFT JOIN achievement_read_model rm ON rm.achievement_id = ar.id
+	LEFT JOIN achievement_scores sc ON sc.achievement_id = ar.id`
+
+// achievementListSortColumns memetakan sort_by ke kolom; nilai lain memakai created_at
+var achievementListSortColumns = map[string]string{
+	"created_at":        "ar.created_at",
+	"updated_at":        "ar.updated_at",
+	"status":            "ar.status",
+	"title":             "rm.title",
+	"achievement_type":  "rm.achievement_type",
+	"competition_level": "rm.competition_level",
+	"event_date":        "rm.event_date",
+	"points":            "COALESCE(sc.points, 0)",
+	"student_name":      "u.full_name",
+}
+
+// appendAchievementListFilters menambahkan filter status dan field read model ke klausa WHERE
+func appendAchievementListFilters(where string, args []interface{}, filters model.AchievementListFilters) (string, []interface{}) {
+	if filters.Status != "" {
+		args = append(args, filters.Status)
+		where += fmt.Sprintf(" AND ar.status = $%d", len(args))
+	}
+
+	if filters.AchievementType != "" {
+		args = append(args, utils.NormalizeAchievementType(filters.AchievementType))
+		where += fmt.Sprintf(" AND rm.achievement_type = $%d", len(args))
+	}
+
+	// Nilai read model disimpan ternormalisasi (lowercase, tanpa spasi di tepi), input filter diperlakukan sama
+	if level := normalizeReadModelValue(&filters.CompetitionLevel); level != nil {
+		args = append(args, *level)
+		where += fmt.Sprintf(" AND rm.competition_level = $%d", len(args))
+	}
+
+	if medal := normalizeReadModelValue(&filters.MedalType); medal != nil {
+		args = append(args, *medal)
+		where += fmt.Sprintf(" AND rm.medal_type = $%d", len(args))
+	}
+
+	if tag := normalizeReadModelValue(&filters.Tag); tag != nil {
+		args = append(args, *tag)
+		where += fmt.Sprintf(" AND $%d = ANY(rm.tags)", len(args))
+	}
+
+	if filters.MinPoints != nil {
+		args = append(args, *filters.MinPoints)
+		where += fmt.Sprintf(" AND COALESCE(sc.points, 0) >= $%d", len(args))
+	}
+
+	if filters.MaxPoints != nil {
+		args = append(args, *filters.MaxPoints)
+		where += fmt.Sprintf(" AND COALESCE(sc.points, 0) <= $%d", len(args))
 	}
 
+	return where, args
+}
+
+// queryAchievementList menjalankan count dan query listing dari read model dengan sorting dan pagination
+func (r *achievementRepo) queryAchievementList(ctx context.Context, where string, args []interface{}, filters model.AchievementListFilters, page, limit int) ([]model.AchievementWithStudent, int, error) {
 	// Get total count
 	var total int
-	err := r.pgDB.QueryRow(ctx, countQuery, args...).Scan(&total)
+	err := r.pgDB.QueryRow(ctx, `SELECT COUNT(*)`+achievementListFrom+where, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Apply sorting
-	sortBy := "ar.created_at"
-	if filters.SortBy != "" {
-		switch filters.SortBy {
-		case "created_at":
-			sortBy = "ar.created_at"
-		case "updated_at":
-			sortBy = "ar.updated_at"
-		case "status":
-			sortBy = "ar.status"
-		}
+	// Apply sorting; id sebagai tie-breaker agar pagination stabil
+	sortBy, ok := achievementListSortColumns[filters.SortBy]
+	if !ok {
+		sortBy = "ar.created_at"
 	}
 
 	sortOrder := "DESC"
@@ -474,28 +800,35 @@ func (r *achievementRepo) GetAllAchievementsForAdmin(ctx context.Context, filter
 		sortOrder = "ASC"
 	}
 
-	baseQuery += fmt.Sprintf(" ORDER BY %s %s", sortBy, sortOrder)
-
-	// Add pagination
-	baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argCount, argCount+1)
+	query := `
+		SELECT 
+			ar.id, ar.student_id, ar.mongo_achievement_id, ar.status,
+			ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note,
+			ar.revision, ar.created_at, ar.updated_at,
+			s.student_id as student_nim, u.full_name as student_name, s.program_study,
+			rm.title, rm.achievement_type, rm.competition_level, rm.medal_type,
+			COALESCE(rm.tags, '{}'), COALESCE(sc.points, 0)
+	` + achievementListFrom + where +
+		fmt.Sprintf(" ORDER BY %s %s NULLS LAST, ar.id %s LIMIT $%d OFFSET $%d", sortBy, sortOrder, sortOrder, len(args)+1, len(args)+2)
 	offset := (page - 1) * limit
 	args = append(args, limit, offset)
 
-	// Execute query
-	rows, err := r.pgDB.Query(ctx, baseQuery, args...)
+	rows, err := r.pgDB.Query(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var achievements []model.AchievementWithStudent
+	achievements := []model.AchievementWithStudent{}
 	for rows.Next() {
 		var a model.AchievementWithStudent
 		err := rows.Scan(
 			&a.ID, &a.StudentID, &a.MongoAchievementID, &a.Status,
 			&a.SubmittedAt, &a.VerifiedAt, &a.VerifiedBy, &a.RejectionNote,
-			&a.CreatedAt, &a.UpdatedAt,
+			&a.Revision, &a.CreatedAt, &a.UpdatedAt,
 			&a.StudentNIM, &a.StudentName, &a.ProgramStudy,
+			&a.Title, &a.AchievementType, &a.CompetitionLevel, &a.MedalType,
+			&a.Tags, &a.Points,
 		)
 		if err != nil {
 			return nil, 0, err
@@ -540,61 +873,23 @@ func (r *achievementRepo) GetTotalAchievements(ctx context.Context, studentIDs [
 }
 
 // GetStatisticsByType menghitung statistik berdasarkan tipe achievement dari MongoDB
-func (r *achievementRepo) GetStatisticsByType(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters, mongoColl *mongo.Collection) ([]model.StatsByType, error) {
-	// Get achievement references
-	query := `SELECT mongo_achievement_id FROM achievement_references WHERE student_id = ANY($1)`
-	args := []interface{}{pq.Array(studentIDs)}
-	argCount := 2
-
-	if filters.Status != "" {
-		query += fmt.Sprintf(" AND status = $%d", argCount)
-		args = append(args, filters.Status)
-		argCount++
+func (r *achievementRepo) GetStatisticsByType(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters) ([]model.StatsByType, error) {
+	mongoIDs, err := r.getStatisticsMongoIDs(ctx, studentIDs, filters)
+	if err != nil {
+		return nil, err
 	}
 
-	if filters.DateFrom != nil {
-		query += fmt.Sprintf(" AND created_at >= $%d", argCount)
-		args = append(args, *filters.DateFrom)
-		argCount++
+	counts, err := r.countMongoAchievementsBy(ctx, mongoIDs, "$achievementType", bson.M{})
+	if err != nil {
+		return nil, err
 	}
 
-	if filters.DateTo != nil {
-		query += fmt.Sprintf(" AND created_at <= $%d", argCount)
-		args = append(args, *filters.DateTo)
-		argCount++
+	// Normalisasi agar data lama seperti "Competition" dan "competition" tidak terpisah
+	typeCount := make(map[string]int)
+	for typ, count := range counts {
+		typeCount[utils.NormalizeAchievementType(typ)] += count
 	}
 
-	rows, err := r.pgDB.Query(ctx, query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	// Collect mongo IDs
-	var mongoIDs []primitive.ObjectID
-	for rows.Next() {
-		var mongoIDStr string
-		if err := rows.Scan(&mongoIDStr); err != nil {
-			continue
-		}
-		mongoID, err := primitive.ObjectIDFromHex(mongoIDStr)
-		if err != nil {
-			continue
-		}
-		mongoIDs = append(mongoIDs, mongoID)
-	}
-
-	// Count by category from MongoDB
-	typeCount := make(map[string]int)
-	for _, mongoID := range mongoIDs {
-		var achievement mongodb.Achievement
-		err := mongoColl.FindOne(ctx, bson.M{"_id": mongoID}).Decode(&achievement)
-		if err == nil {
-			typeCount[achievement.AchievementType]++
-		}
-	}
-
-	// Convert to slice
 	var stats []model.StatsByType
 	for typ, count := range typeCount {
 		stats = append(stats, model.StatsByType{
@@ -656,7 +951,7 @@ func (r *achievementRepo) GetStatisticsByPeriod(ctx context.Context, studentIDs
 	return stats, nil
 }
 
-// GetTopStudents mendapatkan top students berdasarkan jumlah achievement
+// GetTopStudents mendapatkan top students berdasarkan total poin (achievement_scores), lalu jumlah achievement
 func (r *achievementRepo) GetTopStudents(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters, limit int) ([]model.TopStudent, error) {
 	query := `
 		SELECT 
@@ -664,10 +959,12 @@ func (r *achievementRepo) GetTopStudents(ctx context.Context, studentIDs []uuid.
 			s.student_id as student_nim,
 			u.full_name as student_name,
 			s.program_study,
-			COUNT(*) as count
+			COUNT(*) as count,
+			COALESCE(SUM(sc.points), 0) as total_points
 		FROM achievement_references ar
 		JOIN students s ON ar.student_id = s.id
 		JOIN users u ON s.user_id = u.id
+		LEFT JOIN achievement_scores sc ON sc.achievement_id = ar.id
 		WHERE ar.student_id = ANY($1)
 	`
 	args := []interface{}{pq.Array(studentIDs)}
@@ -691,7 +988,7 @@ func (r *achievementRepo) GetTopStudents(ctx context.Context, studentIDs []uuid.
 		argCount++
 	}
 
-	query += fmt.Sprintf(" GROUP BY ar.student_id, s.student_id, u.full_name, s.program_study ORDER BY count DESC LIMIT $%d", argCount)
+	query += fmt.Sprintf(" GROUP BY ar.student_id, s.student_id, u.full_name, s.program_study ORDER BY total_points DESC, count DESC LIMIT $%d", argCount)
 	args = append(args, limit)
 
 	rows, err := r.pgDB.Query(ctx, query, args...)
@@ -703,7 +1000,7 @@ func (r *achievementRepo) GetTopStudents(ctx context.Context, studentIDs []uuid.
 	var topStudents []model.TopStudent
 	for rows.Next() {
 		var student model.TopStudent
-		if err := rows.Scan(&student.StudentID, &student.StudentNIM, &student.StudentName, &student.ProgramStudy, &student.Count); err != nil {
+		if err := rows.Scan(&student.StudentID, &student.StudentNIM, &student.StudentName, &student.ProgramStudy, &student.Count, &student.TotalPoints); err != nil {
 			return nil, err
 		}
 		topStudents = append(topStudents, student)
@@ -713,8 +1010,32 @@ func (r *achievementRepo) GetTopStudents(ctx context.Context, studentIDs []uuid.
 }
 
 // GetLevelDistribution mendapatkan distribusi berdasarkan level dari MongoDB
-func (r *achievementRepo) GetLevelDistribution(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters, mongoColl *mongo.Collection) ([]model.LevelDistribution, error) {
-	// Get achievement references
+func (r *achievementRepo) GetLevelDistribution(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters) ([]model.LevelDistribution, error) {
+	mongoIDs, err := r.getStatisticsMongoIDs(ctx, studentIDs, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hanya prestasi yang punya details.competitionLevel yang dihitung
+	levelCount, err := r.countMongoAchievementsBy(ctx, mongoIDs, "$details.competitionLevel", bson.M{"details.competitionLevel": bson.M{"$type": "string"}})
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []model.LevelDistribution
+	for level, count := range levelCount {
+		stats = append(stats, model.LevelDistribution{
+			Level: level,
+			Count: count,
+		})
+	}
+
+	return stats, nil
+}
+
+// getStatisticsMongoIDs mengambil ID dokumen MongoDB dari reference yang lolos filter statistik;
+// filter dijalankan di PostgreSQL sehingga MongoDB hanya menerima ID yang relevan
+func (r *achievementRepo) getStatisticsMongoIDs(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters) ([]primitive.ObjectID, error) {
 	query := `SELECT mongo_achievement_id FROM achievement_references WHERE student_id = ANY($1)`
 	args := []interface{}{pq.Array(studentIDs)}
 	argCount := 2
@@ -743,12 +1064,11 @@ func (r *achievementRepo) GetLevelDistribution(ctx context.Context, studentIDs [
 	}
 	defer rows.Close()
 
-	// Collect mongo IDs
 	var mongoIDs []primitive.ObjectID
 	for rows.Next() {
 		var mongoIDStr string
 		if err := rows.Scan(&mongoIDStr); err != nil {
-			continue
+			return nil, err
 		}
 		mongoID, err := primitive.ObjectIDFromHex(mongoIDStr)
 		if err != nil {
@@ -757,29 +1077,54 @@ func (r *achievementRepo) GetLevelDistribution(ctx context.Context, studentIDs [
 		mongoIDs = append(mongoIDs, mongoID)
 	}
 
-	// Count by level from MongoDB
-	levelCount := make(map[string]int)
-	for _, mongoID := range mongoIDs {
-		var achievement mongodb.Achievement
-		err := mongoColl.FindOne(ctx, bson.M{"_id": mongoID}).Decode(&achievement)
-		if err == nil {
-			// Get level from Details.CompetitionLevel
-			if achievement.Details.CompetitionLevel != nil {
-				levelCount[*achievement.Details.CompetitionLevel]++
-			}
+	return mongoIDs, rows.Err()
+}
+
+// statisticsMongoBatchSize membatasi jumlah ID dalam satu $in agar pipeline tetap di bawah batas ukuran dokumen BSON
+const statisticsMongoBatchSize = 1000
+
+// countMongoAchievementsBy menghitung dokumen per nilai groupField dengan pipeline $match/$group,
+// satu aggregate per batch ID, lalu menggabungkan hasilnya
+func (r *achievementRepo) countMongoAchievementsBy(ctx context.Context, mongoIDs []primitive.ObjectID, groupField string, match bson.M) (map[string]int, error) {
+	counts := make(map[string]int)
+	for start := 0; start < len(mongoIDs); start += statisticsMongoBatchSize {
+		end := start + statisticsMongoBatchSize
+		if end > len(mongoIDs) {
+			end = len(mongoIDs)
 		}
-	}
 
-	// Convert to slice
-	var stats []model.LevelDistribution
-	for level, count := range levelCount {
-		stats = append(stats, model.LevelDistribution{
-			Level: level,
-			Count: count,
-		})
+		batchMatch := bson.M{"_id": bson.M{"$in": mongoIDs[start:end]}}
+		for field, cond := range match {
+			batchMatch[field] = cond
+		}
+		pipeline := mongo.Pipeline{
+			{{Key: "$match", Value: batchMatch}},
+			{{Key: "$group", Value: bson.M{"_id": groupField, "count": bson.M{"$sum": 1}}}},
+		}
+
+		cursor, err := r.mongoColl.Aggregate(ctx, pipeline)
+		if err != nil {
+			return nil, err
+		}
+
+		var groups []struct {
+			Key   *string `bson:"_id"`
+			Count int     `bson:"count"`
+		}
+		if err := cursor.All(ctx, &groups); err != nil {
+			return nil, err
+		}
+
+		for _, group := range groups {
+			key := ""
+			if group.Key != nil {
+				key = *group.Key
+			}
+			counts[key] += group.Count
+		}
 	}
 
-	return stats, nil
+	return counts, nil
 }
 
 // GetStatusDistribution mendapatkan distribusi berdasarkan status
@@ -826,20 +1171,163 @@ func (r *achievementRepo) GetStatusDistribution(ctx context.Context, studentIDs
 	return stats, nil
 }
 
-// UpdateAchievementInMongo updates achievement data in MongoDB
-func (r *achievementRepo) UpdateAchievementInMongo(ctx context.Context, achievement mongodb.Achievement) error {
-	filter := bson.M{"_id": achievement.ID}
-	update := bson.M{"$set": achievement}
+// SearchAchievementDocuments mencari prestasi dengan text index MongoDB, urut skor relevansi.
+// studentIDs nil berarti tanpa batasan mahasiswa (admin); dokumen di trash tidak ikut dicari.
+// Lingkup diambil dari reference PostgreSQL (bukan studentId dokumen) dan dipasang di query MongoDB,
+// sehingga total untuk pagination sama dengan hasil yang boleh ditampilkan.
+func (r *achievementRepo) SearchAchievementDocuments(ctx context.Context, query string, studentIDs []uuid.UUID, skip, limit int) ([]model.AchievementSearchMatch, int64, error) {
+	mongoIDs, err := r.getSearchableMongoIDs(ctx, studentIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(mongoIDs) == 0 {
+		return []model.AchievementSearchMatch{}, 0, nil
+	}
 
-	_, err := r.mongoColl.UpdateOne(ctx, filter, update)
-	return err
+	filter := bson.M{
+		"_id":        bson.M{"$in": mongoIDs},
+		"$text":      bson.M{"$search": query},
+		"deleted_at": bson.M{"$exists": false},
+	}
+
+	total, err := r.mongoColl.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(skip)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.mongoColl.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	matches := []model.AchievementSearchMatch{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			mongodb.Achievement `bson:",inline"`
+			Score               float64 `bson:"score"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, 0, err
+		}
+		matches = append(matches, model.AchievementSearchMatch{Achievement: doc.Achievement, Score: doc.Score})
+	}
+
+	return matches, total, cursor.Err()
 }
 
-// UpdateAchievementTimestamp updates the updated_at timestamp in PostgreSQL
-func (r *achievementRepo) UpdateAchievementTimestamp(ctx context.Context, achievementID uuid.UUID) error {
-	query := `UPDATE achievement_references SET updated_at = $1 WHERE id = $2`
-	_, err := r.pgDB.Exec(ctx, query, time.Now(), achievementID)
-	return err
+// getSearchableMongoIDs mengambil ID dokumen MongoDB dari reference yang tidak di trash,
+// dibatasi ke mahasiswa tertentu jika studentIDs tidak nil
+func (r *achievementRepo) getSearchableMongoIDs(ctx context.Context, studentIDs []uuid.UUID) ([]primitive.ObjectID, error) {
+	query := `SELECT mongo_achievement_id FROM achievement_references WHERE status != 'deleted'`
+	args := []interface{}{}
+	if studentIDs != nil {
+		query += ` AND student_id = ANY($1)`
+		args = append(args, pq.Array(studentIDs))
+	}
+
+	rows, err := r.pgDB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mongoIDs := []primitive.ObjectID{}
+	for rows.Next() {
+		var mongoIDStr string
+		if err := rows.Scan(&mongoIDStr); err != nil {
+			return nil, err
+		}
+		mongoID, err := primitive.ObjectIDFromHex(mongoIDStr)
+		if err != nil {
+			continue
+		}
+		mongoIDs = append(mongoIDs, mongoID)
+	}
+
+	return mongoIDs, rows.Err()
+}
+
+// GetAchievementsByMongoIDs mengambil reference beserta info mahasiswa untuk daftar ID dokumen MongoDB
+func (r *achievementRepo) GetAchievementsByMongoIDs(ctx context.Context, mongoIDs []string) (map[string]model.AchievementWithStudent, error) {
+	query := `
+		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status,
+		       s.student_id as student_nim, u.full_name as student_name, s.program_study
+		FROM achievement_references ar
+		JOIN students s ON ar.student_id = s.id
+		JOIN users u ON s.user_id = u.id
+		WHERE ar.mongo_achievement_id = ANY($1)
+	`
+
+	rows, err := r.pgDB.Query(ctx, query, mongoIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	achievements := map[string]model.AchievementWithStudent{}
+	for rows.Next() {
+		var a model.AchievementWithStudent
+		if err := rows.Scan(&a.ID, &a.StudentID, &a.MongoAchievementID, &a.Status, &a.StudentNIM, &a.StudentName, &a.ProgramStudy); err != nil {
+			return nil, err
+		}
+		achievements[a.MongoAchievementID] = a
+	}
+
+	return achievements, rows.Err()
+}
+
+// UpdateAchievementContent menyimpan isi baru prestasi sebagai versi berikutnya beserta event outbox untuk
+// dokumen MongoDB-nya; prestasi yang ditolak (revise) sekaligus berpindah ke status 'revised'
+func (r *achievementRepo) UpdateAchievementContent(ctx context.Context, achievementID uuid.UUID, snapshot mongodb.Achievement, changedBy uuid.UUID, revise bool) error {
+	return r.saveAchievementChange(ctx, achievementID, snapshot, changedBy, revise, func(tx pgx.Tx, at time.Time) error {
+		return insertOutboxUpsert(ctx, tx, achievementID, snapshot, at)
+	})
+}
+
+// saveAchievementChange menyimpan snapshot sebagai versi berikutnya dan event outbox dari writeEvent dalam satu transaksi;
+// jika revise, status sekaligus berpindah ke 'revised' beserta log-nya
+func (r *achievementRepo) saveAchievementChange(ctx context.Context, achievementID uuid.UUID, snapshot mongodb.Achievement, changedBy uuid.UUID, revise bool, writeEvent func(tx pgx.Tx, at time.Time) error) error {
+	now := time.Now()
+	saveContent := func(tx pgx.Tx) error {
+		if _, err := insertAchievementVersion(ctx, tx, achievementID, snapshot, changedBy, now); err != nil {
+			return err
+		}
+		return writeEvent(tx, now)
+	}
+
+	if revise {
+		query := `UPDATE achievement_references SET status = 'revised', updated_at = $1 WHERE id = $2`
+		return r.updateStatusWithLogTx(ctx, achievementID, "revised", changedBy, nil, now, nil, saveContent, query, now, achievementID)
+	}
+
+	tx, err := r.pgDB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// UPDATE sekaligus mengunci baris reference agar nomor versi tidak bentrok
+	tag, err := tx.Exec(ctx, `UPDATE achievement_references SET updated_at = $1 WHERE id = $2`, now, achievementID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	if err := saveContent(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 // GetUserByID gets user data by ID
@@ -858,27 +1346,516 @@ func (r *achievementRepo) GetAchievementsByID(ctx context.Context, userID uuid.U
 	return &user, nil
 }
 
-// AddAttachmentToAchievement adds an attachment to an achievement in MongoDB
-func (r *achievementRepo) AddAttachmentToAchievement(ctx context.Context, mongoAchievementID, fileName, fileURL, fileType string) error {
-	objectID, err := primitive.ObjectIDFromHex(mongoAchievementID)
+// AddAttachmentToAchievement menyimpan snapshot yang sudah berisi attachment baru sebagai versi berikutnya beserta
+// event outbox penambahan attachment ke dokumen MongoDB; prestasi yang ditolak (revise) berpindah ke 'revised'
+func (r *achievementRepo) AddAttachmentToAchievement(ctx context.Context, achievementID uuid.UUID, attachment mongodb.Attachment, snapshot mongodb.Achievement, changedBy uuid.UUID, revise bool) error {
+	payload, err := bson.Marshal(attachment)
 	if err != nil {
 		return err
 	}
+	return r.saveAchievementChange(ctx, achievementID, snapshot, changedBy, revise, func(tx pgx.Tx, at time.Time) error {
+		return insertOutboxEvent(ctx, tx, achievementID, model.OutboxOpAddAttachment, payload, at)
+	})
+}
 
-	attachment := mongodb.Attachment{
-		FileName:   fileName,
-		FileUrl:    fileURL,
-		FileType:   fileType,
-		UploadedAt: time.Now(),
+// RemoveAttachmentFromAchievement menyimpan snapshot tanpa attachment tersebut sebagai versi berikutnya beserta
+// event outbox penghapusan attachment dari dokumen MongoDB; prestasi yang ditolak (revise) berpindah ke 'revised'.
+// File attachment dihapus relay outbox setelah event diterapkan, jika tidak dipakai dokumen lain.
+func (r *achievementRepo) RemoveAttachmentFromAchievement(ctx context.Context, achievementID uuid.UUID, attachment mongodb.Attachment, snapshot mongodb.Achievement, changedBy uuid.UUID, revise bool) error {
+	payload, err := bson.Marshal(removeAttachmentPayload{ID: attachment.ID, StorageKey: attachment.StorageKey})
+	if err != nil {
+		return err
 	}
+	return r.saveAchievementChange(ctx, achievementID, snapshot, changedBy, revise, func(tx pgx.Tx, at time.Time) error {
+		return insertOutboxEvent(ctx, tx, achievementID, model.OutboxOpRemoveAttachment, payload, at)
+	})
+}
 
-	filter := bson.M{"_id": objectID}
-	update := bson.M{
-		"$push": bson.M{"attachments": attachment},
-		"$set":  bson.M{"updatedAt": time.Now()},
+// CountAttachmentsByStorageKey menghitung dokumen yang masih memakai file dengan storage key tertentu
+func (r *achievementRepo) CountAttachmentsByStorageKey(ctx context.Context, storageKey string) (int64, error) {
+	return r.mongoColl.CountDocuments(ctx, bson.M{"attachments.storageKey": storageKey})
+}
+
+// CountAchievementsByType menghitung dokumen prestasi (termasuk yang soft-deleted) dengan tipe tertentu
+func (r *achievementRepo) CountAchievementsByType(ctx context.Context, achievementType string) (int64, error) {
+	return r.mongoColl.CountDocuments(ctx, bson.M{"achievementType": achievementType})
+}
+
+// GetAchievementReferencesByStatus mengambil semua achievement reference dengan status tertentu
+func (r *achievementRepo) GetAchievementReferencesByStatus(ctx context.Context, status string) ([]model.AchievementReference, error) {
+	query := `SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, revision,
+              review_due_at, escalated_at, escalated_to, created_at, updated_at
+              FROM achievement_references WHERE status = $1 ORDER BY created_at`
+
+	rows, err := r.pgDB.Query(ctx, query, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []model.AchievementReference
+	for rows.Next() {
+		var ref model.AchievementReference
+		if err := rows.Scan(
+			&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status, &ref.SubmittedAt,
+			&ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote, &ref.Revision,
+			&ref.ReviewDueAt, &ref.EscalatedAt, &ref.EscalatedTo, &ref.CreatedAt, &ref.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
+
+// SaveAchievementVersion menyimpan snapshot isi prestasi sebagai versi berikutnya dan mengembalikan nomor versinya
+func (r *achievementRepo) SaveAchievementVersion(ctx context.Context, achievementID uuid.UUID, snapshot mongodb.Achievement, changedBy uuid.UUID) (int, error) {
+	tx, err := r.pgDB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock baris reference agar nomor versi tidak bentrok saat update bersamaan
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM achievement_references WHERE id = $1 FOR UPDATE`, achievementID); err != nil {
+		return 0, err
+	}
+
+	version, err := insertAchievementVersion(ctx, tx, achievementID, snapshot, changedBy, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	return version, tx.Commit(ctx)
+}
+
+// insertAchievementVersion menyimpan snapshot sebagai versi berikutnya di dalam transaksi yang sedang berjalan;
+// pemanggil harus sudah mengunci baris reference
+func insertAchievementVersion(ctx context.Context, tx pgx.Tx, achievementID uuid.UUID, snapshot mongodb.Achievement, changedBy uuid.UUID, at time.Time) (int, error) {
+	snapshotJSON, keysJSON, err := encodeVersionSnapshot(snapshot)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int
+	err = tx.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) + 1 FROM achievement_versions WHERE achievement_id = $1`, achievementID).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `INSERT INTO achievement_versions (id, achievement_id, version, snapshot, attachment_keys, changed_by, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	if _, err := tx.Exec(ctx, query, uuid.New(), achievementID, version, snapshotJSON, keysJSON, changedBy, at); err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// GetLatestAchievementSnapshot mengambil isi prestasi dari versi terakhir; nil jika belum ada versi
+func (r *achievementRepo) GetLatestAchievementSnapshot(ctx context.Context, achievementID uuid.UUID) (*mongodb.Achievement, error) {
+	return latestAchievementSnapshot(ctx, r.pgDB, achievementID)
+}
+
+// latestAchievementSnapshot membaca snapshot versi terakhir beserta storage key attachment-nya
+func latestAchievementSnapshot(ctx context.Context, pgDB *pgxpool.Pool, achievementID uuid.UUID) (*mongodb.Achievement, error) {
+	var snapshot, keys []byte
+	err := pgDB.QueryRow(ctx, `SELECT snapshot, attachment_keys FROM achievement_versions WHERE achievement_id = $1 ORDER BY version DESC LIMIT 1`, achievementID).
+		Scan(&snapshot, &keys)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	_, err = r.mongoColl.UpdateOne(ctx, filter, update)
+	return decodeVersionSnapshot(snapshot, keys)
+}
+
+// encodeVersionSnapshot menyusun snapshot JSON beserta storage key attachment-nya. Storage key tidak ikut
+// di-marshal ke snapshot (json:"-"), sehingga disimpan terpisah agar dokumen dapat dibuat ulang dari versi.
+func encodeVersionSnapshot(snapshot mongodb.Achievement) ([]byte, []byte, error) {
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := map[string]string{}
+	for _, attachment := range snapshot.Attachments {
+		if attachment.ID != "" && attachment.StorageKey != "" {
+			keys[attachment.ID] = attachment.StorageKey
+		}
+	}
+	keysJSON, err := json.Marshal(keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return snapshotJSON, keysJSON, nil
+}
+
+// decodeVersionSnapshot membaca snapshot versi dan mengembalikan storage key attachment-nya;
+// attachment dari versi yang disimpan sebelum attachment_keys ada tetap tanpa storage key
+func decodeVersionSnapshot(snapshotJSON, keysJSON []byte) (*mongodb.Achievement, error) {
+	var achievement mongodb.Achievement
+	if err := json.Unmarshal(snapshotJSON, &achievement); err != nil {
+		return nil, err
+	}
+	keys := map[string]string{}
+	if len(keysJSON) > 0 {
+		if err := json.Unmarshal(keysJSON, &keys); err != nil {
+			return nil, err
+		}
+	}
+	for i := range achievement.Attachments {
+		achievement.Attachments[i].StorageKey = keys[achievement.Attachments[i].ID]
+	}
+	return &achievement, nil
+}
+
+// GetAchievementsWithoutVersions mengambil reference (status apa pun) yang belum punya versi sama sekali,
+// diurutkan per id setelah afterID agar reference tanpa dokumen tidak dipindai ulang
+func (r *achievementRepo) GetAchievementsWithoutVersions(ctx context.Context, afterID uuid.UUID, limit int) ([]model.AchievementReference, error) {
+	query := `SELECT id, student_id, mongo_achievement_id, status, created_at, updated_at
+              FROM achievement_references ar
+              WHERE ar.id > $1
+                AND NOT EXISTS (SELECT 1 FROM achievement_versions av WHERE av.achievement_id = ar.id)
+              ORDER BY ar.id
+              LIMIT $2`
+
+	rows, err := r.pgDB.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refs := []model.AchievementReference{}
+	for rows.Next() {
+		var ref model.AchievementReference
+		if err := rows.Scan(&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status, &ref.CreatedAt, &ref.UpdatedAt); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
+
+// SaveBaselineVersionsFromDocuments menyimpan isi dokumen MongoDB saat ini sebagai versi 1 untuk prestasi
+// yang dibuat sebelum versioning ada; mengembalikan jumlah versi yang disimpan. Reference tanpa dokumen
+// dilewati, dan prestasi yang sudah mendapat versi dari transaksi lain tidak ditimpa.
+func (r *achievementRepo) SaveBaselineVersionsFromDocuments(ctx context.Context, refs []model.AchievementReference) (int, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(refs))
+	for _, ref := range refs {
+		if objectID, err := primitive.ObjectIDFromHex(ref.MongoAchievementID); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+	if len(objectIDs) == 0 {
+		return 0, nil
+	}
+
+	cursor, err := r.mongoColl.Find(ctx, bson.M{"_id": bson.M{"$in": objectIDs}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	docs := map[string]mongodb.Achievement{}
+	for cursor.Next(ctx) {
+		var achievement mongodb.Achievement
+		if err := cursor.Decode(&achievement); err != nil {
+			return 0, err
+		}
+		docs[achievement.ID.Hex()] = achievement
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+
+	saved := 0
+	for _, ref := range refs {
+		achievement, found := docs[ref.MongoAchievementID]
+		if !found {
+			continue
+		}
+		inserted, err := r.saveBaselineVersion(ctx, ref, achievement)
+		if err != nil {
+			return saved, err
+		}
+		if inserted {
+			saved++
+		}
+	}
+
+	return saved, nil
+}
+
+// saveBaselineVersion menyimpan versi 1 tanpa changed_by; baris reference dikunci seperti penulisan versi lain
+func (r *achievementRepo) saveBaselineVersion(ctx context.Context, ref model.AchievementReference, snapshot mongodb.Achievement) (bool, error) {
+	snapshotJSON, keysJSON, err := encodeVersionSnapshot(snapshot)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := r.pgDB.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM achievement_references WHERE id = $1 FOR UPDATE`, ref.ID); err != nil {
+		return false, err
+	}
+
+	at := snapshot.UpdatedAt
+	if at.IsZero() {
+		at = ref.CreatedAt
+	}
+
+	query := `INSERT INTO achievement_versions (id, achievement_id, version, snapshot, attachment_keys, changed_by, created_at)
+              SELECT $1, $2, 1, $3, $4, NULL, $5
+              WHERE NOT EXISTS (SELECT 1 FROM achievement_versions WHERE achievement_id = $2)`
+	tag, err := tx.Exec(ctx, query, uuid.New(), ref.ID, snapshotJSON, keysJSON, at)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, tx.Commit(ctx)
+}
+
+// GetAchievementVersions mengambil daftar versi prestasi (tanpa snapshot), versi lama lebih dulu
+func (r *achievementRepo) GetAchievementVersions(ctx context.Context, achievementID uuid.UUID) ([]model.AchievementVersion, error) {
+	query := `
+		SELECT av.id, av.achievement_id, av.version, av.changed_by, u.full_name, av.created_at
+		FROM achievement_versions av
+		LEFT JOIN users u ON av.changed_by = u.id
+		WHERE av.achievement_id = $1
+		ORDER BY av.version ASC
+	`
+
+	rows, err := r.pgDB.Query(ctx, query, achievementID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []model.AchievementVersion{}
+	for rows.Next() {
+		var v model.AchievementVersion
+		if err := rows.Scan(&v.ID, &v.AchievementID, &v.Version, &v.ChangedBy, &v.ChangedByName, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+// GetAchievementVersion mengambil satu versi prestasi beserta snapshot-nya; nil jika tidak ada
+func (r *achievementRepo) GetAchievementVersion(ctx context.Context, achievementID uuid.UUID, version int) (*model.AchievementVersion, error) {
+	query := `
+		SELECT av.id, av.achievement_id, av.version, av.snapshot, av.attachment_keys, av.changed_by, u.full_name, av.created_at
+		FROM achievement_versions av
+		LEFT JOIN users u ON av.changed_by = u.id
+		WHERE av.achievement_id = $1 AND av.version = $2
+	`
+
+	var v model.AchievementVersion
+	var snapshot, keys []byte
+	err := r.pgDB.QueryRow(ctx, query, achievementID, version).Scan(
+		&v.ID, &v.AchievementID, &v.Version, &snapshot, &keys, &v.ChangedBy, &v.ChangedByName, &v.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	v.Snapshot, err = decodeVersionSnapshot(snapshot, keys)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ErrApprovalAlreadyDecided dikembalikan jika tahap persetujuan sudah diputuskan oleh request lain
+var ErrApprovalAlreadyDecided = errors.New("approval stage already decided")
+
+// insertApprovalStages membuat tahap persetujuan (pending) untuk revision prestasi saat ini
+func insertApprovalStages(ctx context.Context, tx pgx.Tx, achievementID uuid.UUID, stages []model.ApprovalStage, createdAt time.Time) error {
+	query := `INSERT INTO achievement_approvals (id, achievement_id, revision, stage_order, stage_name, approver_role, status, created_at)
+              SELECT $1, id, revision, $2, $3, $4, 'pending', $5 FROM achievement_references WHERE id = $6`
+
+	for i, stage := range stages {
+		if _, err := tx.Exec(ctx, query, uuid.New(), i+1, stage.Name, stage.ApproverRole, createdAt, achievementID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decideApproval menyimpan keputusan satu tahap yang masih pending dan mengembalikan urutan serta nama tahapnya
+func decideApproval(ctx context.Context, tx pgx.Tx, decision model.ApprovalDecision) (int, string, error) {
+	// decision_seconds dihitung sejak tahap ini menjadi tahap berjalan (tahap sebelumnya diputuskan)
+	query := `UPDATE achievement_approvals aa
+              SET status = $1, decided_by = $2, note = $3, decided_at = $4, on_behalf_of = $5, delegation_id = $6,
+                  decision_seconds = GREATEST(0, EXTRACT(EPOCH FROM ($4 - COALESCE((
+                      SELECT MAX(prev.decided_at) FROM achievement_approvals prev
+                      WHERE prev.achievement_id = aa.achievement_id AND prev.revision = aa.revision
+                        AND prev.stage_order < aa.stage_order
+                  ), aa.created_at)))::BIGINT)
+              WHERE aa.id = $7 AND aa.status = 'pending'
+              RETURNING aa.stage_order, aa.stage_name`
+
+	var stageOrder int
+	var stageName string
+	err := tx.QueryRow(ctx, query, decision.Status, decision.DecidedBy, decision.Note, decision.DecidedAt,
+		decision.OnBehalfOf, decision.DelegationID, decision.ApprovalID).Scan(&stageOrder, &stageName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", ErrApprovalAlreadyDecided
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	return stageOrder, stageName, nil
+}
+
+// ApproveAchievementStage menyetujui tahap yang bukan tahap terakhir; status prestasi tetap 'submitted'.
+// Keputusan tahap dicatat di log status (termasuk dosen wali yang diwakili delegasi) dalam transaksi yang sama.
+func (r *achievementRepo) ApproveAchievementStage(ctx context.Context, decision model.ApprovalDecision) error {
+	var achievementID uuid.UUID
+	err := r.pgDB.QueryRow(ctx, `SELECT achievement_id FROM achievement_approvals WHERE id = $1`, decision.ApprovalID).Scan(&achievementID)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE achievement_references SET updated_at = $1 WHERE id = $2`
+	return r.updateStatusWithLogTx(ctx, achievementID, "submitted", decision.DecidedBy, decision.Note, decision.DecidedAt, &decision, nil, query, decision.DecidedAt, achievementID)
+}
+
+const achievementApprovalColumns = `aa.id, aa.achievement_id, aa.revision, aa.stage_order, aa.stage_name, aa.approver_role,
+              aa.status, aa.decided_by, u.full_name, aa.note, aa.decided_at, aa.created_at,
+              aa.on_behalf_of, ob.full_name, aa.delegation_id, aa.decision_seconds`
+
+// achievementApprovalJoins melengkapi achievementApprovalColumns dengan nama pemutus dan dosen wali yang diwakili
+const achievementApprovalJoins = `LEFT JOIN users u ON aa.decided_by = u.id
+              LEFT JOIN users ob ON aa.on_behalf_of = ob.id`
+
+func scanAchievementApprovals(rows pgx.Rows) ([]model.AchievementApproval, error) {
+	defer rows.Close()
+
+	approvals := []model.AchievementApproval{}
+	for rows.Next() {
+		var a model.AchievementApproval
+		err := rows.Scan(&a.ID, &a.AchievementID, &a.Revision, &a.StageOrder, &a.StageName, &a.ApproverRole,
+			&a.Status, &a.DecidedBy, &a.DecidedByName, &a.Note, &a.DecidedAt, &a.CreatedAt,
+			&a.OnBehalfOf, &a.OnBehalfOfName, &a.DelegationID, &a.DecisionSeconds)
+		if err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, a)
+	}
+
+	return approvals, rows.Err()
+}
+
+// GetAchievementApprovals mengambil tahap persetujuan satu revision prestasi, urut sesuai tahap
+func (r *achievementRepo) GetAchievementApprovals(ctx context.Context, achievementID uuid.UUID, revision int) ([]model.AchievementApproval, error) {
+	query := `SELECT ` + achievementApprovalColumns + `
+              FROM achievement_approvals aa
+              ` + achievementApprovalJoins + `
+              WHERE aa.achievement_id = $1 AND aa.revision = $2
+              ORDER BY aa.stage_order ASC`
+
+	rows, err := r.pgDB.Query(ctx, query, achievementID, revision)
+	if err != nil {
+		return nil, err
+	}
+	return scanAchievementApprovals(rows)
+}
+
+// GetPendingApprovalsByRole mengambil tahap yang sedang menunggu approver_role tertentu untuk mahasiswa
+// yang program studinya sama dengan department approver, yaitu tahap pending pertama pada revision terbaru
+// prestasi yang berstatus 'submitted', termasuk tahap prestasi yang dieskalasi ke role tersebut
+func (r *achievementRepo) GetPendingApprovalsByRole(ctx context.Context, approverRole, department string) ([]model.AchievementApproval, error) {
+	query := `SELECT ` + achievementApprovalColumns + `
+              FROM achievement_approvals aa
+              JOIN achievement_references ar ON ar.id = aa.achievement_id AND ar.revision = aa.revision
+              JOIN students s ON s.id = ar.student_id
+              ` + achievementApprovalJoins + `
+              WHERE ar.status = 'submitted' AND aa.status = 'pending'
+                AND (LOWER(ar.escalated_to) = LOWER($1)
+                     OR (LOWER(aa.approver_role) = LOWER($1) AND TRIM($2) <> ''
+                         AND LOWER(TRIM(s.program_study)) = LOWER(TRIM($2))))
+                AND NOT EXISTS (
+                    SELECT 1 FROM achievement_approvals prev
+                    WHERE prev.achievement_id = aa.achievement_id AND prev.revision = aa.revision
+                      AND prev.stage_order < aa.stage_order AND prev.status = 'pending'
+                )
+              ORDER BY aa.created_at ASC`
+
+	rows, err := r.pgDB.Query(ctx, query, approverRole, department)
+	if err != nil {
+		return nil, err
+	}
+	return scanAchievementApprovals(rows)
+}
+
+// GetUserRoleName mengambil nama role user, dipakai untuk mencocokkan approver_role
+func (r *achievementRepo) GetUserRoleName(ctx context.Context, userID uuid.UUID) (string, error) {
+	var roleName string
+	err := r.pgDB.QueryRow(ctx, `SELECT r.name FROM users u JOIN roles r ON u.role_id = r.id WHERE u.id = $1`, userID).Scan(&roleName)
+	return roleName, err
+}
+
+// GetStudentAttachmentUsage menghitung total ukuran attachment milik student (prestasi yang belum dihapus)
+func (r *achievementRepo) GetStudentAttachmentUsage(ctx context.Context, studentID uuid.UUID) (int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"studentId": studentID, "deleted_at": bson.M{"$exists": false}}}},
+		{{Key: "$unwind", Value: "$attachments"}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$attachments.size"}}}},
+	}
+
+	cursor, err := r.mongoColl.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var result struct {
+		Total int64 `bson:"total"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return 0, err
+		}
+	}
+	return result.Total, cursor.Err()
+}
+
+// GetStudentStorageQuota mengambil override kuota storage student; nil jika memakai default
+func (r *achievementRepo) GetStudentStorageQuota(ctx context.Context, studentID uuid.UUID) (*int64, error) {
+	query := `SELECT quota_bytes FROM student_storage_quotas WHERE student_id = $1`
+
+	var quota int64
+	err := r.pgDB.QueryRow(ctx, query, studentID).Scan(&quota)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &quota, nil
+}
+
+// SetStudentStorageQuota menyimpan override kuota storage student
+func (r *achievementRepo) SetStudentStorageQuota(ctx context.Context, studentID uuid.UUID, quotaBytes int64) error {
+	query := `INSERT INTO student_storage_quotas (student_id, quota_bytes, updated_at)
+              VALUES ($1, $2, $3)
+              ON CONFLICT (student_id) DO UPDATE SET quota_bytes = EXCLUDED.quota_bytes, updated_at = EXCLUDED.updated_at`
+
+	_, err := r.pgDB.Exec(ctx, query, studentID, quotaBytes, time.Now())
 	return err
 }
 
@@ -928,7 +1905,7 @@ func (r *achievementRepo) GetStudentAchievements(ctx context.Context, studentID
 
 	// Get achievements with pagination
 	offset := (page - 1) * limit
-	query := `SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note, ar.created_at, ar.updated_at,
+	query := `SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note, ar.revision, ar.created_at, ar.updated_at,
                      s.student_id as student_nim, u.full_name as student_name, s.program_study
               FROM achievement_references ar
               JOIN students s ON ar.student_id = s.id
@@ -951,7 +1928,7 @@ func (r *achievementRepo) GetStudentAchievements(ctx context.Context, studentID
 		err := rows.Scan(
 			&achievement.ID, &achievement.StudentID, &achievement.MongoAchievementID,
 			&achievement.Status, &achievement.SubmittedAt, &achievement.VerifiedAt,
-			&achievement.VerifiedBy, &achievement.RejectionNote, &achievement.CreatedAt,
+			&achievement.VerifiedBy, &achievement.RejectionNote, &achievement.Revision, &achievement.CreatedAt,
 			&achievement.UpdatedAt, &achievement.StudentNIM, &achievement.StudentName,
 			&achievement.ProgramStudy,
 		)
@@ -989,3 +1966,206 @@ func (r *achievementRepo) GetAllStudentIDs(ctx context.Context) ([]uuid.UUID, er
 
 	return ids, nil
 }
+
+// achievementCommentColumns dipakai bersama oleh query komentar prestasi
+const achievementCommentColumns = `c.id, c.achievement_id, c.parent_id, c.author_id, u.full_name, COALESCE(r.name, ''),
+		c.body, c.field_path, c.attachment_id, c.is_resolved, c.resolved_by, c.resolved_at, c.created_at
+		FROM achievement_comments c
+		JOIN users u ON c.author_id = u.id
+		LEFT JOIN roles r ON u.role_id = r.id`
+
+func scanAchievementComment(row pgx.Row) (*model.AchievementComment, error) {
+	var c model.AchievementComment
+	err := row.Scan(&c.ID, &c.AchievementID, &c.ParentID, &c.AuthorID, &c.AuthorName, &c.AuthorRole,
+		&c.Body, &c.FieldPath, &c.AttachmentID, &c.IsResolved, &c.ResolvedBy, &c.ResolvedAt, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetAchievementComments mengambil semua komentar prestasi (utama dan balasan), terurut dari yang paling lama
+func (r *achievementRepo) GetAchievementComments(ctx context.Context, achievementID uuid.UUID) ([]model.AchievementComment, error) {
+	query := `SELECT ` + achievementCommentColumns + `
+		WHERE c.achievement_id = $1
+		ORDER BY c.created_at ASC, c.id ASC`
+
+	rows, err := r.pgDB.Query(ctx, query, achievementID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []model.AchievementComment{}
+	for rows.Next() {
+		c, err := scanAchievementComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, *c)
+	}
+
+	return comments, rows.Err()
+}
+
+// GetAchievementCommentByID mengambil satu komentar; nil jika tidak ada
+func (r *achievementRepo) GetAchievementCommentByID(ctx context.Context, commentID uuid.UUID) (*model.AchievementComment, error) {
+	query := `SELECT ` + achievementCommentColumns + `
+		WHERE c.id = $1`
+
+	c, err := scanAchievementComment(r.pgDB.QueryRow(ctx, query, commentID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return c, err
+}
+
+// CreateAchievementComment menyimpan komentar baru
+func (r *achievementRepo) CreateAchievementComment(ctx context.Context, comment model.AchievementComment) error {
+	query := `INSERT INTO achievement_comments (id, achievement_id, parent_id, author_id, body, field_path, attachment_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.pgDB.Exec(ctx, query, comment.ID, comment.AchievementID, comment.ParentID, comment.AuthorID,
+		comment.Body, comment.FieldPath, comment.AttachmentID, comment.CreatedAt)
+	return err
+}
+
+// SetAchievementCommentResolved menandai thread komentar selesai atau membukanya kembali
+func (r *achievementRepo) SetAchievementCommentResolved(ctx context.Context, commentID uuid.UUID, resolved bool, resolvedBy uuid.UUID, resolvedAt time.Time) error {
+	query := `UPDATE achievement_comments
+		SET is_resolved = $2,
+		    resolved_by = CASE WHEN $2 THEN $3::uuid END,
+		    resolved_at = CASE WHEN $2 THEN $4::timestamptz END
+		WHERE id = $1`
+
+	_, err := r.pgDB.Exec(ctx, query, commentID, resolved, resolvedBy, resolvedAt)
+	return err
+}
+
+// ErrAchievementNotDeleted dikembalikan jika prestasi sudah tidak berada di trash saat dipulihkan atau dihapus permanen
+var ErrAchievementNotDeleted = errors.New("achievement is not deleted")
+
+// GetDeletedAchievements mengambil prestasi di trash, yang paling lama dihapus lebih dulu
+func (r *achievementRepo) GetDeletedAchievements(ctx context.Context, page, limit int) ([]model.TrashedAchievement, int, error) {
+	var total int
+	if err := r.pgDB.QueryRow(ctx, `SELECT COUNT(*) FROM achievement_references WHERE status = 'deleted'`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT
+			ar.id, ar.student_id, ar.mongo_achievement_id, ar.status,
+			ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note,
+			ar.revision, ar.created_at, ar.updated_at,
+			s.student_id, u.full_name, s.program_study,
+			COALESCE(ar.deleted_at, ar.updated_at), del.changed_by, du.full_name
+		FROM achievement_references ar
+		JOIN students s ON ar.student_id = s.id
+		JOIN users u ON s.user_id = u.id
+		LEFT JOIN LATERAL (
+			SELECT asl.changed_by FROM achievement_status_logs asl
+			WHERE asl.achievement_id = ar.id AND asl.status = 'deleted'
+			ORDER BY asl.created_at DESC LIMIT 1
+		) del ON TRUE
+		LEFT JOIN users du ON del.changed_by = du.id
+		WHERE ar.status = 'deleted'
+		ORDER BY COALESCE(ar.deleted_at, ar.updated_at) ASC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.pgDB.Query(ctx, query, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	achievements := []model.TrashedAchievement{}
+	for rows.Next() {
+		var a model.TrashedAchievement
+		err := rows.Scan(
+			&a.ID, &a.StudentID, &a.MongoAchievementID, &a.Status,
+			&a.SubmittedAt, &a.VerifiedAt, &a.VerifiedBy, &a.RejectionNote,
+			&a.Revision, &a.CreatedAt, &a.UpdatedAt,
+			&a.StudentNIM, &a.StudentName, &a.ProgramStudy,
+			&a.DeletedAt, &a.DeletedBy, &a.DeletedByName,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		achievements = append(achievements, a)
+	}
+
+	return achievements, total, rows.Err()
+}
+
+// UpdateAchievementReferenceToRestored mengembalikan prestasi dari trash ke 'draft'
+// dan mencatat pemulihan dokumen MongoDB di outbox
+func (r *achievementRepo) UpdateAchievementReferenceToRestored(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID) error {
+	query := `UPDATE achievement_references
+              SET status = 'draft', deleted_at = NULL, updated_at = $1
+              WHERE id = $2 AND status = 'deleted'`
+
+	now := time.Now()
+	note := "Restored from trash"
+	restoreDocument := func(tx pgx.Tx) error {
+		if err := expectAchievementStatus(ctx, tx, achievementID, "draft", ErrAchievementNotDeleted); err != nil {
+			return err
+		}
+		return insertOutboxEvent(ctx, tx, achievementID, model.OutboxOpRestoreDocument, nil, now)
+	}
+	return r.updateStatusWithLogTx(ctx, achievementID, "draft", changedBy, &note, now, nil, restoreDocument, query, now, achievementID)
+}
+
+// GetAchievementsDeletedBefore mengambil prestasi di trash yang dihapus sebelum batas retensi;
+// prestasi yang masih punya event outbox tertunda dilewati sampai dokumennya sinkron
+func (r *achievementRepo) GetAchievementsDeletedBefore(ctx context.Context, before time.Time, limit int) ([]model.AchievementReference, error) {
+	query := `SELECT id, student_id, mongo_achievement_id, status, created_at, updated_at
+              FROM achievement_references ar
+              WHERE status = 'deleted' AND COALESCE(deleted_at, updated_at) < $1
+                AND NOT EXISTS (SELECT 1 FROM achievement_outbox o WHERE o.achievement_id = ar.id AND o.processed_at IS NULL)
+              ORDER BY COALESCE(deleted_at, updated_at) ASC
+              LIMIT $2`
+
+	rows, err := r.pgDB.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refs := []model.AchievementReference{}
+	for rows.Next() {
+		var ref model.AchievementReference
+		if err := rows.Scan(&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status, &ref.CreatedAt, &ref.UpdatedAt); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
+
+// PurgeAchievementMongo menghapus permanen dokumen prestasi yang sudah soft-deleted;
+// dokumen yang sudah tidak ada tidak dianggap error
+func (r *achievementRepo) PurgeAchievementMongo(ctx context.Context, mongoAchievementID string) error {
+	objectID, err := primitive.ObjectIDFromHex(mongoAchievementID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.mongoColl.DeleteOne(ctx, bson.M{"_id": objectID, "deleted_at": bson.M{"$exists": true}})
+	return err
+}
+
+// PurgeAchievementReference menghapus permanen reference di trash beserta data turunannya
+// (riwayat status, versi, skor, approval, komentar; ON DELETE CASCADE). Mengembalikan ErrAchievementNotDeleted
+// jika reference sudah dipulihkan sejak dipilih.
+func (r *achievementRepo) PurgeAchievementReference(ctx context.Context, achievementID uuid.UUID) error {
+	tag, err := r.pgDB.Exec(ctx, `DELETE FROM achievement_references WHERE id = $1 AND status = 'deleted'`, achievementID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAchievementNotDeleted
+	}
+	return nil
+}
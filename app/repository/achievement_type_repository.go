@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	model "UASBE/app/model/Postgresql"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AchievementTypeRepository mengelola katalog tipe prestasi di PostgreSQL
+type AchievementTypeRepository interface {
+	GetAllAchievementTypes(ctx context.Context, activeOnly bool) ([]model.AchievementType, error)
+	GetAchievementTypeByCode(ctx context.Context, code string) (*model.AchievementType, error)
+	CreateAchievementType(ctx context.Context, achievementType *model.AchievementType) error
+	UpdateAchievementType(ctx context.Context, achievementType *model.AchievementType) error
+	DeleteAchievementType(ctx context.Context, code string) error
+}
+
+type achievementTypeRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAchievementTypeRepository(db *pgxpool.Pool) AchievementTypeRepository {
+	return &achievementTypeRepo{db: db}
+}
+
+// GetAllAchievementTypes mengambil semua tipe prestasi, atau hanya yang aktif
+func (r *achievementTypeRepo) GetAllAchievementTypes(ctx context.Context, activeOnly bool) ([]model.AchievementType, error) {
+	query := `SELECT code, name_id, name_en, is_active, default_points, created_at, updated_at
+              FROM achievement_types`
+	if activeOnly {
+		query += ` WHERE is_active = TRUE`
+	}
+	query += ` ORDER BY code`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := []model.AchievementType{}
+	for rows.Next() {
+		var t model.AchievementType
+		if err := rows.Scan(&t.Code, &t.NameID, &t.NameEN, &t.IsActive, &t.DefaultPoints, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return types, nil
+}
+
+// GetAchievementTypeByCode mengambil satu tipe prestasi; nil jika code tidak terdaftar
+func (r *achievementTypeRepo) GetAchievementTypeByCode(ctx context.Context, code string) (*model.AchievementType, error) {
+	query := `SELECT code, name_id, name_en, is_active, default_points, created_at, updated_at
+              FROM achievement_types WHERE code = $1`
+
+	var t model.AchievementType
+	err := r.db.QueryRow(ctx, query, code).Scan(&t.Code, &t.NameID, &t.NameEN, &t.IsActive, &t.DefaultPoints, &t.CreatedAt, &t.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateAchievementType menambah tipe prestasi baru
+func (r *achievementTypeRepo) CreateAchievementType(ctx context.Context, t *model.AchievementType) error {
+	query := `INSERT INTO achievement_types (code, name_id, name_en, is_active, default_points, created_at, updated_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := r.db.Exec(ctx, query, t.Code, t.NameID, t.NameEN, t.IsActive, t.DefaultPoints, t.CreatedAt, t.UpdatedAt)
+	return err
+}
+
+// UpdateAchievementType mengubah nama, status aktif dan poin default tipe prestasi
+func (r *achievementTypeRepo) UpdateAchievementType(ctx context.Context, t *model.AchievementType) error {
+	query := `UPDATE achievement_types
+              SET name_id = $1, name_en = $2, is_active = $3, default_points = $4, updated_at = $5
+              WHERE code = $6`
+
+	_, err := r.db.Exec(ctx, query, t.NameID, t.NameEN, t.IsActive, t.DefaultPoints, t.UpdatedAt, t.Code)
+	return err
+}
+
+// DeleteAchievementType menghapus tipe prestasi dari katalog
+func (r *achievementTypeRepo) DeleteAchievementType(ctx context.Context, code string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM achievement_types WHERE code = $1`, code)
+	return err
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	model "UASBE/app/model/Postgresql"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ApprovalChainRepository mengelola konfigurasi approval chain prestasi di PostgreSQL
+type ApprovalChainRepository interface {
+	GetApprovalChains(ctx context.Context) ([]model.ApprovalChain, error)
+	GetApprovalChainByID(ctx context.Context, id uuid.UUID) (*model.ApprovalChain, error)
+	GetApprovalChainByScope(ctx context.Context, achievementType, competitionLevel *string) (*model.ApprovalChain, error)
+	FindApprovalChain(ctx context.Context, achievementType, competitionLevel string) (*model.ApprovalChain, error)
+	CreateApprovalChain(ctx context.Context, chain *model.ApprovalChain) error
+	UpdateApprovalChain(ctx context.Context, chain *model.ApprovalChain) error
+	DeleteApprovalChain(ctx context.Context, id uuid.UUID) error
+	RoleExists(ctx context.Context, name string) (bool, error)
+}
+
+type approvalChainRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewApprovalChainRepository(db *pgxpool.Pool) ApprovalChainRepository {
+	return &approvalChainRepo{db: db}
+}
+
+const approvalChainColumns = `id, name, achievement_type, competition_level, stages, is_active, created_at, updated_at`
+
+func scanApprovalChain(row pgx.Row) (*model.ApprovalChain, error) {
+	var chain model.ApprovalChain
+	var stages []byte
+	err := row.Scan(&chain.ID, &chain.Name, &chain.AchievementType, &chain.CompetitionLevel, &stages,
+		&chain.IsActive, &chain.CreatedAt, &chain.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(stages, &chain.Stages); err != nil {
+		return nil, err
+	}
+	return &chain, nil
+}
+
+// GetApprovalChains mengambil semua approval chain
+func (r *approvalChainRepo) GetApprovalChains(ctx context.Context) ([]model.ApprovalChain, error) {
+	query := `SELECT ` + approvalChainColumns + ` FROM approval_chains
+              ORDER BY achievement_type NULLS FIRST, competition_level NULLS FIRST`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chains := []model.ApprovalChain{}
+	for rows.Next() {
+		chain, err := scanApprovalChain(rows)
+		if err != nil {
+			return nil, err
+		}
+		chains = append(chains, *chain)
+	}
+
+	return chains, rows.Err()
+}
+
+// GetApprovalChainByID mengambil satu approval chain; nil jika tidak ada
+func (r *approvalChainRepo) GetApprovalChainByID(ctx context.Context, id uuid.UUID) (*model.ApprovalChain, error) {
+	chain, err := scanApprovalChain(r.db.QueryRow(ctx, `SELECT `+approvalChainColumns+` FROM approval_chains WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return chain, err
+}
+
+// GetApprovalChainByScope mengambil chain dengan kombinasi tipe & tingkat yang persis sama (NULL = semua)
+func (r *approvalChainRepo) GetApprovalChainByScope(ctx context.Context, achievementType, competitionLevel *string) (*model.ApprovalChain, error) {
+	query := `SELECT ` + approvalChainColumns + ` FROM approval_chains
+              WHERE achievement_type IS NOT DISTINCT FROM $1 AND competition_level IS NOT DISTINCT FROM $2`
+
+	chain, err := scanApprovalChain(r.db.QueryRow(ctx, query, achievementType, competitionLevel))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return chain, err
+}
+
+// FindApprovalChain mengambil chain aktif yang paling spesifik untuk prestasi:
+// tipe + tingkat, lalu tipe saja, lalu tingkat saja, lalu chain default. nil jika tidak ada yang cocok.
+func (r *approvalChainRepo) FindApprovalChain(ctx context.Context, achievementType, competitionLevel string) (*model.ApprovalChain, error) {
+	query := `SELECT ` + approvalChainColumns + ` FROM approval_chains
+              WHERE is_active = TRUE
+                AND (achievement_type IS NULL OR achievement_type = $1)
+                AND (competition_level IS NULL OR competition_level = $2)
+              ORDER BY (achievement_type IS NOT NULL) DESC, (competition_level IS NOT NULL) DESC
+              LIMIT 1`
+
+	chain, err := scanApprovalChain(r.db.QueryRow(ctx, query, achievementType, competitionLevel))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return chain, err
+}
+
+// CreateApprovalChain menyimpan approval chain baru
+func (r *approvalChainRepo) CreateApprovalChain(ctx context.Context, chain *model.ApprovalChain) error {
+	stages, err := json.Marshal(chain.Stages)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO approval_chains (id, name, achievement_type, competition_level, stages, is_active, created_at, updated_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err = r.db.Exec(ctx, query, chain.ID, chain.Name, chain.AchievementType, chain.CompetitionLevel, stages,
+		chain.IsActive, chain.CreatedAt, chain.UpdatedAt)
+	return err
+}
+
+// UpdateApprovalChain mengganti isi approval chain; prestasi yang sudah disubmit tetap memakai tahap lamanya
+func (r *approvalChainRepo) UpdateApprovalChain(ctx context.Context, chain *model.ApprovalChain) error {
+	stages, err := json.Marshal(chain.Stages)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE approval_chains
+              SET name = $1, achievement_type = $2, competition_level = $3, stages = $4, is_active = $5, updated_at = $6
+              WHERE id = $7`
+
+	_, err = r.db.Exec(ctx, query, chain.Name, chain.AchievementType, chain.CompetitionLevel, stages,
+		chain.IsActive, chain.UpdatedAt, chain.ID)
+	return err
+}
+
+// DeleteApprovalChain menghapus approval chain
+func (r *approvalChainRepo) DeleteApprovalChain(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM approval_chains WHERE id = $1`, id)
+	return err
+}
+
+// RoleExists mengecek nama role (case-insensitive) untuk approver_role tahap persetujuan
+func (r *approvalChainRepo) RoleExists(ctx context.Context, name string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM roles WHERE LOWER(name) = LOWER($1))`, name).Scan(&exists)
+	return exists, err
+}
@@ -3,21 +3,39 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 	model "UASBE/app/model/Postgresql"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-type AuthRepository struct {
-	DB *pgxpool.Pool
+// ErrRefreshTokenAlreadyRotated dikembalikan jika refresh token sudah dirotasi oleh request lain
+var ErrRefreshTokenAlreadyRotated = errors.New("refresh token already rotated")
+
+type AuthRepository interface {
+	FindUserByEmailOrUsername(identifier string) (*model.Users, string, error)
+	GetPermissionsByRoleID(roleID uuid.UUID) ([]string, error)
+	GetUserProfile(userID uuid.UUID) (*model.UserProfileResponse, error)
+	FindUserByID(userID uuid.UUID) (*model.Users, string, error)
+
+	// Refresh token
+	CreateRefreshToken(token model.RefreshToken) error
+	GetRefreshTokenByHash(tokenHash string) (*model.RefreshToken, error)
+	RotateRefreshToken(oldTokenID uuid.UUID, newToken model.RefreshToken) error
+	RevokeRefreshTokenFamily(familyID uuid.UUID) error
 }
 
-func NewAuthRepository(db *pgxpool.Pool) *AuthRepository {
-	return &AuthRepository{DB: db}
+type authRepo struct {
+	db *pgxpool.Pool
 }
 
-func (r *AuthRepository) FindUserByEmailOrUsername(identifier string) (*model.Users, string, error) {
+func NewAuthRepository(db *pgxpool.Pool) AuthRepository {
+	return &authRepo{db: db}
+}
+
+func (r *authRepo) FindUserByEmailOrUsername(identifier string) (*model.Users, string, error) {
 	var user model.Users
 	var roleName string
 
@@ -32,7 +50,7 @@ func (r *AuthRepository) FindUserByEmailOrUsername(identifier string) (*model.Us
 		LIMIT 1
 	`
 
-	err := r.DB.QueryRow(context.Background(), query, identifier).Scan(
+	err := r.db.QueryRow(context.Background(), query, identifier).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
@@ -55,7 +73,7 @@ func (r *AuthRepository) FindUserByEmailOrUsername(identifier string) (*model.Us
 	return &user, roleName, nil
 }
 
-func (r *AuthRepository) GetPermissionsByRoleID(roleID uuid.UUID) ([]string, error) {
+func (r *authRepo) GetPermissionsByRoleID(roleID uuid.UUID) ([]string, error) {
 	query := `
 		SELECT p.name
 		FROM permissions p
@@ -63,7 +81,7 @@ func (r *AuthRepository) GetPermissionsByRoleID(roleID uuid.UUID) ([]string, err
 		WHERE rp.role_id = $1
 	`
 
-	rows, err := r.DB.Query(context.Background(), query, roleID)
+	rows, err := r.db.Query(context.Background(), query, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +98,7 @@ func (r *AuthRepository) GetPermissionsByRoleID(roleID uuid.UUID) ([]string, err
 
 	return permissions, nil
 }
-func (r *AuthRepository) GetUserProfile(userID uuid.UUID) (*model.UserProfileResponse, error) {
+func (r *authRepo) GetUserProfile(userID uuid.UUID) (*model.UserProfileResponse, error) {
 	var (
 		user model.Users
 		roleName string
@@ -98,7 +116,7 @@ func (r *AuthRepository) GetUserProfile(userID uuid.UUID) (*model.UserProfileRes
 		LIMIT 1
 	`
 
-	err := r.DB.QueryRow(context.Background(), userQuery, userID).Scan(
+	err := r.db.QueryRow(context.Background(), userQuery, userID).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
@@ -133,7 +151,7 @@ func (r *AuthRepository) GetUserProfile(userID uuid.UUID) (*model.UserProfileRes
 	var student model.ProfileData
 	var advisorID uuid.UUID
 
-	err = r.DB.QueryRow(context.Background(), studentQuery, userID).
+	err = r.db.QueryRow(context.Background(), studentQuery, userID).
 		Scan(&student.StudentID, &student.ProgramStudy, &student.AcademicYear, &advisorID)
 
 	if err == nil {
@@ -151,7 +169,7 @@ func (r *AuthRepository) GetUserProfile(userID uuid.UUID) (*model.UserProfileRes
 	`
 
 	var lecturer model.ProfileData
-	err = r.DB.QueryRow(context.Background(), lecturerQuery, userID).
+	err = r.db.QueryRow(context.Background(), lecturerQuery, userID).
 		Scan(&lecturer.LecturerID, &lecturer.Department)
 
 	if err == nil {
@@ -162,3 +180,133 @@ func (r *AuthRepository) GetUserProfile(userID uuid.UUID) (*model.UserProfileRes
 	// 4. ADMIN / ROLE LAIN → tetap return user info
 	return response, nil
 }
+
+// FindUserByID mengambil user beserta nama role berdasarkan ID (dipakai saat refresh token)
+func (r *authRepo) FindUserByID(userID uuid.UUID) (*model.Users, string, error) {
+	var user model.Users
+	var roleName string
+
+	query := `
+		SELECT 
+			u.id, u.username, u.email, u.password_hash, u.full_name, 
+			u.role_id, u.is_active, u.created_at, u.updated_at,
+			r.name
+		FROM users u
+		JOIN roles r ON u.role_id = r.id
+		WHERE u.id = $1
+		LIMIT 1
+	`
+
+	err := r.db.QueryRow(context.Background(), query, userID).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.PasswordHash,
+		&user.FullName,
+		&user.RoleID,
+		&user.ISActive,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&roleName,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", errors.New("user not found")
+		}
+		return nil, "", err
+	}
+
+	return &user, roleName, nil
+}
+
+// CreateRefreshToken menyimpan refresh token baru (hash saja)
+func (r *authRepo) CreateRefreshToken(token model.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.Exec(context.Background(), query,
+		token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.CreatedAt,
+	)
+	return err
+}
+
+// GetRefreshTokenByHash mengambil refresh token berdasarkan hash
+func (r *authRepo) GetRefreshTokenByHash(tokenHash string) (*model.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, created_at, rotated_at, replaced_by, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	var token model.RefreshToken
+	err := r.db.QueryRow(context.Background(), query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.RotatedAt,
+		&token.ReplacedBy,
+		&token.RevokedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("refresh token not found")
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// RotateRefreshToken menandai token lama sebagai rotated dan menyimpan penggantinya dalam satu transaksi.
+// Mengembalikan ErrRefreshTokenAlreadyRotated jika token lama sudah dipakai sebelumnya.
+func (r *authRepo) RotateRefreshToken(oldTokenID uuid.UUID, newToken model.RefreshToken) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	insertQuery := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err = tx.Exec(ctx, insertQuery,
+		newToken.ID, newToken.UserID, newToken.FamilyID, newToken.TokenHash, newToken.ExpiresAt, newToken.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	updateQuery := `
+		UPDATE refresh_tokens
+		SET rotated_at = $1, replaced_by = $2
+		WHERE id = $3 AND rotated_at IS NULL AND revoked_at IS NULL
+	`
+	tag, err := tx.Exec(ctx, updateQuery, time.Now(), newToken.ID, oldTokenID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrRefreshTokenAlreadyRotated
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RevokeRefreshTokenFamily me-revoke semua refresh token dalam satu family
+func (r *authRepo) RevokeRefreshTokenFamily(familyID uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`
+
+	_, err := r.db.Exec(context.Background(), query, time.Now(), familyID)
+	return err
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	model "UASBE/app/model/Postgresql"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DelegationRepository mengelola delegasi hak verifikasi dosen wali di PostgreSQL
+type DelegationRepository interface {
+	GetDelegations(ctx context.Context) ([]model.VerificationDelegation, error)
+	GetDelegationsGivenBy(ctx context.Context, lecturerID uuid.UUID) ([]model.VerificationDelegation, error)
+	GetDelegationsReceivedBy(ctx context.Context, lecturerID uuid.UUID) ([]model.VerificationDelegation, error)
+	GetDelegationByID(ctx context.Context, id uuid.UUID) (*model.VerificationDelegation, error)
+	CreateDelegation(ctx context.Context, delegation *model.VerificationDelegation) error
+	RevokeDelegation(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+	HasOverlappingDelegation(ctx context.Context, delegatorID, delegateID uuid.UUID, startDate, endDate time.Time) (bool, error)
+	FindActiveDelegation(ctx context.Context, delegatorID, delegateID uuid.UUID, at time.Time) (*model.VerificationDelegation, error)
+	GetDelegatedPendingApprovals(ctx context.Context, delegateID uuid.UUID, at time.Time) ([]model.AchievementApproval, error)
+	LecturerExists(ctx context.Context, lecturerID uuid.UUID) (bool, error)
+}
+
+type delegationRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewDelegationRepository(db *pgxpool.Pool) DelegationRepository {
+	return &delegationRepo{db: db}
+}
+
+const delegationSelect = `SELECT vd.id, vd.delegator_id, dl.user_id, du.full_name, vd.delegate_id, eu.full_name,
+              vd.kind, vd.start_date, vd.end_date, vd.reason, vd.created_by, vd.revoked_at, vd.created_at
+              FROM verification_delegations vd
+              JOIN lecturers dl ON vd.delegator_id = dl.id
+              JOIN users du ON dl.user_id = du.id
+              JOIN lecturers el ON vd.delegate_id = el.id
+              JOIN users eu ON el.user_id = eu.id`
+
+func scanDelegation(row pgx.Row) (*model.VerificationDelegation, error) {
+	var d model.VerificationDelegation
+	err := row.Scan(&d.ID, &d.DelegatorID, &d.DelegatorUserID, &d.DelegatorName, &d.DelegateID, &d.DelegateName,
+		&d.Kind, &d.StartDate, &d.EndDate, &d.Reason, &d.CreatedBy, &d.RevokedAt, &d.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *delegationRepo) queryDelegations(ctx context.Context, query string, args ...interface{}) ([]model.VerificationDelegation, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	delegations := []model.VerificationDelegation{}
+	for rows.Next() {
+		d, err := scanDelegation(rows)
+		if err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, *d)
+	}
+
+	return delegations, rows.Err()
+}
+
+// GetDelegations mengambil semua delegasi, terbaru lebih dulu
+func (r *delegationRepo) GetDelegations(ctx context.Context) ([]model.VerificationDelegation, error) {
+	return r.queryDelegations(ctx, delegationSelect+` ORDER BY vd.created_at DESC`)
+}
+
+// GetDelegationsGivenBy mengambil delegasi yang diberikan seorang dosen wali
+func (r *delegationRepo) GetDelegationsGivenBy(ctx context.Context, lecturerID uuid.UUID) ([]model.VerificationDelegation, error) {
+	return r.queryDelegations(ctx, delegationSelect+` WHERE vd.delegator_id = $1 ORDER BY vd.start_date DESC`, lecturerID)
+}
+
+// GetDelegationsReceivedBy mengambil delegasi yang diterima seorang dosen
+func (r *delegationRepo) GetDelegationsReceivedBy(ctx context.Context, lecturerID uuid.UUID) ([]model.VerificationDelegation, error) {
+	return r.queryDelegations(ctx, delegationSelect+` WHERE vd.delegate_id = $1 ORDER BY vd.start_date DESC`, lecturerID)
+}
+
+// GetDelegationByID mengambil satu delegasi; nil jika tidak ada
+func (r *delegationRepo) GetDelegationByID(ctx context.Context, id uuid.UUID) (*model.VerificationDelegation, error) {
+	d, err := scanDelegation(r.db.QueryRow(ctx, delegationSelect+` WHERE vd.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return d, err
+}
+
+// CreateDelegation menyimpan delegasi baru
+func (r *delegationRepo) CreateDelegation(ctx context.Context, d *model.VerificationDelegation) error {
+	query := `INSERT INTO verification_delegations
+              (id, delegator_id, delegate_id, kind, start_date, end_date, reason, created_by, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.db.Exec(ctx, query, d.ID, d.DelegatorID, d.DelegateID, d.Kind, d.StartDate, d.EndDate,
+		d.Reason, d.CreatedBy, d.CreatedAt)
+	return err
+}
+
+// RevokeDelegation mencabut delegasi; keputusan yang sudah diambil delegasi tetap tercatat
+func (r *delegationRepo) RevokeDelegation(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE verification_delegations SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`, revokedAt, id)
+	return err
+}
+
+// HasOverlappingDelegation mengecek delegasi aktif ke dosen yang sama dengan rentang tanggal yang beririsan
+func (r *delegationRepo) HasOverlappingDelegation(ctx context.Context, delegatorID, delegateID uuid.UUID, startDate, endDate time.Time) (bool, error) {
+	query := `SELECT EXISTS (
+                  SELECT 1 FROM verification_delegations
+                  WHERE delegator_id = $1 AND delegate_id = $2 AND revoked_at IS NULL
+                    AND start_date <= $4::date AND end_date >= $3::date
+              )`
+
+	var exists bool
+	err := r.db.QueryRow(ctx, query, delegatorID, delegateID, startDate, endDate).Scan(&exists)
+	return exists, err
+}
+
+// FindActiveDelegation mengambil delegasi dari delegator ke delegate yang berlaku pada tanggal at; nil jika tidak ada
+func (r *delegationRepo) FindActiveDelegation(ctx context.Context, delegatorID, delegateID uuid.UUID, at time.Time) (*model.VerificationDelegation, error) {
+	query := delegationSelect + `
+              WHERE vd.delegator_id = $1 AND vd.delegate_id = $2 AND vd.revoked_at IS NULL
+                AND $3::date BETWEEN vd.start_date AND vd.end_date
+              ORDER BY vd.created_at DESC
+              LIMIT 1`
+
+	d, err := scanDelegation(r.db.QueryRow(ctx, query, delegatorID, delegateID, at))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return d, err
+}
+
+// GetDelegatedPendingApprovals mengambil tahap dosen wali yang sedang menunggu keputusan untuk mahasiswa
+// bimbingan dosen yang mendelegasikan haknya ke delegateID pada tanggal at
+func (r *delegationRepo) GetDelegatedPendingApprovals(ctx context.Context, delegateID uuid.UUID, at time.Time) ([]model.AchievementApproval, error) {
+	query := `SELECT ` + achievementApprovalColumns + `
+              FROM achievement_approvals aa
+              JOIN achievement_references ar ON ar.id = aa.achievement_id AND ar.revision = aa.revision
+              JOIN students s ON ar.student_id = s.id
+              ` + achievementApprovalJoins + `
+              WHERE ar.status = 'submitted' AND aa.status = 'pending' AND aa.approver_role = 'advisor'
+                AND NOT EXISTS (
+                    SELECT 1 FROM achievement_approvals prev
+                    WHERE prev.achievement_id = aa.achievement_id AND prev.revision = aa.revision
+                      AND prev.stage_order < aa.stage_order AND prev.status = 'pending'
+                )
+                AND EXISTS (
+                    SELECT 1 FROM verification_delegations vd
+                    WHERE vd.delegator_id = s.advisor_id AND vd.delegate_id = $1 AND vd.revoked_at IS NULL
+                      AND $2::date BETWEEN vd.start_date AND vd.end_date
+                )
+              ORDER BY aa.created_at ASC`
+
+	rows, err := r.db.Query(ctx, query, delegateID, at)
+	if err != nil {
+		return nil, err
+	}
+	return scanAchievementApprovals(rows)
+}
+
+// LecturerExists mengecek apakah ID dosen terdaftar
+func (r *delegationRepo) LecturerExists(ctx context.Context, lecturerID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM lecturers WHERE id = $1)`, lecturerID).Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	model "UASBE/app/model/Postgresql"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NotificationRepository mengelola notifikasi user di PostgreSQL
+type NotificationRepository interface {
+	GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]model.Notification, error)
+	CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkNotificationRead(ctx context.Context, userID, notificationID uuid.UUID, readAt time.Time) (bool, error)
+}
+
+type notificationRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewNotificationRepository(db *pgxpool.Pool) NotificationRepository {
+	return &notificationRepo{db: db}
+}
+
+// GetNotifications mengambil notifikasi user, terbaru lebih dulu
+func (r *notificationRepo) GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]model.Notification, error) {
+	query := `SELECT id, user_id, type, achievement_id, message, read_at, created_at
+              FROM notifications
+              WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+              ORDER BY created_at DESC
+              LIMIT $3`
+
+	rows, err := r.db.Query(ctx, query, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []model.Notification{}
+	for rows.Next() {
+		var n model.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.AchievementID, &n.Message, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+// CountUnreadNotifications menghitung notifikasi user yang belum dibaca
+func (r *notificationRepo) CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+// MarkNotificationRead menandai notifikasi milik user sudah dibaca; false jika notifikasi tidak ditemukan
+func (r *notificationRepo) MarkNotificationRead(ctx context.Context, userID, notificationID uuid.UUID, readAt time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE notifications SET read_at = COALESCE(read_at, $3) WHERE id = $1 AND user_id = $2`,
+		notificationID, userID, readAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// insertAdvisorNotification membuat notifikasi untuk dosen wali pemilik prestasi di dalam transaksi yang sedang berjalan;
+// tidak melakukan apa pun jika mahasiswa belum memiliki dosen wali
+func insertAdvisorNotification(ctx context.Context, tx pgx.Tx, achievementID uuid.UUID, notificationType, message string, createdAt time.Time) error {
+	query := `INSERT INTO notifications (id, user_id, type, achievement_id, message, created_at)
+              SELECT $1, l.user_id, $2, ar.id, $3, $4
+              FROM achievement_references ar
+              JOIN students s ON ar.student_id = s.id
+              JOIN lecturers l ON s.advisor_id = l.id
+              WHERE ar.id = $5`
+
+	_, err := tx.Exec(ctx, query, uuid.New(), notificationType, message, createdAt, achievementID)
+	return err
+}
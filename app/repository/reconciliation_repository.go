@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReconciliationRepository memindai achievement_references dan koleksi achievements untuk mencari drift,
+// serta mencatat perbaikannya (lewat outbox untuk dokumen yang masih punya reference)
+type ReconciliationRepository interface {
+	GetReferencesForReconciliation(ctx context.Context, afterID uuid.UUID, limit int) ([]model.ReconciliationReference, error)
+	GetDocumentsByMongoIDs(ctx context.Context, mongoIDs []string) (map[string]model.ReconciliationDocument, error)
+	GetDocumentsForReconciliation(ctx context.Context, afterMongoID string, limit int) ([]model.ReconciliationDocument, error)
+	GetReferencedMongoIDs(ctx context.Context, mongoIDs []string) (map[string]bool, error)
+	GetLatestAchievementSnapshot(ctx context.Context, achievementID uuid.UUID) (*mongodb.Achievement, error)
+	EnqueueDocumentUpsert(ctx context.Context, achievementID uuid.UUID, achievement mongodb.Achievement) error
+	EnqueueDocumentOperation(ctx context.Context, achievementID uuid.UUID, operation string) error
+	MoveReferenceToTrash(ctx context.Context, achievementID uuid.UUID, note string) error
+	DeleteOrphanDocument(ctx context.Context, mongoAchievementID string) error
+}
+
+type reconciliationRepo struct {
+	pgDB      *pgxpool.Pool
+	mongoColl *mongo.Collection
+}
+
+func NewReconciliationRepository(pgDB *pgxpool.Pool, mongoColl *mongo.Collection) ReconciliationRepository {
+	return &reconciliationRepo{pgDB: pgDB, mongoColl: mongoColl}
+}
+
+// reconciliationDocument adalah proyeksi dokumen MongoDB yang dipakai rekonsiliasi
+type reconciliationDocument struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	StudentID uuid.UUID          `bson:"studentId"`
+	DeletedAt *time.Time         `bson:"deleted_at"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+var reconciliationProjection = bson.M{"_id": 1, "studentId": 1, "deleted_at": 1, "createdAt": 1}
+
+func (d reconciliationDocument) toModel() model.ReconciliationDocument {
+	return model.ReconciliationDocument{
+		MongoAchievementID: d.ID.Hex(),
+		StudentID:          d.StudentID,
+		Deleted:            d.DeletedAt != nil,
+		CreatedAt:          d.CreatedAt,
+	}
+}
+
+// GetReferencesForReconciliation mengambil reference urut id (keyset) mulai setelah afterID
+func (r *reconciliationRepo) GetReferencesForReconciliation(ctx context.Context, afterID uuid.UUID, limit int) ([]model.ReconciliationReference, error) {
+	query := `SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status,
+                     EXISTS (SELECT 1 FROM achievement_outbox o WHERE o.achievement_id = ar.id AND o.processed_at IS NULL)
+              FROM achievement_references ar
+              WHERE ar.id > $1
+              ORDER BY ar.id
+              LIMIT $2`
+
+	rows, err := r.pgDB.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refs := []model.ReconciliationReference{}
+	for rows.Next() {
+		var ref model.ReconciliationReference
+		if err := rows.Scan(&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status, &ref.HasPendingOutbox); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
+
+// GetDocumentsByMongoIDs mengambil dokumen (termasuk yang soft-deleted) untuk daftar ID; ID tidak valid dilewati
+func (r *reconciliationRepo) GetDocumentsByMongoIDs(ctx context.Context, mongoIDs []string) (map[string]model.ReconciliationDocument, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(mongoIDs))
+	for _, id := range mongoIDs {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+
+	docs := map[string]model.ReconciliationDocument{}
+	if len(objectIDs) == 0 {
+		return docs, nil
+	}
+
+	cursor, err := r.mongoColl.Find(ctx, bson.M{"_id": bson.M{"$in": objectIDs}}, options.Find().SetProjection(reconciliationProjection))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc reconciliationDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		docs[doc.ID.Hex()] = doc.toModel()
+	}
+
+	return docs, cursor.Err()
+}
+
+// GetDocumentsForReconciliation mengambil dokumen urut _id (keyset) mulai setelah afterMongoID ("" = dari awal)
+func (r *reconciliationRepo) GetDocumentsForReconciliation(ctx context.Context, afterMongoID string, limit int) ([]model.ReconciliationDocument, error) {
+	filter := bson.M{}
+	if afterMongoID != "" {
+		afterID, err := primitive.ObjectIDFromHex(afterMongoID)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
+
+	opts := options.Find().SetProjection(reconciliationProjection).SetSort(bson.M{"_id": 1}).SetLimit(int64(limit))
+	cursor, err := r.mongoColl.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	docs := []model.ReconciliationDocument{}
+	for cursor.Next(ctx) {
+		var doc reconciliationDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc.toModel())
+	}
+
+	return docs, cursor.Err()
+}
+
+// GetReferencedMongoIDs mengembalikan ID dokumen yang masih punya reference (status apa pun)
+func (r *reconciliationRepo) GetReferencedMongoIDs(ctx context.Context, mongoIDs []string) (map[string]bool, error) {
+	rows, err := r.pgDB.Query(ctx, `SELECT mongo_achievement_id FROM achievement_references WHERE mongo_achievement_id = ANY($1)`, mongoIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	referenced := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		referenced[id] = true
+	}
+
+	return referenced, rows.Err()
+}
+
+// GetLatestAchievementSnapshot mengambil snapshot versi terakhir prestasi beserta storage key attachment-nya;
+// nil jika belum ada versi
+func (r *reconciliationRepo) GetLatestAchievementSnapshot(ctx context.Context, achievementID uuid.UUID) (*mongodb.Achievement, error) {
+	return latestAchievementSnapshot(ctx, r.pgDB, achievementID)
+}
+
+// EnqueueDocumentUpsert mencatat penulisan ulang isi dokumen di outbox
+func (r *reconciliationRepo) EnqueueDocumentUpsert(ctx context.Context, achievementID uuid.UUID, achievement mongodb.Achievement) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		return insertOutboxUpsert(ctx, tx, achievementID, achievement, time.Now())
+	})
+}
+
+// EnqueueDocumentOperation mencatat operasi dokumen tanpa payload (soft delete / restore) di outbox
+func (r *reconciliationRepo) EnqueueDocumentOperation(ctx context.Context, achievementID uuid.UUID, operation string) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		return insertOutboxEvent(ctx, tx, achievementID, operation, nil, time.Now())
+	})
+}
+
+// MoveReferenceToTrash memindahkan reference yang dokumennya hilang ke trash ('deleted') beserta log statusnya;
+// dicatat tanpa changed_by karena dilakukan sistem
+func (r *reconciliationRepo) MoveReferenceToTrash(ctx context.Context, achievementID uuid.UUID, note string) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		var previous string
+		err := tx.QueryRow(ctx, `SELECT status FROM achievement_references WHERE id = $1 FOR UPDATE`, achievementID).Scan(&previous)
+		if err != nil {
+			return err
+		}
+		if previous == "deleted" {
+			return nil
+		}
+
+		now := time.Now()
+		_, err = tx.Exec(ctx, `UPDATE achievement_references SET status = 'deleted', deleted_at = $1, updated_at = $1 WHERE id = $2`, now, achievementID)
+		if err != nil {
+			return err
+		}
+
+		return insertStatusLog(ctx, tx, model.AchievementStatusLog{
+			ID:             uuid.New(),
+			AchievementID:  achievementID,
+			Status:         "deleted",
+			PreviousStatus: &previous,
+			Note:           &note,
+			CreatedAt:      now,
+		})
+	})
+}
+
+// DeleteOrphanDocument menghapus permanen dokumen yang tidak punya reference
+func (r *reconciliationRepo) DeleteOrphanDocument(ctx context.Context, mongoAchievementID string) error {
+	objectID, err := primitive.ObjectIDFromHex(mongoAchievementID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.mongoColl.DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
+
+func (r *reconciliationRepo) withTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := r.pgDB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
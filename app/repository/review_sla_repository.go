@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	model "UASBE/app/model/Postgresql"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ReviewSLARepository mengelola batas waktu review, eskalasi, dan statistik keputusan reviewer di PostgreSQL
+type ReviewSLARepository interface {
+	GetReviewSLAs(ctx context.Context) ([]model.ReviewSLA, error)
+	GetReviewSLA(ctx context.Context, level string) (*model.ReviewSLA, error)
+	UpsertReviewSLA(ctx context.Context, sla *model.ReviewSLA) error
+	DeleteReviewSLA(ctx context.Context, level string) error
+	EscalateOverdueAchievements(ctx context.Context, now time.Time) ([]model.AchievementEscalation, error)
+	GetOverdueAchievements(ctx context.Context, now time.Time) ([]model.OverdueAchievement, error)
+	GetReviewerThroughput(ctx context.Context, from, to *time.Time) ([]model.ReviewerThroughput, error)
+}
+
+type reviewSLARepo struct {
+	db *pgxpool.Pool
+}
+
+func NewReviewSLARepository(db *pgxpool.Pool) ReviewSLARepository {
+	return &reviewSLARepo{db: db}
+}
+
+// GetReviewSLAs mengambil semua SLA review
+func (r *reviewSLARepo) GetReviewSLAs(ctx context.Context) ([]model.ReviewSLA, error) {
+	rows, err := r.db.Query(ctx, `SELECT level, deadline_hours, escalate_to_role, updated_at FROM review_slas
+              ORDER BY level = 'default' DESC, level`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slas := []model.ReviewSLA{}
+	for rows.Next() {
+		var sla model.ReviewSLA
+		if err := rows.Scan(&sla.Level, &sla.DeadlineHours, &sla.EscalateToRole, &sla.UpdatedAt); err != nil {
+			return nil, err
+		}
+		slas = append(slas, sla)
+	}
+
+	return slas, rows.Err()
+}
+
+// GetReviewSLA mengambil SLA satu level; nil jika tidak ada
+func (r *reviewSLARepo) GetReviewSLA(ctx context.Context, level string) (*model.ReviewSLA, error) {
+	var sla model.ReviewSLA
+	err := r.db.QueryRow(ctx, `SELECT level, deadline_hours, escalate_to_role, updated_at FROM review_slas WHERE level = $1`, level).
+		Scan(&sla.Level, &sla.DeadlineHours, &sla.EscalateToRole, &sla.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sla, nil
+}
+
+// UpsertReviewSLA membuat atau mengganti SLA satu level; berlaku untuk submission berikutnya
+func (r *reviewSLARepo) UpsertReviewSLA(ctx context.Context, sla *model.ReviewSLA) error {
+	query := `INSERT INTO review_slas (level, deadline_hours, escalate_to_role, updated_at)
+              VALUES ($1, $2, $3, $4)
+              ON CONFLICT (level) DO UPDATE
+              SET deadline_hours = EXCLUDED.deadline_hours, escalate_to_role = EXCLUDED.escalate_to_role, updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.Exec(ctx, query, sla.Level, sla.DeadlineHours, sla.EscalateToRole, sla.UpdatedAt)
+	return err
+}
+
+// DeleteReviewSLA menghapus SLA satu level; level tersebut kembali memakai SLA default
+func (r *reviewSLARepo) DeleteReviewSLA(ctx context.Context, level string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM review_slas WHERE level = $1`, level)
+	return err
+}
+
+// EscalateOverdueAchievements menandai prestasi 'submitted' yang melewati review_due_at dan belum dieskalasi,
+// lalu mencatat eskalasi di riwayat status dalam transaksi yang sama
+func (r *reviewSLARepo) EscalateOverdueAchievements(ctx context.Context, now time.Time) ([]model.AchievementEscalation, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE achievement_references ar
+              SET escalated_at = $1,
+                  escalated_to = COALESCE(
+                      (SELECT escalate_to_role FROM review_slas WHERE level = ar.review_level),
+                      (SELECT escalate_to_role FROM review_slas WHERE level = 'default')
+                  )
+              WHERE ar.status = 'submitted' AND ar.review_due_at < $1 AND ar.escalated_at IS NULL
+                AND EXISTS (SELECT 1 FROM review_slas WHERE level IN (ar.review_level, 'default'))
+              RETURNING ar.id, ar.escalated_to, ar.review_due_at`
+
+	rows, err := tx.Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+
+	escalations := []model.AchievementEscalation{}
+	for rows.Next() {
+		var e model.AchievementEscalation
+		if err := rows.Scan(&e.AchievementID, &e.EscalatedTo, &e.ReviewDueAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		escalations = append(escalations, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	submitted := "submitted"
+	for _, e := range escalations {
+		note := "Review overdue since " + e.ReviewDueAt.Format(time.RFC3339) + "; escalated to " + e.EscalatedTo
+		err := insertStatusLog(ctx, tx, model.AchievementStatusLog{
+			ID:             uuid.New(),
+			AchievementID:  e.AchievementID,
+			Status:         submitted,
+			PreviousStatus: &submitted,
+			Note:           &note,
+			CreatedAt:      now,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return escalations, nil
+}
+
+// GetOverdueAchievements mengambil prestasi 'submitted' yang melewati batas waktu review, paling lama lebih dulu
+func (r *reviewSLARepo) GetOverdueAchievements(ctx context.Context, now time.Time) ([]model.OverdueAchievement, error) {
+	query := `SELECT ar.id, ar.student_id, s.student_id, u.full_name, ar.revision, ar.review_level,
+                     ar.submitted_at, ar.review_due_at, EXTRACT(EPOCH FROM ($1 - ar.review_due_at))::FLOAT8 / 3600,
+                     cur.stage_name, cur.approver_role, ar.escalated_at, ar.escalated_to
+              FROM achievement_references ar
+              JOIN students s ON ar.student_id = s.id
+              JOIN users u ON s.user_id = u.id
+              LEFT JOIN LATERAL (
+                  SELECT aa.stage_name, aa.approver_role FROM achievement_approvals aa
+                  WHERE aa.achievement_id = ar.id AND aa.revision = ar.revision AND aa.status = 'pending'
+                  ORDER BY aa.stage_order LIMIT 1
+              ) cur ON TRUE
+              WHERE ar.status = 'submitted' AND ar.review_due_at < $1
+              ORDER BY ar.review_due_at ASC`
+
+	rows, err := r.db.Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overdue := []model.OverdueAchievement{}
+	for rows.Next() {
+		var o model.OverdueAchievement
+		err := rows.Scan(&o.ID, &o.StudentID, &o.StudentNIM, &o.StudentName, &o.Revision, &o.ReviewLevel,
+			&o.SubmittedAt, &o.ReviewDueAt, &o.OverdueHours, &o.CurrentStageName, &o.CurrentApproverRole,
+			&o.EscalatedAt, &o.EscalatedTo)
+		if err != nil {
+			return nil, err
+		}
+		overdue = append(overdue, o)
+	}
+
+	return overdue, rows.Err()
+}
+
+// GetReviewerThroughput merangkum keputusan tahap persetujuan per reviewer dalam rentang decided_at (opsional)
+func (r *reviewSLARepo) GetReviewerThroughput(ctx context.Context, from, to *time.Time) ([]model.ReviewerThroughput, error) {
+	query := `SELECT aa.decided_by, u.full_name, COUNT(*),
+                     COUNT(*) FILTER (WHERE aa.status = 'approved'),
+                     COUNT(*) FILTER (WHERE aa.status = 'rejected'),
+                     COALESCE(AVG(aa.decision_seconds), 0)::FLOAT8 / 3600,
+                     COALESCE(MAX(aa.decision_seconds), 0)::FLOAT8 / 3600
+              FROM achievement_approvals aa
+              JOIN users u ON aa.decided_by = u.id
+              WHERE aa.status IN ('approved', 'rejected')
+                AND ($1::timestamptz IS NULL OR aa.decided_at >= $1)
+                AND ($2::timestamptz IS NULL OR aa.decided_at < $2)
+              GROUP BY aa.decided_by, u.full_name
+              ORDER BY COUNT(*) DESC, u.full_name`
+
+	rows, err := r.db.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	throughput := []model.ReviewerThroughput{}
+	for rows.Next() {
+		var t model.ReviewerThroughput
+		err := rows.Scan(&t.ReviewerID, &t.ReviewerName, &t.Decisions, &t.Approved, &t.Rejected,
+			&t.AvgDecisionHours, &t.MaxDecisionHours)
+		if err != nil {
+			return nil, err
+		}
+		throughput = append(throughput, t)
+	}
+
+	return throughput, rows.Err()
+}
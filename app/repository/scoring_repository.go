@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	model "UASBE/app/model/Postgresql"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ScoringRepository mengelola versi aturan skor dan skor prestasi di PostgreSQL
+type ScoringRepository interface {
+	GetScoringRuleSets(ctx context.Context) ([]model.ScoringRuleSet, error)
+	GetScoringRuleSetByVersion(ctx context.Context, version int) (*model.ScoringRuleSet, error)
+	GetActiveScoringRuleSet(ctx context.Context) (*model.ScoringRuleSet, error)
+	CreateScoringRuleSet(ctx context.Context, ruleSet *model.ScoringRuleSet) error
+	ActivateScoringRuleSet(ctx context.Context, version int) error
+	GetAchievementScore(ctx context.Context, achievementID uuid.UUID) (*model.AchievementScore, error)
+	SaveAchievementScore(ctx context.Context, score model.AchievementScore) error
+}
+
+type scoringRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewScoringRepository(db *pgxpool.Pool) ScoringRepository {
+	return &scoringRepo{db: db}
+}
+
+const scoringRuleSetColumns = `version, description, rules, is_active, created_by, created_at, activated_at`
+
+func scanScoringRuleSet(row pgx.Row) (*model.ScoringRuleSet, error) {
+	var rs model.ScoringRuleSet
+	var rules []byte
+	if err := row.Scan(&rs.Version, &rs.Description, &rules, &rs.IsActive, &rs.CreatedBy, &rs.CreatedAt, &rs.ActivatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(rules, &rs.Rules); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+// GetScoringRuleSets mengambil semua versi aturan skor, terbaru lebih dulu
+func (r *scoringRepo) GetScoringRuleSets(ctx context.Context) ([]model.ScoringRuleSet, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+scoringRuleSetColumns+` FROM scoring_rule_sets ORDER BY version DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ruleSets := []model.ScoringRuleSet{}
+	for rows.Next() {
+		rs, err := scanScoringRuleSet(rows)
+		if err != nil {
+			return nil, err
+		}
+		ruleSets = append(ruleSets, *rs)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ruleSets, nil
+}
+
+// GetScoringRuleSetByVersion mengambil satu versi aturan skor; nil jika tidak ada
+func (r *scoringRepo) GetScoringRuleSetByVersion(ctx context.Context, version int) (*model.ScoringRuleSet, error) {
+	rs, err := scanScoringRuleSet(r.db.QueryRow(ctx, `SELECT `+scoringRuleSetColumns+` FROM scoring_rule_sets WHERE version = $1`, version))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return rs, err
+}
+
+// GetActiveScoringRuleSet mengambil versi aturan skor yang aktif; nil jika belum ada
+func (r *scoringRepo) GetActiveScoringRuleSet(ctx context.Context) (*model.ScoringRuleSet, error) {
+	rs, err := scanScoringRuleSet(r.db.QueryRow(ctx, `SELECT `+scoringRuleSetColumns+` FROM scoring_rule_sets WHERE is_active = TRUE`))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return rs, err
+}
+
+// CreateScoringRuleSet menyimpan versi aturan baru (version = versi terakhir + 1).
+// Jika ruleSet.IsActive, versi lain dinonaktifkan dalam transaksi yang sama.
+func (r *scoringRepo) CreateScoringRuleSet(ctx context.Context, ruleSet *model.ScoringRuleSet) error {
+	rules, err := json.Marshal(ruleSet.Rules)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialisasi pembuatan versi agar nomor versi tidak bentrok
+	if _, err := tx.Exec(ctx, `LOCK TABLE scoring_rule_sets IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) + 1 FROM scoring_rule_sets`).Scan(&ruleSet.Version); err != nil {
+		return err
+	}
+
+	if ruleSet.IsActive {
+		if _, err := tx.Exec(ctx, `UPDATE scoring_rule_sets SET is_active = FALSE WHERE is_active = TRUE`); err != nil {
+			return err
+		}
+	}
+
+	query := `INSERT INTO scoring_rule_sets (version, description, rules, is_active, created_by, created_at, activated_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	if _, err := tx.Exec(ctx, query, ruleSet.Version, ruleSet.Description, rules, ruleSet.IsActive, ruleSet.CreatedBy, ruleSet.CreatedAt, ruleSet.ActivatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ActivateScoringRuleSet menjadikan satu versi sebagai aturan aktif untuk verifikasi berikutnya
+func (r *scoringRepo) ActivateScoringRuleSet(ctx context.Context, version int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE scoring_rule_sets SET is_active = FALSE WHERE is_active = TRUE AND version <> $1`, version); err != nil {
+		return err
+	}
+
+	query := `UPDATE scoring_rule_sets SET is_active = TRUE, activated_at = $1 WHERE version = $2 AND is_active = FALSE`
+	if _, err := tx.Exec(ctx, query, time.Now(), version); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetAchievementScore mengambil skor prestasi; nil jika belum pernah dihitung
+func (r *scoringRepo) GetAchievementScore(ctx context.Context, achievementID uuid.UUID) (*model.AchievementScore, error) {
+	query := `SELECT achievement_id, rule_version, points, breakdown, calculated_by, calculated_at
+              FROM achievement_scores WHERE achievement_id = $1`
+
+	var score model.AchievementScore
+	var breakdown []byte
+	err := r.db.QueryRow(ctx, query, achievementID).Scan(
+		&score.AchievementID, &score.RuleVersion, &score.Points, &breakdown, &score.CalculatedBy, &score.CalculatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(breakdown, &score.Breakdown); err != nil {
+		return nil, err
+	}
+	return &score, nil
+}
+
+// SaveAchievementScore menyimpan (upsert) skor prestasi beserta event outbox poinnya untuk dokumen MongoDB
+func (r *scoringRepo) SaveAchievementScore(ctx context.Context, score model.AchievementScore) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := upsertAchievementScore(ctx, tx, score); err != nil {
+		return err
+	}
+	if err := insertOutboxSetPoints(ctx, tx, score.AchievementID, score.Points, score.CalculatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// execer dipenuhi oleh *pgxpool.Pool maupun pgx.Tx
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// upsertAchievementScore dipakai langsung maupun di dalam transaksi verifikasi
+func upsertAchievementScore(ctx context.Context, db execer, score model.AchievementScore) error {
+	breakdown, err := json.Marshal(score.Breakdown)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO achievement_scores (achievement_id, rule_version, points, breakdown, calculated_by, calculated_at)
+              VALUES ($1, $2, $3, $4, $5, $6)
+              ON CONFLICT (achievement_id) DO UPDATE SET
+                  rule_version = EXCLUDED.rule_version, points = EXCLUDED.points, breakdown = EXCLUDED.breakdown,
+                  calculated_by = EXCLUDED.calculated_by, calculated_at = EXCLUDED.calculated_at`
+
+	_, err = db.Exec(ctx, query, score.AchievementID, score.RuleVersion, score.Points, breakdown, score.CalculatedBy, score.CalculatedAt)
+	return err
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"UASBE/utils"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TokenBlacklistRepository menyimpan token yang sudah di-revoke di PostgreSQL
+// sehingga revocation tetap berlaku setelah restart dan di semua instance API.
+type TokenBlacklistRepository interface {
+	utils.TokenBlacklistStore
+	DeleteExpired(ctx context.Context) (int64, error)
+	StartCleanup(interval time.Duration)
+}
+
+type tokenBlacklistRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewTokenBlacklistRepository(db *pgxpool.Pool) TokenBlacklistRepository {
+	return &tokenBlacklistRepo{db: db}
+}
+
+// Revoke menyimpan jti ke tabel revoked_tokens
+func (r *tokenBlacklistRepo) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	query := `INSERT INTO revoked_tokens (jti, expires_at, revoked_at)
+              VALUES ($1, $2, $3)
+              ON CONFLICT (jti) DO NOTHING`
+
+	_, err := r.db.Exec(ctx, query, tokenID, expiresAt, time.Now())
+	return err
+}
+
+// IsRevoked mengecek apakah jti sudah di-revoke dan belum expired
+func (r *tokenBlacklistRepo) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1 AND expires_at > $2)`
+
+	var revoked bool
+	err := r.db.QueryRow(ctx, query, tokenID, time.Now()).Scan(&revoked)
+	return revoked, err
+}
+
+// DeleteExpired menghapus token yang sudah expired dari tabel revoked_tokens
+func (r *tokenBlacklistRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM revoked_tokens WHERE expires_at <= $1`
+
+	tag, err := r.db.Exec(ctx, query, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// StartCleanup menjalankan DeleteExpired secara berkala
+func (r *tokenBlacklistRepo) StartCleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := r.DeleteExpired(context.Background()); err != nil {
+			log.Printf("⚠️ Failed cleaning revoked tokens: %v", err)
+		}
+	}
+}
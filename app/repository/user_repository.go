@@ -343,7 +343,7 @@ func (r *userRepo) GetStudentAchievements(ctx context.Context, studentID uuid.UU
 
 	// Get achievements with pagination
 	offset := (page - 1) * limit
-	query := `SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note, ar.created_at, ar.updated_at,
+	query := `SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note, ar.revision, ar.created_at, ar.updated_at,
                      s.student_id as student_nim, u.full_name as student_name, s.program_study
               FROM achievement_references ar
               JOIN students s ON ar.student_id = s.id
@@ -366,7 +366,7 @@ func (r *userRepo) GetStudentAchievements(ctx context.Context, studentID uuid.UU
 		err := rows.Scan(
 			&achievement.ID, &achievement.StudentID, &achievement.MongoAchievementID,
 			&achievement.Status, &achievement.SubmittedAt, &achievement.VerifiedAt,
-			&achievement.VerifiedBy, &achievement.RejectionNote, &achievement.CreatedAt,
+			&achievement.VerifiedBy, &achievement.RejectionNote, &achievement.Revision, &achievement.CreatedAt,
 			&achievement.UpdatedAt, &achievement.StudentNIM, &achievement.StudentName,
 			&achievement.ProgramStudy,
 		)
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// defaultApprovalStages dipakai jika tidak ada approval chain yang cocok: cukup disetujui dosen wali
+var defaultApprovalStages = []model.ApprovalStage{
+	{Name: "Dosen Wali", ApproverRole: model.ApproverRoleAdvisor},
+}
+
+// achievementLevel mengambil tingkat prestasi (competitionLevel) untuk approval chain dan SLA review; kosong jika tidak ada
+func achievementLevel(achievement mongodb.Achievement) string {
+	if achievement.Details.CompetitionLevel == nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(*achievement.Details.CompetitionLevel))
+}
+
+// resolveApprovalStages menentukan tahap persetujuan prestasi dari approval chain yang paling spesifik
+func (s *achievementService) resolveApprovalStages(ctx context.Context, achievement mongodb.Achievement) ([]model.ApprovalStage, error) {
+	chain, err := s.approvalRepo.FindApprovalChain(ctx, utils.NormalizeAchievementType(achievement.AchievementType), achievementLevel(achievement))
+	if err != nil {
+		return nil, errors.New("failed to resolve approval chain")
+	}
+	if chain == nil || len(chain.Stages) == 0 {
+		return defaultApprovalStages, nil
+	}
+	return chain.Stages, nil
+}
+
+// currentApprovalStage mengambil tahap pending pertama pada revision prestasi saat ini
+func (s *achievementService) currentApprovalStage(ctx context.Context, ref *model.AchievementReference) (*model.AchievementApproval, bool, error) {
+	approvals, err := s.repo.GetAchievementApprovals(ctx, ref.ID, ref.Revision)
+	if err != nil {
+		return nil, false, errors.New("failed to get approval stages")
+	}
+
+	for i := range approvals {
+		if approvals[i].Status == "pending" {
+			return &approvals[i], i == len(approvals)-1, nil
+		}
+	}
+	return nil, false, errors.New("no pending approval stage")
+}
+
+// approvalActor adalah approver yang memutuskan tahap persetujuan. VerifierID dicatat sebagai verified_by
+// (ID dosen jika approver adalah dosen, selain itu ID user); OnBehalfOf dan DelegationID terisi jika
+// dosen memutuskan sebagai delegasi/pengganti dosen wali.
+type approvalActor struct {
+	VerifierID   uuid.UUID
+	OnBehalfOf   *uuid.UUID
+	DelegationID *uuid.UUID
+}
+
+// authorizeApprovalStage memastikan user adalah approver tahap yang sedang berjalan.
+// action ("verify" atau "reject") hanya dipakai untuk pesan error.
+func (s *achievementService) authorizeApprovalStage(ctx context.Context, userID uuid.UUID, ref *model.AchievementReference, stage *model.AchievementApproval, action string) (*approvalActor, error) {
+	// Prestasi yang melewati batas waktu review juga dapat diputuskan oleh role tujuan eskalasi
+	if s.isEscalationTarget(ctx, userID, ref) {
+		return s.roleApproverActor(ctx, userID), nil
+	}
+
+	if stage.ApproverRole == model.ApproverRoleAdvisor {
+		lecturer, err := s.repo.GetLecturerByUserID(ctx, userID)
+		if err != nil {
+			return nil, errors.New("lecturer data not found for this user")
+		}
+
+		student, err := s.repo.GetStudentByID(ctx, ref.StudentID)
+		if err != nil {
+			return nil, errors.New("student data not found")
+		}
+
+		if student.AdvisorID == lecturer.ID {
+			return &approvalActor{VerifierID: lecturer.ID}, nil
+		}
+
+		// Dosen lain hanya boleh memutuskan selama delegasi dari dosen wali masih berlaku
+		delegation, err := s.delegations.FindActiveDelegation(ctx, student.AdvisorID, lecturer.ID, time.Now())
+		if err != nil {
+			return nil, errors.New("failed to check verification delegation")
+		}
+		if delegation == nil {
+			return nil, errors.New("unauthorized: you can only " + action + " achievements of your advisees")
+		}
+		return &approvalActor{
+			VerifierID:   lecturer.ID,
+			OnBehalfOf:   &delegation.DelegatorUserID,
+			DelegationID: &delegation.ID,
+		}, nil
+	}
+
+	roleName, err := s.repo.GetUserRoleName(ctx, userID)
+	if err != nil || !strings.EqualFold(roleName, stage.ApproverRole) {
+		return nil, errors.New("unauthorized: you are not the approver of the current approval stage")
+	}
+
+	// Approver berdasarkan role (mis. kepala departemen) hanya memutuskan prestasi mahasiswa departemennya
+	lecturer, ok := s.departmentApprover(ctx, userID, ref)
+	if !ok {
+		return nil, errors.New("unauthorized: you can only " + action + " achievements of students in your department")
+	}
+
+	return &approvalActor{VerifierID: lecturer.ID}, nil
+}
+
+// departmentApprover mengambil data dosen approver dan mengecek departemennya sama dengan program studi
+// mahasiswa pemilik prestasi. User tanpa data dosen tidak terikat departemen sehingga tidak lolos.
+func (s *achievementService) departmentApprover(ctx context.Context, userID uuid.UUID, ref *model.AchievementReference) (*model.Lecturers, bool) {
+	lecturer, err := s.repo.GetLecturerByUserID(ctx, userID)
+	if err != nil {
+		return nil, false
+	}
+
+	student, err := s.repo.GetStudentByID(ctx, ref.StudentID)
+	if err != nil {
+		return nil, false
+	}
+
+	department := strings.TrimSpace(lecturer.Department)
+	return lecturer, department != "" && strings.EqualFold(department, strings.TrimSpace(student.Program_Study))
+}
+
+// roleApproverActor membuat approvalActor untuk approver berdasarkan role (bukan dosen wali)
+func (s *achievementService) roleApproverActor(ctx context.Context, userID uuid.UUID) *approvalActor {
+	if lecturer, err := s.repo.GetLecturerByUserID(ctx, userID); err == nil {
+		return &approvalActor{VerifierID: lecturer.ID}
+	}
+	return &approvalActor{VerifierID: userID}
+}
+
+// isEscalationTarget mengecek apakah prestasi sudah dieskalasi ke role user
+func (s *achievementService) isEscalationTarget(ctx context.Context, userID uuid.UUID, ref *model.AchievementReference) bool {
+	if ref.EscalatedTo == nil {
+		return false
+	}
+
+	roleName, err := s.repo.GetUserRoleName(ctx, userID)
+	return err == nil && strings.EqualFold(roleName, *ref.EscalatedTo)
+}
+
+// isActiveDelegate mengecek apakah dosen sedang memegang delegasi hak verifikasi dari dosen wali
+func (s *achievementService) isActiveDelegate(ctx context.Context, advisorID, lecturerID uuid.UUID) bool {
+	delegation, err := s.delegations.FindActiveDelegation(ctx, advisorID, lecturerID, time.Now())
+	return err == nil && delegation != nil
+}
+
+// isCurrentStageApprover mengecek apakah user (selain dosen wali) adalah approver tahap yang sedang berjalan
+// atau tujuan eskalasi prestasi
+func (s *achievementService) isCurrentStageApprover(ctx context.Context, userID uuid.UUID, ref *model.AchievementReference) bool {
+	if ref.Status != "submitted" {
+		return false
+	}
+	if s.isEscalationTarget(ctx, userID, ref) {
+		return true
+	}
+
+	stage, _, err := s.currentApprovalStage(ctx, ref)
+	if err != nil || stage.ApproverRole == model.ApproverRoleAdvisor {
+		return false
+	}
+
+	roleName, err := s.repo.GetUserRoleName(ctx, userID)
+	if err != nil || !strings.EqualFold(roleName, stage.ApproverRole) {
+		return false
+	}
+
+	_, ok := s.departmentApprover(ctx, userID, ref)
+	return ok
+}
+
+// newApprovalDecision membuat keputusan untuk tahap yang sedang berjalan; note kosong tidak disimpan
+func newApprovalDecision(stage *model.AchievementApproval, status string, decidedBy uuid.UUID, actor *approvalActor, note string) model.ApprovalDecision {
+	decision := model.ApprovalDecision{
+		ApprovalID:   stage.ID,
+		Status:       status,
+		DecidedBy:    decidedBy,
+		DecidedAt:    time.Now(),
+		OnBehalfOf:   actor.OnBehalfOf,
+		DelegationID: actor.DelegationID,
+	}
+	if note = strings.TrimSpace(note); note != "" {
+		decision.Note = &note
+	}
+	return decision
+}
+
+// GetAchievementApprovals mengambil tahap persetujuan revision prestasi yang sedang berjalan
+func (s *achievementService) GetAchievementApprovals(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID) (*model.AchievementApprovalsResponse, error) {
+	ref, err := s.getAccessibleReference(ctx, userID, isAdmin, achievementID)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals, err := s.repo.GetAchievementApprovals(ctx, achievementID, ref.Revision)
+	if err != nil {
+		return nil, errors.New("failed to get approval stages")
+	}
+
+	result := &model.AchievementApprovalsResponse{
+		AchievementID: achievementID,
+		Status:        ref.Status,
+		Revision:      ref.Revision,
+		Stages:        approvals,
+	}
+	if ref.Status == "submitted" {
+		for _, approval := range approvals {
+			if approval.Status == "pending" {
+				stage := approval.StageOrder
+				result.CurrentStage = &stage
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// GetPendingApprovals mengambil tahap persetujuan yang sedang menunggu role user untuk mahasiswa departemennya,
+// ditambah tahap dosen wali yang didelegasikan ke user (dosen wali sendiri melihat prestasi bimbingannya
+// lewat GET /achievements)
+func (s *achievementService) GetPendingApprovals(ctx context.Context, userID uuid.UUID) ([]model.AchievementApproval, error) {
+	roleName, err := s.repo.GetUserRoleName(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user role not found")
+	}
+
+	lecturer, lecturerErr := s.repo.GetLecturerByUserID(ctx, userID)
+	department := ""
+	if lecturerErr == nil {
+		department = lecturer.Department
+	}
+
+	approvals, err := s.repo.GetPendingApprovalsByRole(ctx, roleName, department)
+	if err != nil {
+		return nil, errors.New("failed to get pending approvals")
+	}
+
+	if lecturerErr == nil {
+		delegated, err := s.delegations.GetDelegatedPendingApprovals(ctx, lecturer.ID, time.Now())
+		if err != nil {
+			return nil, errors.New("failed to get pending approvals")
+		}
+		approvals = append(approvals, delegated...)
+	}
+	return approvals, nil
+}
+
+// approvalErrorStatus memetakan error tahap persetujuan ke HTTP status code
+func approvalErrorStatus(err error) int {
+	switch {
+	case err.Error() == "lecturer data not found for this user", err.Error() == "achievement not found",
+		err.Error() == "student data not found", err.Error() == "user role not found":
+		return 404
+	case strings.HasPrefix(err.Error(), "unauthorized:"):
+		return 403
+	case err.Error() == "approval stage already decided":
+		return 409
+	case err.Error() == "achievement must be in 'submitted' status to verify",
+		err.Error() == "achievement must be in 'submitted' status to reject",
+		err.Error() == "rejection note is required":
+		return 400
+	default:
+		return 500
+	}
+}
+
+// mapApprovalRepoError menerjemahkan error repository saat menyimpan keputusan tahap persetujuan
+func mapApprovalRepoError(err error, fallback string) error {
+	if errors.Is(err, repository.ErrApprovalAlreadyDecided) {
+		return errors.New("approval stage already decided")
+	}
+	return errors.New(fallback)
+}
+
+// GetAchievementApprovalsEndpoint - GET /achievements/:id/approvals
+func (s *achievementService) GetAchievementApprovalsEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+
+	result, err := s.GetAchievementApprovals(c.Context(), userID, isAdminFromClaims(c), achievementID)
+	if err != nil {
+		return c.Status(approvalErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// GetPendingApprovalsEndpoint - GET /achievements/approvals/pending
+func (s *achievementService) GetPendingApprovalsEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	approvals, err := s.GetPendingApprovals(c.Context(), userID)
+	if err != nil {
+		return c.Status(approvalErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   approvals,
+	})
+}
@@ -0,0 +1,531 @@
+package service
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/helper"
+	"UASBE/storage"
+	"UASBE/utils"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSignedURLTTL = 15 * time.Minute
+	maxSignedURLTTL     = time.Hour
+)
+
+// attachmentPath adalah URL download attachment (butuh bearer token)
+func attachmentPath(achievementID uuid.UUID, attachmentID string) string {
+	return fmt.Sprintf("/api/v1/achievements/%s/attachments/%s", achievementID, attachmentID)
+}
+
+// signedAttachmentPath adalah URL publik yang hanya valid dengan signature
+func signedAttachmentPath(achievementID uuid.UUID, attachmentID string) string {
+	return fmt.Sprintf("/api/v1/public/attachments/%s/%s", achievementID, attachmentID)
+}
+
+// isAdminFromClaims mengecek apakah user memiliki permission admin (user:manage)
+func isAdminFromClaims(c *fiber.Ctx) bool {
+	claims, ok := c.Locals("user_info").(jwt.MapClaims)
+	if !ok {
+		return false
+	}
+
+	permissions, ok := claims["permissions"].([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, p := range permissions {
+		if p == "user:manage" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAchievementAccess memastikan user adalah pemilik, dosen wali pemilik, approver tahap yang sedang berjalan, atau admin
+func (s *achievementService) checkAchievementAccess(ctx context.Context, userID uuid.UUID, isAdmin bool, ref *model.AchievementReference) error {
+	if isAdmin {
+		return nil
+	}
+
+	student, err := s.repo.GetStudentByUserID(ctx, userID)
+	if err == nil && student.ID == ref.StudentID {
+		return nil
+	}
+
+	lecturer, err := s.repo.GetLecturerByUserID(ctx, userID)
+	if err == nil {
+		owner, err := s.repo.GetStudentByID(ctx, ref.StudentID)
+		if err == nil && owner.AdvisorID == lecturer.ID {
+			return nil
+		}
+		// Delegasi/pengganti dosen wali yang sedang berlaku
+		if err == nil && s.isActiveDelegate(ctx, owner.AdvisorID, lecturer.ID) {
+			return nil
+		}
+	}
+
+	// Approver tahap persetujuan yang sedang berjalan (mis. kepala departemen) perlu melihat prestasinya
+	if s.isCurrentStageApprover(ctx, userID, ref) {
+		return nil
+	}
+
+	return errors.New("unauthorized: you do not have access to this achievement")
+}
+
+// findAttachment mengambil reference dan attachment berdasarkan ID
+func (s *achievementService) findAttachment(ctx context.Context, achievementID uuid.UUID, attachmentID string) (*model.AchievementReference, *mongodb.Attachment, error) {
+	ref, err := s.repo.GetAchievementReferenceByID(ctx, achievementID)
+	if err != nil || ref.Status == "deleted" {
+		return nil, nil, errors.New("achievement not found")
+	}
+
+	achievement, err := s.repo.GetAchievementDetailFromMongo(ctx, ref.MongoAchievementID)
+	if err != nil {
+		return nil, nil, errors.New("achievement not found")
+	}
+
+	for i := range achievement.Attachments {
+		if achievement.Attachments[i].ID == attachmentID {
+			return ref, &achievement.Attachments[i], nil
+		}
+	}
+
+	return nil, nil, errors.New("attachment not found")
+}
+
+// openAttachment membuka file attachment dari storage
+func (s *achievementService) openAttachment(ctx context.Context, attachment *mongodb.Attachment) (io.ReadCloser, error) {
+	if s.storage == nil {
+		return nil, errors.New("attachment storage is not configured")
+	}
+
+	reader, _, err := s.storage.Open(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, errors.New("attachment not found")
+	}
+	if err != nil {
+		return nil, errors.New("failed to open attachment")
+	}
+	return reader, nil
+}
+
+// GetAttachment - Download attachment (pemilik, dosen wali, atau admin)
+func (s *achievementService) GetAttachment(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID, attachmentID string) (*mongodb.Attachment, io.ReadCloser, error) {
+	ref, attachment, err := s.findAttachment(ctx, achievementID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.checkAchievementAccess(ctx, userID, isAdmin, ref); err != nil {
+		return nil, nil, err
+	}
+
+	reader, err := s.openAttachment(ctx, attachment)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachment, reader, nil
+}
+
+// DeleteAttachment - Hapus attachment (hanya pemilik, status draft/rejected)
+func (s *achievementService) DeleteAttachment(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID, attachmentID string) error {
+	// 1. Get student data
+	student, err := s.repo.GetStudentByUserID(ctx, userID)
+	if err != nil {
+		return errors.New("student data not found for this user")
+	}
+
+	// 2. Get achievement reference by ID
+	ref, err := s.repo.GetAchievementReferenceByID(ctx, achievementID)
+	if err != nil || ref.Status == "deleted" {
+		return errors.New("achievement not found")
+	}
+
+	// 3. Check authorization - only owner can delete
+	if ref.StudentID != student.ID {
+		return errors.New("unauthorized: achievement does not belong to this student")
+	}
+
+	// 4. Check status
+	if !isEditableStatus(ref.Status) {
+		return errors.New("attachments can only be deleted from draft or rejected achievements")
+	}
+
+	// 5. Simpan versi baru (tanpa attachment) dan event outbox dalam satu transaksi Postgres;
+	// prestasi yang ditolak berpindah ke status 'revised'. Attachment dicari di versi terakhir,
+	// karena dokumen MongoDB bisa belum memuat attachment yang baru ditambahkan
+	snapshot, err := s.latestSnapshot(ctx, ref)
+	if err != nil {
+		return errors.New("achievement not found")
+	}
+	var removed *mongodb.Attachment
+	remaining := make([]mongodb.Attachment, 0, len(snapshot.Attachments))
+	for i, a := range snapshot.Attachments {
+		if a.ID == attachmentID {
+			removed = &snapshot.Attachments[i]
+			continue
+		}
+		remaining = append(remaining, a)
+	}
+	if removed == nil {
+		return errors.New("attachment not found")
+	}
+	snapshot.Attachments = remaining
+	snapshot.UpdatedAt = time.Now()
+
+	if err := s.repo.RemoveAttachmentFromAchievement(ctx, achievementID, *removed, *snapshot, userID, ref.Status == "rejected"); err != nil {
+		return errors.New("failed to delete attachment")
+	}
+
+	// Hapus dari MongoDB (disusulkan relay jika gagal); file dihapus outbox setelah attachment hilang dari dokumen
+	dispatchOrDefer(ctx, s.outbox, achievementID)
+
+	return nil
+}
+
+// CreateAttachmentSignedURL - Buat URL download sementara tanpa bearer token (untuk preview)
+func (s *achievementService) CreateAttachmentSignedURL(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID, attachmentID string, ttl time.Duration) (string, time.Time, error) {
+	ref, _, err := s.findAttachment(ctx, achievementID, attachmentID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if err := s.checkAchievementAccess(ctx, userID, isAdmin, ref); err != nil {
+		return "", time.Time{}, err
+	}
+
+	if ttl <= 0 {
+		ttl = defaultSignedURLTTL
+	}
+	if ttl > maxSignedURLTTL {
+		ttl = maxSignedURLTTL
+	}
+
+	url, expiresAt := utils.SignURL(signedAttachmentPath(achievementID, attachmentID), ttl)
+	return url, expiresAt, nil
+}
+
+// limitedReader menghentikan stream jika melebihi batas ukuran (ukuran dari client tidak dipercaya)
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		l.exceeded = true
+		return 0, errors.New("file too large")
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		l.exceeded = true
+		return n, errors.New("file too large")
+	}
+	return n, err
+}
+
+func fileTooLargeError(maxBytes int64) error {
+	return helper.NewAPIError(413, "FILE_TOO_LARGE", "File size too large", fiber.Map{
+		"maxBytes": maxBytes,
+	})
+}
+
+// checkAttachmentQuota memvalidasi ukuran file, jumlah & total ukuran attachment per prestasi,
+// dan kuota storage mahasiswa sebelum file disimpan
+func (s *achievementService) checkAttachmentQuota(ctx context.Context, ref *model.AchievementReference, size int64) error {
+	if size > utils.MaxAttachmentSize {
+		return fileTooLargeError(utils.MaxAttachmentSize)
+	}
+
+	achievement, err := s.repo.GetAchievementDetailFromMongo(ctx, ref.MongoAchievementID)
+	if err != nil {
+		return errors.New("achievement not found")
+	}
+
+	if len(achievement.Attachments) >= utils.MaxAttachmentsPerAchievement {
+		return helper.NewAPIError(422, "ATTACHMENT_LIMIT_EXCEEDED", "Maximum number of attachments reached", fiber.Map{
+			"maxAttachments": utils.MaxAttachmentsPerAchievement,
+		})
+	}
+
+	var achievementBytes int64
+	for _, a := range achievement.Attachments {
+		achievementBytes += a.Size
+	}
+	if achievementBytes+size > utils.MaxAttachmentBytesPerAchievement {
+		return helper.NewAPIError(413, "ACHIEVEMENT_QUOTA_EXCEEDED", "Attachment storage limit for this achievement exceeded", fiber.Map{
+			"maxBytes":  utils.MaxAttachmentBytesPerAchievement,
+			"usedBytes": achievementBytes,
+		})
+	}
+
+	quota, err := s.GetStudentStorageQuota(ctx, ref.StudentID)
+	if err != nil {
+		return err
+	}
+	if quota.UsedBytes+size > quota.QuotaBytes {
+		return helper.NewAPIError(413, "STUDENT_QUOTA_EXCEEDED", "Student storage quota exceeded", fiber.Map{
+			"quotaBytes": quota.QuotaBytes,
+			"usedBytes":  quota.UsedBytes,
+		})
+	}
+
+	return nil
+}
+
+// GetStudentStorageQuota - kuota storage attachment mahasiswa beserta pemakaiannya
+func (s *achievementService) GetStudentStorageQuota(ctx context.Context, studentID uuid.UUID) (*model.StudentStorageQuota, error) {
+	override, err := s.repo.GetStudentStorageQuota(ctx, studentID)
+	if err != nil {
+		return nil, errors.New("failed to get storage quota")
+	}
+
+	used, err := s.repo.GetStudentAttachmentUsage(ctx, studentID)
+	if err != nil {
+		return nil, errors.New("failed to get storage usage")
+	}
+
+	quota := &model.StudentStorageQuota{
+		StudentID:  studentID,
+		QuotaBytes: utils.DefaultStudentStorageQuota,
+		UsedBytes:  used,
+		IsDefault:  override == nil,
+	}
+	if override != nil {
+		quota.QuotaBytes = *override
+	}
+
+	return quota, nil
+}
+
+// SetStudentStorageQuota - Admin mengubah kuota storage mahasiswa
+func (s *achievementService) SetStudentStorageQuota(ctx context.Context, studentID uuid.UUID, quotaBytes int64) (*model.StudentStorageQuota, error) {
+	if quotaBytes < 0 {
+		return nil, errors.New("quota_bytes must not be negative")
+	}
+
+	if _, err := s.repo.GetStudentByID(ctx, studentID); err != nil {
+		return nil, errors.New("student not found")
+	}
+
+	if err := s.repo.SetStudentStorageQuota(ctx, studentID, quotaBytes); err != nil {
+		return nil, errors.New("failed to update storage quota")
+	}
+
+	return s.GetStudentStorageQuota(ctx, studentID)
+}
+
+// attachmentErrorStatus memetakan error attachment ke HTTP status code
+func attachmentErrorStatus(err error) int {
+	switch err.Error() {
+	case "student data not found for this user", "achievement not found", "attachment not found":
+		return 404
+	case "unauthorized: you do not have access to this achievement",
+		"unauthorized: achievement does not belong to this student":
+		return 403
+	case "attachments can only be deleted from draft or rejected achievements":
+		return 400
+	default:
+		return 500
+	}
+}
+
+// sendAttachment men-stream isi attachment ke response
+func sendAttachment(c *fiber.Ctx, attachment *mongodb.Attachment, reader io.ReadCloser, disposition string) error {
+	if attachment.FileType != "" {
+		c.Set(fiber.HeaderContentType, attachment.FileType)
+	}
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("%s; filename=%q", disposition, attachment.FileName))
+	if attachment.Checksum != "" {
+		c.Set(fiber.HeaderETag, `"`+attachment.Checksum+`"`)
+	}
+
+	size := -1
+	if attachment.Size > 0 {
+		size = int(attachment.Size)
+	}
+	return c.SendStream(reader, size)
+}
+
+// GetAttachmentEndpoint - GET /achievements/:id/attachments/:attachmentId
+func (s *achievementService) GetAttachmentEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+
+	attachment, reader, err := s.GetAttachment(c.Context(), userID, isAdminFromClaims(c), achievementID, c.Params("attachmentId"))
+	if err != nil {
+		status := attachmentErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to download attachment"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return sendAttachment(c, attachment, reader, "attachment")
+}
+
+// DeleteAttachmentEndpoint - DELETE /achievements/:id/attachments/:attachmentId
+func (s *achievementService) DeleteAttachmentEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+
+	err = s.DeleteAttachment(c.Context(), userID, achievementID, c.Params("attachmentId"))
+	if err != nil {
+		status := attachmentErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to delete attachment"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Attachment deleted successfully",
+	})
+}
+
+// CreateAttachmentSignedURLEndpoint - POST /achievements/:id/attachments/:attachmentId/signed-url?ttl=900
+func (s *achievementService) CreateAttachmentSignedURLEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+
+	ttl := time.Duration(c.QueryInt("ttl", 0)) * time.Second
+
+	url, expiresAt, err := s.CreateAttachmentSignedURL(c.Context(), userID, isAdminFromClaims(c), achievementID, c.Params("attachmentId"), ttl)
+	if err != nil {
+		status := attachmentErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to create signed URL"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"url":       url,
+			"expiresAt": expiresAt,
+		},
+	})
+}
+
+// GetSignedAttachmentEndpoint - GET /public/attachments/:id/:attachmentId?expires=&signature=
+func (s *achievementService) GetSignedAttachmentEndpoint(c *fiber.Ctx) error {
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+	attachmentID := c.Params("attachmentId")
+
+	err = utils.VerifySignedURL(signedAttachmentPath(achievementID, attachmentID), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	_, attachment, err := s.findAttachment(c.Context(), achievementID, attachmentID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	reader, err := s.openAttachment(c.Context(), attachment)
+	if err != nil {
+		status := attachmentErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to download attachment"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return sendAttachment(c, attachment, reader, "inline")
+}
+
+// GetStudentStorageQuotaEndpoint - GET /admin/students/:id/storage-quota
+func (s *achievementService) GetStudentStorageQuotaEndpoint(c *fiber.Ctx) error {
+	studentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid student ID format"})
+	}
+
+	if _, err := s.repo.GetStudentByID(c.Context(), studentID); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "student not found"})
+	}
+
+	quota, err := s.GetStudentStorageQuota(c.Context(), studentID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   quota,
+	})
+}
+
+// SetStudentStorageQuotaEndpoint - PUT /admin/students/:id/storage-quota
+func (s *achievementService) SetStudentStorageQuotaEndpoint(c *fiber.Ctx) error {
+	studentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid student ID format"})
+	}
+
+	var req model.UpdateStorageQuotaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	quota, err := s.SetStudentStorageQuota(c.Context(), studentID, req.QuotaBytes)
+	if err != nil {
+		switch err.Error() {
+		case "quota_bytes must not be negative":
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		case "student not found":
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"data":    quota,
+		"message": "Storage quota updated successfully",
+	})
+}
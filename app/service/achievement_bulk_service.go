@@ -0,0 +1,150 @@
+package service
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// maxBulkAchievementItems membatasi jumlah prestasi per permintaan aksi massal
+const maxBulkAchievementItems = 100
+
+// BulkVerifyAchievements memverifikasi banyak prestasi sekaligus. Setiap item melewati pengecekan
+// yang sama dengan VerifyAchievement dan diproses dalam transaksinya sendiri, sehingga kegagalan
+// satu item tidak membatalkan item lain.
+func (s *achievementService) BulkVerifyAchievements(ctx context.Context, userID uuid.UUID, req model.BulkAchievementActionRequest) (*model.BulkAchievementActionResponse, error) {
+	ids, err := parseBulkAchievementIDs(req.AchievementIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return runBulkAchievementAction(ids, func(id uuid.UUID) (*model.AchievementReference, error) {
+		return s.VerifyAchievement(ctx, userID, id, req.Note)
+	}), nil
+}
+
+// BulkRejectAchievements menolak banyak prestasi sekaligus dengan satu catatan penolakan bersama
+func (s *achievementService) BulkRejectAchievements(ctx context.Context, userID uuid.UUID, req model.BulkAchievementActionRequest) (*model.BulkAchievementActionResponse, error) {
+	note := strings.TrimSpace(req.Note)
+	if note == "" {
+		return nil, errors.New("rejection note is required")
+	}
+
+	ids, err := parseBulkAchievementIDs(req.AchievementIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return runBulkAchievementAction(ids, func(id uuid.UUID) (*model.AchievementReference, error) {
+		return s.RejectAchievement(ctx, userID, id, note)
+	}), nil
+}
+
+// bulkAchievementID adalah satu item permintaan aksi massal; ID nil berarti format ID tidak valid
+type bulkAchievementID struct {
+	raw string
+	id  uuid.UUID
+}
+
+// parseBulkAchievementIDs memvalidasi jumlah item dan membuang ID duplikat (urutan dipertahankan).
+// ID yang formatnya salah tetap diteruskan agar dilaporkan sebagai item gagal.
+func parseBulkAchievementIDs(rawIDs []string) ([]bulkAchievementID, error) {
+	if len(rawIDs) == 0 || len(rawIDs) > maxBulkAchievementItems {
+		return nil, errors.New("achievement_ids must contain between 1 and 100 items")
+	}
+
+	seen := make(map[string]bool, len(rawIDs))
+	ids := make([]bulkAchievementID, 0, len(rawIDs))
+	for _, raw := range rawIDs {
+		raw = strings.TrimSpace(raw)
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			id = uuid.Nil
+		}
+
+		key := raw
+		if id != uuid.Nil {
+			key = id.String()
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		ids = append(ids, bulkAchievementID{raw: raw, id: id})
+	}
+	return ids, nil
+}
+
+// runBulkAchievementAction menjalankan aksi per item secara berurutan dan mengumpulkan hasilnya
+func runBulkAchievementAction(ids []bulkAchievementID, action func(id uuid.UUID) (*model.AchievementReference, error)) *model.BulkAchievementActionResponse {
+	response := &model.BulkAchievementActionResponse{
+		Total:   len(ids),
+		Results: make([]model.BulkAchievementItemResult, 0, len(ids)),
+	}
+
+	for _, item := range ids {
+		result := model.BulkAchievementItemResult{AchievementID: item.raw}
+
+		if item.id == uuid.Nil {
+			msg := "Invalid achievement ID format"
+			result.Error = &msg
+			result.Code = 400
+		} else if ref, err := action(item.id); err != nil {
+			msg := err.Error()
+			result.Error = &msg
+			result.Code = approvalErrorStatus(err)
+		} else {
+			result.Success = true
+			result.Status = &ref.Status
+			result.Code = 200
+		}
+
+		if result.Success {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	return response
+}
+
+// bulkAchievementActionEndpoint membaca body aksi massal dan mengembalikan laporan per item
+func (s *achievementService) bulkAchievementActionEndpoint(c *fiber.Ctx, action func(ctx context.Context, userID uuid.UUID, req model.BulkAchievementActionRequest) (*model.BulkAchievementActionResponse, error), verb string) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req model.BulkAchievementActionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+
+	result, err := action(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Bulk " + verb + " processed",
+		"data":    result,
+	})
+}
+
+// BulkVerifyAchievementsEndpoint - POST /achievements/bulk/verify
+func (s *achievementService) BulkVerifyAchievementsEndpoint(c *fiber.Ctx) error {
+	return s.bulkAchievementActionEndpoint(c, s.BulkVerifyAchievements, "verification")
+}
+
+// BulkRejectAchievementsEndpoint - POST /achievements/bulk/reject
+func (s *achievementService) BulkRejectAchievementsEndpoint(c *fiber.Ctx) error {
+	return s.bulkAchievementActionEndpoint(c, s.BulkRejectAchievements, "rejection")
+}
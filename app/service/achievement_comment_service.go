@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	model "UASBE/app/model/Postgresql"
+	"UASBE/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// maxCommentBodyLength membatasi panjang isi komentar (karakter)
+const maxCommentBodyLength = 2000
+
+// GetAchievementComments mengambil thread komentar prestasi (pemilik, dosen wali, approver, admin)
+func (s *achievementService) GetAchievementComments(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID) (*model.AchievementCommentsResponse, error) {
+	if _, err := s.getAccessibleReference(ctx, userID, isAdmin, achievementID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.GetAchievementComments(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("failed to get achievement comments")
+	}
+
+	// Susun balasan di bawah komentar utamanya
+	threads := []model.AchievementComment{}
+	index := make(map[uuid.UUID]int)
+	for _, c := range comments {
+		if c.ParentID == nil {
+			index[c.ID] = len(threads)
+			threads = append(threads, c)
+		}
+	}
+	for _, c := range comments {
+		if c.ParentID == nil {
+			continue
+		}
+		if i, ok := index[*c.ParentID]; ok {
+			threads[i].Replies = append(threads[i].Replies, c)
+		}
+	}
+	unresolved := 0
+	for _, t := range threads {
+		if !t.IsResolved {
+			unresolved++
+		}
+	}
+
+	return &model.AchievementCommentsResponse{
+		AchievementID: achievementID,
+		Unresolved:    unresolved,
+		Threads:       threads,
+	}, nil
+}
+
+// AddAchievementComment menulis komentar utama (opsional menunjuk satu field atau attachment) atau balasan thread
+func (s *achievementService) AddAchievementComment(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID, req model.CreateAchievementCommentRequest) (*model.AchievementComment, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, errors.New("comment body is required")
+	}
+	if utf8.RuneCountInString(body) > maxCommentBodyLength {
+		return nil, errors.New("comment body must be at most 2000 characters")
+	}
+
+	fieldPath := trimOptional(req.FieldPath)
+	attachmentID := trimOptional(req.AttachmentID)
+	if fieldPath != nil && attachmentID != nil {
+		return nil, errors.New("comment can reference either a field or an attachment, not both")
+	}
+	if fieldPath != nil && !utils.IsAchievementFieldPath(*fieldPath) {
+		return nil, errors.New("invalid field_path")
+	}
+
+	ref, err := s.getAccessibleReference(ctx, userID, isAdmin, achievementID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := model.AchievementComment{
+		ID:            uuid.New(),
+		AchievementID: achievementID,
+		AuthorID:      userID,
+		Body:          body,
+		FieldPath:     fieldPath,
+		AttachmentID:  attachmentID,
+		CreatedAt:     time.Now(),
+	}
+
+	// Balasan mengikuti field/attachment komentar utamanya
+	if parentID := trimOptional(req.ParentID); parentID != nil {
+		id, err := uuid.Parse(*parentID)
+		if err != nil {
+			return nil, errors.New("invalid parent_id format")
+		}
+		if fieldPath != nil || attachmentID != nil {
+			return nil, errors.New("replies cannot reference a field or attachment")
+		}
+		parent, err := s.getAchievementComment(ctx, achievementID, id)
+		if err != nil {
+			return nil, err
+		}
+		if parent.ParentID != nil {
+			return nil, errors.New("replies can only be posted to top-level comments")
+		}
+		comment.ParentID = &parent.ID
+	}
+
+	if attachmentID != nil {
+		if _, _, err := s.findAttachment(ctx, ref.ID, *attachmentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.CreateAchievementComment(ctx, comment); err != nil {
+		return nil, errors.New("failed to create achievement comment")
+	}
+
+	return s.getAchievementComment(ctx, achievementID, comment.ID)
+}
+
+// SetAchievementCommentResolved menandai thread komentar selesai atau membukanya kembali.
+// Semua pihak yang dapat melihat prestasi boleh mengubah status thread.
+func (s *achievementService) SetAchievementCommentResolved(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID, commentID uuid.UUID, resolved bool) (*model.AchievementComment, error) {
+	if _, err := s.getAccessibleReference(ctx, userID, isAdmin, achievementID); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.getAchievementComment(ctx, achievementID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.ParentID != nil {
+		return nil, errors.New("only top-level comments can be resolved")
+	}
+
+	if comment.IsResolved != resolved {
+		if err := s.repo.SetAchievementCommentResolved(ctx, commentID, resolved, userID, time.Now()); err != nil {
+			return nil, errors.New("failed to update achievement comment")
+		}
+	}
+
+	return s.getAchievementComment(ctx, achievementID, commentID)
+}
+
+// getAchievementComment mengambil komentar yang memang milik prestasi tersebut
+func (s *achievementService) getAchievementComment(ctx context.Context, achievementID, commentID uuid.UUID) (*model.AchievementComment, error) {
+	comment, err := s.repo.GetAchievementCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, errors.New("failed to get achievement comment")
+	}
+	if comment == nil || comment.AchievementID != achievementID {
+		return nil, errors.New("comment not found")
+	}
+	return comment, nil
+}
+
+// trimOptional mengembalikan nil untuk string opsional yang kosong
+func trimOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// achievementCommentErrorStatus memetakan error komentar ke HTTP status code
+func achievementCommentErrorStatus(err error) int {
+	switch {
+	case err.Error() == "achievement not found", err.Error() == "comment not found", err.Error() == "attachment not found":
+		return 404
+	case strings.HasPrefix(err.Error(), "unauthorized:"):
+		return 403
+	case err.Error() == "comment body is required", err.Error() == "comment body must be at most 2000 characters",
+		err.Error() == "comment can reference either a field or an attachment, not both",
+		err.Error() == "invalid field_path", err.Error() == "invalid parent_id format",
+		err.Error() == "replies cannot reference a field or attachment",
+		err.Error() == "replies can only be posted to top-level comments",
+		err.Error() == "only top-level comments can be resolved":
+		return 400
+	default:
+		return 500
+	}
+}
+
+// GetAchievementCommentsEndpoint - GET /achievements/:id/comments
+func (s *achievementService) GetAchievementCommentsEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+
+	result, err := s.GetAchievementComments(c.Context(), userID, isAdminFromClaims(c), achievementID)
+	if err != nil {
+		return c.Status(achievementCommentErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// AddAchievementCommentEndpoint - POST /achievements/:id/comments
+func (s *achievementService) AddAchievementCommentEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+
+	var req model.CreateAchievementCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+
+	comment, err := s.AddAchievementComment(c.Context(), userID, isAdminFromClaims(c), achievementID, req)
+	if err != nil {
+		return c.Status(achievementCommentErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Comment posted successfully",
+		"data":    comment,
+	})
+}
+
+// ResolveAchievementCommentEndpoint - POST /achievements/:id/comments/:commentId/resolve
+func (s *achievementService) ResolveAchievementCommentEndpoint(c *fiber.Ctx) error {
+	return s.setAchievementCommentResolvedEndpoint(c, true)
+}
+
+// ReopenAchievementCommentEndpoint - POST /achievements/:id/comments/:commentId/reopen
+func (s *achievementService) ReopenAchievementCommentEndpoint(c *fiber.Ctx) error {
+	return s.setAchievementCommentResolvedEndpoint(c, false)
+}
+
+func (s *achievementService) setAchievementCommentResolvedEndpoint(c *fiber.Ctx, resolved bool) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+	commentID, err := uuid.Parse(c.Params("commentId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid comment ID format"})
+	}
+
+	comment, err := s.SetAchievementCommentResolved(c.Context(), userID, isAdminFromClaims(c), achievementID, commentID, resolved)
+	if err != nil {
+		return c.Status(achievementCommentErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	message := "Comment thread resolved"
+	if !resolved {
+		message = "Comment thread reopened"
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": message,
+		"data":    comment,
+	})
+}
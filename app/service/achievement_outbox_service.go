@@ -0,0 +1,246 @@
+package service
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/storage"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	// outboxRelayBatchSize membatasi jumlah event yang dibaca per jalan relay
+	outboxRelayBatchSize = 500
+	// outboxMaxBackoff membatasi jeda retry event yang terus gagal
+	outboxMaxBackoff = time.Hour
+	// outboxRetention adalah lama event yang sudah diterapkan disimpan sebelum dihapus
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// AchievementOutboxService menerapkan event achievement_outbox ke MongoDB. Perubahan prestasi ditulis ke
+// PostgreSQL (reference + event) dalam satu transaksi, lalu dokumen MongoDB disusulkan: langsung setelah commit
+// lewat DispatchAchievement, dan oleh relay berkala untuk event yang gagal.
+type AchievementOutboxService interface {
+	// Business logic methods
+	DispatchAchievement(ctx context.Context, achievementID uuid.UUID) error
+	RelayPendingEvents(ctx context.Context) (*model.OutboxRelayResult, error)
+	GetPendingEvents(ctx context.Context) ([]model.AchievementOutboxEvent, error)
+	StartRelay(interval time.Duration)
+
+	// HTTP endpoints
+	GetPendingEventsEndpoint(c *fiber.Ctx) error
+	RelayPendingEventsEndpoint(c *fiber.Ctx) error
+}
+
+// Event satu prestasi hanya diterapkan oleh pemegang advisory lock prestasi tersebut (WithAchievementOutboxLock),
+// sehingga dispatch request dan relay di semua instance tidak menerapkannya bersamaan atau keluar urutan.
+type achievementOutboxService struct {
+	repo    repository.AchievementOutboxRepository
+	storage storage.Storage
+}
+
+func NewAchievementOutboxService(repo repository.AchievementOutboxRepository, store storage.Storage) AchievementOutboxService {
+	return &achievementOutboxService{repo: repo, storage: store}
+}
+
+// outboxBackoff menghitung jeda sebelum percobaan berikutnya: 30 detik, dilipatgandakan tiap kegagalan
+func outboxBackoff(attempts int) time.Duration {
+	backoff := 30 * time.Second
+	for i := 0; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+	return backoff
+}
+
+// applyEvent menerapkan satu event lalu menandainya processed; kegagalan dicatat beserta jadwal retry.
+// Jika penandaan gagal setelah dokumen ditulis, event diterapkan ulang nanti (operasinya idempotent).
+func (s *achievementOutboxService) applyEvent(ctx context.Context, event model.AchievementOutboxEvent) error {
+	err := s.repo.ApplyOutboxEvent(ctx, event)
+	if err == nil {
+		err = s.releaseRemovedAttachmentFile(ctx, event)
+	}
+	if err != nil {
+		next := time.Now().Add(outboxBackoff(event.Attempts))
+		if markErr := s.repo.MarkOutboxEventFailed(ctx, event.ID, err.Error(), next); markErr != nil {
+			log.Printf("⚠️ Failed recording outbox event %d failure: %v", event.ID, markErr)
+		}
+		return err
+	}
+	return s.repo.MarkOutboxEventProcessed(ctx, event.ID, time.Now())
+}
+
+// releaseRemovedAttachmentFile menghapus file attachment setelah event remove_attachment diterapkan, hanya jika
+// tidak dipakai dokumen lain (storage content-addressed). Kegagalan membuat event dicoba ulang sehingga file tidak
+// tertinggal selamanya.
+func (s *achievementOutboxService) releaseRemovedAttachmentFile(ctx context.Context, event model.AchievementOutboxEvent) error {
+	key := repository.RemovedAttachmentStorageKey(event)
+	if s.storage == nil || key == "" {
+		return nil
+	}
+	count, err := s.repo.CountAttachmentsByStorageKey(ctx, key)
+	if err != nil || count > 0 {
+		return err
+	}
+	return s.storage.Delete(ctx, key)
+}
+
+// applyAchievementEvents menerapkan event satu prestasi berurutan. Berhenti pada event pertama yang gagal,
+// atau yang belum jatuh tempo jika onlyDue, agar event sesudahnya tidak diterapkan lebih dulu.
+func (s *achievementOutboxService) applyAchievementEvents(ctx context.Context, events []model.AchievementOutboxEvent, onlyDue bool, result *model.OutboxRelayResult) error {
+	now := time.Now()
+	for i, event := range events {
+		if onlyDue && event.NextAttemptAt.After(now) {
+			result.Deferred += len(events) - i
+			return nil
+		}
+
+		if err := s.applyEvent(ctx, event); err != nil {
+			result.Failed++
+			result.Deferred += len(events) - i - 1
+			return err
+		}
+		result.Processed++
+	}
+	return nil
+}
+
+// DispatchAchievement menerapkan semua event tertunda satu prestasi secara berurutan, tanpa menunggu jadwal retry.
+// Jika event prestasi sedang diterapkan worker lain, sisanya disusulkan relay.
+func (s *achievementOutboxService) DispatchAchievement(ctx context.Context, achievementID uuid.UUID) error {
+	locked, err := s.repo.WithAchievementOutboxLock(ctx, achievementID, func() error {
+		events, err := s.repo.GetPendingOutboxEventsByAchievement(ctx, achievementID)
+		if err != nil {
+			return err
+		}
+		return s.applyAchievementEvents(ctx, events, false, &model.OutboxRelayResult{})
+	})
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return errors.New("achievement outbox events are being applied by another worker")
+	}
+	return nil
+}
+
+// RelayPendingEvents menerapkan event tertunda yang sudah jatuh tempo, berurutan per prestasi.
+// Event sebuah prestasi ditunda jika event sebelumnya untuk prestasi yang sama belum berhasil,
+// atau jika event prestasi itu sedang diterapkan worker lain.
+func (s *achievementOutboxService) RelayPendingEvents(ctx context.Context) (*model.OutboxRelayResult, error) {
+	events, err := s.repo.GetPendingOutboxEvents(ctx, outboxRelayBatchSize)
+	if err != nil {
+		return nil, errors.New("failed to get pending outbox events")
+	}
+
+	// Kelompokkan per prestasi, urut sesuai event pertamanya
+	var order []uuid.UUID
+	pending := map[uuid.UUID][]model.AchievementOutboxEvent{}
+	for _, event := range events {
+		if _, found := pending[event.AchievementID]; !found {
+			order = append(order, event.AchievementID)
+		}
+		pending[event.AchievementID] = append(pending[event.AchievementID], event)
+	}
+
+	result := &model.OutboxRelayResult{}
+	now := time.Now()
+	for _, achievementID := range order {
+		batch := pending[achievementID]
+		if batch[0].NextAttemptAt.After(now) {
+			result.Deferred += len(batch)
+			continue
+		}
+
+		locked, err := s.repo.WithAchievementOutboxLock(ctx, achievementID, func() error {
+			// Dibaca ulang setelah lock didapat: sebagian event mungkin sudah diterapkan worker lain
+			current, err := s.repo.GetPendingOutboxEventsByAchievement(ctx, achievementID)
+			if err != nil {
+				result.Deferred += len(batch)
+				return err
+			}
+			return s.applyAchievementEvents(ctx, current, true, result)
+		})
+		if err != nil {
+			log.Printf("⚠️ Failed applying outbox events for achievement %s: %v", achievementID, err)
+			continue
+		}
+		if !locked {
+			result.Deferred += len(batch)
+		}
+	}
+
+	return result, nil
+}
+
+// GetPendingEvents mengambil event yang belum diterapkan ke MongoDB (untuk pemantauan admin)
+func (s *achievementOutboxService) GetPendingEvents(ctx context.Context) ([]model.AchievementOutboxEvent, error) {
+	events, err := s.repo.GetPendingOutboxEvents(ctx, outboxRelayBatchSize)
+	if err != nil {
+		return nil, errors.New("failed to get pending outbox events")
+	}
+	return events, nil
+}
+
+// StartRelay menjalankan RelayPendingEvents secara berkala dan membersihkan event lama yang sudah diterapkan
+func (s *achievementOutboxService) StartRelay(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx := context.Background()
+		result, err := s.RelayPendingEvents(ctx)
+		if err != nil {
+			log.Printf("⚠️ Failed relaying outbox events: %v", err)
+			continue
+		}
+		if result.Processed > 0 || result.Failed > 0 {
+			log.Printf("📤 Outbox relay: %d applied, %d failed, %d deferred", result.Processed, result.Failed, result.Deferred)
+		}
+
+		if _, err := s.repo.DeleteProcessedOutboxEvents(ctx, time.Now().Add(-outboxRetention)); err != nil {
+			log.Printf("⚠️ Failed cleaning up outbox events: %v", err)
+		}
+	}
+}
+
+// dispatchOrDefer menyusulkan perubahan dokumen MongoDB setelah transaksi PostgreSQL commit.
+// Kegagalan tidak menggagalkan request: event tetap tercatat dan diterapkan ulang oleh relay.
+func dispatchOrDefer(ctx context.Context, outbox AchievementOutboxService, achievementID uuid.UUID) {
+	if err := outbox.DispatchAchievement(ctx, achievementID); err != nil {
+		log.Printf("⚠️ MongoDB sync for achievement %s deferred to outbox relay: %v", achievementID, err)
+	}
+}
+
+// GetPendingEventsEndpoint - GET /admin/outbox
+func (s *achievementOutboxService) GetPendingEventsEndpoint(c *fiber.Ctx) error {
+	events, err := s.GetPendingEvents(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   events,
+	})
+}
+
+// RelayPendingEventsEndpoint - POST /admin/outbox/relay
+func (s *achievementOutboxService) RelayPendingEventsEndpoint(c *fiber.Ctx) error {
+	result, err := s.RelayPendingEvents(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
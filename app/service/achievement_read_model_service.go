@@ -0,0 +1,77 @@
+package service
+
+import (
+	"UASBE/app/repository"
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// readModelBackfillBatchSize membatasi jumlah reference yang diisi per batch backfill
+const readModelBackfillBatchSize = 500
+
+// AchievementReadModelService mengisi achievement_read_model untuk prestasi yang belum tersinkron.
+// Setiap penulisan isi prestasi sudah memperbarui read model dalam transaksinya sendiri; backfill hanya
+// dibutuhkan untuk data lama.
+type AchievementReadModelService interface {
+	// Business logic methods
+	BackfillReadModel(ctx context.Context) (int, error)
+	StartBackfill()
+
+	// HTTP endpoints
+	BackfillReadModelEndpoint(c *fiber.Ctx) error
+}
+
+type achievementReadModelService struct {
+	repo repository.AchievementReadModelRepository
+}
+
+func NewAchievementReadModelService(repo repository.AchievementReadModelRepository) AchievementReadModelService {
+	return &achievementReadModelService{repo: repo}
+}
+
+// BackfillReadModel mengisi read model per batch sampai semua reference punya baris; mengembalikan jumlah yang diisi
+func (s *achievementReadModelService) BackfillReadModel(ctx context.Context) (int, error) {
+	filled := 0
+	for {
+		refs, err := s.repo.GetReferencesWithoutReadModel(ctx, readModelBackfillBatchSize)
+		if err != nil {
+			return filled, errors.New("failed to get achievements without read model")
+		}
+		if len(refs) == 0 {
+			return filled, nil
+		}
+
+		if err := s.repo.SaveReadModelFromDocuments(ctx, refs); err != nil {
+			return filled, errors.New("failed to save achievement read model")
+		}
+		filled += len(refs)
+	}
+}
+
+// StartBackfill menjalankan backfill sekali saat aplikasi mulai
+func (s *achievementReadModelService) StartBackfill() {
+	filled, err := s.BackfillReadModel(context.Background())
+	if err != nil {
+		log.Printf("⚠️ Failed backfilling achievement read model after %d achievement(s): %v", filled, err)
+		return
+	}
+	if filled > 0 {
+		log.Printf("📇 Achievement read model backfilled for %d achievement(s)", filled)
+	}
+}
+
+// BackfillReadModelEndpoint - POST /admin/read-model/backfill
+func (s *achievementReadModelService) BackfillReadModelEndpoint(c *fiber.Ctx) error {
+	filled, err := s.BackfillReadModel(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   fiber.Map{"backfilled": filled},
+	})
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	model "UASBE/app/model/Postgresql"
+	"UASBE/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// isEditableStatus: isi prestasi hanya bisa diubah saat draft atau setelah ditolak (rejected/revised)
+func isEditableStatus(status string) bool {
+	return status == "draft" || status == "rejected" || status == "revised"
+}
+
+// GetAchievementChanges membandingkan isi prestasi saat ini dengan snapshot penolakan terakhir,
+// agar reviewer bisa melihat apa saja yang diperbaiki sejak catatan penolakan ditulis
+func (s *achievementService) GetAchievementChanges(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID) (*model.AchievementChangesResponse, error) {
+	ref, err := s.getAccessibleReference(ctx, userID, isAdmin, achievementID)
+	if err != nil {
+		return nil, err
+	}
+
+	rejection, err := s.repo.GetLatestAchievementRejection(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("failed to get achievement changes")
+	}
+	if rejection == nil {
+		return nil, errors.New("achievement has never been rejected")
+	}
+
+	current, err := s.repo.GetAchievementDetailFromMongo(ctx, ref.MongoAchievementID)
+	if err != nil {
+		return nil, errors.New("achievement not found")
+	}
+
+	changes, err := utils.DiffAchievements(rejection.Snapshot, *current)
+	if err != nil {
+		return nil, errors.New("failed to get achievement changes")
+	}
+
+	return &model.AchievementChangesResponse{
+		AchievementID: achievementID,
+		Status:        ref.Status,
+		Revision:      ref.Revision,
+		RejectionNote: rejection.RejectionNote,
+		RejectedBy:    rejection.RejectedBy,
+		RejectedAt:    rejection.RejectedAt,
+		Changes:       changes,
+	}, nil
+}
+
+// GetAchievementChangesEndpoint - GET /achievements/:id/changes
+func (s *achievementService) GetAchievementChangesEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+
+	result, err := s.GetAchievementChanges(c.Context(), userID, isAdminFromClaims(c), achievementID)
+	if err != nil {
+		switch err.Error() {
+		case "achievement not found", "achievement has never been rejected":
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		case "unauthorized: you do not have access to this achievement":
+			return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to get achievement changes"})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// calculateScore menghitung skor prestasi dengan satu versi aturan skor.
+// Poin dasar diambil dari type_points versi tersebut agar recalculation dengan versi lama tetap sama
+// walau default_points di katalog berubah. Hanya tipe yang ditambahkan setelah versi dibuat yang memakai
+// default_points katalog; tipe yang tidak ada di katalog bernilai 0.
+func calculateScore(ctx context.Context, typeRepo repository.AchievementTypeRepository, ruleSet *model.ScoringRuleSet, achievementID uuid.UUID, achievement *mongodb.Achievement, calculatedBy *uuid.UUID) (model.AchievementScore, error) {
+	code := utils.NormalizeAchievementType(achievement.AchievementType)
+
+	basePoints, ok := ruleSet.Rules.TypePoints[code]
+	if !ok {
+		achievementType, err := typeRepo.GetAchievementTypeByCode(ctx, code)
+		if err != nil {
+			return model.AchievementScore{}, errors.New("failed to calculate achievement score")
+		}
+		if achievementType != nil {
+			basePoints = achievementType.DefaultPoints
+		}
+	}
+
+	points, breakdown := utils.CalculateAchievementScore(*achievement, basePoints, ruleSet.Rules)
+
+	return model.AchievementScore{
+		AchievementID: achievementID,
+		RuleVersion:   ruleSet.Version,
+		Points:        points,
+		Breakdown:     breakdown,
+		CalculatedBy:  calculatedBy,
+		CalculatedAt:  time.Now(),
+	}, nil
+}
+
+// scoreForVerification menghitung skor prestasi dengan aturan skor yang sedang aktif. Isi prestasi diambil dari
+// versi terakhir di PostgreSQL agar perubahan yang masih tertunda di outbox ikut terhitung.
+func (s *achievementService) scoreForVerification(ctx context.Context, ref *model.AchievementReference, verifiedBy uuid.UUID) (model.AchievementScore, error) {
+	achievement, err := s.latestSnapshot(ctx, ref)
+	if err != nil {
+		return model.AchievementScore{}, errors.New("achievement not found")
+	}
+
+	ruleSet, err := s.scoringRepo.GetActiveScoringRuleSet(ctx)
+	if err != nil {
+		return model.AchievementScore{}, errors.New("failed to calculate achievement score")
+	}
+	if ruleSet == nil {
+		return model.AchievementScore{}, errors.New("no active scoring rules")
+	}
+
+	return calculateScore(ctx, s.typeRepo, ruleSet, ref.ID, achievement, &verifiedBy)
+}
+
+// GetAchievementScore mengambil skor dan breakdown prestasi (pemilik, dosen wali, atau admin)
+func (s *achievementService) GetAchievementScore(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID) (*model.AchievementScore, error) {
+	if _, err := s.getAccessibleReference(ctx, userID, isAdmin, achievementID); err != nil {
+		return nil, err
+	}
+
+	score, err := s.scoringRepo.GetAchievementScore(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("failed to get achievement score")
+	}
+	if score == nil {
+		return nil, errors.New("achievement has not been scored")
+	}
+
+	return score, nil
+}
+
+// GetAchievementScoreEndpoint - GET /achievements/:id/score
+func (s *achievementService) GetAchievementScoreEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+
+	score, err := s.GetAchievementScore(c.Context(), userID, isAdminFromClaims(c), achievementID)
+	if err != nil {
+		switch err.Error() {
+		case "achievement not found", "achievement has not been scored":
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		case "unauthorized: you do not have access to this achievement":
+			return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to get achievement score"})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   score,
+	})
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/utils"
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// maxSearchQueryLength membatasi panjang query pencarian
+const maxSearchQueryLength = 200
+
+// searchScope menentukan mahasiswa yang prestasinya boleh dicari user: admin semua (nil),
+// mahasiswa miliknya sendiri, dosen wali mahasiswa bimbingannya
+func (s *achievementService) searchScope(ctx context.Context, userID uuid.UUID, isAdmin bool) ([]uuid.UUID, error) {
+	if isAdmin {
+		return nil, nil
+	}
+
+	if student, err := s.repo.GetStudentByUserID(ctx, userID); err == nil {
+		return []uuid.UUID{student.ID}, nil
+	}
+
+	lecturer, err := s.repo.GetLecturerByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("unauthorized: only students, advisors and admins can search achievements")
+	}
+	studentIDs, err := s.repo.GetStudentIDsByAdvisorID(ctx, lecturer.ID)
+	if err != nil {
+		return nil, errors.New("failed to get student list")
+	}
+	if studentIDs == nil {
+		studentIDs = []uuid.UUID{}
+	}
+	return studentIDs, nil
+}
+
+// achievementSearchHighlights membuat cuplikan untuk setiap field yang diindeks text search dan cocok dengan query
+func achievementSearchHighlights(highlighter *utils.SearchHighlighter, achievement mongodb.Achievement) []model.SearchHighlight {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"title", &achievement.Title},
+		{"description", &achievement.Description},
+		{"details.competitionName", achievement.Details.CompetitionName},
+		{"details.publicationTitle", achievement.Details.PublicationTitle},
+		{"details.organizationName", achievement.Details.OrganizationName},
+		{"details.organizer", achievement.Details.Organizer},
+	}
+
+	highlights := []model.SearchHighlight{}
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+		if snippet, ok := highlighter.Snippet(*field.value); ok {
+			highlights = append(highlights, model.SearchHighlight{Field: field.name, Snippet: snippet})
+		}
+	}
+	for _, tag := range achievement.Tags {
+		if snippet, ok := highlighter.Snippet(tag); ok {
+			highlights = append(highlights, model.SearchHighlight{Field: "tags", Snippet: snippet})
+		}
+	}
+	return highlights
+}
+
+// SearchAchievements mencari prestasi dalam lingkup user dengan text index MongoDB, urut relevansi
+func (s *achievementService) SearchAchievements(ctx context.Context, userID uuid.UUID, isAdmin bool, query string, page, limit int) (*model.AchievementSearchResponse, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("search query is required")
+	}
+	if utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return nil, errors.New("search query is too long")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	response := &model.AchievementSearchResponse{
+		Query:      query,
+		Results:    []model.AchievementSearchResult{},
+		Pagination: model.PaginationMetadata{Page: page, Limit: limit},
+	}
+
+	studentIDs, err := s.searchScope(ctx, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if studentIDs != nil && len(studentIDs) == 0 {
+		return response, nil
+	}
+
+	matches, total, err := s.repo.SearchAchievementDocuments(ctx, query, studentIDs, (page-1)*limit, limit)
+	if err != nil {
+		return nil, errors.New("failed to search achievements")
+	}
+	response.Pagination.Total = int(total)
+	response.Pagination.TotalPages = (int(total) + limit - 1) / limit
+	if len(matches) == 0 {
+		return response, nil
+	}
+
+	mongoIDs := make([]string, len(matches))
+	for i, match := range matches {
+		mongoIDs[i] = match.Achievement.ID.Hex()
+	}
+	refs, err := s.repo.GetAchievementsByMongoIDs(ctx, mongoIDs)
+	if err != nil {
+		return nil, errors.New("failed to search achievements")
+	}
+
+	// Repository sudah membatasi pencarian ke reference dalam lingkup; dicek ulang di sini untuk reference
+	// yang berubah (dihapus/dipindah) di antara dua query tersebut
+	inScope := map[uuid.UUID]bool{}
+	for _, id := range studentIDs {
+		inScope[id] = true
+	}
+
+	highlighter := utils.NewSearchHighlighter(query)
+	for _, match := range matches {
+		// Dokumen tanpa reference aktif (orphan atau di trash) tidak ditampilkan
+		ref, found := refs[match.Achievement.ID.Hex()]
+		if !found || ref.Status == "deleted" || (studentIDs != nil && !inScope[ref.StudentID]) {
+			continue
+		}
+
+		tags := match.Achievement.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		response.Results = append(response.Results, model.AchievementSearchResult{
+			AchievementID:      ref.ID,
+			MongoAchievementID: ref.MongoAchievementID,
+			StudentID:          ref.StudentID,
+			StudentNIM:         ref.StudentNIM,
+			StudentName:        ref.StudentName,
+			Status:             ref.Status,
+			Title:              match.Achievement.Title,
+			AchievementType:    match.Achievement.AchievementType,
+			Tags:               tags,
+			Score:              match.Score,
+			Highlights:         achievementSearchHighlights(highlighter, match.Achievement),
+		})
+	}
+
+	return response, nil
+}
+
+// SearchAchievementsEndpoint - GET /achievements/search?q=
+func (s *achievementService) SearchAchievementsEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := s.SearchAchievements(c.Context(), userID, isAdminFromClaims(c), c.Query("q"), c.QueryInt("page", 1), c.QueryInt("limit", 10))
+	if err != nil {
+		switch err.Error() {
+		case "search query is required", "search query is too long":
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		case "unauthorized: only students, advisors and admins can search achievements":
+			return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
@@ -6,6 +6,14 @@ import (
 	mongodb "UASBE/app/model/MongoDB"
 	model "UASBE/app/model/Postgresql"
 	"UASBE/app/repository"
+	"UASBE/helper"
+	"UASBE/storage"
+	"UASBE/utils"
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -20,16 +28,36 @@ type AchievementService interface {
 	GetAchievementByID(ctx context.Context, userID uuid.UUID, mongoAchievementID string) (*mongodb.Achievement, error)
 	UpdateAchievement(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID, req mongodb.Achievement) (*model.AchievementReference, error)
 	SubmitForVerification(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID) (*model.AchievementReference, error)
+	WithdrawAchievement(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID, reason string) (*model.AchievementReference, error)
 	DeleteDraftAchievement(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID) error
-	GetStudentAchievements(ctx context.Context, userID uuid.UUID, status string, page, limit int) (*model.AchievementListResponse, error)
-	VerifyAchievement(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID) (*model.AchievementReference, error)
+	GetStudentAchievements(ctx context.Context, userID uuid.UUID, filters model.AchievementListFilters, page, limit int) (*model.AchievementListResponse, error)
+	VerifyAchievement(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID, note string) (*model.AchievementReference, error)
 	RejectAchievement(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID, rejectionNote string) (*model.AchievementReference, error)
+	BulkVerifyAchievements(ctx context.Context, userID uuid.UUID, req model.BulkAchievementActionRequest) (*model.BulkAchievementActionResponse, error)
+	BulkRejectAchievements(ctx context.Context, userID uuid.UUID, req model.BulkAchievementActionRequest) (*model.BulkAchievementActionResponse, error)
+	GetAchievementComments(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID) (*model.AchievementCommentsResponse, error)
+	AddAchievementComment(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID, req model.CreateAchievementCommentRequest) (*model.AchievementComment, error)
+	SetAchievementCommentResolved(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID, commentID uuid.UUID, resolved bool) (*model.AchievementComment, error)
 	GetAchievementHistory(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID) (*model.AchievementHistoryResponse, error)
 	GetAllAchievementsForAdmin(ctx context.Context, filters model.AdminAchievementFilters, page, limit int) (*model.AchievementListResponse, error)
 	GetAchievementStatistics(ctx context.Context, userID uuid.UUID, filters model.StatisticsFilters) (*model.AchievementStatistics, error)
 	GetReportsStatistics(ctx context.Context, userID uuid.UUID, filters model.StatisticsFilters) (*model.AchievementStatistics, error)
 	GetStudentReport(ctx context.Context, userID uuid.UUID, studentID uuid.UUID) (*model.StudentReportResponse, error)
-	UploadAttachment(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID, fileName, fileURL, fileType string) error
+	UploadAttachment(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID, fileName string, size int64, content io.Reader) (*mongodb.Attachment, error)
+	GetAttachment(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID, attachmentID string) (*mongodb.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID, attachmentID string) error
+	CreateAttachmentSignedURL(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID, attachmentID string, ttl time.Duration) (string, time.Time, error)
+	GetStudentStorageQuota(ctx context.Context, studentID uuid.UUID) (*model.StudentStorageQuota, error)
+	SetStudentStorageQuota(ctx context.Context, studentID uuid.UUID, quotaBytes int64) (*model.StudentStorageQuota, error)
+	GetAchievementScore(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID) (*model.AchievementScore, error)
+	GetAchievementChanges(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID) (*model.AchievementChangesResponse, error)
+	GetAchievementVersions(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID) (*model.AchievementVersionsResponse, error)
+	DiffAchievementVersions(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID, fromVersion, toVersion int) (*model.AchievementVersionDiff, error)
+	BackfillBaselineVersions(ctx context.Context) (int, error)
+	StartVersionBackfill()
+	GetAchievementApprovals(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID) (*model.AchievementApprovalsResponse, error)
+	GetPendingApprovals(ctx context.Context, userID uuid.UUID) ([]model.AchievementApproval, error)
+	SearchAchievements(ctx context.Context, userID uuid.UUID, isAdmin bool, query string, page, limit int) (*model.AchievementSearchResponse, error)
 
 	// HTTP endpoints
 	GetAchievementsEndpoint(c *fiber.Ctx) error
@@ -38,20 +66,46 @@ type AchievementService interface {
 	UpdateAchievementEndpoint(c *fiber.Ctx) error
 	DeleteAchievementEndpoint(c *fiber.Ctx) error
 	SubmitAchievementEndpoint(c *fiber.Ctx) error
+	WithdrawAchievementEndpoint(c *fiber.Ctx) error
 	VerifyAchievementEndpoint(c *fiber.Ctx) error
 	RejectAchievementEndpoint(c *fiber.Ctx) error
+	BulkVerifyAchievementsEndpoint(c *fiber.Ctx) error
+	BulkRejectAchievementsEndpoint(c *fiber.Ctx) error
+	GetAchievementCommentsEndpoint(c *fiber.Ctx) error
+	AddAchievementCommentEndpoint(c *fiber.Ctx) error
+	ResolveAchievementCommentEndpoint(c *fiber.Ctx) error
+	ReopenAchievementCommentEndpoint(c *fiber.Ctx) error
 	GetAchievementHistoryEndpoint(c *fiber.Ctx) error
 	GetAchievementStatisticsEndpoint(c *fiber.Ctx) error
 	GetAllAchievementsForAdminEndpoint(c *fiber.Ctx) error
 	GetReportsStatisticsEndpoint(c *fiber.Ctx) error
 	GetStudentReportEndpoint(c *fiber.Ctx) error
 	UploadAttachmentEndpoint(c *fiber.Ctx) error
+	GetAttachmentEndpoint(c *fiber.Ctx) error
+	DeleteAttachmentEndpoint(c *fiber.Ctx) error
+	CreateAttachmentSignedURLEndpoint(c *fiber.Ctx) error
+	GetSignedAttachmentEndpoint(c *fiber.Ctx) error
+	GetStudentStorageQuotaEndpoint(c *fiber.Ctx) error
+	SetStudentStorageQuotaEndpoint(c *fiber.Ctx) error
+	GetAchievementScoreEndpoint(c *fiber.Ctx) error
+	GetAchievementChangesEndpoint(c *fiber.Ctx) error
+	GetAchievementVersionsEndpoint(c *fiber.Ctx) error
+	DiffAchievementVersionsEndpoint(c *fiber.Ctx) error
+	GetAchievementApprovalsEndpoint(c *fiber.Ctx) error
+	GetPendingApprovalsEndpoint(c *fiber.Ctx) error
+	SearchAchievementsEndpoint(c *fiber.Ctx) error
 	GetAllStudentIDs(ctx context.Context) ([]uuid.UUID, error)
 	GetAchievementAdminDetailEndpoint(c *fiber.Ctx) error
 }
 
 type achievementService struct {
-	repo repository.AchievementRepository
+	repo         repository.AchievementRepository
+	typeRepo     repository.AchievementTypeRepository
+	scoringRepo  repository.ScoringRepository
+	approvalRepo repository.ApprovalChainRepository
+	delegations  repository.DelegationRepository
+	storage      storage.Storage
+	outbox       AchievementOutboxService
 }
 
 // GetAllStudentIDs implements AchievementService.
@@ -59,8 +113,8 @@ func (s *achievementService) GetAllStudentIDs(ctx context.Context) ([]uuid.UUID,
 	return s.repo.GetAllStudentIDs(ctx)
 }
 
-func NewAchievementService(repo repository.AchievementRepository) AchievementService {
-	return &achievementService{repo: repo}
+func NewAchievementService(repo repository.AchievementRepository, typeRepo repository.AchievementTypeRepository, scoringRepo repository.ScoringRepository, approvalRepo repository.ApprovalChainRepository, delegations repository.DelegationRepository, store storage.Storage, outbox AchievementOutboxService) AchievementService {
+	return &achievementService{repo: repo, typeRepo: typeRepo, scoringRepo: scoringRepo, approvalRepo: approvalRepo, delegations: delegations, storage: store, outbox: outbox}
 }
 
 // Helper function untuk mengekstrak user ID dari JWT claims
@@ -88,6 +142,55 @@ func extractUserIDFromClaims(c *fiber.Ctx) (uuid.UUID, error) {
 	return userID, nil
 }
 
+// parseAchievementListFilters membaca filter dan sorting listing prestasi dari query string;
+// nilai poin yang bukan angka diabaikan
+func parseAchievementListFilters(c *fiber.Ctx) model.AchievementListFilters {
+	filters := model.AchievementListFilters{
+		Status:           c.Query("status", ""),
+		AchievementType:  c.Query("achievement_type", ""),
+		CompetitionLevel: c.Query("competition_level", ""),
+		MedalType:        c.Query("medal_type", ""),
+		Tag:              c.Query("tag", ""),
+		SortBy:           c.Query("sort_by", "created_at"),
+		SortOrder:        c.Query("sort_order", "desc"),
+	}
+
+	if minPoints, err := strconv.Atoi(c.Query("min_points")); err == nil {
+		filters.MinPoints = &minPoints
+	}
+	if maxPoints, err := strconv.Atoi(c.Query("max_points")); err == nil {
+		filters.MaxPoints = &maxPoints
+	}
+
+	return filters
+}
+
+// validateAchievement memvalidasi tipe prestasi terhadap katalog achievement_types,
+// lalu details & customFields terhadap definisi tipe prestasi.
+// requireActive dipakai saat create/update: tipe yang sudah dinonaktifkan tidak boleh dipakai lagi.
+func (s *achievementService) validateAchievement(ctx context.Context, achievement mongodb.Achievement, requireActive bool) error {
+	errs := utils.ValidateAchievement(achievement)
+
+	if achievement.AchievementType != "" {
+		achievementType, err := s.typeRepo.GetAchievementTypeByCode(ctx, achievement.AchievementType)
+		if err != nil {
+			return errors.New("failed to validate achievement type")
+		}
+		if achievementType == nil {
+			errs = append(errs, utils.FieldError{Field: "achievementType", Message: "unknown achievement type"})
+		} else if requireActive && !achievementType.IsActive {
+			errs = append(errs, utils.FieldError{Field: "achievementType", Message: "achievement type is inactive"})
+		}
+	}
+
+	if len(errs) > 0 {
+		return helper.NewAPIError(422, "VALIDATION_FAILED", "Achievement validation failed", fiber.Map{
+			"fields": errs,
+		})
+	}
+	return nil
+}
+
 func (s *achievementService) SubmitPrestasi(ctx context.Context, userID uuid.UUID, req mongodb.Achievement) (*model.AchievementReference, error) {
 	// 1. Cari data Student berdasarkan User ID yang login
 	student, err := s.repo.GetStudentByUserID(ctx, userID)
@@ -95,9 +198,16 @@ func (s *achievementService) SubmitPrestasi(ctx context.Context, userID uuid.UUI
 		return nil, errors.New("student data not found for this user")
 	}
 
-	// 2. Setup Data untuk MongoDB
+	// 2. Validasi tipe (katalog) dan details sesuai tipe prestasi
+	req.AchievementType = utils.NormalizeAchievementType(req.AchievementType)
+	if err := s.validateAchievement(ctx, req, true); err != nil {
+		return nil, err
+	}
+
+	// 3. Setup Data untuk MongoDB
 	req.ID = primitive.NewObjectID()
 	req.StudentID = student.ID // Link ke UUID Student di Postgres
+	req.Points = 0             // Poin dihitung scoring engine saat verifikasi
 	req.CreatedAt = time.Now()
 	req.UpdatedAt = time.Now()
 
@@ -105,29 +215,27 @@ func (s *achievementService) SubmitPrestasi(ctx context.Context, userID uuid.UUI
 		req.CustomFields = make(map[string]interface{})
 	}
 
-	// 3. Simpan ke MongoDB
-	mongoID, err := s.repo.SaveAchievementMongo(ctx, req)
-	if err != nil {
-		return nil, err
-	}
-
 	// 4. Setup Data untuk Postgres (Reference)
 	// Sesuai SRS Flow 4: Status awal 'draft'
 	ref := model.AchievementReference{
 		ID:                 uuid.New(),
 		StudentID:          student.ID,
-		MongoAchievementID: mongoID,
+		MongoAchievementID: req.ID.Hex(),
 		Status:             "draft",
 		CreatedAt:          time.Now(),
 		UpdatedAt:          time.Now(),
 	}
 
-	// 5. Simpan ke Postgres
-	err = s.repo.SaveAchievementReference(ctx, ref)
+	// 5. Simpan reference, versi 1, dan event outbox dokumen dalam satu transaksi Postgres;
+	// jika gagal, belum ada yang tertulis ke MongoDB
+	err = s.repo.SaveAchievementReference(ctx, ref, req, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	// 6. Tulis dokumen ke MongoDB (disusulkan relay jika gagal)
+	dispatchOrDefer(ctx, s.outbox, ref.ID)
+
 	return &ref, nil
 }
 
@@ -195,31 +303,46 @@ func (s *achievementService) UpdateAchievement(ctx context.Context, userID uuid.
 		return nil, errors.New("unauthorized: achievement does not belong to this student")
 	}
 
-	// 4. Check status - only draft can be updated
-	if ref.Status != "draft" {
-		return nil, errors.New("only draft achievements can be updated")
+	// 4. Check status - draft, atau prestasi yang ditolak (rejected/revised) untuk diperbaiki
+	if !isEditableStatus(ref.Status) {
+		return nil, errors.New("only draft or rejected achievements can be updated")
+	}
+
+	// 5. Validasi tipe (katalog) dan details sesuai tipe prestasi
+	req.AchievementType = utils.NormalizeAchievementType(req.AchievementType)
+	if err := s.validateAchievement(ctx, req, true); err != nil {
+		return nil, err
 	}
 
-	// 5. Update achievement in MongoDB
+	// 6. Field yang dikelola server diambil dari versi terakhir
 	objectID, err := primitive.ObjectIDFromHex(ref.MongoAchievementID)
 	if err != nil {
 		return nil, errors.New("invalid mongo achievement ID")
 	}
+	existing, err := s.latestSnapshot(ctx, ref)
+	if err != nil {
+		return nil, errors.New("achievement not found")
+	}
 	req.ID = objectID
+	req.StudentID = existing.StudentID
+	req.Attachments = existing.Attachments // attachment dikelola lewat endpoint attachments
+	req.Points = 0                         // Poin dihitung scoring engine saat verifikasi
+	req.CreatedAt = existing.CreatedAt
 	req.UpdatedAt = time.Now()
+	if req.CustomFields == nil {
+		req.CustomFields = make(map[string]interface{})
+	}
 
-	err = s.repo.UpdateAchievementInMongo(ctx, req)
-	if err != nil {
+	// 7. Simpan versi baru dan event outbox dalam satu transaksi Postgres;
+	// prestasi yang ditolak berpindah ke status 'revised'
+	if err := s.repo.UpdateAchievementContent(ctx, achievementID, req, userID, ref.Status == "rejected"); err != nil {
 		return nil, errors.New("failed to update achievement")
 	}
 
-	// 6. Update timestamp in PostgreSQL
-	err = s.repo.UpdateAchievementTimestamp(ctx, achievementID)
-	if err != nil {
-		return nil, errors.New("failed to update achievement timestamp")
-	}
+	// 8. Tulis perubahan ke MongoDB (disusulkan relay jika gagal)
+	dispatchOrDefer(ctx, s.outbox, achievementID)
 
-	// 7. Get updated achievement reference
+	// 9. Get updated achievement reference
 	updatedRef, err := s.repo.GetAchievementReferenceByID(ctx, achievementID)
 	if err != nil {
 		return nil, err
@@ -247,18 +370,42 @@ func (s *achievementService) SubmitForVerification(ctx context.Context, userID u
 		return nil, errors.New("unauthorized: achievement does not belong to this student")
 	}
 
-	// 4. Validasi: Pastikan status adalah 'draft'
-	if ref.Status != "draft" {
-		return nil, errors.New("achievement must be in 'draft' status to submit")
+	// 4. Validasi: Pastikan status adalah 'draft', atau 'revised' untuk resubmission
+	if ref.Status == "rejected" {
+		return nil, errors.New("rejected achievement must be revised before resubmitting")
+	}
+	if ref.Status != "draft" && ref.Status != "revised" {
+		return nil, errors.New("achievement must be in 'draft' or 'revised' status to submit")
+	}
+
+	// 5. Validasi ulang isi prestasi (draft lama mungkin dibuat sebelum validasi per tipe)
+	achievement, err := s.repo.GetAchievementDetailFromMongo(ctx, ref.MongoAchievementID)
+	if err != nil {
+		return nil, errors.New("achievement not found")
+	}
+	if err := s.validateAchievement(ctx, *achievement, false); err != nil {
+		return nil, err
 	}
 
-	// 5. Update status menjadi 'submitted'
-	err = s.repo.UpdateAchievementStatusToSubmitted(ctx, achievementID)
+	// 6. Tentukan tahap persetujuan dari approval chain (tipe/tingkat prestasi)
+	stages, err := s.resolveApprovalStages(ctx, *achievement)
+	if err != nil {
+		return nil, err
+	}
+
+	// 7. Update status menjadi 'submitted' (resubmission menaikkan revision); batas waktu review
+	// mengikuti SLA tingkat prestasi
+	reviewLevel := achievementLevel(*achievement)
+	if ref.Status == "revised" {
+		err = s.repo.UpdateAchievementStatusToResubmitted(ctx, achievementID, userID, stages, reviewLevel)
+	} else {
+		err = s.repo.UpdateAchievementStatusToSubmitted(ctx, achievementID, userID, stages, reviewLevel)
+	}
 	if err != nil {
 		return nil, errors.New("failed to update achievement status")
 	}
 
-	// 6. Get updated achievement reference
+	// 8. Get updated achievement reference
 	updatedRef, err := s.repo.GetAchievementReferenceByID(ctx, achievementID)
 	if err != nil {
 		return nil, err
@@ -267,6 +414,54 @@ func (s *achievementService) SubmitForVerification(ctx context.Context, userID u
 	return updatedRef, nil
 }
 
+// WithdrawAchievement menarik prestasi 'submitted' kembali ke 'draft' oleh mahasiswa pemiliknya.
+// Persetujuan tahap yang sudah berjalan dibuang dan dosen wali diberi notifikasi.
+func (s *achievementService) WithdrawAchievement(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID, reason string) (*model.AchievementReference, error) {
+	// 1. Cari data Student berdasarkan User ID yang login
+	student, err := s.repo.GetStudentByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("student data not found for this user")
+	}
+
+	// 2. Get achievement reference by ID
+	ref, err := s.repo.GetAchievementReferenceByID(ctx, achievementID)
+	if err != nil || ref.Status == "deleted" {
+		return nil, errors.New("achievement not found")
+	}
+
+	// 3. Validasi: hanya pemilik dan hanya selama status 'submitted'
+	if ref.StudentID != student.ID {
+		return nil, errors.New("unauthorized: achievement does not belong to this student")
+	}
+	if ref.Status != "submitted" {
+		return nil, errors.New("achievement must be in 'submitted' status to withdraw")
+	}
+
+	achievement, err := s.repo.GetAchievementDetailFromMongo(ctx, ref.MongoAchievementID)
+	if err != nil {
+		return nil, errors.New("achievement not found")
+	}
+
+	// 4. Catatan riwayat status dan pesan notifikasi untuk dosen wali
+	reason = strings.TrimSpace(reason)
+	note := "Withdrawn by student"
+	message := fmt.Sprintf("Student %s withdrew \"%s\" from verification", student.StudentID, achievement.Title)
+	if reason != "" {
+		note += ": " + reason
+		message += ": " + reason
+	}
+
+	// 5. Update status menjadi 'draft'; gagal jika prestasi sudah diputuskan lebih dulu
+	if err := s.repo.UpdateAchievementStatusToWithdrawn(ctx, achievementID, userID, note, message); err != nil {
+		if errors.Is(err, repository.ErrAchievementNotSubmitted) {
+			return nil, errors.New("achievement must be in 'submitted' status to withdraw")
+		}
+		return nil, errors.New("failed to withdraw achievement")
+	}
+
+	return s.repo.GetAchievementReferenceByID(ctx, achievementID)
+}
+
 // DeleteDraftAchievement - FR-005: Hapus Prestasi
 func (s *achievementService) DeleteDraftAchievement(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID) error {
 	// 1. Cari data Student berdasarkan User ID yang login
@@ -291,58 +486,75 @@ func (s *achievementService) DeleteDraftAchievement(ctx context.Context, userID
 		return errors.New("only draft achievements can be deleted")
 	}
 
-	// 5. Soft delete di MongoDB
-	err = s.repo.SoftDeleteAchievementMongo(ctx, ref.MongoAchievementID)
-	if err != nil {
-		return errors.New("failed to delete achievement from MongoDB")
-	}
-
-	// 6. Update status di PostgreSQL menjadi 'deleted'
-	err = s.repo.UpdateAchievementReferenceToDeleted(ctx, achievementID)
+	// 5. Update status di PostgreSQL menjadi 'deleted' beserta event soft delete dokumen;
+	// gagal jika prestasi sudah disubmit lebih dulu
+	err = s.repo.UpdateAchievementReferenceToDeleted(ctx, achievementID, userID)
 	if err != nil {
+		if errors.Is(err, repository.ErrAchievementNotDraft) {
+			return errors.New("only draft achievements can be deleted")
+		}
 		return errors.New("failed to update achievement status in PostgreSQL")
 	}
 
+	// 6. Soft delete di MongoDB (disusulkan relay jika gagal)
+	dispatchOrDefer(ctx, s.outbox, achievementID)
+
 	return nil
 }
 
 // VerifyAchievement - FR-007: Verify Prestasi
-func (s *achievementService) VerifyAchievement(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID) (*model.AchievementReference, error) {
-	// 1. Get lecturer data by user ID
-	lecturer, err := s.repo.GetLecturerByUserID(ctx, userID)
-	if err != nil {
-		return nil, errors.New("lecturer data not found for this user")
-	}
-
-	// 2. Get achievement reference by ID
+// Menyetujui tahap persetujuan yang sedang berjalan; status 'verified' baru diset pada tahap terakhir.
+func (s *achievementService) VerifyAchievement(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID, note string) (*model.AchievementReference, error) {
+	// 1. Get achievement reference by ID
 	ref, err := s.repo.GetAchievementReferenceByID(ctx, achievementID)
 	if err != nil {
 		return nil, errors.New("achievement not found")
 	}
 
-	// 3. Validasi: Pastikan status adalah 'submitted'
+	// 2. Validasi: Pastikan status adalah 'submitted'
 	if ref.Status != "submitted" {
 		return nil, errors.New("achievement must be in 'submitted' status to verify")
 	}
 
-	// 4. Get student data untuk validasi advisor
-	student, err := s.repo.GetStudentByID(ctx, ref.StudentID)
+	// 3. Ambil tahap persetujuan yang sedang berjalan
+	stage, isLastStage, err := s.currentApprovalStage(ctx, ref)
 	if err != nil {
-		return nil, errors.New("student data not found")
+		return nil, err
+	}
+
+	// 4. Validasi: user adalah approver tahap ini (dosen wali mahasiswa, delegasinya, atau role approver)
+	actor, err := s.authorizeApprovalStage(ctx, userID, ref, stage, "verify")
+	if err != nil {
+		return nil, err
+	}
+
+	decision := newApprovalDecision(stage, "approved", userID, actor, note)
+
+	// 5. Tahap berikutnya masih ada: simpan persetujuan tahap ini, status tetap 'submitted'
+	if !isLastStage {
+		if err := s.repo.ApproveAchievementStage(ctx, decision); err != nil {
+			return nil, mapApprovalRepoError(err, "failed to approve achievement stage")
+		}
+		return s.repo.GetAchievementReferenceByID(ctx, achievementID)
 	}
 
-	// 5. Validasi: Pastikan achievement milik mahasiswa bimbingan dosen ini
-	if student.AdvisorID != lecturer.ID {
-		return nil, errors.New("unauthorized: you can only verify achievements of your advisees")
+	// 6. Hitung poin dengan aturan skor yang aktif
+	score, err := s.scoreForVerification(ctx, ref, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// 6. Update status menjadi 'verified' dengan verified_by dan verified_at
-	err = s.repo.UpdateAchievementStatusToVerified(ctx, achievementID, lecturer.ID)
+	// 7. Update status menjadi 'verified' (verified_by, verified_at) sekaligus simpan skor, event outbox poin
+	// & keputusan tahap terakhir dalam satu transaksi
+	err = s.repo.UpdateAchievementStatusToVerified(ctx, achievementID, actor.VerifierID, userID, score, decision)
 	if err != nil {
-		return nil, errors.New("failed to verify achievement")
+		return nil, mapApprovalRepoError(err, "failed to verify achievement")
 	}
 
-	// 7. Get updated achievement reference
+	// 8. Tulis poin ke MongoDB setelah commit (disusulkan relay jika gagal)
+	dispatchOrDefer(ctx, s.outbox, achievementID)
+
+	// 9. Get updated achievement reference
 	updatedRef, err := s.repo.GetAchievementReferenceByID(ctx, achievementID)
 	if err != nil {
 		return nil, err
@@ -352,47 +564,48 @@ func (s *achievementService) VerifyAchievement(ctx context.Context, userID uuid.
 }
 
 // RejectAchievement - FR-008: Reject Prestasi
+// Approver tahap yang sedang berjalan dapat menolak; prestasi kembali ke mahasiswa untuk direvisi.
 func (s *achievementService) RejectAchievement(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID, rejectionNote string) (*model.AchievementReference, error) {
 	// 1. Validasi rejection note tidak kosong
 	if rejectionNote == "" {
 		return nil, errors.New("rejection note is required")
 	}
 
-	// 2. Get lecturer data by user ID
-	lecturer, err := s.repo.GetLecturerByUserID(ctx, userID)
-	if err != nil {
-		return nil, errors.New("lecturer data not found for this user")
-	}
-
-	// 3. Get achievement reference by ID
+	// 2. Get achievement reference by ID
 	ref, err := s.repo.GetAchievementReferenceByID(ctx, achievementID)
 	if err != nil {
 		return nil, errors.New("achievement not found")
 	}
 
-	// 4. Validasi: Pastikan status adalah 'submitted'
+	// 3. Validasi: Pastikan status adalah 'submitted'
 	if ref.Status != "submitted" {
 		return nil, errors.New("achievement must be in 'submitted' status to reject")
 	}
 
-	// 5. Get student data untuk validasi advisor
-	student, err := s.repo.GetStudentByID(ctx, ref.StudentID)
+	// 4. Validasi: user adalah approver tahap yang sedang berjalan
+	stage, _, err := s.currentApprovalStage(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	actor, err := s.authorizeApprovalStage(ctx, userID, ref, stage, "reject")
 	if err != nil {
-		return nil, errors.New("student data not found")
+		return nil, err
 	}
 
-	// 6. Validasi: Pastikan achievement milik mahasiswa bimbingan dosen ini
-	if student.AdvisorID != lecturer.ID {
-		return nil, errors.New("unauthorized: you can only reject achievements of your advisees")
+	// 5. Snapshot isi prestasi saat ditolak, pembanding ketika mahasiswa mengirim ulang
+	achievement, err := s.repo.GetAchievementDetailFromMongo(ctx, ref.MongoAchievementID)
+	if err != nil {
+		return nil, errors.New("achievement not found")
 	}
 
-	// 7. Update status menjadi 'rejected' dengan rejection note
-	err = s.repo.UpdateAchievementStatusToRejected(ctx, achievementID, rejectionNote)
+	// 6. Update status menjadi 'rejected' dengan rejection note
+	decision := newApprovalDecision(stage, "rejected", userID, actor, rejectionNote)
+	err = s.repo.UpdateAchievementStatusToRejected(ctx, achievementID, rejectionNote, userID, *achievement, decision)
 	if err != nil {
-		return nil, errors.New("failed to reject achievement")
+		return nil, mapApprovalRepoError(err, "failed to reject achievement")
 	}
 
-	// 8. Get updated achievement reference
+	// 7. Get updated achievement reference
 	updatedRef, err := s.repo.GetAchievementReferenceByID(ctx, achievementID)
 	if err != nil {
 		return nil, err
@@ -402,7 +615,7 @@ func (s *achievementService) RejectAchievement(ctx context.Context, userID uuid.
 }
 
 // GetStudentAchievements - FR-006: View Prestasi Mahasiswa Bimbingan
-func (s *achievementService) GetStudentAchievements(ctx context.Context, userID uuid.UUID, status string, page, limit int) (*model.AchievementListResponse, error) {
+func (s *achievementService) GetStudentAchievements(ctx context.Context, userID uuid.UUID, filters model.AchievementListFilters, page, limit int) (*model.AchievementListResponse, error) {
 	// Set default pagination values
 	if page < 1 {
 		page = 1
@@ -437,7 +650,7 @@ func (s *achievementService) GetStudentAchievements(ctx context.Context, userID
 	}
 
 	// 3. Get achievements with student info and pagination
-	achievements, total, err := s.repo.GetAchievementsWithStudentInfo(ctx, studentIDs, status, page, limit)
+	achievements, total, err := s.repo.GetAchievementsWithStudentInfo(ctx, studentIDs, filters, page, limit)
 	if err != nil {
 		return nil, errors.New("failed to get achievements")
 	}
@@ -533,6 +746,12 @@ func (s *achievementService) GetAllAchievementsForAdmin(ctx context.Context, fil
 		// If error fetching from MongoDB, just skip (details will be nil)
 	}
 
+	// Facet counts untuk setiap dimensi filter
+	facets, err := s.repo.GetAdminAchievementFacets(ctx, filters)
+	if err != nil {
+		return nil, errors.New("failed to get achievement facets")
+	}
+
 	// Calculate pagination metadata
 	totalPages := (total + limit - 1) / limit
 
@@ -544,6 +763,7 @@ func (s *achievementService) GetAllAchievementsForAdmin(ctx context.Context, fil
 			Total:      total,
 			TotalPages: totalPages,
 		},
+		Facets: facets,
 	}, nil
 }
 
@@ -597,30 +817,40 @@ func (s *achievementService) GetAchievementStatistics(ctx context.Context, userI
 		return nil, errors.New("failed to get total achievements")
 	}
 
-	// Get statistics by type (need MongoDB collection)
-	// Note: We need to pass mongoColl to repository methods
-	// For now, we'll return empty arrays for MongoDB-dependent stats
-	byType := []model.StatsByType{}
+	// Get statistics by type (agregasi MongoDB); kegagalan dikembalikan agar dashboard tidak menampilkan angka kosong
+	byType, err := s.repo.GetStatisticsByType(ctx, studentIDs, filters)
+	if err != nil {
+		return nil, errors.New("failed to get statistics by type")
+	}
+	if byType == nil {
+		byType = []model.StatsByType{}
+	}
 
 	// Get statistics by period
 	byPeriod, err := s.repo.GetStatisticsByPeriod(ctx, studentIDs, filters)
 	if err != nil {
-		byPeriod = []model.StatsByPeriod{}
+		return nil, errors.New("failed to get statistics by period")
 	}
 
 	// Get top students (limit to 10)
 	topStudents, err := s.repo.GetTopStudents(ctx, studentIDs, filters, 10)
 	if err != nil {
-		topStudents = []model.TopStudent{}
+		return nil, errors.New("failed to get top students")
 	}
 
-	// Get level distribution
-	levelDist := []model.LevelDistribution{}
+	// Get level distribution (agregasi MongoDB)
+	levelDist, err := s.repo.GetLevelDistribution(ctx, studentIDs, filters)
+	if err != nil {
+		return nil, errors.New("failed to get level distribution")
+	}
+	if levelDist == nil {
+		levelDist = []model.LevelDistribution{}
+	}
 
 	// Get status distribution
 	statusDist, err := s.repo.GetStatusDistribution(ctx, studentIDs, filters)
 	if err != nil {
-		statusDist = []model.StatusDistribution{}
+		return nil, errors.New("failed to get status distribution")
 	}
 
 	return &model.AchievementStatistics{
@@ -642,9 +872,8 @@ func (s *achievementService) GetAchievementsEndpoint(c *fiber.Ctx) error {
 
 	page := c.QueryInt("page", 1)
 	limit := c.QueryInt("limit", 10)
-	status := c.Query("status", "")
 
-	result, err := s.GetStudentAchievements(c.Context(), userID, status, page, limit)
+	result, err := s.GetStudentAchievements(c.Context(), userID, parseAchievementListFilters(c), page, limit)
 	if err != nil {
 		switch err.Error() {
 		case "lecturer data not found for this user":
@@ -677,6 +906,11 @@ func (s *achievementService) CreateAchievementEndpoint(c *fiber.Ctx) error {
 
 	result, err := s.SubmitPrestasi(c.Context(), userID, req)
 	if err != nil {
+		var apiErr *helper.APIError
+		if errors.As(err, &apiErr) {
+			return helper.ErrorDetail(c, apiErr)
+		}
+
 		switch err.Error() {
 		case "student data not found for this user":
 			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
@@ -738,6 +972,11 @@ func (s *achievementService) SubmitAchievementEndpoint(c *fiber.Ctx) error {
 
 	result, err := s.SubmitForVerification(c.Context(), userID, achievementID)
 	if err != nil {
+		var apiErr *helper.APIError
+		if errors.As(err, &apiErr) {
+			return helper.ErrorDetail(c, apiErr)
+		}
+
 		switch err.Error() {
 		case "student data not found for this user":
 			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
@@ -745,7 +984,8 @@ func (s *achievementService) SubmitAchievementEndpoint(c *fiber.Ctx) error {
 			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
 		case "unauthorized: achievement does not belong to this student":
 			return c.Status(403).JSON(fiber.Map{"error": err.Error()})
-		case "achievement must be in 'draft' status to submit":
+		case "achievement must be in 'draft' or 'revised' status to submit",
+			"rejected achievement must be revised before resubmitting":
 			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 		default:
 			return c.Status(500).JSON(fiber.Map{"error": "Failed to submit achievement"})
@@ -759,7 +999,8 @@ func (s *achievementService) SubmitAchievementEndpoint(c *fiber.Ctx) error {
 	})
 }
 
-func (s *achievementService) VerifyAchievementEndpoint(c *fiber.Ctx) error {
+// WithdrawAchievementEndpoint - POST /achievements/:id/withdraw
+func (s *achievementService) WithdrawAchievementEndpoint(c *fiber.Ctx) error {
 	userID, err := extractUserIDFromClaims(c)
 	if err != nil {
 		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
@@ -770,29 +1011,81 @@ func (s *achievementService) VerifyAchievementEndpoint(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
 	}
 
-	result, err := s.VerifyAchievement(c.Context(), userID, achievementID)
+	// Alasan bersifat opsional, body boleh kosong
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+		}
+	}
+
+	result, err := s.WithdrawAchievement(c.Context(), userID, achievementID, req.Reason)
 	if err != nil {
 		switch err.Error() {
-		case "lecturer data not found for this user":
-			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
-		case "achievement not found":
-			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
-		case "achievement must be in 'submitted' status to verify":
-			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
-		case "student data not found":
+		case "student data not found for this user", "achievement not found":
 			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
-		case "unauthorized: you can only verify achievements of your advisees":
+		case "unauthorized: achievement does not belong to this student":
 			return c.Status(403).JSON(fiber.Map{"error": err.Error()})
-		case "failed to verify achievement":
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		case "achievement must be in 'submitted' status to withdraw":
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 		default:
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to verify achievement"})
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to withdraw achievement"})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Achievement withdrawn to draft",
+		"data":    result,
+	})
+}
+
+func (s *achievementService) VerifyAchievementEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+
+	// Catatan approver bersifat opsional, body boleh kosong
+	var req struct {
+		Note string `json:"note"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+		}
+	}
+
+	result, err := s.VerifyAchievement(c.Context(), userID, achievementID, req.Note)
+	if err != nil {
+		status := approvalErrorStatus(err)
+		if status == 500 {
+			switch err.Error() {
+			case "failed to verify achievement", "no active scoring rules", "failed to calculate achievement score",
+				"failed to approve achievement stage":
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			default:
+				return c.Status(500).JSON(fiber.Map{"error": "Failed to verify achievement"})
+			}
 		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	message := "Achievement verified successfully"
+	if result.Status == "submitted" {
+		message = "Approval stage approved; waiting for the next stage"
 	}
 
 	return c.JSON(fiber.Map{
 		"status":  "success",
-		"message": "Achievement verified successfully",
+		"message": message,
 		"data":    result,
 	})
 }
@@ -817,24 +1110,14 @@ func (s *achievementService) RejectAchievementEndpoint(c *fiber.Ctx) error {
 
 	result, err := s.RejectAchievement(c.Context(), userID, achievementID, req.RejectionNote)
 	if err != nil {
-		switch err.Error() {
-		case "rejection note is required":
-			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
-		case "lecturer data not found for this user":
-			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
-		case "achievement not found":
-			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
-		case "achievement must be in 'submitted' status to reject":
-			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
-		case "student data not found":
-			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
-		case "unauthorized: you can only reject achievements of your advisees":
-			return c.Status(403).JSON(fiber.Map{"error": err.Error()})
-		case "failed to reject achievement":
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
-		default:
+		status := approvalErrorStatus(err)
+		if status == 500 {
+			if err.Error() == "failed to reject achievement" {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
 			return c.Status(500).JSON(fiber.Map{"error": "Failed to reject achievement"})
 		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	return c.JSON(fiber.Map{
@@ -916,9 +1199,7 @@ func (s *achievementService) GetAllAchievementsForAdminEndpoint(c *fiber.Ctx) er
 	limit := c.QueryInt("limit", 10)
 
 	filters := model.AdminAchievementFilters{
-		Status:    c.Query("status", ""),
-		SortBy:    c.Query("sort_by", "created_at"),
-		SortOrder: c.Query("sort_order", "desc"),
+		AchievementListFilters: parseAchievementListFilters(c),
 	}
 
 	if studentIDStr := c.Query("student_id"); studentIDStr != "" {
@@ -942,6 +1223,16 @@ func (s *achievementService) GetAllAchievementsForAdminEndpoint(c *fiber.Ctx) er
 		}
 	}
 
+	filters.ProgramStudy = c.Query("program_study")
+	filters.AcademicYear = c.Query("academic_year")
+
+	if advisorIDStr := c.Query("advisor_id"); advisorIDStr != "" {
+		advisorID, err := uuid.Parse(advisorIDStr)
+		if err == nil {
+			filters.AdvisorID = &advisorID
+		}
+	}
+
 	result, err := s.GetAllAchievementsForAdmin(c.Context(), filters, page, limit)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
@@ -989,11 +1280,6 @@ func (s *achievementService) GetAchievementByIDEndpoint(c *fiber.Ctx) error {
 			"detail":    detail,
 		},
 	})
-
-	return c.JSON(fiber.Map{
-		"status": "success",
-		"data":   ref,
-	})
 }
 
 func (s *achievementService) UpdateAchievementEndpoint(c *fiber.Ctx) error {
@@ -1014,6 +1300,11 @@ func (s *achievementService) UpdateAchievementEndpoint(c *fiber.Ctx) error {
 
 	result, err := s.UpdateAchievement(c.Context(), userID, achievementID, req)
 	if err != nil {
+		var apiErr *helper.APIError
+		if errors.As(err, &apiErr) {
+			return helper.ErrorDetail(c, apiErr)
+		}
+
 		switch err.Error() {
 		case "student data not found for this user":
 			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
@@ -1021,7 +1312,7 @@ func (s *achievementService) UpdateAchievementEndpoint(c *fiber.Ctx) error {
 			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
 		case "unauthorized: achievement does not belong to this student":
 			return c.Status(403).JSON(fiber.Map{"error": err.Error()})
-		case "only draft achievements can be updated":
+		case "only draft or rejected achievements can be updated":
 			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 		default:
 			return c.Status(500).JSON(fiber.Map{"error": "Failed to update achievement"})
@@ -1188,36 +1479,89 @@ func (s *achievementService) GetStudentReportEndpoint(c *fiber.Ctx) error {
 }
 
 // UploadAttachment - Upload file attachment to achievement
-func (s *achievementService) UploadAttachment(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID, fileName, fileURL, fileType string) error {
+func (s *achievementService) UploadAttachment(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID, fileName string, size int64, content io.Reader) (*mongodb.Attachment, error) {
 	// 1. Get student data
 	student, err := s.repo.GetStudentByUserID(ctx, userID)
 	if err != nil {
-		return errors.New("student data not found for this user")
+		return nil, errors.New("student data not found for this user")
 	}
 
 	// 2. Get achievement reference by ID
 	ref, err := s.repo.GetAchievementReferenceByID(ctx, achievementID)
 	if err != nil {
-		return errors.New("achievement not found")
+		return nil, errors.New("achievement not found")
 	}
 
 	// 3. Check authorization - only owner can upload
 	if ref.StudentID != student.ID {
-		return errors.New("unauthorized: achievement does not belong to this student")
+		return nil, errors.New("unauthorized: achievement does not belong to this student")
+	}
+
+	// 4. Check status - only draft and rejected (termasuk yang sedang direvisi) can have new attachments
+	if !isEditableStatus(ref.Status) {
+		return nil, errors.New("attachments can only be added to draft or rejected achievements")
+	}
+
+	// 5. Validasi ukuran file & kuota (per prestasi dan per mahasiswa)
+	if err := s.checkAttachmentQuota(ctx, ref, size); err != nil {
+		return nil, err
 	}
 
-	// 4. Check status - only draft and rejected can have new attachments
-	if ref.Status != "draft" && ref.Status != "rejected" {
-		return errors.New("attachments can only be added to draft or rejected achievements")
+	// 6. Deteksi tipe file dari magic bytes, bukan dari Content-Type client
+	reader := bufio.NewReaderSize(content, utils.SniffLength)
+	head, _ := reader.Peek(utils.SniffLength)
+	fileType := utils.DetectFileType(head)
+	if !utils.AllowedAttachmentTypes[fileType] {
+		return nil, helper.NewAPIError(415, "FILE_TYPE_NOT_ALLOWED", "File type not allowed", fiber.Map{
+			"detectedType": fileType,
+		})
 	}
 
-	// 5. Add attachment to MongoDB
-	err = s.repo.AddAttachmentToAchievement(ctx, ref.MongoAchievementID, fileName, fileURL, fileType)
+	// 7. Simpan file ke storage (streaming, content-addressed)
+	if s.storage == nil {
+		return nil, errors.New("attachment storage is not configured")
+	}
+
+	limited := &limitedReader{r: reader, remaining: utils.MaxAttachmentSize}
+	obj, err := s.storage.Put(ctx, limited, fileType)
+	if limited.exceeded {
+		return nil, fileTooLargeError(utils.MaxAttachmentSize)
+	}
 	if err != nil {
-		return errors.New("failed to add attachment")
+		return nil, errors.New("failed to store attachment")
 	}
 
-	return nil
+	attachmentID := uuid.New().String()
+	attachment := mongodb.Attachment{
+		ID:         attachmentID,
+		FileName:   fileName,
+		FileUrl:    attachmentPath(achievementID, attachmentID),
+		FileType:   fileType,
+		Size:       obj.Size,
+		Checksum:   obj.Checksum,
+		StorageKey: obj.Key,
+		UploadedAt: time.Now(),
+	}
+
+	// 8. Simpan versi baru (dengan attachment) dan event outbox dalam satu transaksi Postgres;
+	// prestasi yang ditolak berpindah ke status 'revised'.
+	// File di storage tidak dihapus jika gagal: key content-addressed bisa dipakai attachment lain
+	snapshot, err := s.latestSnapshot(ctx, ref)
+	if err != nil {
+		return nil, errors.New("achievement not found")
+	}
+	snapshot.Attachments = append(snapshot.Attachments, attachment)
+	snapshot.UpdatedAt = attachment.UploadedAt
+
+	err = s.repo.AddAttachmentToAchievement(ctx, achievementID, attachment, *snapshot, userID, ref.Status == "rejected")
+	if err != nil {
+		return nil, errors.New("failed to add attachment")
+	}
+
+	// 9. Tulis attachment ke MongoDB (disusulkan relay jika gagal)
+	dispatchOrDefer(ctx, s.outbox, achievementID)
+
+	return &attachment, nil
 }
 
 // File Upload HTTP Endpoint
@@ -1238,39 +1582,19 @@ func (s *achievementService) UploadAttachmentEndpoint(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "No file uploaded"})
 	}
 
-	// Validate file size (max 10MB)
-	if file.Size > 10*1024*1024 {
-		return c.Status(400).JSON(fiber.Map{"error": "File size too large (max 10MB)"})
-	}
-
-	// Validate file type
-	allowedTypes := map[string]bool{
-		"image/jpeg":         true,
-		"image/png":          true,
-		"image/gif":          true,
-		"application/pdf":    true,
-		"application/msword": true,
-		"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
-	}
-
-	if !allowedTypes[file.Header.Get("Content-Type")] {
-		return c.Status(400).JSON(fiber.Map{"error": "File type not allowed"})
+	content, err := file.Open()
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Failed to read uploaded file"})
 	}
+	defer content.Close()
 
-	// For this implementation, we'll simulate file storage
-	// In production, you would save to disk, cloud storage, etc.
-	fileName := file.Filename
-	fileURL := "/uploads/" + achievementID.String() + "/" + fileName
-	fileType := file.Header.Get("Content-Type")
-
-	// Save file (simplified - in production use proper file handling)
-	// err = c.SaveFile(file, "./uploads/"+achievementID.String()+"/"+fileName)
-	// if err != nil {
-	//     return c.Status(500).JSON(fiber.Map{"error": "Failed to save file"})
-	// }
-
-	err = s.UploadAttachment(c.Context(), userID, achievementID, fileName, fileURL, fileType)
+	attachment, err := s.UploadAttachment(c.Context(), userID, achievementID, file.Filename, file.Size, content)
 	if err != nil {
+		var apiErr *helper.APIError
+		if errors.As(err, &apiErr) {
+			return helper.ErrorDetail(c, apiErr)
+		}
+
 		switch err.Error() {
 		case "student data not found for this user":
 			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
@@ -1289,9 +1613,11 @@ func (s *achievementService) UploadAttachmentEndpoint(c *fiber.Ctx) error {
 		"status":  "success",
 		"message": "File uploaded successfully",
 		"data": fiber.Map{
-			"filename": fileName,
-			"url":      fileURL,
-			"type":     fileType,
+			"filename": attachment.FileName,
+			"url":      attachment.FileUrl,
+			"type":     attachment.FileType,
+			"size":     attachment.Size,
+			"checksum": attachment.Checksum,
 		},
 	})
 }
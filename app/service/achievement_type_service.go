@@ -0,0 +1,283 @@
+package service
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/utils"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// achievementTypeCodePattern: huruf kecil, angka dan underscore, diawali huruf (mis. "community_service")
+var achievementTypeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+type AchievementTypeService interface {
+	// Business logic methods
+	GetAchievementTypes(ctx context.Context, activeOnly bool) ([]model.AchievementType, error)
+	GetAchievementType(ctx context.Context, code string) (*model.AchievementType, error)
+	CreateAchievementType(ctx context.Context, req model.CreateAchievementTypeRequest) (*model.AchievementType, error)
+	UpdateAchievementType(ctx context.Context, code string, req model.UpdateAchievementTypeRequest) (*model.AchievementType, error)
+	DeleteAchievementType(ctx context.Context, code string) error
+
+	// HTTP endpoints
+	GetActiveAchievementTypesEndpoint(c *fiber.Ctx) error
+	GetAchievementTypesEndpoint(c *fiber.Ctx) error
+	GetAchievementTypeEndpoint(c *fiber.Ctx) error
+	CreateAchievementTypeEndpoint(c *fiber.Ctx) error
+	UpdateAchievementTypeEndpoint(c *fiber.Ctx) error
+	DeleteAchievementTypeEndpoint(c *fiber.Ctx) error
+}
+
+type achievementTypeService struct {
+	repo            repository.AchievementTypeRepository
+	achievementRepo repository.AchievementRepository
+}
+
+func NewAchievementTypeService(repo repository.AchievementTypeRepository, achievementRepo repository.AchievementRepository) AchievementTypeService {
+	return &achievementTypeService{repo: repo, achievementRepo: achievementRepo}
+}
+
+// GetAchievementTypes mengambil katalog tipe prestasi
+func (s *achievementTypeService) GetAchievementTypes(ctx context.Context, activeOnly bool) ([]model.AchievementType, error) {
+	types, err := s.repo.GetAllAchievementTypes(ctx, activeOnly)
+	if err != nil {
+		return nil, errors.New("failed to get achievement types")
+	}
+	return types, nil
+}
+
+// GetAchievementType mengambil satu tipe prestasi berdasarkan code
+func (s *achievementTypeService) GetAchievementType(ctx context.Context, code string) (*model.AchievementType, error) {
+	achievementType, err := s.repo.GetAchievementTypeByCode(ctx, utils.NormalizeAchievementType(code))
+	if err != nil {
+		return nil, errors.New("failed to get achievement type")
+	}
+	if achievementType == nil {
+		return nil, errors.New("achievement type not found")
+	}
+	return achievementType, nil
+}
+
+// CreateAchievementType menambah tipe prestasi baru ke katalog
+func (s *achievementTypeService) CreateAchievementType(ctx context.Context, req model.CreateAchievementTypeRequest) (*model.AchievementType, error) {
+	code := utils.NormalizeAchievementType(req.Code)
+	if !achievementTypeCodePattern.MatchString(code) {
+		return nil, errors.New("code must be 2-50 lowercase letters, digits or underscores")
+	}
+	if strings.TrimSpace(req.NameID) == "" {
+		return nil, errors.New("name_id is required")
+	}
+	if strings.TrimSpace(req.NameEN) == "" {
+		return nil, errors.New("name_en is required")
+	}
+	if req.DefaultPoints < 0 {
+		return nil, errors.New("default_points must not be negative")
+	}
+
+	existing, err := s.repo.GetAchievementTypeByCode(ctx, code)
+	if err != nil {
+		return nil, errors.New("failed to check achievement type")
+	}
+	if existing != nil {
+		return nil, errors.New("achievement type already exists")
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	now := time.Now()
+	achievementType := &model.AchievementType{
+		Code:          code,
+		NameID:        strings.TrimSpace(req.NameID),
+		NameEN:        strings.TrimSpace(req.NameEN),
+		IsActive:      isActive,
+		DefaultPoints: req.DefaultPoints,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.repo.CreateAchievementType(ctx, achievementType); err != nil {
+		return nil, errors.New("failed to create achievement type")
+	}
+
+	return achievementType, nil
+}
+
+// UpdateAchievementType mengubah nama, status aktif atau poin default tipe prestasi.
+// Code tidak bisa diubah karena tersimpan di dokumen prestasi MongoDB.
+func (s *achievementTypeService) UpdateAchievementType(ctx context.Context, code string, req model.UpdateAchievementTypeRequest) (*model.AchievementType, error) {
+	achievementType, err := s.GetAchievementType(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.NameID); name != "" {
+		achievementType.NameID = name
+	}
+	if name := strings.TrimSpace(req.NameEN); name != "" {
+		achievementType.NameEN = name
+	}
+	if req.IsActive != nil {
+		achievementType.IsActive = *req.IsActive
+	}
+	if req.DefaultPoints != nil {
+		if *req.DefaultPoints < 0 {
+			return nil, errors.New("default_points must not be negative")
+		}
+		achievementType.DefaultPoints = *req.DefaultPoints
+	}
+	achievementType.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateAchievementType(ctx, achievementType); err != nil {
+		return nil, errors.New("failed to update achievement type")
+	}
+
+	return achievementType, nil
+}
+
+// DeleteAchievementType menghapus tipe prestasi yang belum pernah dipakai.
+// Tipe yang sudah dipakai harus dinonaktifkan (is_active = false) agar statistik tetap konsisten.
+func (s *achievementTypeService) DeleteAchievementType(ctx context.Context, code string) error {
+	achievementType, err := s.GetAchievementType(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.achievementRepo.CountAchievementsByType(ctx, achievementType.Code)
+	if err != nil {
+		return errors.New("failed to check achievement type usage")
+	}
+	if count > 0 {
+		return errors.New("achievement type is in use; deactivate it instead")
+	}
+
+	if err := s.repo.DeleteAchievementType(ctx, achievementType.Code); err != nil {
+		return errors.New("failed to delete achievement type")
+	}
+
+	return nil
+}
+
+// achievementTypeErrorStatus memetakan error katalog tipe prestasi ke HTTP status code
+func achievementTypeErrorStatus(err error) int {
+	switch err.Error() {
+	case "achievement type not found":
+		return 404
+	case "achievement type already exists", "achievement type is in use; deactivate it instead":
+		return 409
+	case "code must be 2-50 lowercase letters, digits or underscores",
+		"name_id is required", "name_en is required", "default_points must not be negative":
+		return 400
+	default:
+		return 500
+	}
+}
+
+// GetActiveAchievementTypesEndpoint - GET /achievement-types (tipe aktif untuk form prestasi)
+func (s *achievementTypeService) GetActiveAchievementTypesEndpoint(c *fiber.Ctx) error {
+	types, err := s.GetAchievementTypes(c.Context(), true)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   types,
+	})
+}
+
+// GetAchievementTypesEndpoint - GET /admin/achievement-types?active_only=true
+func (s *achievementTypeService) GetAchievementTypesEndpoint(c *fiber.Ctx) error {
+	types, err := s.GetAchievementTypes(c.Context(), c.QueryBool("active_only", false))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   types,
+	})
+}
+
+// GetAchievementTypeEndpoint - GET /admin/achievement-types/:code
+func (s *achievementTypeService) GetAchievementTypeEndpoint(c *fiber.Ctx) error {
+	achievementType, err := s.GetAchievementType(c.Context(), c.Params("code"))
+	if err != nil {
+		return c.Status(achievementTypeErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   achievementType,
+	})
+}
+
+// CreateAchievementTypeEndpoint - POST /admin/achievement-types
+func (s *achievementTypeService) CreateAchievementTypeEndpoint(c *fiber.Ctx) error {
+	var req model.CreateAchievementTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+
+	achievementType, err := s.CreateAchievementType(c.Context(), req)
+	if err != nil {
+		status := achievementTypeErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to create achievement type"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Achievement type created successfully",
+		"data":    achievementType,
+	})
+}
+
+// UpdateAchievementTypeEndpoint - PUT /admin/achievement-types/:code
+func (s *achievementTypeService) UpdateAchievementTypeEndpoint(c *fiber.Ctx) error {
+	var req model.UpdateAchievementTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+
+	achievementType, err := s.UpdateAchievementType(c.Context(), c.Params("code"), req)
+	if err != nil {
+		status := achievementTypeErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to update achievement type"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Achievement type updated successfully",
+		"data":    achievementType,
+	})
+}
+
+// DeleteAchievementTypeEndpoint - DELETE /admin/achievement-types/:code
+func (s *achievementTypeService) DeleteAchievementTypeEndpoint(c *fiber.Ctx) error {
+	err := s.DeleteAchievementType(c.Context(), c.Params("code"))
+	if err != nil {
+		status := achievementTypeErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to delete achievement type"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Achievement type deleted successfully",
+	})
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// versionBackfillBatchSize membatasi jumlah reference yang diperiksa per batch backfill versi
+const versionBackfillBatchSize = 500
+
+// getAccessibleReference mengambil reference prestasi yang boleh dilihat user (pemilik, dosen wali, admin)
+func (s *achievementService) getAccessibleReference(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID) (*model.AchievementReference, error) {
+	ref, err := s.repo.GetAchievementReferenceByID(ctx, achievementID)
+	if err != nil || ref.Status == "deleted" {
+		return nil, errors.New("achievement not found")
+	}
+
+	if err := s.checkAchievementAccess(ctx, userID, isAdmin, ref); err != nil {
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+// latestSnapshot mengambil isi prestasi terakhir dari versi di PostgreSQL (sumber kebenaran) sebagai dasar versi
+// berikutnya; dokumen MongoDB bisa tertinggal selama event outbox tertunda. Prestasi yang belum punya versi
+// (belum terjangkau backfill) memakai dokumennya.
+func (s *achievementService) latestSnapshot(ctx context.Context, ref *model.AchievementReference) (*mongodb.Achievement, error) {
+	return latestAchievementSnapshot(ctx, s.repo, ref)
+}
+
+func latestAchievementSnapshot(ctx context.Context, repo repository.AchievementRepository, ref *model.AchievementReference) (*mongodb.Achievement, error) {
+	snapshot, err := repo.GetLatestAchievementSnapshot(ctx, ref.ID)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot != nil {
+		return snapshot, nil
+	}
+	return repo.GetAchievementDetailFromMongo(ctx, ref.MongoAchievementID)
+}
+
+// GetAchievementVersions mengambil daftar versi isi prestasi
+func (s *achievementService) GetAchievementVersions(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID) (*model.AchievementVersionsResponse, error) {
+	if _, err := s.getAccessibleReference(ctx, userID, isAdmin, achievementID); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.repo.GetAchievementVersions(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("failed to get achievement versions")
+	}
+
+	return &model.AchievementVersionsResponse{
+		AchievementID: achievementID,
+		Versions:      versions,
+	}, nil
+}
+
+// DiffAchievementVersions membandingkan isi dua versi prestasi per field (details, tags, attachments, dst.)
+func (s *achievementService) DiffAchievementVersions(ctx context.Context, userID uuid.UUID, isAdmin bool, achievementID uuid.UUID, fromVersion, toVersion int) (*model.AchievementVersionDiff, error) {
+	if _, err := s.getAccessibleReference(ctx, userID, isAdmin, achievementID); err != nil {
+		return nil, err
+	}
+
+	from, err := s.getAchievementVersion(ctx, achievementID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.getAchievementVersion(ctx, achievementID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := utils.DiffAchievements(*from.Snapshot, *to.Snapshot)
+	if err != nil {
+		return nil, errors.New("failed to diff achievement versions")
+	}
+
+	return &model.AchievementVersionDiff{
+		AchievementID: achievementID,
+		FromVersion:   fromVersion,
+		ToVersion:     toVersion,
+		Changes:       changes,
+	}, nil
+}
+
+// BackfillBaselineVersions menyimpan isi dokumen saat ini sebagai versi 1 untuk prestasi yang dibuat sebelum
+// versioning ada, sehingga diff dan pemulihan dokumen oleh rekonsiliasi punya titik awal
+func (s *achievementService) BackfillBaselineVersions(ctx context.Context) (int, error) {
+	saved := 0
+	afterID := uuid.Nil
+	for {
+		refs, err := s.repo.GetAchievementsWithoutVersions(ctx, afterID, versionBackfillBatchSize)
+		if err != nil {
+			return saved, errors.New("failed to get achievements without versions")
+		}
+		if len(refs) == 0 {
+			return saved, nil
+		}
+
+		n, err := s.repo.SaveBaselineVersionsFromDocuments(ctx, refs)
+		saved += n
+		if err != nil {
+			return saved, errors.New("failed to save baseline achievement versions")
+		}
+		afterID = refs[len(refs)-1].ID
+	}
+}
+
+// StartVersionBackfill menjalankan backfill versi awal sekali saat aplikasi mulai
+func (s *achievementService) StartVersionBackfill() {
+	saved, err := s.BackfillBaselineVersions(context.Background())
+	if err != nil {
+		log.Printf("⚠️ Failed backfilling achievement versions after %d achievement(s): %v", saved, err)
+		return
+	}
+	if saved > 0 {
+		log.Printf("🗂️ Baseline version saved for %d achievement(s)", saved)
+	}
+}
+
+func (s *achievementService) getAchievementVersion(ctx context.Context, achievementID uuid.UUID, version int) (*model.AchievementVersion, error) {
+	v, err := s.repo.GetAchievementVersion(ctx, achievementID, version)
+	if err != nil {
+		return nil, errors.New("failed to get achievement version")
+	}
+	if v == nil {
+		return nil, errors.New("achievement version not found")
+	}
+	return v, nil
+}
+
+// achievementVersionErrorStatus memetakan error versioning ke HTTP status code
+func achievementVersionErrorStatus(err error) int {
+	switch err.Error() {
+	case "achievement not found", "achievement version not found":
+		return 404
+	case "unauthorized: you do not have access to this achievement":
+		return 403
+	default:
+		return 500
+	}
+}
+
+// GetAchievementVersionsEndpoint - GET /achievements/:id/versions
+func (s *achievementService) GetAchievementVersionsEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+
+	result, err := s.GetAchievementVersions(c.Context(), userID, isAdminFromClaims(c), achievementID)
+	if err != nil {
+		return c.Status(achievementVersionErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// DiffAchievementVersionsEndpoint - GET /achievements/:id/versions/:a/diff/:b
+func (s *achievementService) DiffAchievementVersionsEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+
+	fromVersion, errFrom := strconv.Atoi(c.Params("a"))
+	toVersion, errTo := strconv.Atoi(c.Params("b"))
+	if errFrom != nil || errTo != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid version format"})
+	}
+
+	result, err := s.DiffAchievementVersions(c.Context(), userID, isAdminFromClaims(c), achievementID, fromVersion, toVersion)
+	if err != nil {
+		return c.Status(achievementVersionErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/utils"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// maxApprovalStages membatasi panjang approval chain
+const maxApprovalStages = 10
+
+type ApprovalChainService interface {
+	// Business logic methods
+	GetApprovalChains(ctx context.Context) ([]model.ApprovalChain, error)
+	GetApprovalChain(ctx context.Context, id uuid.UUID) (*model.ApprovalChain, error)
+	CreateApprovalChain(ctx context.Context, req model.ApprovalChainRequest) (*model.ApprovalChain, error)
+	UpdateApprovalChain(ctx context.Context, id uuid.UUID, req model.ApprovalChainRequest) (*model.ApprovalChain, error)
+	DeleteApprovalChain(ctx context.Context, id uuid.UUID) error
+
+	// HTTP endpoints
+	GetApprovalChainsEndpoint(c *fiber.Ctx) error
+	GetApprovalChainEndpoint(c *fiber.Ctx) error
+	CreateApprovalChainEndpoint(c *fiber.Ctx) error
+	UpdateApprovalChainEndpoint(c *fiber.Ctx) error
+	DeleteApprovalChainEndpoint(c *fiber.Ctx) error
+}
+
+type approvalChainService struct {
+	repo     repository.ApprovalChainRepository
+	typeRepo repository.AchievementTypeRepository
+}
+
+func NewApprovalChainService(repo repository.ApprovalChainRepository, typeRepo repository.AchievementTypeRepository) ApprovalChainService {
+	return &approvalChainService{repo: repo, typeRepo: typeRepo}
+}
+
+// GetApprovalChains mengambil semua approval chain
+func (s *approvalChainService) GetApprovalChains(ctx context.Context) ([]model.ApprovalChain, error) {
+	chains, err := s.repo.GetApprovalChains(ctx)
+	if err != nil {
+		return nil, errors.New("failed to get approval chains")
+	}
+	return chains, nil
+}
+
+// GetApprovalChain mengambil satu approval chain
+func (s *approvalChainService) GetApprovalChain(ctx context.Context, id uuid.UUID) (*model.ApprovalChain, error) {
+	chain, err := s.repo.GetApprovalChainByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("failed to get approval chain")
+	}
+	if chain == nil {
+		return nil, errors.New("approval chain not found")
+	}
+	return chain, nil
+}
+
+// CreateApprovalChain menambah approval chain untuk kombinasi tipe/tingkat prestasi yang belum punya chain
+func (s *approvalChainService) CreateApprovalChain(ctx context.Context, req model.ApprovalChainRequest) (*model.ApprovalChain, error) {
+	now := time.Now()
+	chain := &model.ApprovalChain{
+		ID:        uuid.New(),
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.applyRequest(ctx, chain, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateApprovalChain(ctx, chain); err != nil {
+		return nil, errors.New("failed to create approval chain")
+	}
+
+	return chain, nil
+}
+
+// UpdateApprovalChain mengganti isi approval chain. Prestasi yang sudah disubmit tetap memakai tahap
+// yang disalin saat submit; chain baru berlaku untuk submission berikutnya.
+func (s *approvalChainService) UpdateApprovalChain(ctx context.Context, id uuid.UUID, req model.ApprovalChainRequest) (*model.ApprovalChain, error) {
+	chain, err := s.GetApprovalChain(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyRequest(ctx, chain, req); err != nil {
+		return nil, err
+	}
+	chain.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateApprovalChain(ctx, chain); err != nil {
+		return nil, errors.New("failed to update approval chain")
+	}
+
+	return chain, nil
+}
+
+// DeleteApprovalChain menghapus approval chain; submission berikutnya memakai chain lain yang cocok
+func (s *approvalChainService) DeleteApprovalChain(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetApprovalChain(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteApprovalChain(ctx, id); err != nil {
+		return errors.New("failed to delete approval chain")
+	}
+	return nil
+}
+
+// applyRequest memvalidasi request dan mengisinya ke chain
+func (s *approvalChainService) applyRequest(ctx context.Context, chain *model.ApprovalChain, req model.ApprovalChainRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	achievementType, err := s.normalizeAchievementType(ctx, req.AchievementType)
+	if err != nil {
+		return err
+	}
+
+	competitionLevel, err := normalizeCompetitionLevel(req.CompetitionLevel)
+	if err != nil {
+		return err
+	}
+
+	stages, err := s.normalizeStages(ctx, req.Stages)
+	if err != nil {
+		return err
+	}
+
+	// Satu chain per kombinasi tipe & tingkat
+	existing, err := s.repo.GetApprovalChainByScope(ctx, achievementType, competitionLevel)
+	if err != nil {
+		return errors.New("failed to check approval chain")
+	}
+	if existing != nil && existing.ID != chain.ID {
+		return errors.New("approval chain already exists for this achievement type and level")
+	}
+
+	chain.Name = name
+	chain.AchievementType = achievementType
+	chain.CompetitionLevel = competitionLevel
+	chain.Stages = stages
+	if req.IsActive != nil {
+		chain.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+func (s *approvalChainService) normalizeAchievementType(ctx context.Context, value *string) (*string, error) {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil, nil
+	}
+
+	code := utils.NormalizeAchievementType(*value)
+	achievementType, err := s.typeRepo.GetAchievementTypeByCode(ctx, code)
+	if err != nil {
+		return nil, errors.New("failed to check achievement type")
+	}
+	if achievementType == nil {
+		return nil, errors.New("unknown achievement type")
+	}
+	return &code, nil
+}
+
+func normalizeCompetitionLevel(value *string) (*string, error) {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil, nil
+	}
+
+	level := strings.ToLower(strings.TrimSpace(*value))
+	allowed := utils.AchievementTypes["competition"].AllowedValues["competitionLevel"]
+	for _, v := range allowed {
+		if v == level {
+			return &level, nil
+		}
+	}
+	return nil, errors.New("competition_level must be one of: " + strings.Join(allowed, ", "))
+}
+
+// normalizeStages memastikan setiap tahap punya nama dan approver_role yang dikenal
+// ("advisor" atau nama role di tabel roles)
+func (s *approvalChainService) normalizeStages(ctx context.Context, stages []model.ApprovalStage) ([]model.ApprovalStage, error) {
+	if len(stages) == 0 || len(stages) > maxApprovalStages {
+		return nil, errors.New("stages must contain 1 to 10 stages")
+	}
+
+	normalized := make([]model.ApprovalStage, 0, len(stages))
+	for _, stage := range stages {
+		name := strings.TrimSpace(stage.Name)
+		if name == "" {
+			return nil, errors.New("stage name is required")
+		}
+
+		role := strings.TrimSpace(stage.ApproverRole)
+		if role == "" {
+			return nil, errors.New("stage approver_role is required")
+		}
+
+		if strings.EqualFold(role, model.ApproverRoleAdvisor) {
+			role = model.ApproverRoleAdvisor
+		} else {
+			exists, err := s.repo.RoleExists(ctx, role)
+			if err != nil {
+				return nil, errors.New("failed to check approver role")
+			}
+			if !exists {
+				return nil, errors.New("unknown approver role: " + role)
+			}
+		}
+
+		normalized = append(normalized, model.ApprovalStage{Name: name, ApproverRole: role})
+	}
+	return normalized, nil
+}
+
+// approvalChainErrorStatus memetakan error approval chain ke HTTP status code
+func approvalChainErrorStatus(err error) int {
+	switch {
+	case err.Error() == "approval chain not found":
+		return 404
+	case err.Error() == "approval chain already exists for this achievement type and level":
+		return 409
+	case err.Error() == "name is required", err.Error() == "unknown achievement type",
+		err.Error() == "stages must contain 1 to 10 stages", err.Error() == "stage name is required",
+		err.Error() == "stage approver_role is required",
+		strings.HasPrefix(err.Error(), "competition_level must be one of"),
+		strings.HasPrefix(err.Error(), "unknown approver role"):
+		return 400
+	default:
+		return 500
+	}
+}
+
+// GetApprovalChainsEndpoint - GET /admin/approval-chains
+func (s *approvalChainService) GetApprovalChainsEndpoint(c *fiber.Ctx) error {
+	chains, err := s.GetApprovalChains(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   chains,
+	})
+}
+
+// GetApprovalChainEndpoint - GET /admin/approval-chains/:id
+func (s *approvalChainService) GetApprovalChainEndpoint(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid approval chain ID format"})
+	}
+
+	chain, err := s.GetApprovalChain(c.Context(), id)
+	if err != nil {
+		return c.Status(approvalChainErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   chain,
+	})
+}
+
+// CreateApprovalChainEndpoint - POST /admin/approval-chains
+func (s *approvalChainService) CreateApprovalChainEndpoint(c *fiber.Ctx) error {
+	var req model.ApprovalChainRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+
+	chain, err := s.CreateApprovalChain(c.Context(), req)
+	if err != nil {
+		status := approvalChainErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to create approval chain"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Approval chain created successfully",
+		"data":    chain,
+	})
+}
+
+// UpdateApprovalChainEndpoint - PUT /admin/approval-chains/:id
+func (s *approvalChainService) UpdateApprovalChainEndpoint(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid approval chain ID format"})
+	}
+
+	var req model.ApprovalChainRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+
+	chain, err := s.UpdateApprovalChain(c.Context(), id, req)
+	if err != nil {
+		status := approvalChainErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to update approval chain"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Approval chain updated successfully",
+		"data":    chain,
+	})
+}
+
+// DeleteApprovalChainEndpoint - DELETE /admin/approval-chains/:id
+func (s *approvalChainService) DeleteApprovalChainEndpoint(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid approval chain ID format"})
+	}
+
+	if err := s.DeleteApprovalChain(c.Context(), id); err != nil {
+		status := approvalChainErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to delete approval chain"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Approval chain deleted successfully",
+	})
+}
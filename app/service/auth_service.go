@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	model "UASBE/app/model/Postgresql"
 	"UASBE/app/repository"
@@ -16,19 +17,23 @@ import (
 type AuthService interface {
 	Login(ctx context.Context, req model.LoginRequest) (*model.LoginResponse, error)
 	Logout(ctx context.Context, token string) error
+	RefreshToken(ctx context.Context, refreshToken string) (*model.LoginResponse, error)
+	RevokeRefreshToken(ctx context.Context, userID uuid.UUID, refreshToken string) error
 	GetProfile(ctx context.Context, userID uuid.UUID) (*model.UserProfileResponse, error) // <- ubah
 
 	// HTTP endpoints
 	LoginEndpoint(c *fiber.Ctx) error
 	LogoutEndpoint(c *fiber.Ctx) error
+	RefreshTokenEndpoint(c *fiber.Ctx) error
 	ProfileEndpoint(c *fiber.Ctx) error
+	JWKSEndpoint(c *fiber.Ctx) error
 }
 
 type authService struct {
-	authRepo *repository.AuthRepository
+	authRepo repository.AuthRepository
 }
 
-func NewAuthService(authRepo *repository.AuthRepository) AuthService {
+func NewAuthService(authRepo repository.AuthRepository) AuthService {
 	return &authService{authRepo: authRepo}
 }
 
@@ -91,9 +96,132 @@ func (s *authService) Login(ctx context.Context, req model.LoginRequest) (*model
 		return nil, errors.New("failed to generate token")
 	}
 
+	// Login selalu memulai family refresh token baru
+	refreshToken, err := s.issueRefreshToken(user.ID, uuid.New())
+	if err != nil {
+		return nil, errors.New("failed to generate refresh token")
+	}
+
+	return buildLoginResponse(user, roleName, permissions, token, refreshToken), nil
+}
+
+// RefreshToken menukar refresh token dengan access token baru dan merotasi refresh token.
+// Pemakaian ulang refresh token yang sudah dirotasi akan me-revoke seluruh family.
+func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*model.LoginResponse, error) {
+	if refreshToken == "" {
+		return nil, errors.New("refresh token is required")
+	}
+
+	stored, err := s.authRepo.GetRefreshTokenByHash(utils.HashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	if stored.RevokedAt != nil {
+		return nil, errors.New("refresh token has been revoked")
+	}
+
+	if stored.RotatedAt != nil {
+		// Token lama dipakai lagi → kemungkinan dicuri, revoke seluruh family
+		_ = s.authRepo.RevokeRefreshTokenFamily(stored.FamilyID)
+		return nil, errors.New("refresh token reuse detected")
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, errors.New("refresh token expired")
+	}
+
+	user, roleName, err := s.authRepo.FindUserByID(stored.UserID)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	if !user.ISActive {
+		_ = s.authRepo.RevokeRefreshTokenFamily(stored.FamilyID)
+		return nil, errors.New("account is inactive, please contact admin")
+	}
+
+	permissions, err := s.authRepo.GetPermissionsByRoleID(user.RoleID)
+	if err != nil {
+		return nil, errors.New("failed to fetch permissions")
+	}
+
+	token, err := utils.GenerateJWT(user.ID.String(), user.Username, roleName, permissions)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	newRefreshToken, newStored, err := newRefreshTokenRecord(user.ID, stored.FamilyID)
+	if err != nil {
+		return nil, errors.New("failed to generate refresh token")
+	}
+
+	err = s.authRepo.RotateRefreshToken(stored.ID, *newStored)
+	if err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenAlreadyRotated) {
+			_ = s.authRepo.RevokeRefreshTokenFamily(stored.FamilyID)
+			return nil, errors.New("refresh token reuse detected")
+		}
+		return nil, errors.New("failed to rotate refresh token")
+	}
+
+	return buildLoginResponse(user, roleName, permissions, token, newRefreshToken), nil
+}
+
+// RevokeRefreshToken me-revoke family dari refresh token milik user yang logout;
+// token milik user lain ditolak agar family-nya tidak bisa di-revoke orang lain
+func (s *authService) RevokeRefreshToken(ctx context.Context, userID uuid.UUID, refreshToken string) error {
+	stored, err := s.authRepo.GetRefreshTokenByHash(utils.HashRefreshToken(refreshToken))
+	if err != nil {
+		return errors.New("invalid refresh token")
+	}
+
+	if stored.UserID != userID {
+		return errors.New("unauthorized: refresh token does not belong to this user")
+	}
+
+	if err := s.authRepo.RevokeRefreshTokenFamily(stored.FamilyID); err != nil {
+		return errors.New("failed to revoke refresh token")
+	}
+	return nil
+}
+
+// issueRefreshToken membuat dan menyimpan refresh token baru untuk family tertentu
+func (s *authService) issueRefreshToken(userID, familyID uuid.UUID) (string, error) {
+	raw, record, err := newRefreshTokenRecord(userID, familyID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.authRepo.CreateRefreshToken(*record); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// newRefreshTokenRecord membuat refresh token mentah beserta record yang akan disimpan (hash saja)
+func newRefreshTokenRecord(userID, familyID uuid.UUID) (string, *model.RefreshToken, error) {
+	raw, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	return raw, &model.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: utils.HashRefreshToken(raw),
+		ExpiresAt: now.Add(utils.RefreshTokenTTL),
+		CreatedAt: now,
+	}, nil
+}
+
+func buildLoginResponse(user *model.Users, roleName string, permissions []string, token, refreshToken string) *model.LoginResponse {
 	return &model.LoginResponse{
 		Token:        token,
-		RefreshToken: "",
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(utils.AccessTokenTTL.Seconds()),
 		User: model.UserResponse{
 			ID:          user.ID,
 			Username:    user.Username,
@@ -101,7 +229,7 @@ func (s *authService) Login(ctx context.Context, req model.LoginRequest) (*model
 			Role:        roleName,
 			Permissions: permissions,
 		},
-	}, nil
+	}
 }
 
 func (s *authService) Logout(ctx context.Context, token string) error {
@@ -111,8 +239,15 @@ func (s *authService) Logout(ctx context.Context, token string) error {
 		return errors.New("failed to get token expiration")
 	}
 
-	// Add token to in-memory blacklist
-	utils.BlacklistManager.AddToken(token, expiresAt)
+	tokenID, err := utils.GetTokenID(token)
+	if err != nil {
+		return errors.New("failed to get token id")
+	}
+
+	// Simpan jti ke blacklist store (persistent, shared antar instance)
+	if err := utils.TokenBlacklist.Revoke(ctx, tokenID, expiresAt); err != nil {
+		return errors.New("failed to revoke token")
+	}
 	return nil
 }
 
@@ -166,7 +301,28 @@ func (s *authService) LogoutEndpoint(c *fiber.Ctx) error {
 		return c.Status(401).JSON(fiber.Map{"error": "Token is empty"})
 	}
 
-	err := s.Logout(c.Context(), token)
+	userID, err := extractUserIDFromClaimsAuth(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	// Refresh token bersifat opsional di body; jika ada, seluruh family-nya ikut di-revoke.
+	// Diproses sebelum access token agar logout yang ditolak tidak mengubah apa pun.
+	var req model.RefreshTokenRequest
+	if err := c.BodyParser(&req); err == nil && req.RefreshToken != "" {
+		if err := s.RevokeRefreshToken(c.Context(), userID, req.RefreshToken); err != nil {
+			switch err.Error() {
+			case "invalid refresh token":
+				return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+			case "unauthorized: refresh token does not belong to this user":
+				return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+			default:
+				return c.Status(500).JSON(fiber.Map{"error": "Logout failed"})
+			}
+		}
+	}
+
+	err = s.Logout(c.Context(), token)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Logout failed"})
 	}
@@ -177,6 +333,38 @@ func (s *authService) LogoutEndpoint(c *fiber.Ctx) error {
 	})
 }
 
+func (s *authService) RefreshTokenEndpoint(c *fiber.Ctx) error {
+	var req model.RefreshTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+
+	result, err := s.RefreshToken(c.Context(), req.RefreshToken)
+	if err != nil {
+		switch err.Error() {
+		case "refresh token is required":
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		case "invalid refresh token":
+			return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+		case "refresh token has been revoked":
+			return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+		case "refresh token reuse detected":
+			return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+		case "refresh token expired":
+			return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+		case "account is inactive, please contact admin":
+			return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return c.Status(500).JSON(fiber.Map{"error": "Refresh token failed"})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
+
 func (s *authService) ProfileEndpoint(c *fiber.Ctx) error {
 	userID, err := extractUserIDFromClaimsAuth(c)
 	if err != nil {
@@ -192,4 +380,10 @@ func (s *authService) ProfileEndpoint(c *fiber.Ctx) error {
 		"status": "success",
 		"data":   profile,
 	})
-}
\ No newline at end of file
+}
+
+// JWKSEndpoint mempublikasikan public key verifikasi JWT (RS256/EdDSA) untuk service lain
+func (s *authService) JWKSEndpoint(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(utils.CurrentKeySet().JWKS())
+}
@@ -0,0 +1,338 @@
+package service
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// delegationDateLayout adalah format tanggal start_date/end_date delegasi
+const delegationDateLayout = "2006-01-02"
+
+type DelegationService interface {
+	// Business logic methods
+	GetMyDelegations(ctx context.Context, userID uuid.UUID) (*model.LecturerDelegationsResponse, error)
+	GetDelegations(ctx context.Context) ([]model.VerificationDelegation, error)
+	DelegateVerification(ctx context.Context, userID uuid.UUID, req model.VerificationDelegationRequest) (*model.VerificationDelegation, error)
+	AssignSubstituteReviewer(ctx context.Context, adminID uuid.UUID, req model.VerificationDelegationRequest) (*model.VerificationDelegation, error)
+	RevokeDelegation(ctx context.Context, userID uuid.UUID, isAdmin bool, id uuid.UUID) error
+
+	// HTTP endpoints
+	GetMyDelegationsEndpoint(c *fiber.Ctx) error
+	GetDelegationsEndpoint(c *fiber.Ctx) error
+	DelegateVerificationEndpoint(c *fiber.Ctx) error
+	AssignSubstituteReviewerEndpoint(c *fiber.Ctx) error
+	RevokeDelegationEndpoint(c *fiber.Ctx) error
+}
+
+type delegationService struct {
+	repo            repository.DelegationRepository
+	achievementRepo repository.AchievementRepository
+}
+
+func NewDelegationService(repo repository.DelegationRepository, achievementRepo repository.AchievementRepository) DelegationService {
+	return &delegationService{repo: repo, achievementRepo: achievementRepo}
+}
+
+// GetMyDelegations mengambil delegasi yang diberikan dan diterima dosen yang sedang login
+func (s *delegationService) GetMyDelegations(ctx context.Context, userID uuid.UUID) (*model.LecturerDelegationsResponse, error) {
+	lecturer, err := s.achievementRepo.GetLecturerByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("lecturer data not found for this user")
+	}
+
+	given, err := s.repo.GetDelegationsGivenBy(ctx, lecturer.ID)
+	if err != nil {
+		return nil, errors.New("failed to get delegations")
+	}
+	received, err := s.repo.GetDelegationsReceivedBy(ctx, lecturer.ID)
+	if err != nil {
+		return nil, errors.New("failed to get delegations")
+	}
+
+	return &model.LecturerDelegationsResponse{Given: given, Received: received}, nil
+}
+
+// GetDelegations mengambil semua delegasi (admin)
+func (s *delegationService) GetDelegations(ctx context.Context) ([]model.VerificationDelegation, error) {
+	delegations, err := s.repo.GetDelegations(ctx)
+	if err != nil {
+		return nil, errors.New("failed to get delegations")
+	}
+	return delegations, nil
+}
+
+// DelegateVerification - dosen wali mendelegasikan hak verifikasinya ke dosen lain selama rentang tanggal
+func (s *delegationService) DelegateVerification(ctx context.Context, userID uuid.UUID, req model.VerificationDelegationRequest) (*model.VerificationDelegation, error) {
+	lecturer, err := s.achievementRepo.GetLecturerByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("lecturer data not found for this user")
+	}
+
+	return s.createDelegation(ctx, userID, lecturer.ID, model.DelegationKindDelegation, req)
+}
+
+// AssignSubstituteReviewer - admin menetapkan reviewer pengganti untuk dosen wali (mis. sedang cuti)
+func (s *delegationService) AssignSubstituteReviewer(ctx context.Context, adminID uuid.UUID, req model.VerificationDelegationRequest) (*model.VerificationDelegation, error) {
+	if req.DelegatorID == nil || *req.DelegatorID == uuid.Nil {
+		return nil, errors.New("delegator_id is required")
+	}
+
+	exists, err := s.repo.LecturerExists(ctx, *req.DelegatorID)
+	if err != nil {
+		return nil, errors.New("failed to check lecturer")
+	}
+	if !exists {
+		return nil, errors.New("delegator lecturer not found")
+	}
+
+	return s.createDelegation(ctx, adminID, *req.DelegatorID, model.DelegationKindSubstitute, req)
+}
+
+func (s *delegationService) createDelegation(ctx context.Context, createdBy, delegatorID uuid.UUID, kind string, req model.VerificationDelegationRequest) (*model.VerificationDelegation, error) {
+	if req.DelegateID == uuid.Nil {
+		return nil, errors.New("delegate_id is required")
+	}
+	if req.DelegateID == delegatorID {
+		return nil, errors.New("delegate must be a different lecturer")
+	}
+
+	startDate, endDate, err := parseDelegationPeriod(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.LecturerExists(ctx, req.DelegateID)
+	if err != nil {
+		return nil, errors.New("failed to check lecturer")
+	}
+	if !exists {
+		return nil, errors.New("delegate lecturer not found")
+	}
+
+	overlapping, err := s.repo.HasOverlappingDelegation(ctx, delegatorID, req.DelegateID, startDate, endDate)
+	if err != nil {
+		return nil, errors.New("failed to check delegation")
+	}
+	if overlapping {
+		return nil, errors.New("delegation to this lecturer already exists for an overlapping period")
+	}
+
+	var reason *string
+	if req.Reason != nil && strings.TrimSpace(*req.Reason) != "" {
+		trimmed := strings.TrimSpace(*req.Reason)
+		reason = &trimmed
+	}
+
+	delegation := &model.VerificationDelegation{
+		ID:          uuid.New(),
+		DelegatorID: delegatorID,
+		DelegateID:  req.DelegateID,
+		Kind:        kind,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Reason:      reason,
+		CreatedBy:   &createdBy,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.repo.CreateDelegation(ctx, delegation); err != nil {
+		return nil, errors.New("failed to create delegation")
+	}
+
+	return delegation, nil
+}
+
+// parseDelegationPeriod memvalidasi rentang tanggal delegasi (inklusif, tidak boleh berakhir di masa lalu)
+func parseDelegationPeriod(start, end string) (time.Time, time.Time, error) {
+	startDate, err := time.ParseInLocation(delegationDateLayout, strings.TrimSpace(start), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("start_date and end_date must use YYYY-MM-DD format")
+	}
+	endDate, err := time.ParseInLocation(delegationDateLayout, strings.TrimSpace(end), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("start_date and end_date must use YYYY-MM-DD format")
+	}
+
+	if endDate.Before(startDate) {
+		return time.Time{}, time.Time{}, errors.New("end_date must not be before start_date")
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if endDate.Before(today) {
+		return time.Time{}, time.Time{}, errors.New("end_date must not be in the past")
+	}
+
+	return startDate, endDate, nil
+}
+
+// RevokeDelegation mencabut delegasi. Dosen hanya dapat mencabut delegasi yang ia buat sendiri;
+// reviewer pengganti dari admin hanya dapat dicabut admin.
+func (s *delegationService) RevokeDelegation(ctx context.Context, userID uuid.UUID, isAdmin bool, id uuid.UUID) error {
+	delegation, err := s.repo.GetDelegationByID(ctx, id)
+	if err != nil {
+		return errors.New("failed to get delegation")
+	}
+	if delegation == nil {
+		return errors.New("delegation not found")
+	}
+
+	if !isAdmin {
+		lecturer, err := s.achievementRepo.GetLecturerByUserID(ctx, userID)
+		if err != nil || lecturer.ID != delegation.DelegatorID {
+			return errors.New("delegation not found")
+		}
+		if delegation.Kind != model.DelegationKindDelegation {
+			return errors.New("unauthorized: substitute reviewers can only be revoked by admins")
+		}
+	}
+
+	if delegation.RevokedAt != nil {
+		return errors.New("delegation already revoked")
+	}
+
+	if err := s.repo.RevokeDelegation(ctx, id, time.Now()); err != nil {
+		return errors.New("failed to revoke delegation")
+	}
+	return nil
+}
+
+// delegationErrorStatus memetakan error delegasi ke HTTP status code
+func delegationErrorStatus(err error) int {
+	switch {
+	case err.Error() == "lecturer data not found for this user", err.Error() == "delegation not found",
+		err.Error() == "delegate lecturer not found", err.Error() == "delegator lecturer not found":
+		return 404
+	case strings.HasPrefix(err.Error(), "unauthorized:"):
+		return 403
+	case err.Error() == "delegation to this lecturer already exists for an overlapping period",
+		err.Error() == "delegation already revoked":
+		return 409
+	case err.Error() == "delegate_id is required", err.Error() == "delegator_id is required",
+		err.Error() == "delegate must be a different lecturer",
+		err.Error() == "start_date and end_date must use YYYY-MM-DD format",
+		err.Error() == "end_date must not be before start_date", err.Error() == "end_date must not be in the past":
+		return 400
+	default:
+		return 500
+	}
+}
+
+// GetMyDelegationsEndpoint - GET /delegations
+func (s *delegationService) GetMyDelegationsEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := s.GetMyDelegations(c.Context(), userID)
+	if err != nil {
+		return c.Status(delegationErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// GetDelegationsEndpoint - GET /admin/delegations
+func (s *delegationService) GetDelegationsEndpoint(c *fiber.Ctx) error {
+	delegations, err := s.GetDelegations(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   delegations,
+	})
+}
+
+// DelegateVerificationEndpoint - POST /delegations
+func (s *delegationService) DelegateVerificationEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req model.VerificationDelegationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+
+	delegation, err := s.DelegateVerification(c.Context(), userID, req)
+	if err != nil {
+		status := delegationErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to create delegation"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Verification delegated successfully",
+		"data":    delegation,
+	})
+}
+
+// AssignSubstituteReviewerEndpoint - POST /admin/delegations
+func (s *delegationService) AssignSubstituteReviewerEndpoint(c *fiber.Ctx) error {
+	adminID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req model.VerificationDelegationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+
+	delegation, err := s.AssignSubstituteReviewer(c.Context(), adminID, req)
+	if err != nil {
+		status := delegationErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to assign substitute reviewer"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Substitute reviewer assigned successfully",
+		"data":    delegation,
+	})
+}
+
+// RevokeDelegationEndpoint - DELETE /delegations/:id dan DELETE /admin/delegations/:id
+func (s *delegationService) RevokeDelegationEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid delegation ID format"})
+	}
+
+	if err := s.RevokeDelegation(c.Context(), userID, isAdminFromClaims(c), id); err != nil {
+		status := delegationErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to revoke delegation"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Delegation revoked successfully",
+	})
+}
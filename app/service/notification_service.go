@@ -0,0 +1,106 @@
+package service
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// notificationListLimit membatasi jumlah notifikasi yang dikembalikan per permintaan
+const notificationListLimit = 50
+
+type NotificationService interface {
+	// Business logic methods
+	GetMyNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool) (*model.NotificationsResponse, error)
+	MarkNotificationRead(ctx context.Context, userID, notificationID uuid.UUID) error
+
+	// HTTP endpoints
+	GetMyNotificationsEndpoint(c *fiber.Ctx) error
+	MarkNotificationReadEndpoint(c *fiber.Ctx) error
+}
+
+type notificationService struct {
+	repo repository.NotificationRepository
+}
+
+func NewNotificationService(repo repository.NotificationRepository) NotificationService {
+	return &notificationService{repo: repo}
+}
+
+// GetMyNotifications mengambil notifikasi terbaru milik user yang login beserta jumlah yang belum dibaca
+func (s *notificationService) GetMyNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool) (*model.NotificationsResponse, error) {
+	notifications, err := s.repo.GetNotifications(ctx, userID, unreadOnly, notificationListLimit)
+	if err != nil {
+		return nil, errors.New("failed to get notifications")
+	}
+
+	unread, err := s.repo.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to get notifications")
+	}
+
+	return &model.NotificationsResponse{
+		Unread:        unread,
+		Notifications: notifications,
+	}, nil
+}
+
+// MarkNotificationRead menandai notifikasi milik user sudah dibaca
+func (s *notificationService) MarkNotificationRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	found, err := s.repo.MarkNotificationRead(ctx, userID, notificationID, time.Now())
+	if err != nil {
+		return errors.New("failed to update notification")
+	}
+	if !found {
+		return errors.New("notification not found")
+	}
+	return nil
+}
+
+// GetMyNotificationsEndpoint - GET /notifications?unread=true
+func (s *notificationService) GetMyNotificationsEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := s.GetMyNotifications(c.Context(), userID, c.QueryBool("unread"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// MarkNotificationReadEndpoint - POST /notifications/:id/read
+func (s *notificationService) MarkNotificationReadEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	notificationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid notification ID format"})
+	}
+
+	if err := s.MarkNotificationRead(c.Context(), userID, notificationID); err != nil {
+		if err.Error() == "notification not found" {
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Notification marked as read",
+	})
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/storage"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// reconciliationBatchSize membatasi jumlah reference/dokumen yang dibaca per query
+	reconciliationBatchSize = 500
+	// orphanDocumentGracePeriod melewati dokumen baru agar tulisan yang sedang berjalan tidak dianggap orphan
+	orphanDocumentGracePeriod = time.Hour
+)
+
+type ReconciliationService interface {
+	// Business logic methods
+	Reconcile(ctx context.Context, repair bool) (*model.ReconciliationReport, error)
+	StartReconciliationJob(interval time.Duration, repair bool)
+
+	// HTTP endpoints
+	ReconcileEndpoint(c *fiber.Ctx) error
+}
+
+type reconciliationService struct {
+	repo            repository.ReconciliationRepository
+	achievementRepo repository.AchievementRepository
+	outbox          AchievementOutboxService
+	storage         storage.Storage
+}
+
+func NewReconciliationService(repo repository.ReconciliationRepository, achievementRepo repository.AchievementRepository, outbox AchievementOutboxService, store storage.Storage) ReconciliationService {
+	return &reconciliationService{repo: repo, achievementRepo: achievementRepo, outbox: outbox, storage: store}
+}
+
+// Reconcile memindai kedua store dan melaporkan drift; jika repair, setiap drift diperbaiki
+// dengan PostgreSQL sebagai sumber kebenaran. Tanpa repair (dry-run) tidak ada yang diubah.
+func (s *reconciliationService) Reconcile(ctx context.Context, repair bool) (*model.ReconciliationReport, error) {
+	report := &model.ReconciliationReport{
+		DryRun:    !repair,
+		StartedAt: time.Now(),
+		Summary:   map[string]int{},
+		Issues:    []model.ReconciliationIssue{},
+	}
+
+	if err := s.scanReferences(ctx, report); err != nil {
+		return nil, errors.New("failed to scan achievement references")
+	}
+	if err := s.scanDocuments(ctx, report); err != nil {
+		return nil, errors.New("failed to scan achievement documents")
+	}
+
+	for i := range report.Issues {
+		issue := &report.Issues[i]
+		report.Summary[issue.Type]++
+		if !repair {
+			continue
+		}
+
+		if err := s.repairIssue(ctx, issue); err != nil {
+			msg := err.Error()
+			issue.Error = &msg
+			report.RepairFailed++
+			continue
+		}
+		issue.Repaired = true
+		report.Repaired++
+		report.AttachmentsLost += len(issue.LostAttachments)
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// scanReferences membandingkan setiap reference dengan dokumennya
+func (s *reconciliationService) scanReferences(ctx context.Context, report *model.ReconciliationReport) error {
+	afterID := uuid.Nil
+	for {
+		refs, err := s.repo.GetReferencesForReconciliation(ctx, afterID, reconciliationBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(refs) == 0 {
+			return nil
+		}
+
+		mongoIDs := make([]string, len(refs))
+		for i, ref := range refs {
+			mongoIDs[i] = ref.MongoAchievementID
+		}
+		docs, err := s.repo.GetDocumentsByMongoIDs(ctx, mongoIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, ref := range refs {
+			report.ReferencesScanned++
+			// Dokumen masih menunggu relay outbox; perbandingan baru bermakna setelah event diterapkan
+			if ref.HasPendingOutbox {
+				report.SkippedPending++
+				continue
+			}
+			doc, found := docs[ref.MongoAchievementID]
+			report.Issues = append(report.Issues, s.compareReference(ctx, ref, doc, found)...)
+		}
+
+		afterID = refs[len(refs)-1].ID
+	}
+}
+
+// compareReference menghasilkan drift satu reference terhadap dokumennya beserta rencana perbaikannya
+func (s *reconciliationService) compareReference(ctx context.Context, ref model.ReconciliationReference, doc model.ReconciliationDocument, found bool) []model.ReconciliationIssue {
+	newIssue := func(driftType, repair string) model.ReconciliationIssue {
+		id, studentID, status := ref.ID, ref.StudentID, ref.Status
+		return model.ReconciliationIssue{
+			Type:               driftType,
+			AchievementID:      &id,
+			MongoAchievementID: ref.MongoAchievementID,
+			ReferenceStatus:    &status,
+			ReferenceStudentID: &studentID,
+			Repair:             repair,
+		}
+	}
+
+	deleted := ref.Status == "deleted"
+	if !found {
+		// Reference di trash tanpa dokumen akan dibersihkan retention job
+		if deleted {
+			return nil
+		}
+		return []model.ReconciliationIssue{newIssue(model.DriftMissingDocument, s.planMissingDocumentRepair(ctx, ref))}
+	}
+
+	issues := []model.ReconciliationIssue{}
+	switch {
+	case doc.Deleted && !deleted:
+		issues = append(issues, newIssue(model.DriftDeletedDocument, model.RepairRestoreDocument))
+	case !doc.Deleted && deleted:
+		issues = append(issues, newIssue(model.DriftUndeletedDocument, model.RepairSoftDeleteDocument))
+	}
+	if doc.StudentID != ref.StudentID {
+		issue := newIssue(model.DriftStudentMismatch, model.RepairSetDocumentStudent)
+		documentStudentID := doc.StudentID
+		issue.DocumentStudentID = &documentStudentID
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// planMissingDocumentRepair: dokumen dibuat ulang dari versi terakhir jika ada, selain itu reference dipindah ke trash
+func (s *reconciliationService) planMissingDocumentRepair(ctx context.Context, ref model.ReconciliationReference) string {
+	if !primitive.IsValidObjectID(ref.MongoAchievementID) {
+		return model.RepairMoveReferenceToTrash
+	}
+	snapshot, err := s.repo.GetLatestAchievementSnapshot(ctx, ref.ID)
+	if err != nil || snapshot == nil {
+		return model.RepairMoveReferenceToTrash
+	}
+	return model.RepairRecreateDocument
+}
+
+// scanDocuments mencari dokumen yang tidak punya reference
+func (s *reconciliationService) scanDocuments(ctx context.Context, report *model.ReconciliationReport) error {
+	cutoff := time.Now().Add(-orphanDocumentGracePeriod)
+	afterID := ""
+	for {
+		docs, err := s.repo.GetDocumentsForReconciliation(ctx, afterID, reconciliationBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+
+		mongoIDs := make([]string, len(docs))
+		for i, doc := range docs {
+			mongoIDs[i] = doc.MongoAchievementID
+		}
+		referenced, err := s.repo.GetReferencedMongoIDs(ctx, mongoIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, doc := range docs {
+			report.DocumentsScanned++
+			if referenced[doc.MongoAchievementID] || doc.CreatedAt.After(cutoff) {
+				continue
+			}
+			documentStudentID := doc.StudentID
+			report.Issues = append(report.Issues, model.ReconciliationIssue{
+				Type:               model.DriftOrphanDocument,
+				MongoAchievementID: doc.MongoAchievementID,
+				DocumentStudentID:  &documentStudentID,
+				Repair:             model.RepairDeleteDocument,
+			})
+		}
+
+		afterID = docs[len(docs)-1].MongoAchievementID
+	}
+}
+
+// repairIssue menjalankan tindakan perbaikan satu drift. Perubahan dokumen yang masih punya reference
+// dicatat di outbox lalu diterapkan, sehingga berurutan dengan perubahan lain pada prestasi yang sama.
+func (s *reconciliationService) repairIssue(ctx context.Context, issue *model.ReconciliationIssue) error {
+	switch issue.Repair {
+	case model.RepairRecreateDocument:
+		snapshot, err := s.repo.GetLatestAchievementSnapshot(ctx, *issue.AchievementID)
+		if err != nil {
+			return err
+		}
+		if snapshot == nil {
+			return errors.New("no version snapshot available")
+		}
+		objectID, err := primitive.ObjectIDFromHex(issue.MongoAchievementID)
+		if err != nil {
+			return err
+		}
+		snapshot.ID = objectID
+		snapshot.StudentID = *issue.ReferenceStudentID
+		attachments, lost, err := s.relinkAttachments(ctx, snapshot.Attachments)
+		if err != nil {
+			return err
+		}
+		snapshot.Attachments = attachments
+		if err := s.repo.EnqueueDocumentUpsert(ctx, *issue.AchievementID, *snapshot); err != nil {
+			return err
+		}
+		issue.LostAttachments = lost
+
+	case model.RepairMoveReferenceToTrash:
+		return s.repo.MoveReferenceToTrash(ctx, *issue.AchievementID, "Reconciliation: MongoDB document missing")
+
+	case model.RepairRestoreDocument:
+		if err := s.repo.EnqueueDocumentOperation(ctx, *issue.AchievementID, model.OutboxOpRestoreDocument); err != nil {
+			return err
+		}
+
+	case model.RepairSoftDeleteDocument:
+		if err := s.repo.EnqueueDocumentOperation(ctx, *issue.AchievementID, model.OutboxOpSoftDeleteDocument); err != nil {
+			return err
+		}
+
+	case model.RepairSetDocumentStudent:
+		achievement, err := s.achievementRepo.GetAchievementDetailFromMongo(ctx, issue.MongoAchievementID)
+		if err != nil {
+			return err
+		}
+		achievement.StudentID = *issue.ReferenceStudentID
+		if err := s.repo.EnqueueDocumentUpsert(ctx, *issue.AchievementID, *achievement); err != nil {
+			return err
+		}
+
+	case model.RepairDeleteDocument:
+		return s.deleteOrphanDocument(ctx, issue.MongoAchievementID)
+
+	default:
+		return errors.New("unknown repair action: " + issue.Repair)
+	}
+
+	dispatchOrDefer(ctx, s.outbox, *issue.AchievementID)
+	return nil
+}
+
+// relinkAttachments memilah attachment snapshot untuk dokumen yang dibuat ulang: attachment yang storage key-nya
+// tercatat dan file-nya masih ada ditautkan kembali, sisanya dilaporkan sebagai hilang
+func (s *reconciliationService) relinkAttachments(ctx context.Context, attachments []mongodb.Attachment) ([]mongodb.Attachment, []model.LostAttachment, error) {
+	kept := []mongodb.Attachment{}
+	var lost []model.LostAttachment
+	for _, attachment := range attachments {
+		linked := attachment.StorageKey != ""
+		if linked && s.storage != nil {
+			reader, _, err := s.storage.Open(ctx, attachment.StorageKey)
+			switch {
+			case err == nil:
+				reader.Close()
+			case errors.Is(err, storage.ErrObjectNotFound):
+				linked = false
+			default:
+				return nil, nil, err
+			}
+		}
+		if linked {
+			kept = append(kept, attachment)
+			continue
+		}
+		lost = append(lost, model.LostAttachment{
+			AttachmentID: attachment.ID,
+			FileName:     attachment.FileName,
+			StorageKey:   attachment.StorageKey,
+		})
+	}
+	return kept, lost, nil
+}
+
+// deleteOrphanDocument menghapus dokumen tanpa reference beserta file attachment yang tidak dipakai dokumen lain
+func (s *reconciliationService) deleteOrphanDocument(ctx context.Context, mongoAchievementID string) error {
+	var storageKeys []string
+	achievement, err := s.achievementRepo.GetAchievementDetailFromMongo(ctx, mongoAchievementID)
+	switch {
+	case err == nil:
+		for _, attachment := range achievement.Attachments {
+			if attachment.StorageKey != "" {
+				storageKeys = append(storageKeys, attachment.StorageKey)
+			}
+		}
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return nil
+	default:
+		return err
+	}
+
+	if err := s.repo.DeleteOrphanDocument(ctx, mongoAchievementID); err != nil {
+		return err
+	}
+
+	deleteUnusedAttachmentFiles(ctx, s.achievementRepo, s.storage, storageKeys)
+	return nil
+}
+
+// StartReconciliationJob menjalankan Reconcile secara berkala dan mencatat ringkasannya di log
+func (s *reconciliationService) StartReconciliationJob(interval time.Duration, repair bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		report, err := s.Reconcile(context.Background(), repair)
+		if err != nil {
+			log.Printf("⚠️ Failed reconciling achievements: %v", err)
+			continue
+		}
+		if len(report.Issues) > 0 {
+			log.Printf("🔍 Reconciliation found %d drift issue(s) %v (dry_run=%t, %d repaired, %d failed, %d attachment(s) lost)",
+				len(report.Issues), report.Summary, report.DryRun, report.Repaired, report.RepairFailed, report.AttachmentsLost)
+		}
+	}
+}
+
+// ReconcileEndpoint - POST /admin/reconciliation?dry_run=false
+// Default dry-run: hanya laporan, tanpa perbaikan
+func (s *reconciliationService) ReconcileEndpoint(c *fiber.Ctx) error {
+	report, err := s.Reconcile(c.Context(), !c.QueryBool("dry_run", true))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   report,
+	})
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/utils"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxReviewDeadlineHours membatasi SLA review (90 hari)
+const maxReviewDeadlineHours = 24 * 90
+
+type ReviewSLAService interface {
+	// Business logic methods
+	GetReviewSLAs(ctx context.Context) ([]model.ReviewSLA, error)
+	SetReviewSLA(ctx context.Context, level string, req model.ReviewSLARequest) (*model.ReviewSLA, error)
+	DeleteReviewSLA(ctx context.Context, level string) error
+	EscalateOverdueAchievements(ctx context.Context) ([]model.AchievementEscalation, error)
+	GetOverdueAchievements(ctx context.Context) ([]model.OverdueAchievement, error)
+	GetReviewerThroughput(ctx context.Context, dateFrom, dateTo string) ([]model.ReviewerThroughput, error)
+	StartEscalationScheduler(interval time.Duration)
+
+	// HTTP endpoints
+	GetReviewSLAsEndpoint(c *fiber.Ctx) error
+	SetReviewSLAEndpoint(c *fiber.Ctx) error
+	DeleteReviewSLAEndpoint(c *fiber.Ctx) error
+	GetOverdueAchievementsEndpoint(c *fiber.Ctx) error
+	GetReviewerThroughputEndpoint(c *fiber.Ctx) error
+}
+
+type reviewSLAService struct {
+	repo     repository.ReviewSLARepository
+	roleRepo repository.ApprovalChainRepository
+}
+
+func NewReviewSLAService(repo repository.ReviewSLARepository, roleRepo repository.ApprovalChainRepository) ReviewSLAService {
+	return &reviewSLAService{repo: repo, roleRepo: roleRepo}
+}
+
+// GetReviewSLAs mengambil semua SLA review
+func (s *reviewSLAService) GetReviewSLAs(ctx context.Context) ([]model.ReviewSLA, error) {
+	slas, err := s.repo.GetReviewSLAs(ctx)
+	if err != nil {
+		return nil, errors.New("failed to get review SLAs")
+	}
+	return slas, nil
+}
+
+// SetReviewSLA membuat atau mengganti SLA satu level ("default" atau competitionLevel).
+// Batas waktu dihitung saat submit, sehingga perubahan berlaku untuk submission berikutnya.
+func (s *reviewSLAService) SetReviewSLA(ctx context.Context, level string, req model.ReviewSLARequest) (*model.ReviewSLA, error) {
+	level, err := normalizeReviewLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DeadlineHours < 1 || req.DeadlineHours > maxReviewDeadlineHours {
+		return nil, errors.New("deadline_hours must be between 1 and 2160")
+	}
+
+	role := strings.TrimSpace(req.EscalateToRole)
+	if role == "" {
+		return nil, errors.New("escalate_to_role is required")
+	}
+	exists, err := s.roleRepo.RoleExists(ctx, role)
+	if err != nil {
+		return nil, errors.New("failed to check escalation role")
+	}
+	if !exists {
+		return nil, errors.New("unknown escalation role: " + role)
+	}
+
+	sla := &model.ReviewSLA{
+		Level:          level,
+		DeadlineHours:  req.DeadlineHours,
+		EscalateToRole: role,
+		UpdatedAt:      time.Now(),
+	}
+	if err := s.repo.UpsertReviewSLA(ctx, sla); err != nil {
+		return nil, errors.New("failed to save review SLA")
+	}
+	return sla, nil
+}
+
+// DeleteReviewSLA menghapus SLA khusus satu level; SLA default tidak dapat dihapus
+func (s *reviewSLAService) DeleteReviewSLA(ctx context.Context, level string) error {
+	level, err := normalizeReviewLevel(level)
+	if err != nil {
+		return err
+	}
+	if level == model.ReviewSLALevelDefault {
+		return errors.New("default review SLA cannot be deleted")
+	}
+
+	sla, err := s.repo.GetReviewSLA(ctx, level)
+	if err != nil {
+		return errors.New("failed to get review SLA")
+	}
+	if sla == nil {
+		return errors.New("review SLA not found")
+	}
+
+	if err := s.repo.DeleteReviewSLA(ctx, level); err != nil {
+		return errors.New("failed to delete review SLA")
+	}
+	return nil
+}
+
+// normalizeReviewLevel menerima "default" atau salah satu competitionLevel yang dikenal
+func normalizeReviewLevel(value string) (string, error) {
+	level := strings.ToLower(strings.TrimSpace(value))
+	if level == model.ReviewSLALevelDefault {
+		return level, nil
+	}
+
+	allowed := utils.AchievementTypes["competition"].AllowedValues["competitionLevel"]
+	for _, v := range allowed {
+		if v == level {
+			return level, nil
+		}
+	}
+	return "", errors.New("level must be one of: default, " + strings.Join(allowed, ", "))
+}
+
+// EscalateOverdueAchievements mengeskalasi prestasi yang melewati batas waktu review ke role tujuan SLA-nya
+func (s *reviewSLAService) EscalateOverdueAchievements(ctx context.Context) ([]model.AchievementEscalation, error) {
+	escalations, err := s.repo.EscalateOverdueAchievements(ctx, time.Now())
+	if err != nil {
+		return nil, errors.New("failed to escalate overdue achievements")
+	}
+	return escalations, nil
+}
+
+// StartEscalationScheduler menjalankan EscalateOverdueAchievements secara berkala
+func (s *reviewSLAService) StartEscalationScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		escalations, err := s.EscalateOverdueAchievements(context.Background())
+		if err != nil {
+			log.Printf("⚠️ Failed escalating overdue achievements: %v", err)
+			continue
+		}
+		if len(escalations) > 0 {
+			log.Printf("⏰ Escalated %d overdue achievement(s)", len(escalations))
+		}
+	}
+}
+
+// GetOverdueAchievements mengambil prestasi 'submitted' yang melewati batas waktu review
+func (s *reviewSLAService) GetOverdueAchievements(ctx context.Context) ([]model.OverdueAchievement, error) {
+	overdue, err := s.repo.GetOverdueAchievements(ctx, time.Now())
+	if err != nil {
+		return nil, errors.New("failed to get overdue achievements")
+	}
+	return overdue, nil
+}
+
+// GetReviewerThroughput merangkum jumlah dan lama keputusan per reviewer; date_to inklusif
+func (s *reviewSLAService) GetReviewerThroughput(ctx context.Context, dateFrom, dateTo string) ([]model.ReviewerThroughput, error) {
+	var from, to *time.Time
+	if dateFrom != "" {
+		t, err := time.ParseInLocation("2006-01-02", dateFrom, time.Local)
+		if err != nil {
+			return nil, errors.New("invalid date_from format, use YYYY-MM-DD")
+		}
+		from = &t
+	}
+	if dateTo != "" {
+		t, err := time.ParseInLocation("2006-01-02", dateTo, time.Local)
+		if err != nil {
+			return nil, errors.New("invalid date_to format, use YYYY-MM-DD")
+		}
+		t = t.AddDate(0, 0, 1)
+		to = &t
+	}
+
+	throughput, err := s.repo.GetReviewerThroughput(ctx, from, to)
+	if err != nil {
+		return nil, errors.New("failed to get reviewer throughput")
+	}
+	return throughput, nil
+}
+
+// reviewSLAErrorStatus memetakan error SLA review ke HTTP status code
+func reviewSLAErrorStatus(err error) int {
+	switch {
+	case err.Error() == "review SLA not found":
+		return 404
+	case err.Error() == "deadline_hours must be between 1 and 2160", err.Error() == "escalate_to_role is required",
+		err.Error() == "default review SLA cannot be deleted",
+		err.Error() == "invalid date_from format, use YYYY-MM-DD", err.Error() == "invalid date_to format, use YYYY-MM-DD",
+		strings.HasPrefix(err.Error(), "level must be one of"),
+		strings.HasPrefix(err.Error(), "unknown escalation role"):
+		return 400
+	default:
+		return 500
+	}
+}
+
+// GetReviewSLAsEndpoint - GET /admin/review-slas
+func (s *reviewSLAService) GetReviewSLAsEndpoint(c *fiber.Ctx) error {
+	slas, err := s.GetReviewSLAs(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   slas,
+	})
+}
+
+// SetReviewSLAEndpoint - PUT /admin/review-slas/:level
+func (s *reviewSLAService) SetReviewSLAEndpoint(c *fiber.Ctx) error {
+	var req model.ReviewSLARequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+
+	sla, err := s.SetReviewSLA(c.Context(), c.Params("level"), req)
+	if err != nil {
+		status := reviewSLAErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to save review SLA"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Review SLA saved successfully",
+		"data":    sla,
+	})
+}
+
+// DeleteReviewSLAEndpoint - DELETE /admin/review-slas/:level
+func (s *reviewSLAService) DeleteReviewSLAEndpoint(c *fiber.Ctx) error {
+	if err := s.DeleteReviewSLA(c.Context(), c.Params("level")); err != nil {
+		status := reviewSLAErrorStatus(err)
+		if status == 500 {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to delete review SLA"})
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Review SLA deleted successfully",
+	})
+}
+
+// GetOverdueAchievementsEndpoint - GET /admin/achievements/overdue
+func (s *reviewSLAService) GetOverdueAchievementsEndpoint(c *fiber.Ctx) error {
+	overdue, err := s.GetOverdueAchievements(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   overdue,
+	})
+}
+
+// GetReviewerThroughputEndpoint - GET /admin/reports/reviewer-throughput?date_from=&date_to=
+func (s *reviewSLAService) GetReviewerThroughputEndpoint(c *fiber.Ctx) error {
+	throughput, err := s.GetReviewerThroughput(c.Context(), c.Query("date_from"), c.Query("date_to"))
+	if err != nil {
+		return c.Status(reviewSLAErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   throughput,
+	})
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ScoringService interface {
+	// Business logic methods
+	GetScoringRuleSets(ctx context.Context) ([]model.ScoringRuleSet, error)
+	GetScoringRuleSet(ctx context.Context, version int) (*model.ScoringRuleSet, error)
+	CreateScoringRuleSet(ctx context.Context, userID uuid.UUID, req model.CreateScoringRuleSetRequest) (*model.ScoringRuleSet, error)
+	ActivateScoringRuleSet(ctx context.Context, version int) (*model.ScoringRuleSet, error)
+	RecalculateScores(ctx context.Context, userID uuid.UUID, version *int) (*model.RecalculateScoresResult, error)
+
+	// HTTP endpoints
+	GetScoringRuleSetsEndpoint(c *fiber.Ctx) error
+	GetScoringRuleSetEndpoint(c *fiber.Ctx) error
+	CreateScoringRuleSetEndpoint(c *fiber.Ctx) error
+	ActivateScoringRuleSetEndpoint(c *fiber.Ctx) error
+	RecalculateScoresEndpoint(c *fiber.Ctx) error
+}
+
+type scoringService struct {
+	repo            repository.ScoringRepository
+	achievementRepo repository.AchievementRepository
+	typeRepo        repository.AchievementTypeRepository
+	outbox          AchievementOutboxService
+}
+
+func NewScoringService(repo repository.ScoringRepository, achievementRepo repository.AchievementRepository, typeRepo repository.AchievementTypeRepository, outbox AchievementOutboxService) ScoringService {
+	return &scoringService{repo: repo, achievementRepo: achievementRepo, typeRepo: typeRepo, outbox: outbox}
+}
+
+// GetScoringRuleSets mengambil semua versi aturan skor
+func (s *scoringService) GetScoringRuleSets(ctx context.Context) ([]model.ScoringRuleSet, error) {
+	ruleSets, err := s.repo.GetScoringRuleSets(ctx)
+	if err != nil {
+		return nil, errors.New("failed to get scoring rules")
+	}
+	return ruleSets, nil
+}
+
+// GetScoringRuleSet mengambil satu versi aturan skor
+func (s *scoringService) GetScoringRuleSet(ctx context.Context, version int) (*model.ScoringRuleSet, error) {
+	ruleSet, err := s.repo.GetScoringRuleSetByVersion(ctx, version)
+	if err != nil {
+		return nil, errors.New("failed to get scoring rules")
+	}
+	if ruleSet == nil {
+		return nil, errors.New("scoring rule set not found")
+	}
+	return ruleSet, nil
+}
+
+// CreateScoringRuleSet membuat versi aturan skor baru. Versi lama tidak pernah diubah
+// sehingga skor historis tetap bisa dihitung ulang dengan aturan yang sama.
+func (s *scoringService) CreateScoringRuleSet(ctx context.Context, userID uuid.UUID, req model.CreateScoringRuleSetRequest) (*model.ScoringRuleSet, error) {
+	rules, err := normalizeScoringRules(req.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	// Poin dasar tiap tipe ikut disimpan di versi ini; override dari request tetap dipakai
+	types, err := s.typeRepo.GetAllAchievementTypes(ctx, false)
+	if err != nil {
+		return nil, errors.New("failed to create scoring rules")
+	}
+	if rules.TypePoints == nil {
+		rules.TypePoints = make(map[string]int, len(types))
+	}
+	for _, t := range types {
+		if _, ok := rules.TypePoints[t.Code]; !ok {
+			rules.TypePoints[t.Code] = t.DefaultPoints
+		}
+	}
+
+	now := time.Now()
+	ruleSet := &model.ScoringRuleSet{
+		Description: strings.TrimSpace(req.Description),
+		Rules:       rules,
+		IsActive:    req.Activate,
+		CreatedBy:   &userID,
+		CreatedAt:   now,
+	}
+	if req.Activate {
+		ruleSet.ActivatedAt = &now
+	}
+
+	if err := s.repo.CreateScoringRuleSet(ctx, ruleSet); err != nil {
+		return nil, errors.New("failed to create scoring rules")
+	}
+
+	return ruleSet, nil
+}
+
+// ActivateScoringRuleSet menjadikan satu versi sebagai aturan skor untuk verifikasi berikutnya.
+// Skor yang sudah ada tidak berubah sampai recalculation dijalankan.
+func (s *scoringService) ActivateScoringRuleSet(ctx context.Context, version int) (*model.ScoringRuleSet, error) {
+	if _, err := s.GetScoringRuleSet(ctx, version); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ActivateScoringRuleSet(ctx, version); err != nil {
+		return nil, errors.New("failed to activate scoring rules")
+	}
+
+	return s.GetScoringRuleSet(ctx, version)
+}
+
+// RecalculateScores menghitung ulang skor semua prestasi verified dengan versi aturan tertentu
+// (nil = versi aktif). Kegagalan per prestasi dicatat di hasil tanpa menghentikan proses.
+func (s *scoringService) RecalculateScores(ctx context.Context, userID uuid.UUID, version *int) (*model.RecalculateScoresResult, error) {
+	var ruleSet *model.ScoringRuleSet
+	var err error
+	if version != nil {
+		ruleSet, err = s.GetScoringRuleSet(ctx, *version)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		ruleSet, err = s.repo.GetActiveScoringRuleSet(ctx)
+		if err != nil {
+			return nil, errors.New("failed to get scoring rules")
+		}
+		if ruleSet == nil {
+			return nil, errors.New("no active scoring rules")
+		}
+	}
+
+	refs, err := s.achievementRepo.GetAchievementReferencesByStatus(ctx, "verified")
+	if err != nil {
+		return nil, errors.New("failed to get verified achievements")
+	}
+
+	result := &model.RecalculateScoresResult{RuleVersion: ruleSet.Version}
+	for _, ref := range refs {
+		result.Processed++
+		if err := s.recalculateScore(ctx, ruleSet, ref, userID); err != nil {
+			result.Failed++
+			result.FailedIDs = append(result.FailedIDs, ref.ID)
+			continue
+		}
+		result.Updated++
+	}
+
+	return result, nil
+}
+
+// recalculateScore menyimpan skor baru ke PostgreSQL (sumber kebenaran) beserta event outbox poin untuk MongoDB.
+// Isi prestasi diambil dari versi terakhir, bukan dokumen MongoDB yang bisa tertinggal.
+func (s *scoringService) recalculateScore(ctx context.Context, ruleSet *model.ScoringRuleSet, ref model.AchievementReference, userID uuid.UUID) error {
+	achievement, err := latestAchievementSnapshot(ctx, s.achievementRepo, &ref)
+	if err != nil {
+		return err
+	}
+
+	score, err := calculateScore(ctx, s.typeRepo, ruleSet, ref.ID, achievement, &userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.SaveAchievementScore(ctx, score); err != nil {
+		return err
+	}
+
+	dispatchOrDefer(ctx, s.outbox, ref.ID)
+	return nil
+}
+
+// normalizeScoringRules menyeragamkan key (lowercase) dan memvalidasi nilai poin
+func normalizeScoringRules(rules model.ScoringRules) (model.ScoringRules, error) {
+	tables := []*map[string]int{
+		&rules.TypePoints, &rules.CompetitionLevelPoints, &rules.RankPoints,
+		&rules.MedalTypePoints, &rules.PublicationTypePoints, &rules.PositionPoints,
+	}
+
+	for _, table := range tables {
+		if *table == nil {
+			continue
+		}
+		normalized := make(map[string]int, len(*table))
+		for key, points := range *table {
+			if points < 0 {
+				return rules, errors.New("scoring points must not be negative")
+			}
+			normalized[strings.ToLower(strings.TrimSpace(key))] = points
+		}
+		*table = normalized
+	}
+
+	for key := range rules.RankPoints {
+		if rank, err := strconv.Atoi(key); err != nil || rank < 1 {
+			return rules, errors.New("rank_points keys must be positive integers")
+		}
+	}
+
+	return rules, nil
+}
+
+// scoringErrorStatus memetakan error scoring ke HTTP status code
+func scoringErrorStatus(err error) int {
+	switch err.Error() {
+	case "scoring rule set not found":
+		return 404
+	case "scoring points must not be negative", "rank_points keys must be positive integers":
+		return 400
+	default:
+		return 500
+	}
+}
+
+// GetScoringRuleSetsEndpoint - GET /admin/scoring/rules
+func (s *scoringService) GetScoringRuleSetsEndpoint(c *fiber.Ctx) error {
+	ruleSets, err := s.GetScoringRuleSets(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   ruleSets,
+	})
+}
+
+// GetScoringRuleSetEndpoint - GET /admin/scoring/rules/:version
+func (s *scoringService) GetScoringRuleSetEndpoint(c *fiber.Ctx) error {
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid version format"})
+	}
+
+	ruleSet, err := s.GetScoringRuleSet(c.Context(), version)
+	if err != nil {
+		return c.Status(scoringErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   ruleSet,
+	})
+}
+
+// CreateScoringRuleSetEndpoint - POST /admin/scoring/rules
+func (s *scoringService) CreateScoringRuleSetEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req model.CreateScoringRuleSetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+
+	ruleSet, err := s.CreateScoringRuleSet(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(scoringErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Scoring rules created successfully",
+		"data":    ruleSet,
+	})
+}
+
+// ActivateScoringRuleSetEndpoint - POST /admin/scoring/rules/:version/activate
+func (s *scoringService) ActivateScoringRuleSetEndpoint(c *fiber.Ctx) error {
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid version format"})
+	}
+
+	ruleSet, err := s.ActivateScoringRuleSet(c.Context(), version)
+	if err != nil {
+		return c.Status(scoringErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Scoring rules activated successfully",
+		"data":    ruleSet,
+	})
+}
+
+// RecalculateScoresEndpoint - POST /admin/scoring/recalculate
+func (s *scoringService) RecalculateScoresEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req model.RecalculateScoresRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+		}
+	}
+
+	result, err := s.RecalculateScores(c.Context(), userID, req.Version)
+	if err != nil {
+		return c.Status(scoringErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Achievement scores recalculated",
+		"data":    result,
+	})
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/storage"
+	"UASBE/utils"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// trashPurgeBatchSize membatasi jumlah prestasi yang dihapus permanen per jalan retention job
+const trashPurgeBatchSize = 200
+
+type TrashService interface {
+	// Business logic methods
+	GetTrash(ctx context.Context, page, limit int) (*model.TrashListResponse, error)
+	RestoreAchievement(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID) (*model.AchievementReference, error)
+	PurgeExpiredAchievements(ctx context.Context) (*model.TrashPurgeResult, error)
+	StartRetentionJob(interval time.Duration)
+
+	// HTTP endpoints
+	GetTrashEndpoint(c *fiber.Ctx) error
+	RestoreAchievementEndpoint(c *fiber.Ctx) error
+	PurgeExpiredAchievementsEndpoint(c *fiber.Ctx) error
+}
+
+type trashService struct {
+	repo    repository.AchievementRepository
+	outbox  AchievementOutboxService
+	storage storage.Storage
+}
+
+func NewTrashService(repo repository.AchievementRepository, outbox AchievementOutboxService, store storage.Storage) TrashService {
+	return &trashService{repo: repo, outbox: outbox, storage: store}
+}
+
+// retention mengembalikan masa simpan trash saat ini
+func (s *trashService) retention() time.Duration {
+	return time.Duration(utils.TrashRetentionDays) * 24 * time.Hour
+}
+
+// GetTrash mengambil prestasi yang dihapus beserta kapan akan dihapus permanen
+func (s *trashService) GetTrash(ctx context.Context, page, limit int) (*model.TrashListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	achievements, total, err := s.repo.GetDeletedAchievements(ctx, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to get deleted achievements")
+	}
+
+	for i := range achievements {
+		achievements[i].PurgeAfter = achievements[i].DeletedAt.Add(s.retention())
+		detail, err := s.repo.GetAchievementDetailFromMongo(ctx, achievements[i].MongoAchievementID)
+		if err == nil {
+			achievements[i].Details = detail
+		}
+	}
+
+	return &model.TrashListResponse{
+		Achievements:  achievements,
+		RetentionDays: utils.TrashRetentionDays,
+		Pagination: model.PaginationMetadata{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: (total + limit - 1) / limit,
+		},
+	}, nil
+}
+
+// RestoreAchievement mengembalikan prestasi dari trash ke 'draft' (reference PostgreSQL lalu dokumen MongoDB lewat outbox)
+func (s *trashService) RestoreAchievement(ctx context.Context, userID uuid.UUID, achievementID uuid.UUID) (*model.AchievementReference, error) {
+	ref, err := s.repo.GetAchievementReferenceByID(ctx, achievementID)
+	if err != nil {
+		return nil, errors.New("achievement not found")
+	}
+	if ref.Status != "deleted" {
+		return nil, errors.New("only deleted achievements can be restored")
+	}
+
+	if _, err := s.repo.GetAchievementDetailFromMongo(ctx, ref.MongoAchievementID); err != nil {
+		return nil, errors.New("achievement document not found")
+	}
+
+	if err := s.repo.UpdateAchievementReferenceToRestored(ctx, achievementID, userID); err != nil {
+		if errors.Is(err, repository.ErrAchievementNotDeleted) {
+			return nil, errors.New("only deleted achievements can be restored")
+		}
+		return nil, errors.New("failed to restore achievement")
+	}
+
+	dispatchOrDefer(ctx, s.outbox, achievementID)
+
+	return s.repo.GetAchievementReferenceByID(ctx, achievementID)
+}
+
+// PurgeExpiredAchievements menghapus permanen prestasi yang berada di trash lebih lama dari masa simpan,
+// termasuk file attachment yang tidak dipakai prestasi lain
+func (s *trashService) PurgeExpiredAchievements(ctx context.Context) (*model.TrashPurgeResult, error) {
+	refs, err := s.repo.GetAchievementsDeletedBefore(ctx, time.Now().Add(-s.retention()), trashPurgeBatchSize)
+	if err != nil {
+		return nil, errors.New("failed to get expired achievements")
+	}
+
+	result := &model.TrashPurgeResult{}
+	for _, ref := range refs {
+		deleted, err := s.purgeAchievement(ctx, ref)
+		if errors.Is(err, repository.ErrAchievementNotDeleted) {
+			result.Skipped++
+			continue
+		}
+		if err != nil {
+			log.Printf("⚠️ Failed purging achievement %s: %v", ref.ID, err)
+			result.Failed++
+			continue
+		}
+		result.Purged++
+		result.FilesDeleted += deleted
+	}
+
+	return result, nil
+}
+
+// purgeAchievement menghapus reference PostgreSQL, dokumen MongoDB, lalu file attachment-nya.
+// Storage key dicatat sebelum apa pun dihapus, dan reference dihapus lebih dulu: jika gagal, dokumen masih utuh
+// dan jalan berikutnya mengulang dengan aman. Jika dokumen gagal dihapus setelahnya, reconciliation menanganinya
+// sebagai orphan document. Reference yang sudah dipulihkan (ErrAchievementNotDeleted) tidak disentuh sama sekali,
+// karena dokumennya mungkin masih menunggu outbox restore.
+func (s *trashService) purgeAchievement(ctx context.Context, ref model.AchievementReference) (int, error) {
+	var storageKeys []string
+	achievement, err := s.repo.GetAchievementDetailFromMongo(ctx, ref.MongoAchievementID)
+	documentExists := err == nil
+	switch {
+	case documentExists:
+		for _, attachment := range achievement.Attachments {
+			if attachment.StorageKey != "" {
+				storageKeys = append(storageKeys, attachment.StorageKey)
+			}
+		}
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return 0, err
+	}
+
+	if err := s.repo.PurgeAchievementReference(ctx, ref.ID); err != nil {
+		return 0, err
+	}
+
+	if documentExists {
+		if err := s.repo.PurgeAchievementMongo(ctx, ref.MongoAchievementID); err != nil {
+			return 0, err
+		}
+	}
+
+	return deleteUnusedAttachmentFiles(ctx, s.repo, s.storage, storageKeys), nil
+}
+
+// deleteUnusedAttachmentFiles menghapus file attachment yang sudah tidak dipakai dokumen mana pun.
+// Storage content-addressed: file hanya dihapus jika tidak dipakai attachment lain.
+func deleteUnusedAttachmentFiles(ctx context.Context, repo repository.AchievementRepository, store storage.Storage, storageKeys []string) int {
+	deleted := 0
+	if store == nil {
+		return deleted
+	}
+	for _, key := range storageKeys {
+		count, err := repo.CountAttachmentsByStorageKey(ctx, key)
+		if err != nil || count > 0 {
+			continue
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			log.Printf("⚠️ Failed deleting attachment file %s: %v", key, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted
+}
+
+// StartRetentionJob menjalankan PurgeExpiredAchievements secara berkala
+func (s *trashService) StartRetentionJob(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		result, err := s.PurgeExpiredAchievements(context.Background())
+		if err != nil {
+			log.Printf("⚠️ Failed purging expired achievements: %v", err)
+			continue
+		}
+		if result.Purged > 0 || result.Failed > 0 {
+			log.Printf("🗑️ Purged %d achievement(s) from trash (%d failed, %d file(s) deleted)", result.Purged, result.Failed, result.FilesDeleted)
+		}
+	}
+}
+
+// GetTrashEndpoint - GET /admin/achievements/trash
+func (s *trashService) GetTrashEndpoint(c *fiber.Ctx) error {
+	result, err := s.GetTrash(c.Context(), c.QueryInt("page", 1), c.QueryInt("limit", 10))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// RestoreAchievementEndpoint - POST /admin/achievements/:id/restore
+func (s *trashService) RestoreAchievementEndpoint(c *fiber.Ctx) error {
+	userID, err := extractUserIDFromClaims(c)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	achievementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid achievement ID format"})
+	}
+
+	result, err := s.RestoreAchievement(c.Context(), userID, achievementID)
+	if err != nil {
+		switch err.Error() {
+		case "achievement not found", "achievement document not found":
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		case "only deleted achievements can be restored":
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to restore achievement"})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Achievement restored to draft",
+		"data":    result,
+	})
+}
+
+// PurgeExpiredAchievementsEndpoint - POST /admin/achievements/trash/purge
+// Menjalankan retention job sekarang juga (hanya prestasi yang sudah melewati masa simpan)
+func (s *trashService) PurgeExpiredAchievementsEndpoint(c *fiber.Ctx) error {
+	result, err := s.PurgeExpiredAchievements(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
@@ -0,0 +1,54 @@
+// Command reconcile memindai achievement_references (PostgreSQL) dan koleksi achievements (MongoDB)
+// lalu mencetak laporan drift sebagai JSON. Default dry-run; gunakan -repair untuk memperbaiki.
+//
+//	go run ./cmd/reconcile [-repair]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"UASBE/app/repository"
+	"UASBE/app/service"
+	"UASBE/config"
+	"UASBE/database"
+	"UASBE/storage"
+)
+
+func main() {
+	repair := flag.Bool("repair", false, "repair detected drift (default: dry-run report only)")
+	flag.Parse()
+
+	config.LoadConfig()
+	cfg := config.AppConfig
+
+	dbpool := database.NewPostgresDB(cfg)
+	defer dbpool.Close()
+	mongoClient := database.ConnectMongoDB(cfg.MongoURI)
+	defer mongoClient.Disconnect(context.Background())
+	mongoColl := database.GetCollection(mongoClient, cfg.MongoDB, "achievements")
+
+	// storage dipakai untuk menghapus file attachment milik dokumen orphan
+	store, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed initializing storage: %v", err)
+	}
+
+	achievementRepo := repository.NewAchievementRepository(dbpool, mongoColl)
+	outboxService := service.NewAchievementOutboxService(repository.NewAchievementOutboxRepository(dbpool, mongoColl), store)
+	reconciliationService := service.NewReconciliationService(repository.NewReconciliationRepository(dbpool, mongoColl), achievementRepo, outboxService, store)
+
+	report, err := reconciliationService.Reconcile(context.Background(), *repair)
+	if err != nil {
+		log.Fatalf("❌ Reconciliation failed: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("❌ Failed writing report: %v", err)
+	}
+}
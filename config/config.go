@@ -19,7 +19,32 @@ type Config struct {
 	MongoURI string
 	MongoDB  string
 
-	JWTSecret string
+	JWTSecret          string
+	JWTAlgorithm       string
+	JWTKeyID           string
+	JWTPrivateKeyFile  string
+	JWTVerifyKeysDir   string
+	JWTPreviousSecrets string
+
+	StorageDriver   string
+	StorageLocalDir string
+	S3Endpoint      string
+	S3AccessKey     string
+	S3SecretKey     string
+	S3Bucket        string
+	S3Region        string
+	S3UseSSL        string
+
+	AttachmentURLSecret              string
+	AttachmentMaxFileBytes           string
+	AttachmentMaxPerAchievement      string
+	AttachmentMaxBytesPerAchievement string
+	StudentStorageQuotaBytes         string
+
+	TrashRetentionDays string
+
+	ReconciliationIntervalHours string
+	ReconciliationAutoRepair    string
 }
 
 var AppConfig Config
@@ -43,6 +68,31 @@ func LoadConfig() {
 		MongoURI: os.Getenv("MONGO_URI"),
 		MongoDB:  os.Getenv("MONGO_DB"),
 
-		JWTSecret: os.Getenv("JWT_SECRET"),
+		JWTSecret:          os.Getenv("JWT_SECRET"),
+		JWTAlgorithm:       os.Getenv("JWT_ALGORITHM"),
+		JWTKeyID:           os.Getenv("JWT_KEY_ID"),
+		JWTPrivateKeyFile:  os.Getenv("JWT_PRIVATE_KEY_FILE"),
+		JWTVerifyKeysDir:   os.Getenv("JWT_VERIFY_KEYS_DIR"),
+		JWTPreviousSecrets: os.Getenv("JWT_PREVIOUS_SECRETS"),
+
+		StorageDriver:   os.Getenv("STORAGE_DRIVER"),
+		StorageLocalDir: os.Getenv("STORAGE_LOCAL_DIR"),
+		S3Endpoint:      os.Getenv("S3_ENDPOINT"),
+		S3AccessKey:     os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:     os.Getenv("S3_SECRET_KEY"),
+		S3Bucket:        os.Getenv("S3_BUCKET"),
+		S3Region:        os.Getenv("S3_REGION"),
+		S3UseSSL:        os.Getenv("S3_USE_SSL"),
+
+		AttachmentURLSecret:              os.Getenv("ATTACHMENT_URL_SECRET"),
+		AttachmentMaxFileBytes:           os.Getenv("ATTACHMENT_MAX_FILE_BYTES"),
+		AttachmentMaxPerAchievement:      os.Getenv("ATTACHMENT_MAX_PER_ACHIEVEMENT"),
+		AttachmentMaxBytesPerAchievement: os.Getenv("ATTACHMENT_MAX_BYTES_PER_ACHIEVEMENT"),
+		StudentStorageQuotaBytes:         os.Getenv("STUDENT_STORAGE_QUOTA_BYTES"),
+
+		TrashRetentionDays: os.Getenv("TRASH_RETENTION_DAYS"),
+
+		ReconciliationIntervalHours: os.Getenv("RECONCILIATION_INTERVAL_HOURS"),
+		ReconciliationAutoRepair:    os.Getenv("RECONCILIATION_AUTO_REPAIR"),
 	}
 }
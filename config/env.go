@@ -1,13 +1,24 @@
 package config
 
 import (
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
 func NewFiber() *fiber.App {
-	app := fiber.New()
+	// Upload attachment dibaca secara streaming; batas body sedikit di atas batas file
+	maxFileBytes := 10 * 1024 * 1024
+	if v, err := strconv.Atoi(AppConfig.AttachmentMaxFileBytes); err == nil && v > 0 {
+		maxFileBytes = v
+	}
+
+	app := fiber.New(fiber.Config{
+		BodyLimit:         maxFileBytes + 2*1024*1024,
+		StreamRequestBody: true,
+	})
 
 	app.Use(logger.New())
 	app.Use(cors.New())
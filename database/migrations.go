@@ -0,0 +1,319 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrations berisi DDL untuk tabel tambahan di luar skema awal SRS.
+// Setiap statement harus idempotent (IF NOT EXISTS) karena dijalankan setiap startup.
+var migrations = []string{
+	// Token revocation (logout) — dipakai bersama oleh semua instance API
+	`CREATE TABLE IF NOT EXISTS revoked_tokens (
+		jti        TEXT PRIMARY KEY,
+		expires_at TIMESTAMPTZ NOT NULL,
+		revoked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at)`,
+
+	// Refresh token (hash saja) dengan rotation per family
+	`CREATE TABLE IF NOT EXISTS refresh_tokens (
+		id          UUID PRIMARY KEY,
+		user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		family_id   UUID NOT NULL,
+		token_hash  TEXT NOT NULL UNIQUE,
+		expires_at  TIMESTAMPTZ NOT NULL,
+		created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		rotated_at  TIMESTAMPTZ,
+		replaced_by UUID,
+		revoked_at  TIMESTAMPTZ
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id)`,
+
+	// Riwayat status prestasi — ditulis dalam transaksi yang sama dengan update achievement_references
+	`CREATE TABLE IF NOT EXISTS achievement_status_logs (
+		id             UUID PRIMARY KEY,
+		achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+		status         VARCHAR(20) NOT NULL,
+		changed_by     UUID REFERENCES users(id),
+		rejection_note TEXT,
+		created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`ALTER TABLE achievement_status_logs ADD COLUMN IF NOT EXISTS previous_status VARCHAR(20)`,
+	`ALTER TABLE achievement_status_logs ADD COLUMN IF NOT EXISTS note TEXT`,
+	`CREATE INDEX IF NOT EXISTS idx_achievement_status_logs_achievement_id ON achievement_status_logs (achievement_id, created_at)`,
+
+	// Override kuota storage attachment per mahasiswa (default dari STUDENT_STORAGE_QUOTA_BYTES)
+	`CREATE TABLE IF NOT EXISTS student_storage_quotas (
+		student_id  UUID PRIMARY KEY REFERENCES students(id) ON DELETE CASCADE,
+		quota_bytes BIGINT NOT NULL CHECK (quota_bytes >= 0),
+		updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+
+	// Katalog tipe prestasi (achievementType di MongoDB harus salah satu code di sini)
+	`CREATE TABLE IF NOT EXISTS achievement_types (
+		code           VARCHAR(50) PRIMARY KEY,
+		name_id        VARCHAR(100) NOT NULL,
+		name_en        VARCHAR(100) NOT NULL,
+		is_active      BOOLEAN NOT NULL DEFAULT TRUE,
+		default_points INTEGER NOT NULL DEFAULT 0 CHECK (default_points >= 0),
+		created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`INSERT INTO achievement_types (code, name_id, name_en, default_points) VALUES
+		('academic', 'Akademik', 'Academic', 10),
+		('competition', 'Kompetisi', 'Competition', 20),
+		('organization', 'Organisasi', 'Organization', 10),
+		('publication', 'Publikasi', 'Publication', 25),
+		('certification', 'Sertifikasi', 'Certification', 15),
+		('other', 'Lainnya', 'Other', 5)
+	ON CONFLICT (code) DO NOTHING`,
+
+	// Aturan skor berversi; hanya satu versi yang aktif dan versi lama tidak diubah
+	`CREATE TABLE IF NOT EXISTS scoring_rule_sets (
+		version      INTEGER PRIMARY KEY,
+		description  TEXT NOT NULL DEFAULT '',
+		rules        JSONB NOT NULL,
+		is_active    BOOLEAN NOT NULL DEFAULT FALSE,
+		created_by   UUID REFERENCES users(id),
+		created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		activated_at TIMESTAMPTZ
+	)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_scoring_rule_sets_active ON scoring_rule_sets (is_active) WHERE is_active`,
+	`INSERT INTO scoring_rule_sets (version, description, rules, is_active, activated_at) VALUES (1, 'Initial scoring rules', '{
+		"competition_level_points": {"international": 50, "national": 30, "regional": 20, "local": 10},
+		"rank_points": {"1": 30, "2": 20, "3": 10},
+		"medal_type_points": {"gold": 30, "silver": 20, "bronze": 10},
+		"publication_type_points": {"journal": 40, "conference": 25, "book": 50},
+		"position_points": {"ketua": 20, "chairman": 20, "wakil ketua": 15, "vice chairman": 15, "sekretaris": 10, "secretary": 10, "bendahara": 10, "treasurer": 10, "anggota": 5, "member": 5}
+	}', TRUE, NOW())
+	ON CONFLICT (version) DO NOTHING`,
+
+	// Skor terakhir per prestasi beserta breakdown dan versi aturan yang dipakai
+	`CREATE TABLE IF NOT EXISTS achievement_scores (
+		achievement_id UUID PRIMARY KEY REFERENCES achievement_references(id) ON DELETE CASCADE,
+		rule_version   INTEGER NOT NULL REFERENCES scoring_rule_sets(version),
+		points         INTEGER NOT NULL,
+		breakdown      JSONB NOT NULL,
+		calculated_by  UUID REFERENCES users(id),
+		calculated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+
+	// Resubmission: rejected -> revised -> submitted, revision bertambah setiap kali dikirim ulang
+	`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 0`,
+	// Snapshot isi prestasi saat ditolak, pembanding untuk reviewer saat resubmission
+	`CREATE TABLE IF NOT EXISTS achievement_rejections (
+		id             UUID PRIMARY KEY,
+		achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+		revision       INTEGER NOT NULL,
+		rejection_note TEXT NOT NULL,
+		snapshot       JSONB NOT NULL,
+		rejected_by    UUID REFERENCES users(id),
+		rejected_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_achievement_rejections_achievement_id ON achievement_rejections (achievement_id, rejected_at)`,
+
+	// Versi isi prestasi (snapshot penuh dokumen MongoDB); baris tidak pernah diubah setelah ditulis
+	`CREATE TABLE IF NOT EXISTS achievement_versions (
+		id             UUID PRIMARY KEY,
+		achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+		version        INTEGER NOT NULL,
+		snapshot       JSONB NOT NULL,
+		changed_by     UUID REFERENCES users(id),
+		created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		UNIQUE (achievement_id, version)
+	)`,
+
+	// Approval chain per tipe/tingkat prestasi; tanpa chain yang cocok cukup disetujui dosen wali
+	`CREATE TABLE IF NOT EXISTS approval_chains (
+		id                UUID PRIMARY KEY,
+		name              VARCHAR(100) NOT NULL,
+		achievement_type  VARCHAR(50) REFERENCES achievement_types(code),
+		competition_level VARCHAR(50),
+		stages            JSONB NOT NULL,
+		is_active         BOOLEAN NOT NULL DEFAULT TRUE,
+		created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_approval_chains_scope ON approval_chains (COALESCE(achievement_type, ''), COALESCE(competition_level, ''))`,
+	// Tahap persetujuan per revision prestasi, disalin dari approval chain saat submit
+	`CREATE TABLE IF NOT EXISTS achievement_approvals (
+		id             UUID PRIMARY KEY,
+		achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+		revision       INTEGER NOT NULL,
+		stage_order    INTEGER NOT NULL,
+		stage_name     VARCHAR(100) NOT NULL,
+		approver_role  VARCHAR(50) NOT NULL,
+		status         VARCHAR(20) NOT NULL DEFAULT 'pending',
+		decided_by     UUID REFERENCES users(id),
+		note           TEXT,
+		decided_at     TIMESTAMPTZ,
+		created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		UNIQUE (achievement_id, revision, stage_order)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_achievement_approvals_pending ON achievement_approvals (approver_role) WHERE status = 'pending'`,
+	// Prestasi yang sudah disubmit sebelum approval chain ada tetap cukup disetujui dosen wali
+	`INSERT INTO achievement_approvals (id, achievement_id, revision, stage_order, stage_name, approver_role)
+		SELECT gen_random_uuid(), ar.id, ar.revision, 1, 'Dosen Wali', 'advisor'
+		FROM achievement_references ar
+		WHERE ar.status = 'submitted'
+		  AND NOT EXISTS (SELECT 1 FROM achievement_approvals aa WHERE aa.achievement_id = ar.id AND aa.revision = ar.revision)`,
+
+	// Delegasi hak verifikasi dosen wali ke dosen lain (oleh dosen sendiri atau admin sebagai pengganti)
+	`CREATE TABLE IF NOT EXISTS verification_delegations (
+		id           UUID PRIMARY KEY,
+		delegator_id UUID NOT NULL REFERENCES lecturers(id) ON DELETE CASCADE,
+		delegate_id  UUID NOT NULL REFERENCES lecturers(id) ON DELETE CASCADE,
+		kind         VARCHAR(20) NOT NULL DEFAULT 'delegation',
+		start_date   DATE NOT NULL,
+		end_date     DATE NOT NULL,
+		reason       TEXT,
+		created_by   UUID REFERENCES users(id),
+		revoked_at   TIMESTAMPTZ,
+		created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CHECK (delegator_id <> delegate_id),
+		CHECK (end_date >= start_date)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_verification_delegations_active ON verification_delegations (delegator_id, delegate_id) WHERE revoked_at IS NULL`,
+	`CREATE INDEX IF NOT EXISTS idx_verification_delegations_delegate ON verification_delegations (delegate_id) WHERE revoked_at IS NULL`,
+	// Dosen wali asli yang diwakili saat keputusan diambil oleh delegasi/pengganti
+	`ALTER TABLE achievement_approvals ADD COLUMN IF NOT EXISTS on_behalf_of UUID REFERENCES users(id)`,
+	`ALTER TABLE achievement_approvals ADD COLUMN IF NOT EXISTS delegation_id UUID REFERENCES verification_delegations(id)`,
+	`ALTER TABLE achievement_status_logs ADD COLUMN IF NOT EXISTS on_behalf_of UUID REFERENCES users(id)`,
+	`ALTER TABLE achievement_status_logs ADD COLUMN IF NOT EXISTS delegation_id UUID REFERENCES verification_delegations(id)`,
+	// Tahap persetujuan yang diputuskan; keputusan tahap antara dicatat dengan status tetap 'submitted'
+	`ALTER TABLE achievement_status_logs ADD COLUMN IF NOT EXISTS stage_order INTEGER`,
+	`ALTER TABLE achievement_status_logs ADD COLUMN IF NOT EXISTS stage_name VARCHAR(100)`,
+
+	// Batas waktu review per tingkat prestasi; level 'default' dipakai jika tidak ada SLA khusus
+	`CREATE TABLE IF NOT EXISTS review_slas (
+		level            VARCHAR(50) PRIMARY KEY,
+		deadline_hours   INTEGER NOT NULL CHECK (deadline_hours > 0),
+		escalate_to_role VARCHAR(50) NOT NULL,
+		updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`INSERT INTO review_slas (level, deadline_hours, escalate_to_role) VALUES ('default', 168, 'Admin')
+	ON CONFLICT (level) DO NOTHING`,
+	// Batas waktu dihitung saat submit; escalated_* diisi scheduler ketika batas terlewati
+	`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS review_level VARCHAR(50)`,
+	`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS review_due_at TIMESTAMPTZ`,
+	`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMPTZ`,
+	`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS escalated_to VARCHAR(50)`,
+	`CREATE INDEX IF NOT EXISTS idx_achievement_references_review_due_at ON achievement_references (review_due_at) WHERE status = 'submitted'`,
+	`UPDATE achievement_references
+		SET review_due_at = submitted_at + make_interval(hours => (SELECT deadline_hours FROM review_slas WHERE level = 'default'))
+		WHERE status = 'submitted' AND review_due_at IS NULL AND submitted_at IS NOT NULL`,
+	// Lama keputusan per tahap (detik sejak tahap menjadi tahap berjalan) untuk laporan throughput reviewer
+	`ALTER TABLE achievement_approvals ADD COLUMN IF NOT EXISTS decision_seconds BIGINT`,
+
+	// Thread komentar review; balasan hanya satu tingkat (parent_id menunjuk komentar utama)
+	`CREATE TABLE IF NOT EXISTS achievement_comments (
+		id             UUID PRIMARY KEY,
+		achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+		parent_id      UUID REFERENCES achievement_comments(id) ON DELETE CASCADE,
+		author_id      UUID NOT NULL REFERENCES users(id),
+		body           TEXT NOT NULL,
+		field_path     VARCHAR(200),
+		attachment_id  VARCHAR(100),
+		is_resolved    BOOLEAN NOT NULL DEFAULT FALSE,
+		resolved_by    UUID REFERENCES users(id),
+		resolved_at    TIMESTAMPTZ,
+		created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_achievement_comments_achievement ON achievement_comments (achievement_id, created_at)`,
+
+	// Notifikasi per user (mis. dosen wali diberi tahu saat mahasiswa menarik prestasi dari review)
+	`CREATE TABLE IF NOT EXISTS notifications (
+		id             UUID PRIMARY KEY,
+		user_id        UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		type           VARCHAR(50) NOT NULL,
+		achievement_id UUID REFERENCES achievement_references(id) ON DELETE CASCADE,
+		message        TEXT NOT NULL,
+		read_at        TIMESTAMPTZ,
+		created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at DESC)`,
+
+	// Waktu masuk trash untuk retention job; data lama memakai updated_at saat dihapus
+	`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ`,
+	`UPDATE achievement_references SET deleted_at = updated_at WHERE status = 'deleted' AND deleted_at IS NULL`,
+	`CREATE INDEX IF NOT EXISTS idx_achievement_references_deleted_at ON achievement_references (deleted_at) WHERE status = 'deleted'`,
+
+	// Outbox perubahan dokumen MongoDB; ditulis satu transaksi dengan achievement_references lalu diterapkan relay.
+	// Tanpa foreign key agar event tetap ada walau reference dihapus permanen.
+	`CREATE TABLE IF NOT EXISTS achievement_outbox (
+		id                   BIGSERIAL PRIMARY KEY,
+		achievement_id       UUID NOT NULL,
+		mongo_achievement_id VARCHAR(24) NOT NULL,
+		operation            VARCHAR(30) NOT NULL,
+		payload              BYTEA,
+		attempts             INT NOT NULL DEFAULT 0,
+		last_error           TEXT,
+		next_attempt_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		processed_at         TIMESTAMPTZ
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_achievement_outbox_pending ON achievement_outbox (achievement_id, id) WHERE processed_at IS NULL`,
+
+	// Read model listing: field detail dari dokumen MongoDB, ditulis satu transaksi dengan event outbox upsert.
+	// Status, mahasiswa dan poin tetap diambil lewat join agar selalu terbaru.
+	`CREATE TABLE IF NOT EXISTS achievement_read_model (
+		achievement_id    UUID PRIMARY KEY REFERENCES achievement_references(id) ON DELETE CASCADE,
+		title             TEXT,
+		achievement_type  VARCHAR(100),
+		competition_level VARCHAR(50),
+		medal_type        VARCHAR(50),
+		tags              TEXT[] NOT NULL DEFAULT '{}',
+		event_date        TIMESTAMPTZ,
+		synced_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_achievement_read_model_type ON achievement_read_model (achievement_type)`,
+	`CREATE INDEX IF NOT EXISTS idx_achievement_read_model_level ON achievement_read_model (competition_level)`,
+	`CREATE INDEX IF NOT EXISTS idx_achievement_read_model_tags ON achievement_read_model USING GIN (tags)`,
+
+	// Poin dasar per tipe disimpan di tiap versi aturan skor. Versi lama diisi sekali dari default_points
+	// katalog saat ini (override yang sudah ada tetap dipakai); versi baru sudah membawa type_points sendiri.
+	`ALTER TABLE scoring_rule_sets ADD COLUMN IF NOT EXISTS type_points_snapshotted BOOLEAN NOT NULL DEFAULT FALSE`,
+	`ALTER TABLE scoring_rule_sets ALTER COLUMN type_points_snapshotted SET DEFAULT TRUE`,
+	`UPDATE scoring_rule_sets
+	 SET rules = jsonb_set(rules, '{type_points}',
+	         (SELECT COALESCE(jsonb_object_agg(code, default_points), '{}'::jsonb) FROM achievement_types)
+	         || COALESCE(rules->'type_points', '{}'::jsonb)),
+	     type_points_snapshotted = TRUE
+	 WHERE NOT type_points_snapshotted`,
+
+	// Storage key attachment per versi (id attachment -> key); tidak ikut snapshot karena snapshot dapat dibaca lewat API
+	`ALTER TABLE achievement_versions ADD COLUMN IF NOT EXISTS attachment_keys JSONB NOT NULL DEFAULT '{}'::jsonb`,
+
+	// Tingkat lomba, jenis medali dan tag di read model disimpan lowercase tanpa spasi di tepi;
+	// baris lama dinormalisasi sekali (baris yang sudah normal dilewati)
+	`UPDATE achievement_read_model
+	 SET competition_level = NULLIF(LOWER(TRIM(competition_level)), ''),
+	     medal_type = NULLIF(LOWER(TRIM(medal_type)), ''),
+	     tags = ARRAY(
+	         SELECT n.tag FROM (
+	             SELECT LOWER(TRIM(u.tag)) AS tag, MIN(u.ord) AS ord
+	             FROM unnest(tags) WITH ORDINALITY AS u(tag, ord)
+	             WHERE TRIM(u.tag) <> ''
+	             GROUP BY 1
+	         ) n ORDER BY n.ord)
+	 WHERE competition_level IS DISTINCT FROM NULLIF(LOWER(TRIM(competition_level)), '')
+	    OR medal_type IS DISTINCT FROM NULLIF(LOWER(TRIM(medal_type)), '')
+	    OR EXISTS (SELECT 1 FROM unnest(tags) AS t(tag) WHERE t.tag <> LOWER(TRIM(t.tag)) OR TRIM(t.tag) = '')`,
+}
+
+// RunMigrations menjalankan semua migration secara berurutan
+func RunMigrations(db *pgxpool.Pool) {
+	ctx := context.Background()
+
+	for i, stmt := range migrations {
+		if _, err := db.Exec(ctx, stmt); err != nil {
+			log.Fatalf("❌ Failed running migration #%d: %v", i+1, err)
+		}
+	}
+
+	log.Println("✅ Database migrations applied")
+}
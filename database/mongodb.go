@@ -6,6 +6,7 @@ import (
 	"log"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -34,4 +35,37 @@ func ConnectMongoDB(uri string) *mongo.Client {
 // GetCollection helper untuk mengambil collection
 func GetCollection(client *mongo.Client, dbName, collectionName string) *mongo.Collection {
 	return client.Database(dbName).Collection(collectionName)
-}
\ No newline at end of file
+}
+
+// EnsureAchievementIndexes membuat index koleksi achievements jika belum ada.
+// Text index dipakai pencarian prestasi; bobot membuat kecocokan di judul dan tag lebih relevan.
+// default_language "none" karena isi prestasi berbahasa Indonesia (tanpa stemming/stop word bahasa Inggris).
+func EnsureAchievementIndexes(coll *mongo.Collection) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "description", Value: "text"},
+			{Key: "details.competitionName", Value: "text"},
+			{Key: "details.publicationTitle", Value: "text"},
+			{Key: "details.organizationName", Value: "text"},
+			{Key: "details.organizer", Value: "text"},
+			{Key: "tags", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("achievements_text_search").
+			SetDefaultLanguage("none").
+			SetWeights(bson.D{
+				{Key: "title", Value: 10},
+				{Key: "tags", Value: 5},
+				{Key: "details.competitionName", Value: 4},
+				{Key: "details.publicationTitle", Value: 4},
+				{Key: "details.organizationName", Value: 4},
+				{Key: "details.organizer", Value: 2},
+				{Key: "description", Value: 1},
+			}),
+	})
+	return err
+}
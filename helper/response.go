@@ -14,4 +14,34 @@ func Error(c *fiber.Ctx, code int, message string) error {
 		"status":  "error",
 		"message": message,
 	})
-}
\ No newline at end of file
+}
+
+// APIError is an error with an HTTP status, a machine-readable code and optional details
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Details interface{}
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+func NewAPIError(status int, code, message string, details interface{}) *APIError {
+	return &APIError{Status: status, Code: code, Message: message, Details: details}
+}
+
+// ErrorDetail menulis APIError sebagai response terstruktur.
+// Field "error" tetap ada agar kompatibel dengan format error yang lama.
+func ErrorDetail(c *fiber.Ctx, err *APIError) error {
+	body := fiber.Map{
+		"status": "error",
+		"error":  err.Message,
+		"code":   err.Code,
+	}
+	if err.Details != nil {
+		body["details"] = err.Details
+	}
+	return c.Status(err.Status).JSON(body)
+}
@@ -5,6 +5,8 @@ import (
 	"UASBE/config"
 	"UASBE/database"
 	"UASBE/routes"
+	"UASBE/storage"
+	"UASBE/utils"
 
 	_ "UASBE/docs"
 
@@ -17,14 +19,51 @@ func main() {
 	config.LoadConfig()
 	cfg := config.AppConfig
 
+	// init JWT signing & verification keys
+	if err := utils.InitJWTKeys(cfg); err != nil {
+		log.Fatalf("❌ Failed loading JWT keys: %v", err)
+	}
+
+	// batas ukuran & kuota attachment
+	if err := utils.InitUploadLimits(cfg); err != nil {
+		log.Fatalf("❌ Invalid upload limits: %v", err)
+	}
+
+	// masa simpan prestasi yang dihapus sebelum dihapus permanen
+	if err := utils.InitTrashRetention(cfg); err != nil {
+		log.Fatalf("❌ Invalid trash retention: %v", err)
+	}
+
+	// jadwal & mode rekonsiliasi PostgreSQL-MongoDB
+	if err := utils.InitReconciliation(cfg); err != nil {
+		log.Fatalf("❌ Invalid reconciliation config: %v", err)
+	}
+
+	// key untuk signed download URL attachment
+	if cfg.AttachmentURLSecret != "" {
+		utils.SetURLSigningKey([]byte(cfg.AttachmentURLSecret))
+	} else {
+		log.Println("⚠️ ATTACHMENT_URL_SECRET not set — signed URLs are only valid on this instance")
+	}
+
 	app := config.NewFiber()
 
 	// init db
 	dbpool := database.NewPostgresDB(cfg) // harus *pgxpool.Pool
+	database.RunMigrations(dbpool)
 	mongoClient := database.ConnectMongoDB(cfg.MongoURI)
 	mongoColl := database.GetCollection(mongoClient, cfg.MongoDB, "achievements")
+	if err := database.EnsureAchievementIndexes(mongoColl); err != nil {
+		log.Fatalf("❌ Failed creating MongoDB indexes: %v", err)
+	}
+
+	// init attachment storage (local / s3)
+	store, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed initializing storage: %v", err)
+	}
 
-	routes.SetupRoutes(app, dbpool, mongoColl) // panggil SetupRoutes
+	routes.SetupRoutes(app, dbpool, mongoColl, store) // panggil SetupRoutes
 
 	// Swagger route
 	app.Get("/swagger/*", swaggerWrapper)
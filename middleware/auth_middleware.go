@@ -25,6 +25,19 @@ func RBAC(requiredPermission string) fiber.Handler {
 			return helper.Error(c, fiber.StatusUnauthorized, "Invalid or Expired Token")
 		}
 
+		tokenID, err := utils.GetTokenIDFromClaims(claims)
+		if err != nil {
+			return helper.Error(c, fiber.StatusUnauthorized, "Invalid or Expired Token")
+		}
+
+		revoked, err := utils.TokenBlacklist.IsRevoked(c.Context(), tokenID)
+		if err != nil {
+			return helper.Error(c, fiber.StatusServiceUnavailable, "Unable to verify token status")
+		}
+		if revoked {
+			return helper.Error(c, fiber.StatusUnauthorized, "Token has been revoked")
+		}
+
 		c.Locals("user_info", claims)
 
 		if requiredPermission == "" {
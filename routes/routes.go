@@ -1,9 +1,13 @@
 package routes
 
 import (
+	"time"
+
 	"UASBE/app/repository"
 	"UASBE/app/service"
 	"UASBE/middleware"
+	"UASBE/storage"
+	"UASBE/utils"
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -11,7 +15,7 @@ import (
 )
 
 // SetupRoutes sets up all API v1 routes
-func SetupRoutes(app *fiber.App, dbpool *pgxpool.Pool, mongoColl *mongo.Collection) {
+func SetupRoutes(app *fiber.App, dbpool *pgxpool.Pool, mongoColl *mongo.Collection, store storage.Storage) {
 	// API v1 group
 	API := app.Group("/api/v1")
 
@@ -24,17 +28,62 @@ func SetupRoutes(app *fiber.App, dbpool *pgxpool.Pool, mongoColl *mongo.Collecti
 	authRepo := repository.NewAuthRepository(dbpool)
 	userRepo := repository.NewUserRepository(dbpool)
 	achievementRepo := repository.NewAchievementRepository(dbpool, mongoColl)
+	tokenBlacklistRepo := repository.NewTokenBlacklistRepository(dbpool)
+	achievementTypeRepo := repository.NewAchievementTypeRepository(dbpool)
+	scoringRepo := repository.NewScoringRepository(dbpool)
+	approvalChainRepo := repository.NewApprovalChainRepository(dbpool)
+	delegationRepo := repository.NewDelegationRepository(dbpool)
+	reviewSLARepo := repository.NewReviewSLARepository(dbpool)
+	notificationRepo := repository.NewNotificationRepository(dbpool)
+	achievementOutboxRepo := repository.NewAchievementOutboxRepository(dbpool, mongoColl)
+	reconciliationRepo := repository.NewReconciliationRepository(dbpool, mongoColl)
+	achievementReadModelRepo := repository.NewAchievementReadModelRepository(dbpool, mongoColl)
+
+	// Token revocation disimpan di PostgreSQL agar berlaku di semua instance
+	utils.SetTokenBlacklistStore(tokenBlacklistRepo)
+	go tokenBlacklistRepo.StartCleanup(time.Hour)
 
 	// Initialize services
 	authService := service.NewAuthService(authRepo)
 	userService := service.NewUserService(userRepo)
-	achievementService := service.NewAchievementService(achievementRepo)
+	achievementOutboxService := service.NewAchievementOutboxService(achievementOutboxRepo, store)
+	achievementService := service.NewAchievementService(achievementRepo, achievementTypeRepo, scoringRepo, approvalChainRepo, delegationRepo, store, achievementOutboxService)
+	achievementTypeService := service.NewAchievementTypeService(achievementTypeRepo, achievementRepo)
+	scoringService := service.NewScoringService(scoringRepo, achievementRepo, achievementTypeRepo, achievementOutboxService)
+	approvalChainService := service.NewApprovalChainService(approvalChainRepo, achievementTypeRepo)
+	delegationService := service.NewDelegationService(delegationRepo, achievementRepo)
+	reviewSLAService := service.NewReviewSLAService(reviewSLARepo, approvalChainRepo)
+	notificationService := service.NewNotificationService(notificationRepo)
+	trashService := service.NewTrashService(achievementRepo, achievementOutboxService, store)
+	reconciliationService := service.NewReconciliationService(reconciliationRepo, achievementRepo, achievementOutboxService, store)
+	achievementReadModelService := service.NewAchievementReadModelService(achievementReadModelRepo)
+
+	// Susulkan perubahan dokumen MongoDB yang gagal diterapkan saat request
+	go achievementOutboxService.StartRelay(time.Minute)
+
+	// Isi read model listing untuk prestasi yang dibuat sebelum read model ada
+	go achievementReadModelService.StartBackfill()
+
+	// Simpan versi 1 untuk prestasi yang dibuat sebelum versioning ada
+	go achievementService.StartVersionBackfill()
+
+	// Eskalasi prestasi yang melewati batas waktu review
+	go reviewSLAService.StartEscalationScheduler(15 * time.Minute)
+
+	// Hapus permanen prestasi yang melewati masa simpan trash
+	go trashService.StartRetentionJob(time.Hour)
+
+	// Deteksi (dan opsional perbaiki) drift antara achievement_references dan dokumen MongoDB
+	go reconciliationService.StartReconciliationJob(time.Duration(utils.ReconciliationIntervalHours)*time.Hour, utils.ReconciliationAutoRepair)
+
+	// JWKS untuk verifikasi token oleh service lain
+	app.Get("/.well-known/jwks.json", authService.JWKSEndpoint)
 
 	// Authentication Routes
 	auth := API.Group("/auth")
 	auth.Post("/login", authService.LoginEndpoint)
-	// Refresh token endpoint dihapus sementara karena belum ada
-	// auth.Post("/refresh", middleware.RBAC(""), authService.RefreshTokenEndpoint)
+	// Refresh tidak memakai RBAC karena access token biasanya sudah expired
+	auth.Post("/refresh", authService.RefreshTokenEndpoint)
 	auth.Post("/logout", middleware.RBAC(""), authService.LogoutEndpoint)
 	auth.Get("/profile", middleware.RBAC(""), authService.ProfileEndpoint)
 
@@ -52,20 +101,44 @@ func SetupRoutes(app *fiber.App, dbpool *pgxpool.Pool, mongoColl *mongo.Collecti
 	achievements := API.Group("/achievements")
 	achievements.Use(middleware.RBAC(""))
 	achievements.Get("/", achievementService.GetAchievementsEndpoint)
+	achievements.Get("/approvals/pending", achievementService.GetPendingApprovalsEndpoint)
+	achievements.Get("/search", achievementService.SearchAchievementsEndpoint)
 
 	achievements.Get("/:id", achievementService.GetAchievementByIDEndpoint)
 	achievements.Post("/", achievementService.CreateAchievementEndpoint)
 	achievements.Put("/:id", achievementService.UpdateAchievementEndpoint)
 	achievements.Delete("/:id", achievementService.DeleteAchievementEndpoint)
 
+	achievements.Post("/bulk/verify", achievementService.BulkVerifyAchievementsEndpoint)
+	achievements.Post("/bulk/reject", achievementService.BulkRejectAchievementsEndpoint)
 	achievements.Post("/:id/submit", achievementService.SubmitAchievementEndpoint)
+	achievements.Post("/:id/withdraw", achievementService.WithdrawAchievementEndpoint)
 	achievements.Post("/:id/verify", achievementService.VerifyAchievementEndpoint)
 	achievements.Post("/:id/reject", achievementService.RejectAchievementEndpoint)
 
 	achievements.Get("/:id/history", achievementService.GetAchievementHistoryEndpoint)
+	achievements.Get("/:id/score", achievementService.GetAchievementScoreEndpoint)
+	achievements.Get("/:id/changes", achievementService.GetAchievementChangesEndpoint)
+	achievements.Get("/:id/versions", achievementService.GetAchievementVersionsEndpoint)
+	achievements.Get("/:id/versions/:a/diff/:b", achievementService.DiffAchievementVersionsEndpoint)
+	achievements.Get("/:id/approvals", achievementService.GetAchievementApprovalsEndpoint)
+	achievements.Get("/:id/comments", achievementService.GetAchievementCommentsEndpoint)
+	achievements.Post("/:id/comments", achievementService.AddAchievementCommentEndpoint)
+	achievements.Post("/:id/comments/:commentId/resolve", achievementService.ResolveAchievementCommentEndpoint)
+	achievements.Post("/:id/comments/:commentId/reopen", achievementService.ReopenAchievementCommentEndpoint)
 	achievements.Post("/:id/attachments", achievementService.UploadAttachmentEndpoint)
+	achievements.Get("/:id/attachments/:attachmentId", achievementService.GetAttachmentEndpoint)
+	achievements.Delete("/:id/attachments/:attachmentId", achievementService.DeleteAttachmentEndpoint)
+	achievements.Post("/:id/attachments/:attachmentId/signed-url", achievementService.CreateAttachmentSignedURLEndpoint)
 	achievements.Get("/statistics", achievementService.GetAchievementStatisticsEndpoint)
 
+	// Achievement Types (tipe aktif untuk form prestasi)
+	API.Get("/achievement-types", middleware.RBAC(""), achievementTypeService.GetActiveAchievementTypesEndpoint)
+
+	// Public Routes (akses lewat signed URL, tanpa bearer token)
+	public := API.Group("/public")
+	public.Get("/attachments/:id/:attachmentId", achievementService.GetSignedAttachmentEndpoint)
+
 	// Students Routes
 	students := API.Group("/students")
 	students.Use(middleware.RBAC(""))
@@ -80,6 +153,19 @@ func SetupRoutes(app *fiber.App, dbpool *pgxpool.Pool, mongoColl *mongo.Collecti
 	lecturers.Get("/", userService.GetLecturersEndpoint)
 	lecturers.Get("/:id/advisees", userService.GetLecturerAdviseesEndpoint)
 
+	// Delegasi hak verifikasi dosen wali
+	delegations := API.Group("/delegations")
+	delegations.Use(middleware.RBAC(""))
+	delegations.Get("/", delegationService.GetMyDelegationsEndpoint)
+	delegations.Post("/", delegationService.DelegateVerificationEndpoint)
+	delegations.Delete("/:id", delegationService.RevokeDelegationEndpoint)
+
+	// Notification Routes (user yang login)
+	notifications := API.Group("/notifications")
+	notifications.Use(middleware.RBAC(""))
+	notifications.Get("/", notificationService.GetMyNotificationsEndpoint)
+	notifications.Post("/:id/read", notificationService.MarkNotificationReadEndpoint)
+
 	// Reports & Analytics Routes
 	reports := API.Group("/reports")
 	reports.Use(middleware.RBAC(""))
@@ -90,6 +176,38 @@ func SetupRoutes(app *fiber.App, dbpool *pgxpool.Pool, mongoColl *mongo.Collecti
 	admin := API.Group("/admin")
 	admin.Use(middleware.RBAC("user:manage"))
 	admin.Get("/achievements", achievementService.GetAllAchievementsForAdminEndpoint)
+	admin.Get("/achievements/overdue", reviewSLAService.GetOverdueAchievementsEndpoint)
+	admin.Get("/achievements/trash", trashService.GetTrashEndpoint)
+	admin.Post("/achievements/trash/purge", trashService.PurgeExpiredAchievementsEndpoint)
+	admin.Post("/achievements/:id/restore", trashService.RestoreAchievementEndpoint)
 	admin.Get("/achievements/:id", achievementService.GetAchievementByIDEndpoint)
+	admin.Get("/students/:id/storage-quota", achievementService.GetStudentStorageQuotaEndpoint)
+	admin.Put("/students/:id/storage-quota", achievementService.SetStudentStorageQuotaEndpoint)
+	admin.Get("/achievement-types", achievementTypeService.GetAchievementTypesEndpoint)
+	admin.Get("/achievement-types/:code", achievementTypeService.GetAchievementTypeEndpoint)
+	admin.Post("/achievement-types", achievementTypeService.CreateAchievementTypeEndpoint)
+	admin.Put("/achievement-types/:code", achievementTypeService.UpdateAchievementTypeEndpoint)
+	admin.Delete("/achievement-types/:code", achievementTypeService.DeleteAchievementTypeEndpoint)
+	admin.Get("/scoring/rules", scoringService.GetScoringRuleSetsEndpoint)
+	admin.Get("/scoring/rules/:version", scoringService.GetScoringRuleSetEndpoint)
+	admin.Post("/scoring/rules", scoringService.CreateScoringRuleSetEndpoint)
+	admin.Post("/scoring/rules/:version/activate", scoringService.ActivateScoringRuleSetEndpoint)
+	admin.Post("/scoring/recalculate", scoringService.RecalculateScoresEndpoint)
+	admin.Get("/approval-chains", approvalChainService.GetApprovalChainsEndpoint)
+	admin.Get("/approval-chains/:id", approvalChainService.GetApprovalChainEndpoint)
+	admin.Post("/approval-chains", approvalChainService.CreateApprovalChainEndpoint)
+	admin.Put("/approval-chains/:id", approvalChainService.UpdateApprovalChainEndpoint)
+	admin.Delete("/approval-chains/:id", approvalChainService.DeleteApprovalChainEndpoint)
+	admin.Get("/delegations", delegationService.GetDelegationsEndpoint)
+	admin.Post("/delegations", delegationService.AssignSubstituteReviewerEndpoint)
+	admin.Delete("/delegations/:id", delegationService.RevokeDelegationEndpoint)
+	admin.Get("/review-slas", reviewSLAService.GetReviewSLAsEndpoint)
+	admin.Put("/review-slas/:level", reviewSLAService.SetReviewSLAEndpoint)
+	admin.Delete("/review-slas/:level", reviewSLAService.DeleteReviewSLAEndpoint)
+	admin.Get("/reports/reviewer-throughput", reviewSLAService.GetReviewerThroughputEndpoint)
+	admin.Get("/outbox", achievementOutboxService.GetPendingEventsEndpoint)
+	admin.Post("/outbox/relay", achievementOutboxService.RelayPendingEventsEndpoint)
+	admin.Post("/read-model/backfill", achievementReadModelService.BackfillReadModelEndpoint)
+	admin.Post("/reconciliation", reconciliationService.ReconcileEndpoint)
 
 }
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage menyimpan file di filesystem lokal dengan layout content-addressed
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage membuat root directory (jika belum ada) beserta direktori temp-nya
+func NewLocalStorage(root string) (*LocalStorage, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	// Temp file dibuat di bawah root agar rename ke lokasi final bersifat atomic
+	if err := os.MkdirAll(filepath.Join(abs, ".tmp"), 0o750); err != nil {
+		return nil, err
+	}
+
+	return &LocalStorage{root: abs}, nil
+}
+
+// Put menyimpan isi r; file dengan isi yang sama hanya disimpan sekali
+func (s *LocalStorage) Put(ctx context.Context, r io.Reader, contentType string) (*Object, error) {
+	tmp, checksum, size, err := spoolToTemp(filepath.Join(s.root, ".tmp"), r)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	key := keyForChecksum(checksum)
+	dest := s.path(key)
+
+	if _, err := os.Stat(dest); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(dest), 0o750); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(tmp.Name(), dest); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &Object{
+		Key:         key,
+		Checksum:    checksum,
+		Size:        size,
+		ContentType: contentType,
+		URI:         "local://" + key,
+	}, nil
+}
+
+// Open membuka file berdasarkan key
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, *Object, error) {
+	if !validKey(key) {
+		return nil, nil, ErrObjectNotFound
+	}
+
+	f, err := os.Open(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	return f, &Object{
+		Key:      key,
+		Checksum: filepath.Base(key),
+		Size:     info.Size(),
+		URI:      "local://" + key,
+	}, nil
+}
+
+// Delete menghapus file berdasarkan key
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	if !validKey(key) {
+		return nil
+	}
+
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
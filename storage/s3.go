@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Options berisi konfigurasi koneksi ke storage S3-compatible (AWS S3, MinIO, dll)
+type S3Options struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+}
+
+// S3Storage menyimpan file di bucket S3-compatible dengan layout content-addressed
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Storage membuat client S3 dan memastikan bucket tersedia
+func NewS3Storage(opts S3Options) (*S3Storage, error) {
+	if opts.Endpoint == "" || opts.Bucket == "" {
+		return nil, errors.New("S3_ENDPOINT and S3_BUCKET are required for s3 storage")
+	}
+
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.AccessKey, opts.SecretKey, ""),
+		Secure: opts.UseSSL,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	exists, err := client.BucketExists(ctx, opts.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, opts.Bucket, minio.MakeBucketOptions{Region: opts.Region}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &S3Storage{client: client, bucket: opts.Bucket}, nil
+}
+
+// Put men-spool isi r ke file sementara (untuk menghitung checksum) lalu meng-upload-nya.
+// Object yang sudah ada dengan checksum sama tidak di-upload ulang.
+func (s *S3Storage) Put(ctx context.Context, r io.Reader, contentType string) (*Object, error) {
+	tmp, checksum, size, err := spoolToTemp("", r)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	key := keyForChecksum(checksum)
+	obj := &Object{
+		Key:         key,
+		Checksum:    checksum,
+		Size:        size,
+		ContentType: contentType,
+		URI:         "s3://" + path.Join(s.bucket, key),
+	}
+
+	if _, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{}); err == nil {
+		return obj, nil
+	} else if minio.ToErrorResponse(err).Code != "NoSuchKey" {
+		return nil, err
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	_, err = s.client.PutObject(ctx, s.bucket, key, tmp, size, minio.PutObjectOptions{
+		ContentType:  contentType,
+		UserMetadata: map[string]string{"sha256": checksum},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// Open membuka object berdasarkan key
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, *Object, error) {
+	if !validKey(key) {
+		return nil, nil, ErrObjectNotFound
+	}
+
+	info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, nil, ErrObjectNotFound
+		}
+		return nil, nil, err
+	}
+
+	reader, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reader, &Object{
+		Key:         key,
+		Checksum:    path.Base(key),
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		URI:         "s3://" + path.Join(s.bucket, key),
+	}, nil
+}
+
+// Delete menghapus object berdasarkan key
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	if !validKey(key) {
+		return nil
+	}
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"UASBE/config"
+)
+
+// ErrObjectNotFound dikembalikan oleh Open jika key tidak ada di storage
+var ErrObjectNotFound = errors.New("object not found")
+
+// Object describes a stored file. Key is content-addressed (derived from Checksum),
+// so uploading identical content twice yields the same key.
+type Object struct {
+	Key         string
+	Checksum    string // SHA-256 (hex)
+	Size        int64
+	ContentType string
+	URI         string // lokasi di backend, mis. local://ab/cd/<sha256> atau s3://bucket/ab/cd/<sha256>
+}
+
+// Storage is the contract for attachment backends
+type Storage interface {
+	// Put streams r into storage and returns the content-addressed object
+	Put(ctx context.Context, r io.Reader, contentType string) (*Object, error)
+	// Open returns a reader for the object with the given key
+	Open(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+	// Delete removes the object; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// New membuat storage sesuai STORAGE_DRIVER (local | s3)
+func New(cfg config.Config) (Storage, error) {
+	switch strings.ToLower(cfg.StorageDriver) {
+	case "", "local":
+		dir := cfg.StorageLocalDir
+		if dir == "" {
+			dir = "./uploads"
+		}
+		return NewLocalStorage(dir)
+	case "s3":
+		return NewS3Storage(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			Bucket:    cfg.S3Bucket,
+			Region:    cfg.S3Region,
+			UseSSL:    strings.EqualFold(cfg.S3UseSSL, "true"),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported STORAGE_DRIVER %q", cfg.StorageDriver)
+	}
+}
+
+// keyForChecksum membuat key berbentuk ab/cd/<sha256> agar direktori tidak terlalu besar
+func keyForChecksum(checksum string) string {
+	return path.Join(checksum[0:2], checksum[2:4], checksum)
+}
+
+// validKey memastikan key berasal dari keyForChecksum (mencegah path traversal)
+func validKey(key string) bool {
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 || len(parts[2]) != sha256.Size*2 {
+		return false
+	}
+	if _, err := hex.DecodeString(parts[2]); err != nil {
+		return false
+	}
+	return parts[0] == parts[2][0:2] && parts[1] == parts[2][2:4]
+}
+
+// spoolToTemp menyalin r ke file sementara sambil menghitung SHA-256,
+// sehingga isi file tidak pernah dibaca penuh ke memory.
+func spoolToTemp(dir string, r io.Reader) (*os.File, string, int64, error) {
+	tmp, err := os.CreateTemp(dir, "upload-*")
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, "", 0, err
+	}
+
+	return tmp, hex.EncodeToString(hash.Sum(nil)), size, nil
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"UASBE/middleware"
+	"UASBE/utils"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingBlacklistStore struct{}
+
+func (failingBlacklistStore) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	return errors.New("store unavailable")
+}
+
+func (failingBlacklistStore) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return false, errors.New("store unavailable")
+}
+
+func newRBACTestApp(permission string) *fiber.App {
+	app := fiber.New()
+	app.Get("/protected", middleware.RBAC(permission), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	return app
+}
+
+func TestRBAC_TokenRevocation(t *testing.T) {
+	previous := utils.TokenBlacklist
+	defer utils.SetTokenBlacklistStore(previous)
+
+	userID := "123e4567-e89b-12d3-a456-426614174000"
+
+	t.Run("Valid token is accepted", func(t *testing.T) {
+		utils.SetTokenBlacklistStore(utils.NewTokenBlacklistManager())
+		token, _ := utils.GenerateJWT(userID, "testuser", "student", []string{"read"})
+
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := newRBACTestApp("").Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Revoked token is rejected", func(t *testing.T) {
+		store := utils.NewTokenBlacklistManager()
+		utils.SetTokenBlacklistStore(store)
+		token, _ := utils.GenerateJWT(userID, "testuser", "student", []string{"read"})
+
+		jti, _ := utils.GetTokenID(token)
+		_ = store.Revoke(context.Background(), jti, time.Now().Add(time.Hour))
+
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := newRBACTestApp("").Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Revoking one token does not affect another", func(t *testing.T) {
+		store := utils.NewTokenBlacklistManager()
+		utils.SetTokenBlacklistStore(store)
+		revokedToken, _ := utils.GenerateJWT(userID, "testuser", "student", []string{"read"})
+		activeToken, _ := utils.GenerateJWT(userID, "testuser", "student", []string{"read"})
+
+		jti, _ := utils.GetTokenID(revokedToken)
+		_ = store.Revoke(context.Background(), jti, time.Now().Add(time.Hour))
+
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+activeToken)
+		resp, err := newRBACTestApp("").Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Store failure fails closed", func(t *testing.T) {
+		utils.SetTokenBlacklistStore(failingBlacklistStore{})
+		token, _ := utils.GenerateJWT(userID, "testuser", "student", []string{"read"})
+
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := newRBACTestApp("").Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	})
+}
@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAchievementOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockAchievementOutboxRepository) GetPendingOutboxEvents(ctx context.Context, limit int) ([]model.AchievementOutboxEvent, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementOutboxEvent), args.Error(1)
+}
+
+func (m *MockAchievementOutboxRepository) GetPendingOutboxEventsByAchievement(ctx context.Context, achievementID uuid.UUID) ([]model.AchievementOutboxEvent, error) {
+	args := m.Called(ctx, achievementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementOutboxEvent), args.Error(1)
+}
+
+func (m *MockAchievementOutboxRepository) ApplyOutboxEvent(ctx context.Context, event model.AchievementOutboxEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockAchievementOutboxRepository) MarkOutboxEventProcessed(ctx context.Context, eventID int64, processedAt time.Time) error {
+	args := m.Called(ctx, eventID, processedAt)
+	return args.Error(0)
+}
+
+func (m *MockAchievementOutboxRepository) MarkOutboxEventFailed(ctx context.Context, eventID int64, lastError string, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, eventID, lastError, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockAchievementOutboxRepository) DeleteProcessedOutboxEvents(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAchievementOutboxRepository) WithAchievementOutboxLock(ctx context.Context, achievementID uuid.UUID, fn func() error) (bool, error) {
+	args := m.Called(ctx, achievementID)
+	if !args.Bool(0) || args.Error(1) != nil {
+		return args.Bool(0), args.Error(1)
+	}
+	return true, fn()
+}
+
+func (m *MockAchievementOutboxRepository) CountAttachmentsByStorageKey(ctx context.Context, storageKey string) (int64, error) {
+	args := m.Called(ctx, storageKey)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAchievementReadModelRepository struct {
+	mock.Mock
+}
+
+func (m *MockAchievementReadModelRepository) GetReferencesWithoutReadModel(ctx context.Context, limit int) ([]model.AchievementReference, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementReference), args.Error(1)
+}
+
+func (m *MockAchievementReadModelRepository) SaveReadModelFromDocuments(ctx context.Context, refs []model.AchievementReference) error {
+	args := m.Called(ctx, refs)
+	return args.Error(0)
+}
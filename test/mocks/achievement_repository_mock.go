@@ -2,12 +2,12 @@ package mocks
 
 import (
 	"context"
+	"time"
 	mongodb "UASBE/app/model/MongoDB"
 	model "UASBE/app/model/Postgresql"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type MockAchievementRepository struct {
@@ -15,8 +15,50 @@ type MockAchievementRepository struct {
 }
 
 // AddAttachmentToAchievement implements repository.AchievementRepository.
-func (m *MockAchievementRepository) AddAttachmentToAchievement(ctx context.Context, mongoAchievementID string, fileName string, fileURL string, fileType string) error {
-	panic("unimplemented")
+func (m *MockAchievementRepository) AddAttachmentToAchievement(ctx context.Context, achievementID uuid.UUID, attachment mongodb.Attachment, snapshot mongodb.Achievement, changedBy uuid.UUID, revise bool) error {
+	args := m.Called(ctx, achievementID, attachment, snapshot, changedBy, revise)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) RemoveAttachmentFromAchievement(ctx context.Context, achievementID uuid.UUID, attachment mongodb.Attachment, snapshot mongodb.Achievement, changedBy uuid.UUID, revise bool) error {
+	args := m.Called(ctx, achievementID, attachment, snapshot, changedBy, revise)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) CountAttachmentsByStorageKey(ctx context.Context, storageKey string) (int64, error) {
+	args := m.Called(ctx, storageKey)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAchievementRepository) CountAchievementsByType(ctx context.Context, achievementType string) (int64, error) {
+	args := m.Called(ctx, achievementType)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetAchievementReferencesByStatus(ctx context.Context, status string) ([]model.AchievementReference, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementReference), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetStudentAttachmentUsage(ctx context.Context, studentID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, studentID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetStudentStorageQuota(ctx context.Context, studentID uuid.UUID) (*int64, error) {
+	args := m.Called(ctx, studentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*int64), args.Error(1)
+}
+
+func (m *MockAchievementRepository) SetStudentStorageQuota(ctx context.Context, studentID uuid.UUID, quotaBytes int64) error {
+	args := m.Called(ctx, studentID, quotaBytes)
+	return args.Error(0)
 }
 
 // GetStudentAchievements implements repository.AchievementRepository.
@@ -39,14 +81,10 @@ func (m *MockAchievementRepository) GetUserByID(ctx context.Context, userID uuid
 	panic("unimplemented")
 }
 
-// UpdateAchievementInMongo implements repository.AchievementRepository.
-func (m *MockAchievementRepository) UpdateAchievementInMongo(ctx context.Context, achievement mongodb.Achievement) error {
-	panic("unimplemented")
-}
-
-// UpdateAchievementTimestamp implements repository.AchievementRepository.
-func (m *MockAchievementRepository) UpdateAchievementTimestamp(ctx context.Context, achievementID uuid.UUID) error {
-	panic("unimplemented")
+// UpdateAchievementContent implements repository.AchievementRepository.
+func (m *MockAchievementRepository) UpdateAchievementContent(ctx context.Context, achievementID uuid.UUID, snapshot mongodb.Achievement, changedBy uuid.UUID, revise bool) error {
+	args := m.Called(ctx, achievementID, snapshot, changedBy, revise)
+	return args.Error(0)
 }
 
 func (m *MockAchievementRepository) GetStudentByUserID(ctx context.Context, userID uuid.UUID) (*model.Student, error) {
@@ -57,13 +95,8 @@ func (m *MockAchievementRepository) GetStudentByUserID(ctx context.Context, user
 	return args.Get(0).(*model.Student), args.Error(1)
 }
 
-func (m *MockAchievementRepository) SaveAchievementMongo(ctx context.Context, achievement mongodb.Achievement) (string, error) {
-	args := m.Called(ctx, achievement)
-	return args.String(0), args.Error(1)
-}
-
-func (m *MockAchievementRepository) SaveAchievementReference(ctx context.Context, ref model.AchievementReference) error {
-	args := m.Called(ctx, ref)
+func (m *MockAchievementRepository) SaveAchievementReference(ctx context.Context, ref model.AchievementReference, snapshot mongodb.Achievement, changedBy uuid.UUID) error {
+	args := m.Called(ctx, ref, snapshot, changedBy)
 	return args.Error(0)
 }
 
@@ -75,8 +108,8 @@ func (m *MockAchievementRepository) GetAchievementReferenceByID(ctx context.Cont
 	return args.Get(0).(*model.AchievementReference), args.Error(1)
 }
 
-func (m *MockAchievementRepository) UpdateAchievementStatusToSubmitted(ctx context.Context, achievementID uuid.UUID) error {
-	args := m.Called(ctx, achievementID)
+func (m *MockAchievementRepository) UpdateAchievementStatusToSubmitted(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID, stages []model.ApprovalStage, reviewLevel string) error {
+	args := m.Called(ctx, achievementID, changedBy, stages, reviewLevel)
 	return args.Error(0)
 }
 
@@ -85,13 +118,8 @@ func (m *MockAchievementRepository) GetAdvisorIDByStudentID(ctx context.Context,
 	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
-func (m *MockAchievementRepository) SoftDeleteAchievementMongo(ctx context.Context, mongoAchievementID string) error {
-	args := m.Called(ctx, mongoAchievementID)
-	return args.Error(0)
-}
-
-func (m *MockAchievementRepository) UpdateAchievementReferenceToDeleted(ctx context.Context, achievementID uuid.UUID) error {
-	args := m.Called(ctx, achievementID)
+func (m *MockAchievementRepository) UpdateAchievementReferenceToDeleted(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID) error {
+	args := m.Called(ctx, achievementID, changedBy)
 	return args.Error(0)
 }
 
@@ -111,8 +139,8 @@ func (m *MockAchievementRepository) GetStudentIDsByAdvisorID(ctx context.Context
 	return args.Get(0).([]uuid.UUID), args.Error(1)
 }
 
-func (m *MockAchievementRepository) GetAchievementsWithStudentInfo(ctx context.Context, studentIDs []uuid.UUID, status string, page, limit int) ([]model.AchievementWithStudent, int, error) {
-	args := m.Called(ctx, studentIDs, status, page, limit)
+func (m *MockAchievementRepository) GetAchievementsWithStudentInfo(ctx context.Context, studentIDs []uuid.UUID, filters model.AchievementListFilters, page, limit int) ([]model.AchievementWithStudent, int, error) {
+	args := m.Called(ctx, studentIDs, filters, page, limit)
 	if args.Get(0) == nil {
 		return nil, 0, args.Error(2)
 	}
@@ -127,8 +155,8 @@ func (m *MockAchievementRepository) GetAchievementDetailFromMongo(ctx context.Co
 	return args.Get(0).(*mongodb.Achievement), args.Error(1)
 }
 
-func (m *MockAchievementRepository) UpdateAchievementStatusToVerified(ctx context.Context, achievementID uuid.UUID, lecturerID uuid.UUID) error {
-	args := m.Called(ctx, achievementID, lecturerID)
+func (m *MockAchievementRepository) UpdateAchievementStatusToVerified(ctx context.Context, achievementID uuid.UUID, lecturerID uuid.UUID, changedBy uuid.UUID, score model.AchievementScore, decision model.ApprovalDecision) error {
+	args := m.Called(ctx, achievementID, lecturerID, changedBy, score, decision)
 	return args.Error(0)
 }
 
@@ -140,11 +168,97 @@ func (m *MockAchievementRepository) GetStudentByID(ctx context.Context, studentI
 	return args.Get(0).(*model.Student), args.Error(1)
 }
 
-func (m *MockAchievementRepository) UpdateAchievementStatusToRejected(ctx context.Context, achievementID uuid.UUID, rejectionNote string) error {
-	args := m.Called(ctx, achievementID, rejectionNote)
+func (m *MockAchievementRepository) UpdateAchievementStatusToRejected(ctx context.Context, achievementID uuid.UUID, rejectionNote string, changedBy uuid.UUID, snapshot mongodb.Achievement, decision model.ApprovalDecision) error {
+	args := m.Called(ctx, achievementID, rejectionNote, changedBy, snapshot, decision)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) UpdateAchievementStatusToRevised(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID) error {
+	args := m.Called(ctx, achievementID, changedBy)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) UpdateAchievementStatusToResubmitted(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID, stages []model.ApprovalStage, reviewLevel string) error {
+	args := m.Called(ctx, achievementID, changedBy, stages, reviewLevel)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) GetAchievementApprovals(ctx context.Context, achievementID uuid.UUID, revision int) ([]model.AchievementApproval, error) {
+	args := m.Called(ctx, achievementID, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementApproval), args.Error(1)
+}
+
+func (m *MockAchievementRepository) ApproveAchievementStage(ctx context.Context, decision model.ApprovalDecision) error {
+	args := m.Called(ctx, decision)
 	return args.Error(0)
 }
 
+func (m *MockAchievementRepository) GetPendingApprovalsByRole(ctx context.Context, approverRole, department string) ([]model.AchievementApproval, error) {
+	args := m.Called(ctx, approverRole, department)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementApproval), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetUserRoleName(ctx context.Context, userID uuid.UUID) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAchievementRepository) SaveAchievementVersion(ctx context.Context, achievementID uuid.UUID, snapshot mongodb.Achievement, changedBy uuid.UUID) (int, error) {
+	args := m.Called(ctx, achievementID, snapshot, changedBy)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetAchievementVersions(ctx context.Context, achievementID uuid.UUID) ([]model.AchievementVersion, error) {
+	args := m.Called(ctx, achievementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementVersion), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetAchievementVersion(ctx context.Context, achievementID uuid.UUID, version int) (*model.AchievementVersion, error) {
+	args := m.Called(ctx, achievementID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AchievementVersion), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetAchievementsWithoutVersions(ctx context.Context, afterID uuid.UUID, limit int) ([]model.AchievementReference, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementReference), args.Error(1)
+}
+
+func (m *MockAchievementRepository) SaveBaselineVersionsFromDocuments(ctx context.Context, refs []model.AchievementReference) (int, error) {
+	args := m.Called(ctx, refs)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetLatestAchievementSnapshot(ctx context.Context, achievementID uuid.UUID) (*mongodb.Achievement, error) {
+	args := m.Called(ctx, achievementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongodb.Achievement), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetLatestAchievementRejection(ctx context.Context, achievementID uuid.UUID) (*model.AchievementRejection, error) {
+	args := m.Called(ctx, achievementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AchievementRejection), args.Error(1)
+}
+
 func (m *MockAchievementRepository) GetAchievementStatusHistory(ctx context.Context, achievementID uuid.UUID) ([]model.AchievementStatusLog, error) {
 	args := m.Called(ctx, achievementID)
 	if args.Get(0) == nil {
@@ -166,8 +280,16 @@ func (m *MockAchievementRepository) GetAllAchievementsForAdmin(ctx context.Conte
 	return args.Get(0).([]model.AchievementWithStudent), args.Int(1), args.Error(2)
 }
 
-func (m *MockAchievementRepository) GetStatisticsByType(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters, mongoColl *mongo.Collection) ([]model.StatsByType, error) {
-	args := m.Called(ctx, studentIDs, filters, mongoColl)
+func (m *MockAchievementRepository) GetAdminAchievementFacets(ctx context.Context, filters model.AdminAchievementFilters) (*model.AchievementFacets, error) {
+	args := m.Called(ctx, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AchievementFacets), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetStatisticsByType(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters) ([]model.StatsByType, error) {
+	args := m.Called(ctx, studentIDs, filters)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -190,8 +312,8 @@ func (m *MockAchievementRepository) GetTopStudents(ctx context.Context, studentI
 	return args.Get(0).([]model.TopStudent), args.Error(1)
 }
 
-func (m *MockAchievementRepository) GetLevelDistribution(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters, mongoColl *mongo.Collection) ([]model.LevelDistribution, error) {
-	args := m.Called(ctx, studentIDs, filters, mongoColl)
+func (m *MockAchievementRepository) GetLevelDistribution(ctx context.Context, studentIDs []uuid.UUID, filters model.StatisticsFilters) ([]model.LevelDistribution, error) {
+	args := m.Called(ctx, studentIDs, filters)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -218,3 +340,81 @@ func (m *MockAchievementRepository) GetAllStudentIDs(ctx context.Context) ([]uui
 	}
 	return args.Get(0).([]uuid.UUID), args.Error(1)
 }
+
+func (m *MockAchievementRepository) GetAchievementComments(ctx context.Context, achievementID uuid.UUID) ([]model.AchievementComment, error) {
+	args := m.Called(ctx, achievementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementComment), args.Error(1)
+}
+
+func (m *MockAchievementRepository) GetAchievementCommentByID(ctx context.Context, commentID uuid.UUID) (*model.AchievementComment, error) {
+	args := m.Called(ctx, commentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AchievementComment), args.Error(1)
+}
+
+func (m *MockAchievementRepository) CreateAchievementComment(ctx context.Context, comment model.AchievementComment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) SetAchievementCommentResolved(ctx context.Context, commentID uuid.UUID, resolved bool, resolvedBy uuid.UUID, resolvedAt time.Time) error {
+	args := m.Called(ctx, commentID, resolved, resolvedBy, resolvedAt)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) UpdateAchievementStatusToWithdrawn(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID, note string, advisorMessage string) error {
+	args := m.Called(ctx, achievementID, changedBy, note, advisorMessage)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) GetDeletedAchievements(ctx context.Context, page, limit int) ([]model.TrashedAchievement, int, error) {
+	args := m.Called(ctx, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]model.TrashedAchievement), args.Int(1), args.Error(2)
+}
+
+func (m *MockAchievementRepository) UpdateAchievementReferenceToRestored(ctx context.Context, achievementID uuid.UUID, changedBy uuid.UUID) error {
+	args := m.Called(ctx, achievementID, changedBy)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) GetAchievementsDeletedBefore(ctx context.Context, before time.Time, limit int) ([]model.AchievementReference, error) {
+	args := m.Called(ctx, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementReference), args.Error(1)
+}
+
+func (m *MockAchievementRepository) PurgeAchievementMongo(ctx context.Context, mongoAchievementID string) error {
+	args := m.Called(ctx, mongoAchievementID)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) PurgeAchievementReference(ctx context.Context, achievementID uuid.UUID) error {
+	args := m.Called(ctx, achievementID)
+	return args.Error(0)
+}
+
+func (m *MockAchievementRepository) SearchAchievementDocuments(ctx context.Context, query string, studentIDs []uuid.UUID, skip, limit int) ([]model.AchievementSearchMatch, int64, error) {
+	args := m.Called(ctx, query, studentIDs, skip, limit)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]model.AchievementSearchMatch), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAchievementRepository) GetAchievementsByMongoIDs(ctx context.Context, mongoIDs []string) (map[string]model.AchievementWithStudent, error) {
+	args := m.Called(ctx, mongoIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]model.AchievementWithStudent), args.Error(1)
+}
@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAchievementTypeRepository struct {
+	mock.Mock
+}
+
+func (m *MockAchievementTypeRepository) GetAllAchievementTypes(ctx context.Context, activeOnly bool) ([]model.AchievementType, error) {
+	args := m.Called(ctx, activeOnly)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementType), args.Error(1)
+}
+
+func (m *MockAchievementTypeRepository) GetAchievementTypeByCode(ctx context.Context, code string) (*model.AchievementType, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AchievementType), args.Error(1)
+}
+
+func (m *MockAchievementTypeRepository) CreateAchievementType(ctx context.Context, achievementType *model.AchievementType) error {
+	args := m.Called(ctx, achievementType)
+	return args.Error(0)
+}
+
+func (m *MockAchievementTypeRepository) UpdateAchievementType(ctx context.Context, achievementType *model.AchievementType) error {
+	args := m.Called(ctx, achievementType)
+	return args.Error(0)
+}
+
+func (m *MockAchievementTypeRepository) DeleteAchievementType(ctx context.Context, code string) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockApprovalChainRepository struct {
+	mock.Mock
+}
+
+func (m *MockApprovalChainRepository) GetApprovalChains(ctx context.Context) ([]model.ApprovalChain, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ApprovalChain), args.Error(1)
+}
+
+func (m *MockApprovalChainRepository) GetApprovalChainByID(ctx context.Context, id uuid.UUID) (*model.ApprovalChain, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ApprovalChain), args.Error(1)
+}
+
+func (m *MockApprovalChainRepository) GetApprovalChainByScope(ctx context.Context, achievementType, competitionLevel *string) (*model.ApprovalChain, error) {
+	args := m.Called(ctx, achievementType, competitionLevel)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ApprovalChain), args.Error(1)
+}
+
+func (m *MockApprovalChainRepository) FindApprovalChain(ctx context.Context, achievementType, competitionLevel string) (*model.ApprovalChain, error) {
+	args := m.Called(ctx, achievementType, competitionLevel)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ApprovalChain), args.Error(1)
+}
+
+func (m *MockApprovalChainRepository) CreateApprovalChain(ctx context.Context, chain *model.ApprovalChain) error {
+	args := m.Called(ctx, chain)
+	return args.Error(0)
+}
+
+func (m *MockApprovalChainRepository) UpdateApprovalChain(ctx context.Context, chain *model.ApprovalChain) error {
+	args := m.Called(ctx, chain)
+	return args.Error(0)
+}
+
+func (m *MockApprovalChainRepository) DeleteApprovalChain(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockApprovalChainRepository) RoleExists(ctx context.Context, name string) (bool, error) {
+	args := m.Called(ctx, name)
+	return args.Bool(0), args.Error(1)
+}
@@ -26,3 +26,42 @@ func (m *MockAuthRepository) GetPermissionsByRoleID(roleID uuid.UUID) ([]string,
 	}
 	return args.Get(0).([]string), args.Error(1)
 }
+
+func (m *MockAuthRepository) GetUserProfile(userID uuid.UUID) (*model.UserProfileResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserProfileResponse), args.Error(1)
+}
+
+func (m *MockAuthRepository) FindUserByID(userID uuid.UUID) (*model.Users, string, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*model.Users), args.String(1), args.Error(2)
+}
+
+func (m *MockAuthRepository) CreateRefreshToken(token model.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) GetRefreshTokenByHash(tokenHash string) (*model.RefreshToken, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RefreshToken), args.Error(1)
+}
+
+func (m *MockAuthRepository) RotateRefreshToken(oldTokenID uuid.UUID, newToken model.RefreshToken) error {
+	args := m.Called(oldTokenID, newToken)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) RevokeRefreshTokenFamily(familyID uuid.UUID) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
@@ -0,0 +1,82 @@
+package mocks
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDelegationRepository struct {
+	mock.Mock
+}
+
+func (m *MockDelegationRepository) GetDelegations(ctx context.Context) ([]model.VerificationDelegation, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.VerificationDelegation), args.Error(1)
+}
+
+func (m *MockDelegationRepository) GetDelegationsGivenBy(ctx context.Context, lecturerID uuid.UUID) ([]model.VerificationDelegation, error) {
+	args := m.Called(ctx, lecturerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.VerificationDelegation), args.Error(1)
+}
+
+func (m *MockDelegationRepository) GetDelegationsReceivedBy(ctx context.Context, lecturerID uuid.UUID) ([]model.VerificationDelegation, error) {
+	args := m.Called(ctx, lecturerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.VerificationDelegation), args.Error(1)
+}
+
+func (m *MockDelegationRepository) GetDelegationByID(ctx context.Context, id uuid.UUID) (*model.VerificationDelegation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.VerificationDelegation), args.Error(1)
+}
+
+func (m *MockDelegationRepository) CreateDelegation(ctx context.Context, delegation *model.VerificationDelegation) error {
+	args := m.Called(ctx, delegation)
+	return args.Error(0)
+}
+
+func (m *MockDelegationRepository) RevokeDelegation(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockDelegationRepository) HasOverlappingDelegation(ctx context.Context, delegatorID, delegateID uuid.UUID, startDate, endDate time.Time) (bool, error) {
+	args := m.Called(ctx, delegatorID, delegateID, startDate, endDate)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDelegationRepository) FindActiveDelegation(ctx context.Context, delegatorID, delegateID uuid.UUID, at time.Time) (*model.VerificationDelegation, error) {
+	args := m.Called(ctx, delegatorID, delegateID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.VerificationDelegation), args.Error(1)
+}
+
+func (m *MockDelegationRepository) GetDelegatedPendingApprovals(ctx context.Context, delegateID uuid.UUID, at time.Time) ([]model.AchievementApproval, error) {
+	args := m.Called(ctx, delegateID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementApproval), args.Error(1)
+}
+
+func (m *MockDelegationRepository) LecturerExists(ctx context.Context, lecturerID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, lecturerID)
+	return args.Bool(0), args.Error(1)
+}
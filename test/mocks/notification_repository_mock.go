@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]model.Notification, error) {
+	args := m.Called(ctx, userID, unreadOnly, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkNotificationRead(ctx context.Context, userID, notificationID uuid.UUID, readAt time.Time) (bool, error) {
+	args := m.Called(ctx, userID, notificationID, readAt)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,74 @@
+package mocks
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReconciliationRepository struct {
+	mock.Mock
+}
+
+func (m *MockReconciliationRepository) GetReferencesForReconciliation(ctx context.Context, afterID uuid.UUID, limit int) ([]model.ReconciliationReference, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ReconciliationReference), args.Error(1)
+}
+
+func (m *MockReconciliationRepository) GetDocumentsByMongoIDs(ctx context.Context, mongoIDs []string) (map[string]model.ReconciliationDocument, error) {
+	args := m.Called(ctx, mongoIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]model.ReconciliationDocument), args.Error(1)
+}
+
+func (m *MockReconciliationRepository) GetDocumentsForReconciliation(ctx context.Context, afterMongoID string, limit int) ([]model.ReconciliationDocument, error) {
+	args := m.Called(ctx, afterMongoID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ReconciliationDocument), args.Error(1)
+}
+
+func (m *MockReconciliationRepository) GetReferencedMongoIDs(ctx context.Context, mongoIDs []string) (map[string]bool, error) {
+	args := m.Called(ctx, mongoIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *MockReconciliationRepository) GetLatestAchievementSnapshot(ctx context.Context, achievementID uuid.UUID) (*mongodb.Achievement, error) {
+	args := m.Called(ctx, achievementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mongodb.Achievement), args.Error(1)
+}
+
+func (m *MockReconciliationRepository) EnqueueDocumentUpsert(ctx context.Context, achievementID uuid.UUID, achievement mongodb.Achievement) error {
+	args := m.Called(ctx, achievementID, achievement)
+	return args.Error(0)
+}
+
+func (m *MockReconciliationRepository) EnqueueDocumentOperation(ctx context.Context, achievementID uuid.UUID, operation string) error {
+	args := m.Called(ctx, achievementID, operation)
+	return args.Error(0)
+}
+
+func (m *MockReconciliationRepository) MoveReferenceToTrash(ctx context.Context, achievementID uuid.UUID, note string) error {
+	args := m.Called(ctx, achievementID, note)
+	return args.Error(0)
+}
+
+func (m *MockReconciliationRepository) DeleteOrphanDocument(ctx context.Context, mongoAchievementID string) error {
+	args := m.Called(ctx, mongoAchievementID)
+	return args.Error(0)
+}
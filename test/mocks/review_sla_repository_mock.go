@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReviewSLARepository struct {
+	mock.Mock
+}
+
+func (m *MockReviewSLARepository) GetReviewSLAs(ctx context.Context) ([]model.ReviewSLA, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ReviewSLA), args.Error(1)
+}
+
+func (m *MockReviewSLARepository) GetReviewSLA(ctx context.Context, level string) (*model.ReviewSLA, error) {
+	args := m.Called(ctx, level)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ReviewSLA), args.Error(1)
+}
+
+func (m *MockReviewSLARepository) UpsertReviewSLA(ctx context.Context, sla *model.ReviewSLA) error {
+	args := m.Called(ctx, sla)
+	return args.Error(0)
+}
+
+func (m *MockReviewSLARepository) DeleteReviewSLA(ctx context.Context, level string) error {
+	args := m.Called(ctx, level)
+	return args.Error(0)
+}
+
+func (m *MockReviewSLARepository) EscalateOverdueAchievements(ctx context.Context, now time.Time) ([]model.AchievementEscalation, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementEscalation), args.Error(1)
+}
+
+func (m *MockReviewSLARepository) GetOverdueAchievements(ctx context.Context, now time.Time) ([]model.OverdueAchievement, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.OverdueAchievement), args.Error(1)
+}
+
+func (m *MockReviewSLARepository) GetReviewerThroughput(ctx context.Context, from, to *time.Time) ([]model.ReviewerThroughput, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ReviewerThroughput), args.Error(1)
+}
@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockScoringRepository struct {
+	mock.Mock
+}
+
+func (m *MockScoringRepository) GetScoringRuleSets(ctx context.Context) ([]model.ScoringRuleSet, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ScoringRuleSet), args.Error(1)
+}
+
+func (m *MockScoringRepository) GetScoringRuleSetByVersion(ctx context.Context, version int) (*model.ScoringRuleSet, error) {
+	args := m.Called(ctx, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ScoringRuleSet), args.Error(1)
+}
+
+func (m *MockScoringRepository) GetActiveScoringRuleSet(ctx context.Context) (*model.ScoringRuleSet, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ScoringRuleSet), args.Error(1)
+}
+
+func (m *MockScoringRepository) CreateScoringRuleSet(ctx context.Context, ruleSet *model.ScoringRuleSet) error {
+	args := m.Called(ctx, ruleSet)
+	return args.Error(0)
+}
+
+func (m *MockScoringRepository) ActivateScoringRuleSet(ctx context.Context, version int) error {
+	args := m.Called(ctx, version)
+	return args.Error(0)
+}
+
+func (m *MockScoringRepository) GetAchievementScore(ctx context.Context, achievementID uuid.UUID) (*model.AchievementScore, error) {
+	args := m.Called(ctx, achievementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AchievementScore), args.Error(1)
+}
+
+func (m *MockScoringRepository) SaveAchievementScore(ctx context.Context, score model.AchievementScore) error {
+	args := m.Called(ctx, score)
+	return args.Error(0)
+}
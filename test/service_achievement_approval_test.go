@@ -0,0 +1,256 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// approvalFixture: prestasi lomba internasional dengan chain dosen wali -> kepala departemen
+type approvalFixture struct {
+	mockRepo        *mocks.MockAchievementRepository
+	mockTypeRepo    *mocks.MockAchievementTypeRepository
+	mockScoringRepo *mocks.MockScoringRepository
+	mockDelegations *mocks.MockDelegationRepository
+	mockOutbox      *mocks.MockAchievementOutboxRepository
+	service         service.AchievementService
+	achievementID   uuid.UUID
+	studentID       uuid.UUID
+	advisorUserID   uuid.UUID
+	advisorID       uuid.UUID
+	headUserID      uuid.UUID
+	ref             *model.AchievementReference
+	advisorStage    model.AchievementApproval
+	headStage       model.AchievementApproval
+}
+
+func newApprovalFixture(advisorStageStatus string) *approvalFixture {
+	f := &approvalFixture{
+		mockRepo:        new(mocks.MockAchievementRepository),
+		mockTypeRepo:    new(mocks.MockAchievementTypeRepository),
+		mockScoringRepo: new(mocks.MockScoringRepository),
+		mockDelegations: new(mocks.MockDelegationRepository),
+		mockOutbox:      new(mocks.MockAchievementOutboxRepository),
+		achievementID:   uuid.New(),
+		studentID:       uuid.New(),
+		advisorUserID:   uuid.New(),
+		advisorID:       uuid.New(),
+		headUserID:      uuid.New(),
+	}
+	f.service = service.NewAchievementService(f.mockRepo, f.mockTypeRepo, f.mockScoringRepo, nil, f.mockDelegations, nil, service.NewAchievementOutboxService(f.mockOutbox, nil))
+	f.ref = &model.AchievementReference{
+		ID:                 f.achievementID,
+		StudentID:          f.studentID,
+		MongoAchievementID: "507f1f77bcf86cd799439011",
+		Status:             "submitted",
+		Revision:           1,
+	}
+	f.advisorStage = model.AchievementApproval{
+		ID: uuid.New(), AchievementID: f.achievementID, Revision: 1, StageOrder: 1,
+		StageName: "Dosen Wali", ApproverRole: model.ApproverRoleAdvisor, Status: advisorStageStatus,
+	}
+	f.headStage = model.AchievementApproval{
+		ID: uuid.New(), AchievementID: f.achievementID, Revision: 1, StageOrder: 2,
+		StageName: "Kepala Departemen", ApproverRole: "Kepala Departemen", Status: "pending",
+	}
+
+	f.mockRepo.On("GetAchievementApprovals", mock.Anything, f.achievementID, 1).Return([]model.AchievementApproval{f.advisorStage, f.headStage}, nil)
+	return f
+}
+
+func TestAchievementService_SubmitUsesApprovalChain(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := new(mocks.MockAchievementRepository)
+	mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+	mockApprovalRepo := new(mocks.MockApprovalChainRepository)
+	achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, nil, mockApprovalRepo, nil, nil, nil)
+
+	userID := uuid.New()
+	studentID := uuid.New()
+	achievementID := uuid.New()
+
+	competitionName := "ICPC"
+	competitionLevel := "international"
+	stages := []model.ApprovalStage{
+		{Name: "Dosen Wali", ApproverRole: model.ApproverRoleAdvisor},
+		{Name: "Kepala Departemen", ApproverRole: "Kepala Departemen"},
+	}
+
+	mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+	mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+		ID: achievementID, StudentID: studentID, MongoAchievementID: "mongo_id", Status: "draft",
+	}, nil).Once()
+	mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(&mongodb.Achievement{
+		AchievementType: "competition",
+		Title:           "ICPC World Finals",
+		Details:         mongodb.AchievementDetails{CompetitionName: &competitionName, CompetitionLevel: &competitionLevel},
+	}, nil)
+	mockTypeRepo.On("GetAchievementTypeByCode", ctx, "competition").Return(&model.AchievementType{Code: "competition", IsActive: true}, nil)
+	mockApprovalRepo.On("FindApprovalChain", ctx, "competition", "international").Return(&model.ApprovalChain{Stages: stages}, nil)
+	mockRepo.On("UpdateAchievementStatusToSubmitted", ctx, achievementID, userID, stages, "international").Return(nil)
+	mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+		ID: achievementID, StudentID: studentID, Status: "submitted",
+	}, nil).Once()
+
+	result, err := achievementService.SubmitForVerification(ctx, userID, achievementID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "submitted", result.Status)
+	mockRepo.AssertExpectations(t)
+	mockApprovalRepo.AssertExpectations(t)
+}
+
+func TestAchievementService_MultiStageVerification(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Advisor approval moves to the next stage without verifying", func(t *testing.T) {
+		f := newApprovalFixture("pending")
+
+		f.mockRepo.On("GetAchievementReferenceByID", ctx, f.achievementID).Return(f.ref, nil)
+		f.mockRepo.On("GetLecturerByUserID", ctx, f.advisorUserID).Return(&model.Lecturers{ID: f.advisorID}, nil)
+		f.mockRepo.On("GetStudentByID", ctx, f.studentID).Return(&model.Student{ID: f.studentID, AdvisorID: f.advisorID}, nil)
+		f.mockRepo.On("ApproveAchievementStage", ctx, mock.MatchedBy(func(d model.ApprovalDecision) bool {
+			return d.ApprovalID == f.advisorStage.ID && d.Status == "approved" && d.DecidedBy == f.advisorUserID && d.Note == nil
+		})).Return(nil)
+
+		result, err := f.service.VerifyAchievement(ctx, f.advisorUserID, f.achievementID, "  ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "submitted", result.Status)
+		f.mockRepo.AssertNotCalled(t, "UpdateAchievementStatusToVerified", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		f.mockScoringRepo.AssertNotCalled(t, "GetActiveScoringRuleSet", mock.Anything)
+	})
+
+	t.Run("Department head cannot approve before the advisor", func(t *testing.T) {
+		f := newApprovalFixture("pending")
+
+		f.mockRepo.On("GetAchievementReferenceByID", ctx, f.achievementID).Return(f.ref, nil)
+		headLecturerID := uuid.New()
+		f.mockRepo.On("GetLecturerByUserID", ctx, f.headUserID).Return(&model.Lecturers{ID: headLecturerID}, nil)
+		f.mockRepo.On("GetStudentByID", ctx, f.studentID).Return(&model.Student{ID: f.studentID, AdvisorID: f.advisorID}, nil)
+		f.mockDelegations.On("FindActiveDelegation", ctx, f.advisorID, headLecturerID, mock.Anything).Return(nil, nil)
+
+		result, err := f.service.VerifyAchievement(ctx, f.headUserID, f.achievementID, "")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "unauthorized: you can only verify achievements of your advisees")
+	})
+
+	t.Run("Last stage sets verified", func(t *testing.T) {
+		f := newApprovalFixture("approved")
+		headLecturerID := uuid.New()
+
+		f.mockRepo.On("GetAchievementReferenceByID", ctx, f.achievementID).Return(f.ref, nil).Once()
+		f.mockRepo.On("GetUserRoleName", ctx, f.headUserID).Return("Kepala Departemen", nil)
+		f.mockRepo.On("GetLecturerByUserID", ctx, f.headUserID).Return(&model.Lecturers{ID: headLecturerID, Department: "Teknik Informatika"}, nil)
+		f.mockRepo.On("GetStudentByID", ctx, f.studentID).Return(&model.Student{ID: f.studentID, AdvisorID: f.advisorID, Program_Study: "Teknik Informatika"}, nil)
+		// Skor dihitung dari versi terakhir, bukan dokumen MongoDB yang masih tertinggal
+		f.mockRepo.On("GetLatestAchievementSnapshot", ctx, f.achievementID).Return(&mongodb.Achievement{AchievementType: "other"}, nil)
+		f.mockScoringRepo.On("GetActiveScoringRuleSet", ctx).Return(&model.ScoringRuleSet{Version: 1, IsActive: true}, nil)
+		f.mockTypeRepo.On("GetAchievementTypeByCode", ctx, "other").Return(&model.AchievementType{Code: "other", DefaultPoints: 5}, nil)
+		f.mockRepo.On("UpdateAchievementStatusToVerified", ctx, f.achievementID, headLecturerID, f.headUserID, mock.AnythingOfType("model.AchievementScore"),
+			mock.MatchedBy(func(d model.ApprovalDecision) bool {
+				return d.ApprovalID == f.headStage.ID && d.Status == "approved" && *d.Note == "Disetujui"
+			})).Return(nil)
+		f.mockOutbox.On("WithAchievementOutboxLock", ctx, f.achievementID).Return(true, nil)
+		f.mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, f.achievementID).Return([]model.AchievementOutboxEvent{}, nil)
+		f.mockRepo.On("GetAchievementReferenceByID", ctx, f.achievementID).Return(&model.AchievementReference{ID: f.achievementID, Status: "verified"}, nil).Once()
+
+		result, err := f.service.VerifyAchievement(ctx, f.headUserID, f.achievementID, "Disetujui")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "verified", result.Status)
+		f.mockRepo.AssertExpectations(t)
+		f.mockRepo.AssertNotCalled(t, "GetAchievementDetailFromMongo", mock.Anything, mock.Anything)
+		f.mockOutbox.AssertExpectations(t)
+	})
+
+	t.Run("Department head of another department cannot approve", func(t *testing.T) {
+		f := newApprovalFixture("approved")
+
+		f.mockRepo.On("GetAchievementReferenceByID", ctx, f.achievementID).Return(f.ref, nil)
+		f.mockRepo.On("GetUserRoleName", ctx, f.headUserID).Return("Kepala Departemen", nil)
+		f.mockRepo.On("GetLecturerByUserID", ctx, f.headUserID).Return(&model.Lecturers{ID: uuid.New(), Department: "Teknik Elektro"}, nil)
+		f.mockRepo.On("GetStudentByID", ctx, f.studentID).Return(&model.Student{ID: f.studentID, AdvisorID: f.advisorID, Program_Study: "Teknik Informatika"}, nil)
+
+		result, err := f.service.VerifyAchievement(ctx, f.headUserID, f.achievementID, "")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "unauthorized: you can only verify achievements of students in your department")
+		f.mockRepo.AssertNotCalled(t, "UpdateAchievementStatusToVerified", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		f.mockScoringRepo.AssertNotCalled(t, "GetActiveScoringRuleSet", mock.Anything)
+	})
+
+	t.Run("Other roles cannot approve the department stage", func(t *testing.T) {
+		f := newApprovalFixture("approved")
+
+		f.mockRepo.On("GetAchievementReferenceByID", ctx, f.achievementID).Return(f.ref, nil)
+		f.mockRepo.On("GetUserRoleName", ctx, f.advisorUserID).Return("Dosen Wali", nil)
+
+		result, err := f.service.VerifyAchievement(ctx, f.advisorUserID, f.achievementID, "")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "unauthorized: you are not the approver of the current approval stage")
+	})
+
+	t.Run("Concurrent decision on the same stage", func(t *testing.T) {
+		f := newApprovalFixture("pending")
+
+		f.mockRepo.On("GetAchievementReferenceByID", ctx, f.achievementID).Return(f.ref, nil)
+		f.mockRepo.On("GetLecturerByUserID", ctx, f.advisorUserID).Return(&model.Lecturers{ID: f.advisorID}, nil)
+		f.mockRepo.On("GetStudentByID", ctx, f.studentID).Return(&model.Student{ID: f.studentID, AdvisorID: f.advisorID}, nil)
+		f.mockRepo.On("ApproveAchievementStage", ctx, mock.Anything).Return(repository.ErrApprovalAlreadyDecided)
+
+		result, err := f.service.VerifyAchievement(ctx, f.advisorUserID, f.achievementID, "")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "approval stage already decided")
+	})
+
+	t.Run("Department head rejects at the second stage", func(t *testing.T) {
+		f := newApprovalFixture("approved")
+		snapshot := &mongodb.Achievement{AchievementType: "other", Title: "Sertifikat"}
+
+		f.mockRepo.On("GetAchievementReferenceByID", ctx, f.achievementID).Return(f.ref, nil).Once()
+		f.mockRepo.On("GetUserRoleName", ctx, f.headUserID).Return("Kepala Departemen", nil)
+		f.mockRepo.On("GetLecturerByUserID", ctx, f.headUserID).Return(&model.Lecturers{ID: uuid.New(), Department: " teknik informatika"}, nil)
+		f.mockRepo.On("GetStudentByID", ctx, f.studentID).Return(&model.Student{ID: f.studentID, AdvisorID: f.advisorID, Program_Study: "Teknik Informatika"}, nil)
+		f.mockRepo.On("GetAchievementDetailFromMongo", ctx, f.ref.MongoAchievementID).Return(snapshot, nil)
+		f.mockRepo.On("UpdateAchievementStatusToRejected", ctx, f.achievementID, "Bukti kurang", f.headUserID, *snapshot,
+			mock.MatchedBy(func(d model.ApprovalDecision) bool {
+				return d.ApprovalID == f.headStage.ID && d.Status == "rejected"
+			})).Return(nil)
+		f.mockRepo.On("GetAchievementReferenceByID", ctx, f.achievementID).Return(&model.AchievementReference{ID: f.achievementID, Status: "rejected"}, nil).Once()
+
+		result, err := f.service.RejectAchievement(ctx, f.headUserID, f.achievementID, "Bukti kurang")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "rejected", result.Status)
+		f.mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestAchievementService_GetAchievementApprovals(t *testing.T) {
+	ctx := context.Background()
+	f := newApprovalFixture("approved")
+
+	f.mockRepo.On("GetAchievementReferenceByID", ctx, f.achievementID).Return(f.ref, nil)
+
+	result, err := f.service.GetAchievementApprovals(ctx, uuid.New(), true, f.achievementID)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Stages, 2)
+	assert.Equal(t, 1, result.Revision)
+	if assert.NotNil(t, result.CurrentStage) {
+		assert.Equal(t, 2, *result.CurrentStage)
+	}
+}
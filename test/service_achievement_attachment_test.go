@@ -0,0 +1,469 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/helper"
+	"UASBE/storage"
+	"UASBE/test/mocks"
+	"UASBE/utils"
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAchievementService_UploadAttachment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("File is stored and attachment saved with checksum", func(t *testing.T) {
+		store, err := storage.NewLocalStorage(t.TempDir())
+		assert.NoError(t, err)
+
+		mockRepo := new(mocks.MockAchievementRepository)
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, store, service.NewAchievementOutboxService(mockOutbox, nil))
+
+		userID := uuid.New()
+		studentID := uuid.New()
+		achievementID := uuid.New()
+
+		student := &model.Student{ID: studentID, UserID: userID}
+		ref := &model.AchievementReference{
+			ID:                 achievementID,
+			StudentID:          studentID,
+			MongoAchievementID: "mongo_id_123",
+			Status:             "draft",
+		}
+
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(student, nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil)
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id_123").Return(&mongodb.Achievement{StudentID: studentID}, nil)
+		// Versi terakhir sudah memuat attachment yang event outbox-nya belum diterapkan ke MongoDB
+		mockRepo.On("GetLatestAchievementSnapshot", ctx, achievementID).Return(&mongodb.Achievement{
+			StudentID:   studentID,
+			Attachments: []mongodb.Attachment{{ID: "att-0", FileName: "foto.jpg"}},
+		}, nil)
+		mockRepo.On("GetStudentStorageQuota", ctx, studentID).Return(nil, nil)
+		mockRepo.On("GetStudentAttachmentUsage", ctx, studentID).Return(int64(0), nil)
+		// Versi baru dibangun dari versi terakhir dan sudah berisi attachment; penulisan ke MongoDB lewat outbox
+		mockRepo.On("AddAttachmentToAchievement", ctx, achievementID, mock.MatchedBy(func(a mongodb.Attachment) bool {
+			return a.FileName == "sertifikat.pdf" && a.FileType == "application/pdf" && a.Checksum != "" && a.StorageKey != "" && a.Size == 13
+		}), mock.MatchedBy(func(a mongodb.Achievement) bool {
+			return a.StudentID == studentID && len(a.Attachments) == 2 && a.Attachments[0].ID == "att-0" && a.Attachments[1].FileName == "sertifikat.pdf"
+		}), userID, false).Return(nil)
+		mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{}, nil)
+
+		result, err := achievementService.UploadAttachment(ctx, userID, achievementID, "sertifikat.pdf", 13, strings.NewReader("%PDF-1.4 test"))
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.NotEmpty(t, result.ID)
+		assert.Equal(t, "/api/v1/achievements/"+achievementID.String()+"/attachments/"+result.ID, result.FileUrl)
+
+		// File benar-benar tersimpan
+		reader, _, err := store.Open(ctx, result.StorageKey)
+		assert.NoError(t, err)
+		reader.Close()
+
+		mockRepo.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
+	})
+
+	t.Run("Verified achievement cannot get new attachments", func(t *testing.T) {
+		store, _ := storage.NewLocalStorage(t.TempDir())
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, store, nil)
+
+		userID := uuid.New()
+		studentID := uuid.New()
+		achievementID := uuid.New()
+
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: studentID}, nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+			ID:        achievementID,
+			StudentID: studentID,
+			Status:    "verified",
+		}, nil)
+
+		result, err := achievementService.UploadAttachment(ctx, userID, achievementID, "sertifikat.pdf", 13, strings.NewReader("%PDF-1.4 test"))
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "attachments can only be added to draft or rejected achievements", err.Error())
+		mockRepo.AssertNotCalled(t, "AddAttachmentToAchievement", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Executable renamed to pdf is rejected", func(t *testing.T) {
+		f := newUploadFixture(t, nil, 0)
+
+		content := "MZ\x90\x00 not really a pdf"
+		result, err := f.service.UploadAttachment(ctx, f.userID, f.achievementID, "sertifikat.pdf", int64(len(content)), strings.NewReader(content))
+
+		assert.Nil(t, result)
+		var apiErr *helper.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, 415, apiErr.Status)
+		assert.Equal(t, "FILE_TYPE_NOT_ALLOWED", apiErr.Code)
+		f.mockRepo.AssertNotCalled(t, "AddAttachmentToAchievement", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Attachment count limit per achievement", func(t *testing.T) {
+		existing := make([]mongodb.Attachment, utils.MaxAttachmentsPerAchievement)
+		f := newUploadFixture(t, existing, 0)
+
+		_, err := f.service.UploadAttachment(ctx, f.userID, f.achievementID, "sertifikat.pdf", 13, strings.NewReader("%PDF-1.4 test"))
+
+		var apiErr *helper.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, 422, apiErr.Status)
+		assert.Equal(t, "ATTACHMENT_LIMIT_EXCEEDED", apiErr.Code)
+	})
+
+	t.Run("Student storage quota exceeded", func(t *testing.T) {
+		f := newUploadFixture(t, nil, 95)
+
+		_, err := f.service.UploadAttachment(ctx, f.userID, f.achievementID, "sertifikat.pdf", 13, strings.NewReader("%PDF-1.4 test"))
+
+		var apiErr *helper.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, 413, apiErr.Status)
+		assert.Equal(t, "STUDENT_QUOTA_EXCEEDED", apiErr.Code)
+		f.mockRepo.AssertNotCalled(t, "AddAttachmentToAchievement", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Declared size is not trusted", func(t *testing.T) {
+		f := newUploadFixture(t, nil, 0)
+		original := utils.MaxAttachmentSize
+		utils.MaxAttachmentSize = 16
+		defer func() { utils.MaxAttachmentSize = original }()
+
+		content := "%PDF-1.4 " + strings.Repeat("x", 32)
+		_, err := f.service.UploadAttachment(ctx, f.userID, f.achievementID, "sertifikat.pdf", 13, strings.NewReader(content))
+
+		var apiErr *helper.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, 413, apiErr.Status)
+		assert.Equal(t, "FILE_TOO_LARGE", apiErr.Code)
+		f.mockRepo.AssertNotCalled(t, "AddAttachmentToAchievement", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// uploadFixture menyiapkan draft achievement milik mahasiswa dengan kuota 100 byte
+type uploadFixture struct {
+	service       service.AchievementService
+	mockRepo      *mocks.MockAchievementRepository
+	userID        uuid.UUID
+	achievementID uuid.UUID
+}
+
+func newUploadFixture(t *testing.T, existing []mongodb.Attachment, usedBytes int64) *uploadFixture {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	assert.NoError(t, err)
+
+	f := &uploadFixture{
+		mockRepo:      new(mocks.MockAchievementRepository),
+		userID:        uuid.New(),
+		achievementID: uuid.New(),
+	}
+	f.service = service.NewAchievementService(f.mockRepo, nil, nil, nil, nil, store, nil)
+
+	studentID := uuid.New()
+	quota := int64(100)
+
+	f.mockRepo.On("GetStudentByUserID", mock.Anything, f.userID).Return(&model.Student{ID: studentID}, nil)
+	f.mockRepo.On("GetAchievementReferenceByID", mock.Anything, f.achievementID).Return(&model.AchievementReference{
+		ID:                 f.achievementID,
+		StudentID:          studentID,
+		MongoAchievementID: "mongo_id_123",
+		Status:             "draft",
+	}, nil)
+	f.mockRepo.On("GetAchievementDetailFromMongo", mock.Anything, "mongo_id_123").Return(&mongodb.Achievement{
+		StudentID:   studentID,
+		Attachments: existing,
+	}, nil)
+	f.mockRepo.On("GetLatestAchievementSnapshot", mock.Anything, f.achievementID).Return(&mongodb.Achievement{
+		StudentID:   studentID,
+		Attachments: existing,
+	}, nil).Maybe()
+	f.mockRepo.On("GetStudentStorageQuota", mock.Anything, studentID).Return(&quota, nil)
+	f.mockRepo.On("GetStudentAttachmentUsage", mock.Anything, studentID).Return(usedBytes, nil)
+	f.mockRepo.On("AddAttachmentToAchievement", mock.Anything, f.achievementID, mock.Anything, mock.Anything, f.userID, false).Return(nil)
+
+	return f
+}
+
+// attachmentFixture menyiapkan achievement dengan satu attachment yang sudah tersimpan di storage
+type attachmentFixture struct {
+	store         *storage.LocalStorage
+	mockRepo      *mocks.MockAchievementRepository
+	mockOutbox    *mocks.MockAchievementOutboxRepository
+	userID        uuid.UUID
+	studentID     uuid.UUID
+	achievementID uuid.UUID
+	ref           *model.AchievementReference
+	attachment    mongodb.Attachment
+	snapshot      *mongodb.Achievement // versi terakhir di PostgreSQL
+}
+
+func newAttachmentFixture(t *testing.T, status string) *attachmentFixture {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	assert.NoError(t, err)
+
+	obj, err := store.Put(context.Background(), strings.NewReader("%PDF-1.4 test"), "application/pdf")
+	assert.NoError(t, err)
+
+	f := &attachmentFixture{
+		store:         store,
+		mockRepo:      new(mocks.MockAchievementRepository),
+		mockOutbox:    new(mocks.MockAchievementOutboxRepository),
+		userID:        uuid.New(),
+		studentID:     uuid.New(),
+		achievementID: uuid.New(),
+	}
+	f.ref = &model.AchievementReference{
+		ID:                 f.achievementID,
+		StudentID:          f.studentID,
+		MongoAchievementID: "mongo_id_123",
+		Status:             status,
+	}
+	f.attachment = mongodb.Attachment{
+		ID:         "att-1",
+		FileName:   "sertifikat.pdf",
+		FileType:   "application/pdf",
+		Size:       obj.Size,
+		Checksum:   obj.Checksum,
+		StorageKey: obj.Key,
+	}
+
+	f.mockRepo.On("GetAchievementReferenceByID", mock.Anything, f.achievementID).Return(f.ref, nil)
+	f.mockRepo.On("GetAchievementDetailFromMongo", mock.Anything, "mongo_id_123").Return(&mongodb.Achievement{
+		StudentID:   f.studentID,
+		Attachments: []mongodb.Attachment{f.attachment},
+	}, nil).Maybe()
+	f.snapshot = &mongodb.Achievement{
+		StudentID:   f.studentID,
+		Attachments: []mongodb.Attachment{f.attachment},
+	}
+	f.mockRepo.On("GetLatestAchievementSnapshot", mock.Anything, f.achievementID).Return(f.snapshot, nil).Maybe()
+	f.mockOutbox.On("WithAchievementOutboxLock", mock.Anything, f.achievementID).Return(true, nil)
+	f.mockOutbox.On("GetPendingOutboxEventsByAchievement", mock.Anything, f.achievementID).Return([]model.AchievementOutboxEvent{}, nil)
+
+	return f
+}
+
+func TestAchievementService_GetAttachment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Owner can download", func(t *testing.T) {
+		f := newAttachmentFixture(t, "submitted")
+		achievementService := service.NewAchievementService(f.mockRepo, nil, nil, nil, nil, f.store, nil)
+		f.mockRepo.On("GetStudentByUserID", ctx, f.userID).Return(&model.Student{ID: f.studentID}, nil)
+
+		attachment, reader, err := achievementService.GetAttachment(ctx, f.userID, false, f.achievementID, "att-1")
+		assert.NoError(t, err)
+		defer reader.Close()
+
+		data, _ := io.ReadAll(reader)
+		assert.Equal(t, "%PDF-1.4 test", string(data))
+		assert.Equal(t, "sertifikat.pdf", attachment.FileName)
+	})
+
+	t.Run("Advisor can download", func(t *testing.T) {
+		f := newAttachmentFixture(t, "submitted")
+		achievementService := service.NewAchievementService(f.mockRepo, nil, nil, nil, nil, f.store, nil)
+		lecturerID := uuid.New()
+
+		f.mockRepo.On("GetStudentByUserID", ctx, f.userID).Return(nil, errors.New("not a student"))
+		f.mockRepo.On("GetLecturerByUserID", ctx, f.userID).Return(&model.Lecturers{ID: lecturerID}, nil)
+		f.mockRepo.On("GetStudentByID", ctx, f.studentID).Return(&model.Student{ID: f.studentID, AdvisorID: lecturerID}, nil)
+
+		_, reader, err := achievementService.GetAttachment(ctx, f.userID, false, f.achievementID, "att-1")
+		assert.NoError(t, err)
+		reader.Close()
+	})
+
+	t.Run("Admin can download", func(t *testing.T) {
+		f := newAttachmentFixture(t, "verified")
+		achievementService := service.NewAchievementService(f.mockRepo, nil, nil, nil, nil, f.store, nil)
+
+		_, reader, err := achievementService.GetAttachment(ctx, f.userID, true, f.achievementID, "att-1")
+		assert.NoError(t, err)
+		reader.Close()
+	})
+
+	t.Run("Current stage approver can download", func(t *testing.T) {
+		f := newAttachmentFixture(t, "submitted")
+		mockDelegations := new(mocks.MockDelegationRepository)
+		achievementService := service.NewAchievementService(f.mockRepo, nil, nil, nil, mockDelegations, f.store, nil)
+
+		headLecturerID := uuid.New()
+		f.mockRepo.On("GetStudentByUserID", ctx, f.userID).Return(nil, errors.New("not a student"))
+		f.mockRepo.On("GetLecturerByUserID", ctx, f.userID).Return(&model.Lecturers{ID: headLecturerID, Department: "Teknik Informatika"}, nil)
+		f.mockRepo.On("GetStudentByID", ctx, f.studentID).Return(&model.Student{ID: f.studentID, AdvisorID: uuid.New(), Program_Study: "Teknik Informatika"}, nil)
+		mockDelegations.On("FindActiveDelegation", ctx, mock.Anything, headLecturerID, mock.Anything).Return(nil, nil)
+		f.mockRepo.On("GetAchievementApprovals", ctx, f.achievementID, 0).Return([]model.AchievementApproval{
+			{StageOrder: 1, ApproverRole: model.ApproverRoleAdvisor, Status: "approved"},
+			{StageOrder: 2, ApproverRole: "Kepala Departemen", Status: "pending"},
+		}, nil)
+		f.mockRepo.On("GetUserRoleName", ctx, f.userID).Return("kepala departemen", nil)
+
+		_, reader, err := achievementService.GetAttachment(ctx, f.userID, false, f.achievementID, "att-1")
+		assert.NoError(t, err)
+		reader.Close()
+	})
+
+	t.Run("Other users are denied", func(t *testing.T) {
+		f := newAttachmentFixture(t, "submitted")
+		achievementService := service.NewAchievementService(f.mockRepo, nil, nil, nil, nil, f.store, nil)
+
+		f.mockRepo.On("GetStudentByUserID", ctx, f.userID).Return(&model.Student{ID: uuid.New()}, nil)
+		f.mockRepo.On("GetLecturerByUserID", ctx, f.userID).Return(nil, errors.New("not a lecturer"))
+		f.mockRepo.On("GetAchievementApprovals", ctx, f.achievementID, 0).Return([]model.AchievementApproval{
+			{StageOrder: 1, ApproverRole: "Kepala Departemen", Status: "pending"},
+		}, nil)
+		f.mockRepo.On("GetUserRoleName", ctx, f.userID).Return("Mahasiswa", nil)
+
+		_, reader, err := achievementService.GetAttachment(ctx, f.userID, false, f.achievementID, "att-1")
+		assert.Error(t, err)
+		assert.Nil(t, reader)
+		assert.Equal(t, "unauthorized: you do not have access to this achievement", err.Error())
+	})
+
+	t.Run("Unknown attachment", func(t *testing.T) {
+		f := newAttachmentFixture(t, "submitted")
+		achievementService := service.NewAchievementService(f.mockRepo, nil, nil, nil, nil, f.store, nil)
+
+		_, _, err := achievementService.GetAttachment(ctx, f.userID, true, f.achievementID, "missing")
+		assert.Error(t, err)
+		assert.Equal(t, "attachment not found", err.Error())
+	})
+}
+
+func TestAchievementService_DeleteAttachment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Owner deletes attachment from draft", func(t *testing.T) {
+		f := newAttachmentFixture(t, "draft")
+		achievementService := service.NewAchievementService(f.mockRepo, nil, nil, nil, nil, f.store, service.NewAchievementOutboxService(f.mockOutbox, nil))
+
+		f.mockRepo.On("GetStudentByUserID", ctx, f.userID).Return(&model.Student{ID: f.studentID}, nil)
+		f.mockRepo.On("RemoveAttachmentFromAchievement", ctx, f.achievementID, f.attachment, mock.MatchedBy(func(a mongodb.Achievement) bool {
+			return len(a.Attachments) == 0
+		}), f.userID, false).Return(nil)
+
+		err := achievementService.DeleteAttachment(ctx, f.userID, f.achievementID, "att-1")
+		assert.NoError(t, err)
+
+		// File baru dihapus relay outbox setelah attachment hilang dari dokumen
+		reader, _, err := f.store.Open(ctx, f.attachment.StorageKey)
+		assert.NoError(t, err)
+		reader.Close()
+		f.mockRepo.AssertNotCalled(t, "CountAttachmentsByStorageKey", mock.Anything, mock.Anything)
+
+		f.mockRepo.AssertExpectations(t)
+		f.mockOutbox.AssertExpectations(t)
+	})
+
+	t.Run("Attachment not yet applied to MongoDB can be deleted", func(t *testing.T) {
+		f := newAttachmentFixture(t, "draft")
+		achievementService := service.NewAchievementService(f.mockRepo, nil, nil, nil, nil, f.store, service.NewAchievementOutboxService(f.mockOutbox, nil))
+
+		pending := mongodb.Attachment{ID: "att-2", FileName: "foto.jpg", StorageKey: "ab/cd/pending"}
+		f.snapshot.Attachments = append(f.snapshot.Attachments, pending)
+		f.mockRepo.On("GetStudentByUserID", ctx, f.userID).Return(&model.Student{ID: f.studentID}, nil)
+		f.mockRepo.On("RemoveAttachmentFromAchievement", ctx, f.achievementID, pending, mock.MatchedBy(func(a mongodb.Achievement) bool {
+			return len(a.Attachments) == 1 && a.Attachments[0].ID == "att-1"
+		}), f.userID, false).Return(nil)
+
+		err := achievementService.DeleteAttachment(ctx, f.userID, f.achievementID, "att-2")
+		assert.NoError(t, err)
+		f.mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejected achievement is revised", func(t *testing.T) {
+		f := newAttachmentFixture(t, "rejected")
+		achievementService := service.NewAchievementService(f.mockRepo, nil, nil, nil, nil, f.store, service.NewAchievementOutboxService(f.mockOutbox, nil))
+
+		// Prestasi yang ditolak sekaligus berpindah ke 'revised' di transaksi yang sama
+		f.mockRepo.On("GetStudentByUserID", ctx, f.userID).Return(&model.Student{ID: f.studentID}, nil)
+		f.mockRepo.On("RemoveAttachmentFromAchievement", ctx, f.achievementID, f.attachment, mock.Anything, f.userID, true).Return(nil)
+
+		err := achievementService.DeleteAttachment(ctx, f.userID, f.achievementID, "att-1")
+		assert.NoError(t, err)
+		f.mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Submitted achievement cannot lose attachments", func(t *testing.T) {
+		f := newAttachmentFixture(t, "submitted")
+		achievementService := service.NewAchievementService(f.mockRepo, nil, nil, nil, nil, f.store, nil)
+
+		f.mockRepo.On("GetStudentByUserID", ctx, f.userID).Return(&model.Student{ID: f.studentID}, nil)
+
+		err := achievementService.DeleteAttachment(ctx, f.userID, f.achievementID, "att-1")
+		assert.Error(t, err)
+		assert.Equal(t, "attachments can only be deleted from draft or rejected achievements", err.Error())
+		f.mockRepo.AssertNotCalled(t, "RemoveAttachmentFromAchievement", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Non-owner cannot delete", func(t *testing.T) {
+		f := newAttachmentFixture(t, "draft")
+		achievementService := service.NewAchievementService(f.mockRepo, nil, nil, nil, nil, f.store, nil)
+
+		f.mockRepo.On("GetStudentByUserID", ctx, f.userID).Return(&model.Student{ID: uuid.New()}, nil)
+
+		err := achievementService.DeleteAttachment(ctx, f.userID, f.achievementID, "att-1")
+		assert.Error(t, err)
+		assert.Equal(t, "unauthorized: achievement does not belong to this student", err.Error())
+	})
+}
+
+func TestAchievementService_SignedAttachmentURL(t *testing.T) {
+	ctx := context.Background()
+
+	f := newAttachmentFixture(t, "submitted")
+	achievementService := service.NewAchievementService(f.mockRepo, nil, nil, nil, nil, f.store, nil)
+
+	app := fiber.New()
+	app.Get("/api/v1/public/attachments/:id/:attachmentId", achievementService.GetSignedAttachmentEndpoint)
+
+	t.Run("Signed URL downloads without bearer token", func(t *testing.T) {
+		url, expiresAt, err := achievementService.CreateAttachmentSignedURL(ctx, f.userID, true, f.achievementID, "att-1", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, expiresAt.After(time.Now()))
+
+		resp, err := app.Test(httptest.NewRequest("GET", url, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "application/pdf", resp.Header.Get("Content-Type"))
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "%PDF-1.4 test", string(body))
+	})
+
+	t.Run("Tampered signature is rejected", func(t *testing.T) {
+		url, _, _ := achievementService.CreateAttachmentSignedURL(ctx, f.userID, true, f.achievementID, "att-1", time.Minute)
+
+		resp, err := app.Test(httptest.NewRequest("GET", url+"0", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+
+	t.Run("Signature is bound to the attachment", func(t *testing.T) {
+		url, _, _ := achievementService.CreateAttachmentSignedURL(ctx, f.userID, true, f.achievementID, "att-1", time.Minute)
+
+		resp, err := app.Test(httptest.NewRequest("GET", strings.Replace(url, "att-1", "att-2", 1), nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+}
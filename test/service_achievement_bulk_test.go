@@ -0,0 +1,150 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAchievementService_BulkVerifyAchievements(t *testing.T) {
+	ctx := context.Background()
+
+	userID := uuid.New()
+	lecturerID := uuid.New()
+	adviseeID := uuid.New()
+	otherStudentID := uuid.New()
+	okID := uuid.New()
+	foreignID := uuid.New()
+	draftID := uuid.New()
+
+	mockRepo := new(mocks.MockAchievementRepository)
+	mockDelegations := new(mocks.MockDelegationRepository)
+	mockScoringRepo := new(mocks.MockScoringRepository)
+	mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+	mockOutbox := new(mocks.MockAchievementOutboxRepository)
+	achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, mockScoringRepo, nil, mockDelegations, nil, service.NewAchievementOutboxService(mockOutbox, nil))
+
+	stage := func(id uuid.UUID) {
+		mockRepo.On("GetAchievementApprovals", ctx, id, 0).Return([]model.AchievementApproval{
+			{ID: uuid.New(), StageOrder: 1, ApproverRole: model.ApproverRoleAdvisor, Status: "pending"},
+		}, nil)
+	}
+
+	mockRepo.On("GetLecturerByUserID", ctx, userID).Return(&model.Lecturers{ID: lecturerID, UserID: userID}, nil)
+	mockRepo.On("GetStudentByID", ctx, adviseeID).Return(&model.Student{ID: adviseeID, AdvisorID: lecturerID}, nil)
+	mockRepo.On("GetStudentByID", ctx, otherStudentID).Return(&model.Student{ID: otherStudentID, AdvisorID: uuid.New()}, nil)
+	mockDelegations.On("FindActiveDelegation", ctx, mock.Anything, lecturerID, mock.Anything).Return(nil, nil)
+
+	// Item yang berhasil
+	mockRepo.On("GetAchievementReferenceByID", ctx, okID).Return(&model.AchievementReference{
+		ID: okID, StudentID: adviseeID, MongoAchievementID: "mongo_ok", Status: "submitted",
+	}, nil).Once()
+	stage(okID)
+	mockRepo.On("GetLatestAchievementSnapshot", ctx, okID).Return(&mongodb.Achievement{AchievementType: "other"}, nil)
+	mockScoringRepo.On("GetActiveScoringRuleSet", ctx).Return(&model.ScoringRuleSet{Version: 1, IsActive: true}, nil)
+	mockTypeRepo.On("GetAchievementTypeByCode", ctx, "other").Return(&model.AchievementType{Code: "other", DefaultPoints: 5}, nil)
+	mockRepo.On("UpdateAchievementStatusToVerified", ctx, okID, lecturerID, userID, mock.AnythingOfType("model.AchievementScore"),
+		mock.MatchedBy(func(d model.ApprovalDecision) bool { return d.Note != nil && *d.Note == "Lengkap" })).Return(nil)
+	mockOutbox.On("WithAchievementOutboxLock", ctx, okID).Return(true, nil)
+	mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, okID).Return([]model.AchievementOutboxEvent{}, nil)
+	mockRepo.On("GetAchievementReferenceByID", ctx, okID).Return(&model.AchievementReference{ID: okID, Status: "verified"}, nil).Once()
+
+	// Item milik mahasiswa bimbingan dosen lain
+	mockRepo.On("GetAchievementReferenceByID", ctx, foreignID).Return(&model.AchievementReference{
+		ID: foreignID, StudentID: otherStudentID, MongoAchievementID: "mongo_foreign", Status: "submitted",
+	}, nil)
+	stage(foreignID)
+
+	// Item yang belum disubmit
+	mockRepo.On("GetAchievementReferenceByID", ctx, draftID).Return(&model.AchievementReference{
+		ID: draftID, StudentID: adviseeID, Status: "draft",
+	}, nil)
+
+	result, err := achievementService.BulkVerifyAchievements(ctx, userID, model.BulkAchievementActionRequest{
+		AchievementIDs: []string{okID.String(), foreignID.String(), draftID.String(), "not-a-uuid", okID.String()},
+		Note:           "Lengkap",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Total)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 3, result.Failed)
+
+	assert.True(t, result.Results[0].Success)
+	assert.Equal(t, "verified", *result.Results[0].Status)
+	assert.Equal(t, 403, result.Results[1].Code)
+	assert.Equal(t, 400, result.Results[2].Code)
+	assert.Equal(t, "achievement must be in 'submitted' status to verify", *result.Results[2].Error)
+	assert.Equal(t, "not-a-uuid", result.Results[3].AchievementID)
+	assert.Equal(t, 400, result.Results[3].Code)
+	mockRepo.AssertNumberOfCalls(t, "UpdateAchievementStatusToVerified", 1)
+}
+
+func TestAchievementService_BulkRejectAchievements(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("Error - rejection note is required", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		result, err := achievementService.BulkRejectAchievements(ctx, userID, model.BulkAchievementActionRequest{
+			AchievementIDs: []string{uuid.New().String()},
+			Note:           "  ",
+		})
+
+		assert.EqualError(t, err, "rejection note is required")
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "GetAchievementReferenceByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - too many items", func(t *testing.T) {
+		ids := make([]string, 101)
+		for i := range ids {
+			ids[i] = uuid.New().String()
+		}
+		achievementService := service.NewAchievementService(new(mocks.MockAchievementRepository), nil, nil, nil, nil, nil, nil)
+
+		_, err := achievementService.BulkRejectAchievements(ctx, userID, model.BulkAchievementActionRequest{AchievementIDs: ids, Note: "Kurang bukti"})
+
+		assert.EqualError(t, err, "achievement_ids must contain between 1 and 100 items")
+	})
+
+	t.Run("Success - shared note applied to each item", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, new(mocks.MockDelegationRepository), nil, nil)
+
+		lecturerID := uuid.New()
+		studentID := uuid.New()
+		achievementID := uuid.New()
+
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+			ID: achievementID, StudentID: studentID, MongoAchievementID: "mongo_id", Status: "submitted",
+		}, nil).Once()
+		mockRepo.On("GetAchievementApprovals", ctx, achievementID, 0).Return([]model.AchievementApproval{
+			{ID: uuid.New(), StageOrder: 1, ApproverRole: model.ApproverRoleAdvisor, Status: "pending"},
+		}, nil)
+		mockRepo.On("GetLecturerByUserID", ctx, userID).Return(&model.Lecturers{ID: lecturerID, UserID: userID}, nil)
+		mockRepo.On("GetStudentByID", ctx, studentID).Return(&model.Student{ID: studentID, AdvisorID: lecturerID}, nil)
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(&mongodb.Achievement{AchievementType: "other"}, nil)
+		mockRepo.On("UpdateAchievementStatusToRejected", ctx, achievementID, "Kurang bukti", userID, mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{ID: achievementID, Status: "rejected"}, nil).Once()
+
+		result, err := achievementService.BulkRejectAchievements(ctx, userID, model.BulkAchievementActionRequest{
+			AchievementIDs: []string{achievementID.String()},
+			Note:           " Kurang bukti ",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Succeeded)
+		assert.Equal(t, "rejected", *result.Results[0].Status)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,182 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAchievementService_AchievementComments(t *testing.T) {
+	ctx := context.Background()
+
+	studentUserID := uuid.New()
+	studentID := uuid.New()
+	achievementID := uuid.New()
+	ref := &model.AchievementReference{ID: achievementID, StudentID: studentID, MongoAchievementID: "mongo_id", Status: "submitted"}
+
+	setup := func() (*mocks.MockAchievementRepository, service.AchievementService) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil)
+		mockRepo.On("GetStudentByUserID", ctx, studentUserID).Return(&model.Student{ID: studentID, UserID: studentUserID}, nil)
+		return mockRepo, service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+	}
+
+	t.Run("Threads group replies under their root", func(t *testing.T) {
+		mockRepo, achievementService := setup()
+
+		rootA, rootB := uuid.New(), uuid.New()
+		now := time.Now()
+		mockRepo.On("GetAchievementComments", ctx, achievementID).Return([]model.AchievementComment{
+			{ID: rootA, AchievementID: achievementID, Body: "Sertifikat belum jelas", CreatedAt: now},
+			{ID: rootB, AchievementID: achievementID, Body: "Tanggal lomba?", IsResolved: true, CreatedAt: now.Add(time.Minute)},
+			{ID: uuid.New(), AchievementID: achievementID, ParentID: &rootA, Body: "Sudah saya unggah ulang", CreatedAt: now.Add(2 * time.Minute)},
+		}, nil)
+
+		result, err := achievementService.GetAchievementComments(ctx, studentUserID, false, achievementID)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Threads, 2)
+		assert.Len(t, result.Threads[0].Replies, 1)
+		assert.Empty(t, result.Threads[1].Replies)
+		assert.Equal(t, 1, result.Unresolved)
+	})
+
+	t.Run("Comment anchored to an existing attachment", func(t *testing.T) {
+		mockRepo, achievementService := setup()
+
+		attachmentID := "att-1"
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(&mongodb.Achievement{
+			Attachments: []mongodb.Attachment{{ID: attachmentID, FileName: "sertifikat.pdf"}},
+		}, nil)
+		mockRepo.On("CreateAchievementComment", ctx, mock.MatchedBy(func(c model.AchievementComment) bool {
+			return c.AuthorID == studentUserID && c.Body == "Versi terbaru" && c.AttachmentID != nil && *c.AttachmentID == attachmentID
+		})).Return(nil)
+		mockRepo.On("GetAchievementCommentByID", ctx, mock.AnythingOfType("uuid.UUID")).Return(&model.AchievementComment{
+			AchievementID: achievementID, Body: "Versi terbaru", AttachmentID: &attachmentID,
+		}, nil)
+
+		comment, err := achievementService.AddAchievementComment(ctx, studentUserID, false, achievementID, model.CreateAchievementCommentRequest{
+			Body:         " Versi terbaru ",
+			AttachmentID: &attachmentID,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Versi terbaru", comment.Body)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - unknown attachment", func(t *testing.T) {
+		mockRepo, achievementService := setup()
+
+		attachmentID := "missing"
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(&mongodb.Achievement{}, nil)
+
+		_, err := achievementService.AddAchievementComment(ctx, studentUserID, false, achievementID, model.CreateAchievementCommentRequest{
+			Body:         "Cek ini",
+			AttachmentID: &attachmentID,
+		})
+
+		assert.EqualError(t, err, "attachment not found")
+		mockRepo.AssertNotCalled(t, "CreateAchievementComment", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Comment anchored to a nested detail field", func(t *testing.T) {
+		mockRepo, achievementService := setup()
+
+		fieldPath := "details.period.start"
+		mockRepo.On("CreateAchievementComment", ctx, mock.MatchedBy(func(c model.AchievementComment) bool {
+			return c.FieldPath != nil && *c.FieldPath == fieldPath
+		})).Return(nil)
+		mockRepo.On("GetAchievementCommentByID", ctx, mock.AnythingOfType("uuid.UUID")).Return(&model.AchievementComment{
+			AchievementID: achievementID, Body: "Tanggal mulai salah", FieldPath: &fieldPath,
+		}, nil)
+
+		comment, err := achievementService.AddAchievementComment(ctx, studentUserID, false, achievementID, model.CreateAchievementCommentRequest{
+			Body:      "Tanggal mulai salah",
+			FieldPath: &fieldPath,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, fieldPath, *comment.FieldPath)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid field path", func(t *testing.T) {
+		_, achievementService := setup()
+
+		fieldPath := "points"
+		_, err := achievementService.AddAchievementComment(ctx, studentUserID, false, achievementID, model.CreateAchievementCommentRequest{
+			Body:      "Poin salah",
+			FieldPath: &fieldPath,
+		})
+
+		assert.EqualError(t, err, "invalid field_path")
+	})
+
+	t.Run("Error - reply to a reply", func(t *testing.T) {
+		mockRepo, achievementService := setup()
+
+		rootID, replyID := uuid.New(), uuid.New()
+		mockRepo.On("GetAchievementCommentByID", ctx, replyID).Return(&model.AchievementComment{
+			ID: replyID, AchievementID: achievementID, ParentID: &rootID,
+		}, nil)
+
+		parent := replyID.String()
+		_, err := achievementService.AddAchievementComment(ctx, studentUserID, false, achievementID, model.CreateAchievementCommentRequest{
+			Body:     "Oke",
+			ParentID: &parent,
+		})
+
+		assert.EqualError(t, err, "replies can only be posted to top-level comments")
+	})
+
+	t.Run("Error - comment from another achievement", func(t *testing.T) {
+		mockRepo, achievementService := setup()
+
+		commentID := uuid.New()
+		mockRepo.On("GetAchievementCommentByID", ctx, commentID).Return(&model.AchievementComment{ID: commentID, AchievementID: uuid.New()}, nil)
+
+		_, err := achievementService.SetAchievementCommentResolved(ctx, studentUserID, false, achievementID, commentID, true)
+
+		assert.EqualError(t, err, "comment not found")
+	})
+
+	t.Run("Resolve thread", func(t *testing.T) {
+		mockRepo, achievementService := setup()
+
+		commentID := uuid.New()
+		mockRepo.On("GetAchievementCommentByID", ctx, commentID).Return(&model.AchievementComment{ID: commentID, AchievementID: achievementID}, nil).Once()
+		mockRepo.On("SetAchievementCommentResolved", ctx, commentID, true, studentUserID, mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("GetAchievementCommentByID", ctx, commentID).Return(&model.AchievementComment{ID: commentID, AchievementID: achievementID, IsResolved: true}, nil).Once()
+
+		comment, err := achievementService.SetAchievementCommentResolved(ctx, studentUserID, false, achievementID, commentID, true)
+
+		assert.NoError(t, err)
+		assert.True(t, comment.IsResolved)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - unrelated user cannot comment", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		otherUserID := uuid.New()
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{ID: achievementID, StudentID: studentID, Status: "draft"}, nil)
+		mockRepo.On("GetStudentByUserID", ctx, otherUserID).Return(&model.Student{ID: uuid.New()}, nil)
+		mockRepo.On("GetLecturerByUserID", ctx, otherUserID).Return(nil, errors.New("not found"))
+
+		_, err := achievementService.AddAchievementComment(ctx, otherUserID, false, achievementID, model.CreateAchievementCommentRequest{Body: "Halo"})
+
+		assert.EqualError(t, err, "unauthorized: you do not have access to this achievement")
+		mockRepo.AssertNotCalled(t, "CreateAchievementComment", mock.Anything, mock.Anything)
+	})
+}
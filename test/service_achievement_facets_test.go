@@ -0,0 +1,94 @@
+package test
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAchievementService_GetAllAchievementsForAdmin_Facets(t *testing.T) {
+	ctx := context.Background()
+	filters := model.AdminAchievementFilters{ProgramStudy: "Teknik Informatika"}
+
+	t.Run("Success - facets returned with the listing", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		advisorName := "Dr. Budi"
+		facets := &model.AchievementFacets{
+			Status:       []model.FacetCount{{Value: "verified", Count: 3}, {Value: "submitted", Count: 1}},
+			ProgramStudy: []model.FacetCount{{Value: "Teknik Informatika", Count: 4}, {Value: "Sistem Informasi", Count: 2}},
+			Advisor:      []model.FacetCount{{Value: uuid.New().String(), Label: &advisorName, Count: 4}},
+			Points:       []model.FacetCount{{Value: "0", Count: 1}, {Value: "25-49", Count: 3}},
+		}
+		mockRepo.On("GetAllAchievementsForAdmin", ctx, filters, 1, 10).Return([]model.AchievementWithStudent{}, 4, nil)
+		mockRepo.On("GetAdminAchievementFacets", ctx, filters).Return(facets, nil)
+
+		result, err := achievementService.GetAllAchievementsForAdmin(ctx, filters, 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, facets, result.Facets)
+		assert.Equal(t, 4, result.Pagination.Total)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - facet query fails", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		mockRepo.On("GetAllAchievementsForAdmin", ctx, filters, 1, 10).Return([]model.AchievementWithStudent{}, 0, nil)
+		mockRepo.On("GetAdminAchievementFacets", ctx, filters).Return(nil, errors.New("db error"))
+
+		result, err := achievementService.GetAllAchievementsForAdmin(ctx, filters, 1, 10)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "failed to get achievement facets")
+	})
+}
+
+func TestAchievementService_GetAllAchievementsForAdminEndpoint_StudentFacetFilters(t *testing.T) {
+	mockRepo := new(mocks.MockAchievementRepository)
+	achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+	app := fiber.New()
+	app.Get("/admin/achievements", achievementService.GetAllAchievementsForAdminEndpoint)
+
+	advisorID := uuid.New()
+	expected := model.AdminAchievementFilters{
+		AchievementListFilters: model.AchievementListFilters{AchievementType: "competition", Tag: "ai", SortBy: "created_at", SortOrder: "desc"},
+		ProgramStudy:           "Teknik Informatika",
+		AcademicYear:           "2023",
+		AdvisorID:              &advisorID,
+	}
+	mockRepo.On("GetAllAchievementsForAdmin", mock.Anything, expected, 1, 10).Return([]model.AchievementWithStudent{}, 0, nil)
+	mockRepo.On("GetAdminAchievementFacets", mock.Anything, expected).
+		Return(&model.AchievementFacets{AcademicYear: []model.FacetCount{{Value: "2023", Count: 2}}}, nil)
+
+	url := "/admin/achievements?achievement_type=competition&tag=ai&program_study=Teknik%20Informatika" +
+		"&academic_year=2023&advisor_id=" + advisorID.String()
+	resp, err := app.Test(httptest.NewRequest("GET", url, nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	var payload struct {
+		Data model.AchievementListResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &payload))
+	if assert.NotNil(t, payload.Data.Facets) {
+		assert.Equal(t, []model.FacetCount{{Value: "2023", Count: 2}}, payload.Data.Facets.AcademicYear)
+	}
+	mockRepo.AssertExpectations(t)
+}
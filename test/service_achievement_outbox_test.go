@@ -0,0 +1,461 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/app/service"
+	"UASBE/storage"
+	"UASBE/test/mocks"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Setiap langkah dual-write (transaksi PostgreSQL, penerapan ke MongoDB, penandaan event) digagalkan satu per satu:
+// kegagalan PostgreSQL tidak boleh menyentuh MongoDB, kegagalan MongoDB tidak boleh menghilangkan event.
+
+func TestAchievementOutbox_SubmitPrestasi(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	studentID := uuid.New()
+	competitionName := "Gemastik"
+	competitionLevel := "national"
+	achievement := mongodb.Achievement{
+		AchievementType: "competition",
+		Title:           "Juara 1 Gemastik",
+		Details: mongodb.AchievementDetails{
+			CompetitionName:  &competitionName,
+			CompetitionLevel: &competitionLevel,
+		},
+	}
+
+	setup := func() (*mocks.MockAchievementRepository, *mocks.MockAchievementOutboxRepository, service.AchievementService) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+		mockTypeRepo.On("GetAchievementTypeByCode", ctx, "competition").Return(&model.AchievementType{Code: "competition", IsActive: true}, nil)
+		return mockRepo, mockOutbox, service.NewAchievementService(mockRepo, mockTypeRepo, nil, nil, nil, nil, service.NewAchievementOutboxService(mockOutbox, nil))
+	}
+
+	t.Run("PostgreSQL failure - nothing written to MongoDB", func(t *testing.T) {
+		mockRepo, mockOutbox, achievementService := setup()
+		mockRepo.On("SaveAchievementReference", ctx, mock.AnythingOfType("model.AchievementReference"), mock.AnythingOfType("mongodb.Achievement"), userID).
+			Return(errors.New("connection reset"))
+
+		result, err := achievementService.SubmitPrestasi(ctx, userID, achievement)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockOutbox.AssertNotCalled(t, "GetPendingOutboxEventsByAchievement", mock.Anything, mock.Anything)
+		mockOutbox.AssertNotCalled(t, "ApplyOutboxEvent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("MongoDB failure - submission kept and event scheduled for retry", func(t *testing.T) {
+		mockRepo, mockOutbox, achievementService := setup()
+
+		var saved model.AchievementReference
+		var snapshot mongodb.Achievement
+		mockRepo.On("SaveAchievementReference", ctx, mock.AnythingOfType("model.AchievementReference"), mock.AnythingOfType("mongodb.Achievement"), userID).
+			Run(func(args mock.Arguments) {
+				saved = args.Get(1).(model.AchievementReference)
+				snapshot = args.Get(2).(mongodb.Achievement)
+			}).Return(nil)
+
+		event := model.AchievementOutboxEvent{ID: 1, Operation: model.OutboxOpUpsertDocument}
+		mockOutbox.On("WithAchievementOutboxLock", ctx, mock.AnythingOfType("uuid.UUID")).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, mock.AnythingOfType("uuid.UUID")).Return([]model.AchievementOutboxEvent{event}, nil)
+		mockOutbox.On("ApplyOutboxEvent", ctx, event).Return(errors.New("server selection timeout"))
+		mockOutbox.On("MarkOutboxEventFailed", ctx, int64(1), "server selection timeout", mock.MatchedBy(func(next time.Time) bool {
+			return next.After(time.Now()) && next.Before(time.Now().Add(time.Minute))
+		})).Return(nil)
+
+		result, err := achievementService.SubmitPrestasi(ctx, userID, achievement)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "draft", result.Status)
+		// ID dokumen dibuat sebelum transaksi sehingga reference dan event menunjuk dokumen yang sama
+		assert.Equal(t, snapshot.ID.Hex(), saved.MongoAchievementID)
+		mockOutbox.AssertNotCalled(t, "MarkOutboxEventProcessed", mock.Anything, mock.Anything, mock.Anything)
+		mockOutbox.AssertExpectations(t)
+	})
+}
+
+func TestAchievementOutbox_UpdateAchievement(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	studentID := uuid.New()
+	achievementID := uuid.New()
+	mongoID := "507f1f77bcf86cd799439011"
+
+	setup := func() (*mocks.MockAchievementRepository, *mocks.MockAchievementOutboxRepository, service.AchievementService) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+			ID: achievementID, StudentID: studentID, MongoAchievementID: mongoID, Status: "draft",
+		}, nil)
+		mockTypeRepo.On("GetAchievementTypeByCode", ctx, "other").Return(&model.AchievementType{Code: "other", IsActive: true}, nil)
+		mockRepo.On("GetLatestAchievementSnapshot", ctx, achievementID).Return(&mongodb.Achievement{StudentID: studentID, Title: "Sertifikat"}, nil)
+		return mockRepo, mockOutbox, service.NewAchievementService(mockRepo, mockTypeRepo, nil, nil, nil, nil, service.NewAchievementOutboxService(mockOutbox, nil))
+	}
+	req := mongodb.Achievement{AchievementType: "other", Title: "Sertifikat (lengkap)"}
+
+	t.Run("PostgreSQL failure - MongoDB document untouched", func(t *testing.T) {
+		mockRepo, mockOutbox, achievementService := setup()
+		mockRepo.On("UpdateAchievementContent", ctx, achievementID, mock.AnythingOfType("mongodb.Achievement"), userID, false).
+			Return(errors.New("deadlock detected"))
+
+		result, err := achievementService.UpdateAchievement(ctx, userID, achievementID, req)
+
+		assert.EqualError(t, err, "failed to update achievement")
+		assert.Nil(t, result)
+		mockOutbox.AssertNotCalled(t, "GetPendingOutboxEventsByAchievement", mock.Anything, mock.Anything)
+	})
+
+	t.Run("MongoDB failure - update kept and event left pending", func(t *testing.T) {
+		mockRepo, mockOutbox, achievementService := setup()
+		mockRepo.On("UpdateAchievementContent", ctx, achievementID, mock.AnythingOfType("mongodb.Achievement"), userID, false).Return(nil)
+
+		event := model.AchievementOutboxEvent{ID: 7, AchievementID: achievementID, MongoAchievementID: mongoID, Operation: model.OutboxOpUpsertDocument}
+		mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{event}, nil)
+		mockOutbox.On("ApplyOutboxEvent", ctx, event).Return(errors.New("not primary"))
+		mockOutbox.On("MarkOutboxEventFailed", ctx, int64(7), "not primary", mock.AnythingOfType("time.Time")).Return(nil)
+
+		result, err := achievementService.UpdateAchievement(ctx, userID, achievementID, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, achievementID, result.ID)
+		mockOutbox.AssertNotCalled(t, "MarkOutboxEventProcessed", mock.Anything, mock.Anything, mock.Anything)
+		mockOutbox.AssertExpectations(t)
+	})
+}
+
+func TestAchievementOutbox_DeleteDraftAchievement(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	studentID := uuid.New()
+	achievementID := uuid.New()
+
+	setup := func() (*mocks.MockAchievementRepository, *mocks.MockAchievementOutboxRepository, service.AchievementService) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+			ID: achievementID, StudentID: studentID, MongoAchievementID: "mongo_id", Status: "draft",
+		}, nil)
+		return mockRepo, mockOutbox, service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, service.NewAchievementOutboxService(mockOutbox, nil))
+	}
+
+	t.Run("PostgreSQL failure - MongoDB document not soft-deleted", func(t *testing.T) {
+		mockRepo, mockOutbox, achievementService := setup()
+		mockRepo.On("UpdateAchievementReferenceToDeleted", ctx, achievementID, userID).Return(errors.New("connection reset"))
+
+		err := achievementService.DeleteDraftAchievement(ctx, userID, achievementID)
+
+		assert.EqualError(t, err, "failed to update achievement status in PostgreSQL")
+		mockOutbox.AssertNotCalled(t, "GetPendingOutboxEventsByAchievement", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Submitted concurrently - not deleted", func(t *testing.T) {
+		mockRepo, mockOutbox, achievementService := setup()
+		mockRepo.On("UpdateAchievementReferenceToDeleted", ctx, achievementID, userID).Return(repository.ErrAchievementNotDraft)
+
+		err := achievementService.DeleteDraftAchievement(ctx, userID, achievementID)
+
+		assert.EqualError(t, err, "only draft achievements can be deleted")
+		mockOutbox.AssertNotCalled(t, "GetPendingOutboxEventsByAchievement", mock.Anything, mock.Anything)
+	})
+
+	t.Run("MongoDB failure - deletion kept and event left pending", func(t *testing.T) {
+		mockRepo, mockOutbox, achievementService := setup()
+		mockRepo.On("UpdateAchievementReferenceToDeleted", ctx, achievementID, userID).Return(nil)
+
+		event := model.AchievementOutboxEvent{ID: 3, AchievementID: achievementID, Operation: model.OutboxOpSoftDeleteDocument}
+		mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{event}, nil)
+		mockOutbox.On("ApplyOutboxEvent", ctx, event).Return(errors.New("socket timeout"))
+		mockOutbox.On("MarkOutboxEventFailed", ctx, int64(3), "socket timeout", mock.AnythingOfType("time.Time")).Return(nil)
+
+		err := achievementService.DeleteDraftAchievement(ctx, userID, achievementID)
+
+		assert.NoError(t, err)
+		mockOutbox.AssertExpectations(t)
+	})
+}
+
+func TestAchievementOutbox_RestoreAchievement(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+	achievementID := uuid.New()
+
+	mockRepo := new(mocks.MockAchievementRepository)
+	mockOutbox := new(mocks.MockAchievementOutboxRepository)
+	trashService := service.NewTrashService(mockRepo, service.NewAchievementOutboxService(mockOutbox, nil), nil)
+
+	mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+		ID: achievementID, MongoAchievementID: "mongo_id", Status: "deleted",
+	}, nil)
+	mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(&mongodb.Achievement{}, nil)
+	mockRepo.On("UpdateAchievementReferenceToRestored", ctx, achievementID, adminID).Return(errors.New("connection reset"))
+
+	_, err := trashService.RestoreAchievement(ctx, adminID, achievementID)
+
+	assert.EqualError(t, err, "failed to restore achievement")
+	mockOutbox.AssertNotCalled(t, "GetPendingOutboxEventsByAchievement", mock.Anything, mock.Anything)
+}
+
+func TestAchievementOutboxService_DispatchAchievement(t *testing.T) {
+	ctx := context.Background()
+	achievementID := uuid.New()
+	upsert := model.AchievementOutboxEvent{ID: 1, AchievementID: achievementID, Operation: model.OutboxOpUpsertDocument}
+	softDelete := model.AchievementOutboxEvent{ID: 2, AchievementID: achievementID, Operation: model.OutboxOpSoftDeleteDocument}
+
+	t.Run("Applies pending events in order", func(t *testing.T) {
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		outboxService := service.NewAchievementOutboxService(mockOutbox, nil)
+
+		var applied []int64
+		mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{upsert, softDelete}, nil)
+		mockOutbox.On("ApplyOutboxEvent", ctx, mock.AnythingOfType("model.AchievementOutboxEvent")).
+			Run(func(args mock.Arguments) { applied = append(applied, args.Get(1).(model.AchievementOutboxEvent).ID) }).Return(nil)
+		mockOutbox.On("MarkOutboxEventProcessed", ctx, int64(1), mock.AnythingOfType("time.Time")).Return(nil)
+		mockOutbox.On("MarkOutboxEventProcessed", ctx, int64(2), mock.AnythingOfType("time.Time")).Return(nil)
+
+		err := outboxService.DispatchAchievement(ctx, achievementID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, applied)
+		mockOutbox.AssertExpectations(t)
+	})
+
+	t.Run("Stops at first failure so later events are not applied out of order", func(t *testing.T) {
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		outboxService := service.NewAchievementOutboxService(mockOutbox, nil)
+
+		mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{upsert, softDelete}, nil)
+		mockOutbox.On("ApplyOutboxEvent", ctx, upsert).Return(errors.New("write conflict"))
+		mockOutbox.On("MarkOutboxEventFailed", ctx, int64(1), "write conflict", mock.AnythingOfType("time.Time")).Return(nil)
+
+		err := outboxService.DispatchAchievement(ctx, achievementID)
+
+		assert.Error(t, err)
+		mockOutbox.AssertNotCalled(t, "ApplyOutboxEvent", ctx, softDelete)
+	})
+
+	t.Run("Lock held by another worker - nothing applied here", func(t *testing.T) {
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		outboxService := service.NewAchievementOutboxService(mockOutbox, nil)
+
+		mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(false, nil)
+
+		err := outboxService.DispatchAchievement(ctx, achievementID)
+
+		assert.EqualError(t, err, "achievement outbox events are being applied by another worker")
+		mockOutbox.AssertNotCalled(t, "GetPendingOutboxEventsByAchievement", mock.Anything, mock.Anything)
+		mockOutbox.AssertNotCalled(t, "ApplyOutboxEvent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Mark processed failure - event stays pending and is re-applied", func(t *testing.T) {
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		outboxService := service.NewAchievementOutboxService(mockOutbox, nil)
+
+		mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{upsert}, nil)
+		mockOutbox.On("ApplyOutboxEvent", ctx, upsert).Return(nil)
+		mockOutbox.On("MarkOutboxEventProcessed", ctx, int64(1), mock.AnythingOfType("time.Time")).Return(errors.New("connection reset")).Once()
+		mockOutbox.On("MarkOutboxEventProcessed", ctx, int64(1), mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		assert.Error(t, outboxService.DispatchAchievement(ctx, achievementID))
+		assert.NoError(t, outboxService.DispatchAchievement(ctx, achievementID))
+
+		mockOutbox.AssertNumberOfCalls(t, "ApplyOutboxEvent", 2)
+		mockOutbox.AssertExpectations(t)
+	})
+}
+
+func TestAchievementOutboxService_RelayPendingEvents(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Failed event blocks later events of the same achievement only", func(t *testing.T) {
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		outboxService := service.NewAchievementOutboxService(mockOutbox, nil)
+
+		a, b := uuid.New(), uuid.New()
+		past := time.Now().Add(-time.Minute)
+		a1 := model.AchievementOutboxEvent{ID: 1, AchievementID: a, Operation: model.OutboxOpUpsertDocument, Attempts: 3, NextAttemptAt: past}
+		b1 := model.AchievementOutboxEvent{ID: 2, AchievementID: b, Operation: model.OutboxOpUpsertDocument, NextAttemptAt: past}
+		a2 := model.AchievementOutboxEvent{ID: 3, AchievementID: a, Operation: model.OutboxOpSoftDeleteDocument, NextAttemptAt: past}
+
+		mockOutbox.On("GetPendingOutboxEvents", ctx, 500).Return([]model.AchievementOutboxEvent{a1, b1, a2}, nil)
+		mockOutbox.On("WithAchievementOutboxLock", ctx, a).Return(true, nil)
+		mockOutbox.On("WithAchievementOutboxLock", ctx, b).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, a).Return([]model.AchievementOutboxEvent{a1, a2}, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, b).Return([]model.AchievementOutboxEvent{b1}, nil)
+		mockOutbox.On("ApplyOutboxEvent", ctx, a1).Return(errors.New("timeout"))
+		// Backoff berlipat per percobaan: percobaan ke-4 dijadwalkan 4 menit kemudian
+		mockOutbox.On("MarkOutboxEventFailed", ctx, int64(1), "timeout", mock.MatchedBy(func(next time.Time) bool {
+			wait := time.Until(next)
+			return wait > 3*time.Minute && wait <= 4*time.Minute
+		})).Return(nil)
+		mockOutbox.On("ApplyOutboxEvent", ctx, b1).Return(nil)
+		mockOutbox.On("MarkOutboxEventProcessed", ctx, int64(2), mock.AnythingOfType("time.Time")).Return(nil)
+
+		result, err := outboxService.RelayPendingEvents(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.OutboxRelayResult{Processed: 1, Failed: 1, Deferred: 1}, result)
+		mockOutbox.AssertNotCalled(t, "ApplyOutboxEvent", ctx, a2)
+		mockOutbox.AssertExpectations(t)
+	})
+
+	t.Run("Achievement locked by another instance is deferred", func(t *testing.T) {
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		outboxService := service.NewAchievementOutboxService(mockOutbox, nil)
+
+		a := uuid.New()
+		past := time.Now().Add(-time.Minute)
+		a1 := model.AchievementOutboxEvent{ID: 1, AchievementID: a, Operation: model.OutboxOpUpsertDocument, NextAttemptAt: past}
+		a2 := model.AchievementOutboxEvent{ID: 2, AchievementID: a, Operation: model.OutboxOpSoftDeleteDocument, NextAttemptAt: past}
+		mockOutbox.On("GetPendingOutboxEvents", ctx, 500).Return([]model.AchievementOutboxEvent{a1, a2}, nil)
+		mockOutbox.On("WithAchievementOutboxLock", ctx, a).Return(false, nil)
+
+		result, err := outboxService.RelayPendingEvents(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.OutboxRelayResult{Deferred: 2}, result)
+		mockOutbox.AssertNotCalled(t, "GetPendingOutboxEventsByAchievement", mock.Anything, mock.Anything)
+		mockOutbox.AssertNotCalled(t, "ApplyOutboxEvent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Events applied by another instance since the batch was read are not re-applied", func(t *testing.T) {
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		outboxService := service.NewAchievementOutboxService(mockOutbox, nil)
+
+		a := uuid.New()
+		past := time.Now().Add(-time.Minute)
+		a1 := model.AchievementOutboxEvent{ID: 1, AchievementID: a, Operation: model.OutboxOpUpsertDocument, NextAttemptAt: past}
+		a2 := model.AchievementOutboxEvent{ID: 2, AchievementID: a, Operation: model.OutboxOpSoftDeleteDocument, NextAttemptAt: past}
+		mockOutbox.On("GetPendingOutboxEvents", ctx, 500).Return([]model.AchievementOutboxEvent{a1, a2}, nil)
+		mockOutbox.On("WithAchievementOutboxLock", ctx, a).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, a).Return([]model.AchievementOutboxEvent{a2}, nil)
+		mockOutbox.On("ApplyOutboxEvent", ctx, a2).Return(nil)
+		mockOutbox.On("MarkOutboxEventProcessed", ctx, int64(2), mock.AnythingOfType("time.Time")).Return(nil)
+
+		result, err := outboxService.RelayPendingEvents(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.OutboxRelayResult{Processed: 1}, result)
+		mockOutbox.AssertNotCalled(t, "ApplyOutboxEvent", ctx, a1)
+		mockOutbox.AssertExpectations(t)
+	})
+
+	t.Run("Events waiting for retry are deferred", func(t *testing.T) {
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		outboxService := service.NewAchievementOutboxService(mockOutbox, nil)
+
+		event := model.AchievementOutboxEvent{ID: 1, AchievementID: uuid.New(), NextAttemptAt: time.Now().Add(time.Hour)}
+		mockOutbox.On("GetPendingOutboxEvents", ctx, 500).Return([]model.AchievementOutboxEvent{event}, nil)
+
+		result, err := outboxService.RelayPendingEvents(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Deferred)
+		mockOutbox.AssertNotCalled(t, "ApplyOutboxEvent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - outbox unavailable", func(t *testing.T) {
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		outboxService := service.NewAchievementOutboxService(mockOutbox, nil)
+
+		mockOutbox.On("GetPendingOutboxEvents", ctx, 500).Return(nil, errors.New("connection refused"))
+
+		_, err := outboxService.RelayPendingEvents(ctx)
+
+		assert.EqualError(t, err, "failed to get pending outbox events")
+	})
+}
+
+func TestAchievementOutboxService_RemoveAttachmentReleasesFile(t *testing.T) {
+	ctx := context.Background()
+	achievementID := uuid.New()
+
+	setup := func(t *testing.T) (*mocks.MockAchievementOutboxRepository, service.AchievementOutboxService, storage.Storage, model.AchievementOutboxEvent) {
+		store, err := storage.NewLocalStorage(t.TempDir())
+		assert.NoError(t, err)
+		stored, err := store.Put(ctx, bytes.NewReader([]byte("sertifikat")), "application/pdf")
+		assert.NoError(t, err)
+
+		payload, err := bson.Marshal(bson.M{"id": "att-1", "storageKey": stored.Key})
+		assert.NoError(t, err)
+		event := model.AchievementOutboxEvent{ID: 1, AchievementID: achievementID, Operation: model.OutboxOpRemoveAttachment, Payload: payload}
+
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{event}, nil)
+		return mockOutbox, service.NewAchievementOutboxService(mockOutbox, store), store, event
+	}
+
+	t.Run("Deferred removal deletes the file once applied", func(t *testing.T) {
+		mockOutbox, outboxService, store, event := setup(t)
+		key := repository.RemovedAttachmentStorageKey(event)
+
+		// Dispatch saat request gagal: dokumen masih memuat attachment sehingga file tidak disentuh
+		mockOutbox.On("ApplyOutboxEvent", ctx, event).Return(errors.New("server selection timeout")).Once()
+		mockOutbox.On("MarkOutboxEventFailed", ctx, int64(1), "server selection timeout", mock.AnythingOfType("time.Time")).Return(nil)
+		assert.Error(t, outboxService.DispatchAchievement(ctx, achievementID))
+		mockOutbox.AssertNotCalled(t, "CountAttachmentsByStorageKey", mock.Anything, mock.Anything)
+
+		// Penerapan berikutnya menghapus file yang sudah tidak dipakai dokumen mana pun
+		mockOutbox.On("ApplyOutboxEvent", ctx, event).Return(nil).Once()
+		mockOutbox.On("CountAttachmentsByStorageKey", ctx, key).Return(int64(0), nil)
+		mockOutbox.On("MarkOutboxEventProcessed", ctx, int64(1), mock.AnythingOfType("time.Time")).Return(nil)
+		assert.NoError(t, outboxService.DispatchAchievement(ctx, achievementID))
+
+		_, _, err := store.Open(ctx, key)
+		assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+	})
+
+	t.Run("Shared file is kept", func(t *testing.T) {
+		mockOutbox, outboxService, store, event := setup(t)
+		key := repository.RemovedAttachmentStorageKey(event)
+
+		mockOutbox.On("ApplyOutboxEvent", ctx, event).Return(nil)
+		mockOutbox.On("CountAttachmentsByStorageKey", ctx, key).Return(int64(1), nil)
+		mockOutbox.On("MarkOutboxEventProcessed", ctx, int64(1), mock.AnythingOfType("time.Time")).Return(nil)
+
+		assert.NoError(t, outboxService.DispatchAchievement(ctx, achievementID))
+
+		reader, _, err := store.Open(ctx, key)
+		assert.NoError(t, err)
+		reader.Close()
+	})
+
+	t.Run("Reference count failure - event retried instead of leaking the file", func(t *testing.T) {
+		mockOutbox, outboxService, store, event := setup(t)
+		key := repository.RemovedAttachmentStorageKey(event)
+
+		mockOutbox.On("ApplyOutboxEvent", ctx, event).Return(nil)
+		mockOutbox.On("CountAttachmentsByStorageKey", ctx, key).Return(int64(0), errors.New("connection reset"))
+		mockOutbox.On("MarkOutboxEventFailed", ctx, int64(1), "connection reset", mock.AnythingOfType("time.Time")).Return(nil)
+
+		assert.Error(t, outboxService.DispatchAchievement(ctx, achievementID))
+		mockOutbox.AssertNotCalled(t, "MarkOutboxEventProcessed", mock.Anything, mock.Anything, mock.Anything)
+
+		reader, _, err := store.Open(ctx, key)
+		assert.NoError(t, err)
+		reader.Close()
+	})
+}
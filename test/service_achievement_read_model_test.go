@@ -0,0 +1,91 @@
+package test
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAchievementReadModelService_Backfill(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Fills every batch until no reference is left", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementReadModelRepository)
+		readModelService := service.NewAchievementReadModelService(mockRepo)
+
+		first := []model.AchievementReference{{ID: uuid.New(), MongoAchievementID: "a"}, {ID: uuid.New(), MongoAchievementID: "b"}}
+		second := []model.AchievementReference{{ID: uuid.New(), MongoAchievementID: "c"}}
+
+		mockRepo.On("GetReferencesWithoutReadModel", ctx, 500).Return(first, nil).Once()
+		mockRepo.On("GetReferencesWithoutReadModel", ctx, 500).Return(second, nil).Once()
+		mockRepo.On("GetReferencesWithoutReadModel", ctx, 500).Return([]model.AchievementReference{}, nil).Once()
+		mockRepo.On("SaveReadModelFromDocuments", ctx, first).Return(nil)
+		mockRepo.On("SaveReadModelFromDocuments", ctx, second).Return(nil)
+
+		filled, err := readModelService.BackfillReadModel(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, filled)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Stops on save failure", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementReadModelRepository)
+		readModelService := service.NewAchievementReadModelService(mockRepo)
+
+		refs := []model.AchievementReference{{ID: uuid.New(), MongoAchievementID: "a"}}
+		mockRepo.On("GetReferencesWithoutReadModel", ctx, 500).Return(refs, nil).Once()
+		mockRepo.On("SaveReadModelFromDocuments", ctx, refs).Return(errors.New("connection reset"))
+
+		filled, err := readModelService.BackfillReadModel(ctx)
+
+		assert.EqualError(t, err, "failed to save achievement read model")
+		assert.Equal(t, 0, filled)
+		mockRepo.AssertNumberOfCalls(t, "GetReferencesWithoutReadModel", 1)
+	})
+}
+
+func TestAchievementService_GetAllAchievementsForAdminEndpoint_ReadModelFilters(t *testing.T) {
+	mockRepo := new(mocks.MockAchievementRepository)
+	achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+	app := fiber.New()
+	app.Get("/admin/achievements", achievementService.GetAllAchievementsForAdminEndpoint)
+
+	minPoints, maxPoints := 10, 50
+	expected := model.AdminAchievementFilters{
+		AchievementListFilters: model.AchievementListFilters{
+			Status:           "verified",
+			AchievementType:  "competition",
+			CompetitionLevel: "national",
+			MedalType:        "gold",
+			Tag:              "ai",
+			MinPoints:        &minPoints,
+			MaxPoints:        &maxPoints,
+			SortBy:           "points",
+			SortOrder:        "asc",
+		},
+	}
+	title := "Juara 1 Gemastik"
+	mockRepo.On("GetAllAchievementsForAdmin", mock.Anything, expected, 1, 10).
+		Return([]model.AchievementWithStudent{{ID: uuid.New(), MongoAchievementID: "m1", Title: &title, Points: 40}}, 1, nil)
+	mockRepo.On("GetAchievementDetailFromMongo", mock.Anything, "m1").Return(nil, errors.New("not found"))
+	mockRepo.On("GetAdminAchievementFacets", mock.Anything, expected).Return(&model.AchievementFacets{}, nil)
+
+	url := "/admin/achievements?status=verified&achievement_type=competition&competition_level=national&medal_type=gold" +
+		"&tag=ai&min_points=10&max_points=50&sort_by=points&sort_order=asc"
+	resp, err := app.Test(httptest.NewRequest("GET", url, nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	mockRepo.AssertExpectations(t)
+}
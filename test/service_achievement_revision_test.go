@@ -0,0 +1,215 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAchievementService_UpdateRejectedAchievement(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := new(mocks.MockAchievementRepository)
+	mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+	mockOutbox := new(mocks.MockAchievementOutboxRepository)
+	achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, nil, nil, nil, nil, service.NewAchievementOutboxService(mockOutbox, nil))
+
+	userID := uuid.New()
+	studentID := uuid.New()
+	achievementID := uuid.New()
+	mongoID := "507f1f77bcf86cd799439011"
+
+	ref := &model.AchievementReference{ID: achievementID, StudentID: studentID, MongoAchievementID: mongoID, Status: "rejected"}
+	revisedRef := &model.AchievementReference{ID: achievementID, StudentID: studentID, MongoAchievementID: mongoID, Status: "revised"}
+
+	mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+	mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil).Once()
+	mockTypeRepo.On("GetAchievementTypeByCode", ctx, "other").Return(&model.AchievementType{Code: "other", IsActive: true}, nil)
+	// Versi terakhir menjadi dasar: dokumen MongoDB belum memuat attachment yang event outbox-nya tertunda
+	mockRepo.On("GetLatestAchievementSnapshot", ctx, achievementID).Return(&mongodb.Achievement{
+		StudentID:   studentID,
+		Title:       "Sertifikat",
+		Attachments: []mongodb.Attachment{{ID: "att-1", FileName: "sertifikat.pdf"}},
+	}, nil)
+	// Field yang dikelola server (studentId, attachments) tidak boleh hilang saat update
+	mockRepo.On("UpdateAchievementContent", ctx, achievementID, mock.MatchedBy(func(a mongodb.Achievement) bool {
+		return a.StudentID == studentID && len(a.Attachments) == 1 && a.Attachments[0].ID == "att-1"
+	}), userID, true).Return(nil)
+	mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+	mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{}, nil)
+	mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(revisedRef, nil).Once()
+
+	result, err := achievementService.UpdateAchievement(ctx, userID, achievementID, mongodb.Achievement{
+		AchievementType: "other",
+		Title:           "Sertifikat (lengkap)",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "revised", result.Status)
+	mockRepo.AssertExpectations(t)
+	mockOutbox.AssertExpectations(t)
+}
+
+func TestAchievementService_UpdateAchievement_WithoutVersions(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.MockAchievementRepository)
+	mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+	mockOutbox := new(mocks.MockAchievementOutboxRepository)
+	achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, nil, nil, nil, nil, service.NewAchievementOutboxService(mockOutbox, nil))
+
+	userID := uuid.New()
+	studentID := uuid.New()
+	achievementID := uuid.New()
+	mongoID := "507f1f77bcf86cd799439011"
+	ref := &model.AchievementReference{ID: achievementID, StudentID: studentID, MongoAchievementID: mongoID, Status: "draft"}
+
+	// Prestasi lama yang belum terjangkau backfill versi memakai dokumennya sebagai dasar
+	mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+	mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil)
+	mockTypeRepo.On("GetAchievementTypeByCode", ctx, "other").Return(&model.AchievementType{Code: "other", IsActive: true}, nil)
+	mockRepo.On("GetLatestAchievementSnapshot", ctx, achievementID).Return(nil, nil)
+	mockRepo.On("GetAchievementDetailFromMongo", ctx, mongoID).Return(&mongodb.Achievement{
+		StudentID:   studentID,
+		Attachments: []mongodb.Attachment{{ID: "att-1", FileName: "sertifikat.pdf"}},
+	}, nil)
+	mockRepo.On("UpdateAchievementContent", ctx, achievementID, mock.MatchedBy(func(a mongodb.Achievement) bool {
+		return a.StudentID == studentID && len(a.Attachments) == 1
+	}), userID, false).Return(nil)
+	mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+	mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{}, nil)
+
+	_, err := achievementService.UpdateAchievement(ctx, userID, achievementID, mongodb.Achievement{AchievementType: "other", Title: "Sertifikat"})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAchievementService_Resubmission(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Rejected achievement must be revised first", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		userID := uuid.New()
+		studentID := uuid.New()
+		achievementID := uuid.New()
+
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+			ID: achievementID, StudentID: studentID, Status: "rejected",
+		}, nil)
+
+		result, err := achievementService.SubmitForVerification(ctx, userID, achievementID)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "rejected achievement must be revised before resubmitting")
+	})
+
+	t.Run("Revised achievement is resubmitted with a new revision", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		mockApprovalRepo := new(mocks.MockApprovalChainRepository)
+		achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, nil, mockApprovalRepo, nil, nil, nil)
+
+		userID := uuid.New()
+		studentID := uuid.New()
+		achievementID := uuid.New()
+
+		ref := &model.AchievementReference{ID: achievementID, StudentID: studentID, MongoAchievementID: "mongo_id", Status: "revised"}
+		resubmitted := &model.AchievementReference{ID: achievementID, StudentID: studentID, MongoAchievementID: "mongo_id", Status: "submitted", Revision: 1}
+
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil).Once()
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(&mongodb.Achievement{AchievementType: "other", Title: "Sertifikat"}, nil)
+		mockTypeRepo.On("GetAchievementTypeByCode", ctx, "other").Return(&model.AchievementType{Code: "other", IsActive: true}, nil)
+		mockApprovalRepo.On("FindApprovalChain", ctx, "other", "").Return(nil, nil)
+		mockRepo.On("UpdateAchievementStatusToResubmitted", ctx, achievementID, userID, mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(resubmitted, nil).Once()
+
+		result, err := achievementService.SubmitForVerification(ctx, userID, achievementID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "submitted", result.Status)
+		assert.Equal(t, 1, result.Revision)
+		mockRepo.AssertNotCalled(t, "UpdateAchievementStatusToSubmitted", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestAchievementService_GetAchievementChanges(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Reviewer sees changes since rejection", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		userID := uuid.New()
+		lecturerID := uuid.New()
+		studentID := uuid.New()
+		achievementID := uuid.New()
+		rejectedAt := time.Now().Add(-time.Hour)
+
+		oldRank, newRank := 3, 2
+		location := "Jakarta"
+		snapshot := mongodb.Achievement{
+			AchievementType: "competition",
+			Title:           "Gemastik",
+			Details:         mongodb.AchievementDetails{Rank: &oldRank},
+			UpdatedAt:       rejectedAt,
+		}
+		current := snapshot
+		current.Description = "Bukti terlampir"
+		current.Details = mongodb.AchievementDetails{Rank: &newRank, Location: &location}
+		current.UpdatedAt = time.Now()
+
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+			ID: achievementID, StudentID: studentID, MongoAchievementID: "mongo_id", Status: "submitted", Revision: 1,
+		}, nil)
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(nil, assert.AnError)
+		mockRepo.On("GetLecturerByUserID", ctx, userID).Return(&model.Lecturers{ID: lecturerID, UserID: userID}, nil)
+		mockRepo.On("GetStudentByID", ctx, studentID).Return(&model.Student{ID: studentID, AdvisorID: lecturerID}, nil)
+		mockRepo.On("GetLatestAchievementRejection", ctx, achievementID).Return(&model.AchievementRejection{
+			AchievementID: achievementID,
+			RejectionNote: "Peringkat tidak sesuai sertifikat",
+			Snapshot:      snapshot,
+			RejectedAt:    rejectedAt,
+		}, nil)
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(&current, nil)
+
+		result, err := achievementService.GetAchievementChanges(ctx, userID, false, achievementID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Revision)
+		assert.Equal(t, "Peringkat tidak sesuai sertifikat", result.RejectionNote)
+		assert.Equal(t, []model.FieldChange{
+			{Field: "description", Before: nil, After: "Bukti terlampir"},
+			{Field: "details.location", Before: nil, After: "Jakarta"},
+			{Field: "details.rank", Before: float64(3), After: float64(2)},
+		}, result.Changes)
+	})
+
+	t.Run("Never rejected", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		achievementID := uuid.New()
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+			ID: achievementID, Status: "submitted",
+		}, nil)
+		mockRepo.On("GetLatestAchievementRejection", ctx, achievementID).Return(nil, nil)
+
+		result, err := achievementService.GetAchievementChanges(ctx, uuid.New(), true, achievementID)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "achievement has never been rejected")
+	})
+}
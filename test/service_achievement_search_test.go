@@ -0,0 +1,159 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAchievementService_SearchAchievements(t *testing.T) {
+	ctx := context.Background()
+	competitionName := "Gemastik 2025"
+
+	t.Run("Admin searches everything and gets highlighted results", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		active := mongodb.Achievement{
+			ID:          primitive.NewObjectID(),
+			Title:       "Juara 1 Gemastik",
+			Description: "Kategori data mining",
+			Details:     mongodb.AchievementDetails{CompetitionName: &competitionName},
+			Tags:        []string{"gemastik", "data"},
+		}
+		ref := model.AchievementWithStudent{ID: uuid.New(), StudentID: uuid.New(), MongoAchievementID: active.ID.Hex(), Status: "verified", StudentName: "Budi"}
+
+		// Total dari repository sudah dibatasi ke reference yang tidak di trash
+		mockRepo.On("SearchAchievementDocuments", ctx, "gemastik", []uuid.UUID(nil), 0, 10).Return([]model.AchievementSearchMatch{
+			{Achievement: active, Score: 12.5},
+		}, int64(1), nil)
+		mockRepo.On("GetAchievementsByMongoIDs", ctx, []string{active.ID.Hex()}).Return(map[string]model.AchievementWithStudent{
+			active.ID.Hex(): ref,
+		}, nil)
+
+		result, err := achievementService.SearchAchievements(ctx, uuid.New(), true, "  gemastik ", 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "gemastik", result.Query)
+		assert.Equal(t, 1, result.Pagination.Total)
+		assert.Equal(t, 1, result.Pagination.TotalPages)
+		assert.Len(t, result.Results, 1)
+		hit := result.Results[0]
+		assert.Equal(t, ref.ID, hit.AchievementID)
+		assert.Equal(t, "Budi", hit.StudentName)
+		assert.Equal(t, 12.5, hit.Score)
+		assert.Equal(t, []model.SearchHighlight{
+			{Field: "title", Snippet: "Juara 1 <mark>Gemastik</mark>"},
+			{Field: "details.competitionName", Snippet: "<mark>Gemastik</mark> 2025"},
+			{Field: "tags", Snippet: "<mark>gemastik</mark>"},
+		}, hit.Highlights)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Student only searches own achievements", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		userID := uuid.New()
+		studentID := uuid.New()
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+		mockRepo.On("SearchAchievementDocuments", ctx, "robotik", []uuid.UUID{studentID}, 10, 10).Return([]model.AchievementSearchMatch{}, int64(0), nil)
+
+		result, err := achievementService.SearchAchievements(ctx, userID, false, "robotik", 2, 10)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Results)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Advisor results are rechecked against reference owner", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		userID := uuid.New()
+		lecturerID := uuid.New()
+		advisee := uuid.New()
+		doc := mongodb.Achievement{ID: primitive.NewObjectID(), Title: "Juara robotik", StudentID: advisee}
+
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(nil, errors.New("not a student"))
+		mockRepo.On("GetLecturerByUserID", ctx, userID).Return(&model.Lecturers{ID: lecturerID, UserID: userID}, nil)
+		mockRepo.On("GetStudentIDsByAdvisorID", ctx, lecturerID).Return([]uuid.UUID{advisee}, nil)
+		mockRepo.On("SearchAchievementDocuments", ctx, "robotik", []uuid.UUID{advisee}, 0, 10).
+			Return([]model.AchievementSearchMatch{{Achievement: doc, Score: 5}}, int64(1), nil)
+		// studentId dokumen drift: reference sebenarnya milik mahasiswa lain
+		mockRepo.On("GetAchievementsByMongoIDs", ctx, mock.Anything).Return(map[string]model.AchievementWithStudent{
+			doc.ID.Hex(): {ID: uuid.New(), StudentID: uuid.New(), MongoAchievementID: doc.ID.Hex(), Status: "verified"},
+		}, nil)
+
+		result, err := achievementService.SearchAchievements(ctx, userID, false, "robotik", 1, 10)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Results)
+	})
+
+	t.Run("Reference trashed between queries is skipped", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		trashed := mongodb.Achievement{ID: primitive.NewObjectID(), Title: "Finalis Gemastik"}
+		orphan := mongodb.Achievement{ID: primitive.NewObjectID(), Title: "Peserta Gemastik"}
+		mockRepo.On("SearchAchievementDocuments", ctx, "gemastik", []uuid.UUID(nil), 0, 10).Return([]model.AchievementSearchMatch{
+			{Achievement: trashed, Score: 10}, {Achievement: orphan, Score: 8},
+		}, int64(2), nil)
+		mockRepo.On("GetAchievementsByMongoIDs", ctx, []string{trashed.ID.Hex(), orphan.ID.Hex()}).Return(map[string]model.AchievementWithStudent{
+			trashed.ID.Hex(): {ID: uuid.New(), MongoAchievementID: trashed.ID.Hex(), Status: "deleted"},
+		}, nil)
+
+		result, err := achievementService.SearchAchievements(ctx, uuid.New(), true, "gemastik", 1, 10)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Results)
+	})
+
+	t.Run("Advisor without advisees gets no results", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		userID := uuid.New()
+		lecturerID := uuid.New()
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(nil, errors.New("not a student"))
+		mockRepo.On("GetLecturerByUserID", ctx, userID).Return(&model.Lecturers{ID: lecturerID, UserID: userID}, nil)
+		mockRepo.On("GetStudentIDsByAdvisorID", ctx, lecturerID).Return([]uuid.UUID{}, nil)
+
+		result, err := achievementService.SearchAchievements(ctx, userID, false, "robotik", 1, 10)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Results)
+		mockRepo.AssertNotCalled(t, "SearchAchievementDocuments", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Other users are rejected", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		userID := uuid.New()
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(nil, errors.New("not a student"))
+		mockRepo.On("GetLecturerByUserID", ctx, userID).Return(nil, errors.New("not a lecturer"))
+
+		_, err := achievementService.SearchAchievements(ctx, userID, false, "robotik", 1, 10)
+
+		assert.EqualError(t, err, "unauthorized: only students, advisors and admins can search achievements")
+	})
+
+	t.Run("Empty query", func(t *testing.T) {
+		achievementService := service.NewAchievementService(new(mocks.MockAchievementRepository), nil, nil, nil, nil, nil, nil)
+
+		_, err := achievementService.SearchAchievements(ctx, uuid.New(), true, "   ", 1, 10)
+
+		assert.EqualError(t, err, "search query is required")
+	})
+}
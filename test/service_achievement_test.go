@@ -8,8 +8,11 @@ import (
 	mongodb "UASBE/app/model/MongoDB"
 	model "UASBE/app/model/Postgresql"
 	"UASBE/app/service"
+	"UASBE/helper"
 	"UASBE/test/mocks"
+	"UASBE/utils"
 
+	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -20,7 +23,9 @@ func TestAchievementService_SubmitPrestasi(t *testing.T) {
 
 	t.Run("Successful submission", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, nil, nil, nil, nil, service.NewAchievementOutboxService(mockOutbox, nil))
 
 		userID := uuid.New()
 		studentID := uuid.New()
@@ -31,15 +36,23 @@ func TestAchievementService_SubmitPrestasi(t *testing.T) {
 			Program_Study: "Computer Science",
 		}
 
+		competitionName := "Gemastik"
+		competitionLevel := "national"
 		achievement := mongodb.Achievement{
 			AchievementType: "competition",
 			Title:           "Test Achievement",
 			Description:     "Test Description",
+			Details: mongodb.AchievementDetails{
+				CompetitionName:  &competitionName,
+				CompetitionLevel: &competitionLevel,
+			},
 		}
 
 		mockRepo.On("GetStudentByUserID", ctx, userID).Return(student, nil)
-		mockRepo.On("SaveAchievementMongo", ctx, mock.AnythingOfType("mongodb.Achievement")).Return("mongo_id_123", nil)
-		mockRepo.On("SaveAchievementReference", ctx, mock.AnythingOfType("model.AchievementReference")).Return(nil)
+		mockTypeRepo.On("GetAchievementTypeByCode", ctx, "competition").Return(&model.AchievementType{Code: "competition", IsActive: true}, nil)
+		mockRepo.On("SaveAchievementReference", ctx, mock.AnythingOfType("model.AchievementReference"), mock.AnythingOfType("mongodb.Achievement"), userID).Return(nil)
+		mockOutbox.On("WithAchievementOutboxLock", ctx, mock.AnythingOfType("uuid.UUID")).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, mock.AnythingOfType("uuid.UUID")).Return([]model.AchievementOutboxEvent{}, nil)
 
 		result, err := achievementService.SubmitPrestasi(ctx, userID, achievement)
 
@@ -49,11 +62,14 @@ func TestAchievementService_SubmitPrestasi(t *testing.T) {
 		assert.Equal(t, studentID, result.StudentID)
 
 		mockRepo.AssertExpectations(t)
+		mockTypeRepo.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
 	})
 
 	t.Run("Student not found", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, nil, nil, nil, nil, nil)
 
 		userID := uuid.New()
 		achievement := mongodb.Achievement{
@@ -70,6 +86,89 @@ func TestAchievementService_SubmitPrestasi(t *testing.T) {
 		assert.Equal(t, "student data not found for this user", err.Error())
 
 		mockRepo.AssertExpectations(t)
+		mockTypeRepo.AssertExpectations(t)
+	})
+
+	t.Run("Competition without competitionLevel is rejected", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, nil, nil, nil, nil, nil)
+
+		userID := uuid.New()
+		competitionName := "Gemastik"
+		achievement := mongodb.Achievement{
+			AchievementType: "competition",
+			Title:           "Test Achievement",
+			Details: mongodb.AchievementDetails{
+				CompetitionName: &competitionName,
+			},
+		}
+
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: uuid.New(), UserID: userID}, nil)
+		mockTypeRepo.On("GetAchievementTypeByCode", ctx, "competition").Return(&model.AchievementType{Code: "competition", IsActive: true}, nil)
+
+		result, err := achievementService.SubmitPrestasi(ctx, userID, achievement)
+
+		assert.Nil(t, result)
+		var apiErr *helper.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, 422, apiErr.Status)
+		assert.Equal(t, "VALIDATION_FAILED", apiErr.Code)
+		assert.Contains(t, apiErr.Details.(fiber.Map)["fields"], utils.FieldError{Field: "details.competitionLevel", Message: "is required"})
+
+		mockRepo.AssertNotCalled(t, "SaveAchievementReference", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+		mockTypeRepo.AssertExpectations(t)
+	})
+
+	t.Run("Type is normalized and must be active in the catalog", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, nil, nil, nil, nil, nil)
+
+		userID := uuid.New()
+		achievement := mongodb.Achievement{
+			AchievementType: " Other ",
+			Title:           "Test Achievement",
+		}
+
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: uuid.New(), UserID: userID}, nil)
+		mockTypeRepo.On("GetAchievementTypeByCode", ctx, "other").Return(&model.AchievementType{Code: "other", IsActive: false}, nil)
+
+		result, err := achievementService.SubmitPrestasi(ctx, userID, achievement)
+
+		assert.Nil(t, result)
+		var apiErr *helper.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, []utils.FieldError{{Field: "achievementType", Message: "achievement type is inactive"}}, apiErr.Details.(fiber.Map)["fields"])
+
+		mockRepo.AssertExpectations(t)
+		mockTypeRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown type is rejected", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, nil, nil, nil, nil, nil)
+
+		userID := uuid.New()
+		achievement := mongodb.Achievement{
+			AchievementType: "sports",
+			Title:           "Test Achievement",
+		}
+
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: uuid.New(), UserID: userID}, nil)
+		mockTypeRepo.On("GetAchievementTypeByCode", ctx, "sports").Return(nil, nil)
+
+		result, err := achievementService.SubmitPrestasi(ctx, userID, achievement)
+
+		assert.Nil(t, result)
+		var apiErr *helper.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, []utils.FieldError{{Field: "achievementType", Message: "unknown achievement type"}}, apiErr.Details.(fiber.Map)["fields"])
+
+		mockRepo.AssertNotCalled(t, "SaveAchievementReference", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockTypeRepo.AssertExpectations(t)
 	})
 }
 
@@ -78,7 +177,9 @@ func TestAchievementService_SubmitForVerification(t *testing.T) {
 
 	t.Run("Successful submission for verification", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		mockApprovalRepo := new(mocks.MockApprovalChainRepository)
+		achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, nil, mockApprovalRepo, nil, nil, nil)
 
 		userID := uuid.New()
 		studentID := uuid.New()
@@ -103,9 +204,26 @@ func TestAchievementService_SubmitForVerification(t *testing.T) {
 			Status:             "submitted",
 		}
 
+		competitionName := "Gemastik"
+		competitionLevel := "national"
+		detail := &mongodb.Achievement{
+			AchievementType: "competition",
+			Title:           "Test Achievement",
+			Details: mongodb.AchievementDetails{
+				CompetitionName:  &competitionName,
+				CompetitionLevel: &competitionLevel,
+			},
+		}
+
 		mockRepo.On("GetStudentByUserID", ctx, userID).Return(student, nil)
 		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil).Once()
-		mockRepo.On("UpdateAchievementStatusToSubmitted", ctx, achievementID).Return(nil)
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(detail, nil)
+		mockTypeRepo.On("GetAchievementTypeByCode", ctx, "competition").Return(&model.AchievementType{Code: "competition", IsActive: true}, nil)
+		// Tanpa approval chain yang cocok, cukup satu tahap dosen wali
+		mockApprovalRepo.On("FindApprovalChain", ctx, "competition", "national").Return(nil, nil)
+		mockRepo.On("UpdateAchievementStatusToSubmitted", ctx, achievementID, userID, []model.ApprovalStage{
+			{Name: "Dosen Wali", ApproverRole: model.ApproverRoleAdvisor},
+		}, "national").Return(nil)
 		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(updatedRef, nil).Once()
 
 		result, err := achievementService.SubmitForVerification(ctx, userID, achievementID)
@@ -115,11 +233,49 @@ func TestAchievementService_SubmitForVerification(t *testing.T) {
 		assert.Equal(t, "submitted", result.Status)
 
 		mockRepo.AssertExpectations(t)
+		mockTypeRepo.AssertExpectations(t)
+		mockApprovalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid draft cannot be submitted", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, nil, nil, nil, nil, nil)
+
+		userID := uuid.New()
+		studentID := uuid.New()
+		achievementID := uuid.New()
+
+		ref := &model.AchievementReference{
+			ID:                 achievementID,
+			StudentID:          studentID,
+			MongoAchievementID: "mongo_id",
+			Status:             "draft",
+		}
+
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil)
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(&mongodb.Achievement{
+			AchievementType: "competition",
+			Title:           "Draft lama",
+		}, nil)
+		mockTypeRepo.On("GetAchievementTypeByCode", ctx, "competition").Return(&model.AchievementType{Code: "competition", IsActive: true}, nil)
+
+		result, err := achievementService.SubmitForVerification(ctx, userID, achievementID)
+
+		assert.Nil(t, result)
+		var apiErr *helper.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, 422, apiErr.Status)
+
+		mockRepo.AssertNotCalled(t, "UpdateAchievementStatusToSubmitted", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+		mockTypeRepo.AssertExpectations(t)
 	})
 
 	t.Run("Achievement not in draft status", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
 
 		userID := uuid.New()
 		studentID := uuid.New()
@@ -143,14 +299,14 @@ func TestAchievementService_SubmitForVerification(t *testing.T) {
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
-		assert.Equal(t, "achievement must be in 'draft' status to submit", err.Error())
+		assert.Equal(t, "achievement must be in 'draft' or 'revised' status to submit", err.Error())
 
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Unauthorized - not student's achievement", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
 
 		userID := uuid.New()
 		studentID := uuid.New()
@@ -186,7 +342,8 @@ func TestAchievementService_DeleteDraftAchievement(t *testing.T) {
 
 	t.Run("Successful deletion", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, service.NewAchievementOutboxService(mockOutbox, nil))
 
 		userID := uuid.New()
 		studentID := uuid.New()
@@ -206,19 +363,21 @@ func TestAchievementService_DeleteDraftAchievement(t *testing.T) {
 
 		mockRepo.On("GetStudentByUserID", ctx, userID).Return(student, nil)
 		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil)
-		mockRepo.On("SoftDeleteAchievementMongo", ctx, "mongo_id").Return(nil)
-		mockRepo.On("UpdateAchievementReferenceToDeleted", ctx, achievementID).Return(nil)
+		mockRepo.On("UpdateAchievementReferenceToDeleted", ctx, achievementID, userID).Return(nil)
+		mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{}, nil)
 
 		err := achievementService.DeleteDraftAchievement(ctx, userID, achievementID)
 
 		assert.NoError(t, err)
 
 		mockRepo.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
 	})
 
 	t.Run("Cannot delete non-draft achievement", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
 
 		userID := uuid.New()
 		studentID := uuid.New()
@@ -252,12 +411,16 @@ func TestAchievementService_VerifyAchievement(t *testing.T) {
 
 	t.Run("Successful verification", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		mockScoringRepo := new(mocks.MockScoringRepository)
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, mockScoringRepo, nil, nil, nil, service.NewAchievementOutboxService(mockOutbox, nil))
 
 		userID := uuid.New()
 		lecturerID := uuid.New()
 		studentID := uuid.New()
 		achievementID := uuid.New()
+		mongoID := "507f1f77bcf86cd799439011"
 
 		lecturer := &model.Lecturers{
 			ID:     lecturerID,
@@ -265,9 +428,10 @@ func TestAchievementService_VerifyAchievement(t *testing.T) {
 		}
 
 		ref := &model.AchievementReference{
-			ID:        achievementID,
-			StudentID: studentID,
-			Status:    "submitted",
+			ID:                 achievementID,
+			StudentID:          studentID,
+			MongoAchievementID: mongoID,
+			Status:             "submitted",
 		}
 
 		student := &model.Student{
@@ -275,10 +439,44 @@ func TestAchievementService_VerifyAchievement(t *testing.T) {
 			AdvisorID: lecturerID,
 		}
 
+		competitionLevel := "national"
+		rank := 1
+		achievement := &mongodb.Achievement{
+			AchievementType: "competition",
+			Title:           "Juara 1 Gemastik",
+			Details:         mongodb.AchievementDetails{CompetitionLevel: &competitionLevel, Rank: &rank},
+			Points:          999, // nilai dari client diabaikan
+		}
+
+		ruleSet := &model.ScoringRuleSet{
+			Version:  2,
+			IsActive: true,
+			Rules: model.ScoringRules{
+				CompetitionLevelPoints: map[string]int{"national": 30},
+				RankPoints:             map[string]int{"1": 30},
+			},
+		}
+
+		approvalID := uuid.New()
+
 		mockRepo.On("GetLecturerByUserID", ctx, userID).Return(lecturer, nil)
 		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil).Once()
+		mockRepo.On("GetAchievementApprovals", ctx, achievementID, 0).Return([]model.AchievementApproval{
+			{ID: approvalID, AchievementID: achievementID, StageOrder: 1, ApproverRole: model.ApproverRoleAdvisor, Status: "pending"},
+		}, nil)
 		mockRepo.On("GetStudentByID", ctx, studentID).Return(student, nil)
-		mockRepo.On("UpdateAchievementStatusToVerified", ctx, achievementID, lecturerID).Return(nil)
+		mockRepo.On("GetLatestAchievementSnapshot", ctx, achievementID).Return(achievement, nil)
+		mockScoringRepo.On("GetActiveScoringRuleSet", ctx).Return(ruleSet, nil)
+		mockTypeRepo.On("GetAchievementTypeByCode", ctx, "competition").Return(&model.AchievementType{Code: "competition", DefaultPoints: 20}, nil)
+		mockRepo.On("UpdateAchievementStatusToVerified", ctx, achievementID, lecturerID, userID, mock.MatchedBy(func(score model.AchievementScore) bool {
+			return score.AchievementID == achievementID && score.RuleVersion == 2 && score.Points == 80 &&
+				len(score.Breakdown) == 3 && *score.CalculatedBy == userID
+		}), mock.MatchedBy(func(d model.ApprovalDecision) bool {
+			return d.ApprovalID == approvalID && d.Status == "approved" && d.DecidedBy == userID && *d.Note == "Lengkap"
+		})).Return(nil)
+		// Poin ditulis ke MongoDB lewat outbox setelah transaksi verifikasi commit
+		mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{}, nil)
 
 		updatedRef := &model.AchievementReference{
 			ID:        achievementID,
@@ -287,37 +485,68 @@ func TestAchievementService_VerifyAchievement(t *testing.T) {
 		}
 		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(updatedRef, nil).Once()
 
-		result, err := achievementService.VerifyAchievement(ctx, userID, achievementID)
+		result, err := achievementService.VerifyAchievement(ctx, userID, achievementID, "Lengkap")
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.Equal(t, "verified", result.Status)
 
 		mockRepo.AssertExpectations(t)
+		mockTypeRepo.AssertExpectations(t)
+		mockScoringRepo.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
 	})
 
-	t.Run("Achievement not in submitted status", func(t *testing.T) {
+	t.Run("No active scoring rules", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		mockScoringRepo := new(mocks.MockScoringRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, mockScoringRepo, nil, nil, nil, nil)
 
 		userID := uuid.New()
 		lecturerID := uuid.New()
+		studentID := uuid.New()
 		achievementID := uuid.New()
+		mongoID := "507f1f77bcf86cd799439011"
 
-		lecturer := &model.Lecturers{
-			ID:     lecturerID,
-			UserID: userID,
+		ref := &model.AchievementReference{
+			ID:                 achievementID,
+			StudentID:          studentID,
+			MongoAchievementID: mongoID,
+			Status:             "submitted",
 		}
 
+		mockRepo.On("GetLecturerByUserID", ctx, userID).Return(&model.Lecturers{ID: lecturerID, UserID: userID}, nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil)
+		mockRepo.On("GetAchievementApprovals", ctx, achievementID, 0).Return([]model.AchievementApproval{
+			{ID: uuid.New(), StageOrder: 1, ApproverRole: model.ApproverRoleAdvisor, Status: "pending"},
+		}, nil)
+		mockRepo.On("GetStudentByID", ctx, studentID).Return(&model.Student{ID: studentID, AdvisorID: lecturerID}, nil)
+		mockRepo.On("GetLatestAchievementSnapshot", ctx, achievementID).Return(nil, nil)
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, mongoID).Return(&mongodb.Achievement{AchievementType: "other"}, nil)
+		mockScoringRepo.On("GetActiveScoringRuleSet", ctx).Return(nil, nil)
+
+		result, err := achievementService.VerifyAchievement(ctx, userID, achievementID, "")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "no active scoring rules")
+		mockRepo.AssertNotCalled(t, "UpdateAchievementStatusToVerified", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Achievement not in submitted status", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		userID := uuid.New()
+		achievementID := uuid.New()
+
 		ref := &model.AchievementReference{
 			ID:     achievementID,
 			Status: "draft", // Not submitted
 		}
 
-		mockRepo.On("GetLecturerByUserID", ctx, userID).Return(lecturer, nil)
 		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil)
 
-		result, err := achievementService.VerifyAchievement(ctx, userID, achievementID)
+		result, err := achievementService.VerifyAchievement(ctx, userID, achievementID, "")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -328,7 +557,8 @@ func TestAchievementService_VerifyAchievement(t *testing.T) {
 
 	t.Run("Unauthorized - not advisor", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		mockDelegations := new(mocks.MockDelegationRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, mockDelegations, nil, nil)
 
 		userID := uuid.New()
 		lecturerID := uuid.New()
@@ -354,9 +584,13 @@ func TestAchievementService_VerifyAchievement(t *testing.T) {
 
 		mockRepo.On("GetLecturerByUserID", ctx, userID).Return(lecturer, nil)
 		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil)
+		mockRepo.On("GetAchievementApprovals", ctx, achievementID, 0).Return([]model.AchievementApproval{
+			{ID: uuid.New(), StageOrder: 1, ApproverRole: model.ApproverRoleAdvisor, Status: "pending"},
+		}, nil)
 		mockRepo.On("GetStudentByID", ctx, studentID).Return(student, nil)
+		mockDelegations.On("FindActiveDelegation", ctx, otherLecturerID, lecturerID, mock.Anything).Return(nil, nil)
 
-		result, err := achievementService.VerifyAchievement(ctx, userID, achievementID)
+		result, err := achievementService.VerifyAchievement(ctx, userID, achievementID, "")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -371,7 +605,7 @@ func TestAchievementService_RejectAchievement(t *testing.T) {
 
 	t.Run("Successful rejection", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
 
 		userID := uuid.New()
 		lecturerID := uuid.New()
@@ -385,9 +619,10 @@ func TestAchievementService_RejectAchievement(t *testing.T) {
 		}
 
 		ref := &model.AchievementReference{
-			ID:        achievementID,
-			StudentID: studentID,
-			Status:    "submitted",
+			ID:                 achievementID,
+			StudentID:          studentID,
+			MongoAchievementID: "mongo_id",
+			Status:             "submitted",
 		}
 
 		student := &model.Student{
@@ -395,10 +630,20 @@ func TestAchievementService_RejectAchievement(t *testing.T) {
 			AdvisorID: lecturerID,
 		}
 
+		snapshot := &mongodb.Achievement{AchievementType: "other", Title: "Sertifikat"}
+
+		approvalID := uuid.New()
+
 		mockRepo.On("GetLecturerByUserID", ctx, userID).Return(lecturer, nil)
 		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil).Once()
+		mockRepo.On("GetAchievementApprovals", ctx, achievementID, 0).Return([]model.AchievementApproval{
+			{ID: approvalID, StageOrder: 1, ApproverRole: model.ApproverRoleAdvisor, Status: "pending"},
+		}, nil)
 		mockRepo.On("GetStudentByID", ctx, studentID).Return(student, nil)
-		mockRepo.On("UpdateAchievementStatusToRejected", ctx, achievementID, rejectionNote).Return(nil)
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(snapshot, nil)
+		mockRepo.On("UpdateAchievementStatusToRejected", ctx, achievementID, rejectionNote, userID, *snapshot, mock.MatchedBy(func(d model.ApprovalDecision) bool {
+			return d.ApprovalID == approvalID && d.Status == "rejected" && *d.Note == rejectionNote
+		})).Return(nil)
 
 		updatedRef := &model.AchievementReference{
 			ID:            achievementID,
@@ -419,7 +664,7 @@ func TestAchievementService_RejectAchievement(t *testing.T) {
 
 	t.Run("Empty rejection note", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
 
 		userID := uuid.New()
 		achievementID := uuid.New()
@@ -439,7 +684,7 @@ func TestAchievementService_GetStudentAchievements(t *testing.T) {
 
 	t.Run("Successful retrieval", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
 
 		userID := uuid.New()
 		lecturerID := uuid.New()
@@ -464,10 +709,10 @@ func TestAchievementService_GetStudentAchievements(t *testing.T) {
 
 		mockRepo.On("GetLecturerByUserID", ctx, userID).Return(lecturer, nil)
 		mockRepo.On("GetStudentIDsByAdvisorID", ctx, lecturerID).Return(studentIDs, nil)
-		mockRepo.On("GetAchievementsWithStudentInfo", ctx, studentIDs, "", 1, 10).Return(achievements, 1, nil)
+		mockRepo.On("GetAchievementsWithStudentInfo", ctx, studentIDs, model.AchievementListFilters{}, 1, 10).Return(achievements, 1, nil)
 		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id_1").Return(&mongodb.Achievement{}, nil)
 
-		result, err := achievementService.GetStudentAchievements(ctx, userID, "", 1, 10)
+		result, err := achievementService.GetStudentAchievements(ctx, userID, model.AchievementListFilters{}, 1, 10)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -479,7 +724,7 @@ func TestAchievementService_GetStudentAchievements(t *testing.T) {
 
 	t.Run("No students found", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
 
 		userID := uuid.New()
 		lecturerID := uuid.New()
@@ -492,7 +737,7 @@ func TestAchievementService_GetStudentAchievements(t *testing.T) {
 		mockRepo.On("GetLecturerByUserID", ctx, userID).Return(lecturer, nil)
 		mockRepo.On("GetStudentIDsByAdvisorID", ctx, lecturerID).Return([]uuid.UUID{}, nil)
 
-		result, err := achievementService.GetStudentAchievements(ctx, userID, "", 1, 10)
+		result, err := achievementService.GetStudentAchievements(ctx, userID, model.AchievementListFilters{}, 1, 10)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -508,7 +753,7 @@ func TestAchievementService_GetAchievementHistory(t *testing.T) {
 
 	t.Run("Student can view own achievement history", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
 
 		userID := uuid.New()
 		studentID := uuid.New()
@@ -548,7 +793,7 @@ func TestAchievementService_GetAchievementHistory(t *testing.T) {
 
 	t.Run("Unauthorized user", func(t *testing.T) {
 		mockRepo := new(mocks.MockAchievementRepository)
-		achievementService := service.NewAchievementService(mockRepo)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
 
 		userID := uuid.New()
 		otherStudentID := uuid.New()
@@ -572,3 +817,59 @@ func TestAchievementService_GetAchievementHistory(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestAchievementService_GetAchievementStatistics(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Includes type and level statistics", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		userID := uuid.New()
+		studentIDs := []uuid.UUID{uuid.New(), uuid.New()}
+		filters := model.StatisticsFilters{Status: "verified"}
+
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(nil, errors.New("not a student"))
+		mockRepo.On("GetLecturerByUserID", ctx, userID).Return(nil, errors.New("not a lecturer"))
+		mockRepo.On("GetAllStudentIDs", ctx).Return(studentIDs, nil)
+		mockRepo.On("GetTotalAchievements", ctx, studentIDs, filters).Return(3, nil)
+		mockRepo.On("GetStatisticsByType", ctx, studentIDs, filters).Return([]model.StatsByType{{Type: "competition", Count: 3}}, nil)
+		mockRepo.On("GetStatisticsByPeriod", ctx, studentIDs, filters).Return([]model.StatsByPeriod{}, nil)
+		mockRepo.On("GetTopStudents", ctx, studentIDs, filters, 10).Return([]model.TopStudent{}, nil)
+		mockRepo.On("GetLevelDistribution", ctx, studentIDs, filters).Return([]model.LevelDistribution{{Level: "national", Count: 2}}, nil)
+		mockRepo.On("GetStatusDistribution", ctx, studentIDs, filters).Return([]model.StatusDistribution{}, nil)
+
+		result, err := achievementService.GetAchievementStatistics(ctx, userID, filters)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.TotalAchievements)
+		assert.Equal(t, []model.StatsByType{{Type: "competition", Count: 3}}, result.ByType)
+		assert.Equal(t, []model.LevelDistribution{{Level: "national", Count: 2}}, result.LevelDistribution)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("MongoDB aggregation failure is returned", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		userID := uuid.New()
+		studentID := uuid.New()
+		studentIDs := []uuid.UUID{studentID}
+		filters := model.StatisticsFilters{}
+
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+		mockRepo.On("GetTotalAchievements", ctx, studentIDs, filters).Return(1, nil)
+		mockRepo.On("GetStatisticsByType", ctx, studentIDs, filters).Return([]model.StatsByType{}, nil)
+		mockRepo.On("GetStatisticsByPeriod", ctx, studentIDs, filters).Return([]model.StatsByPeriod{}, nil)
+		mockRepo.On("GetTopStudents", ctx, studentIDs, filters, 10).Return([]model.TopStudent{}, nil)
+		mockRepo.On("GetLevelDistribution", ctx, studentIDs, filters).Return(nil, errors.New("server selection timeout"))
+
+		result, err := achievementService.GetAchievementStatistics(ctx, userID, filters)
+
+		// Angka kosong tidak boleh tampil seolah-olah tidak ada prestasi
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "failed to get level distribution")
+		mockRepo.AssertNotCalled(t, "GetStatusDistribution", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,125 @@
+package test
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAchievementTypeService_CreateAchievementType(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Successful creation with normalized code", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementTypeRepository)
+		typeService := service.NewAchievementTypeService(mockRepo, nil)
+
+		mockRepo.On("GetAchievementTypeByCode", ctx, "community_service").Return(nil, nil)
+		mockRepo.On("CreateAchievementType", ctx, mock.MatchedBy(func(at *model.AchievementType) bool {
+			return at.Code == "community_service" && at.IsActive && at.DefaultPoints == 8
+		})).Return(nil)
+
+		result, err := typeService.CreateAchievementType(ctx, model.CreateAchievementTypeRequest{
+			Code:          " Community_Service ",
+			NameID:        "Pengabdian Masyarakat",
+			NameEN:        "Community Service",
+			DefaultPoints: 8,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "community_service", result.Code)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Duplicate code", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementTypeRepository)
+		typeService := service.NewAchievementTypeService(mockRepo, nil)
+
+		mockRepo.On("GetAchievementTypeByCode", ctx, "competition").Return(&model.AchievementType{Code: "competition"}, nil)
+
+		result, err := typeService.CreateAchievementType(ctx, model.CreateAchievementTypeRequest{
+			Code:   "Competition",
+			NameID: "Kompetisi",
+			NameEN: "Competition",
+		})
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "achievement type already exists")
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid code", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementTypeRepository)
+		typeService := service.NewAchievementTypeService(mockRepo, nil)
+
+		result, err := typeService.CreateAchievementType(ctx, model.CreateAchievementTypeRequest{
+			Code:   "lomba-nasional",
+			NameID: "Lomba",
+			NameEN: "Contest",
+		})
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "code must be 2-50 lowercase letters, digits or underscores")
+	})
+}
+
+func TestAchievementTypeService_UpdateAchievementType(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := new(mocks.MockAchievementTypeRepository)
+	typeService := service.NewAchievementTypeService(mockRepo, nil)
+
+	existing := &model.AchievementType{Code: "other", NameID: "Lainnya", NameEN: "Other", IsActive: true, DefaultPoints: 5}
+	mockRepo.On("GetAchievementTypeByCode", ctx, "other").Return(existing, nil)
+	mockRepo.On("UpdateAchievementType", ctx, mock.AnythingOfType("*model.AchievementType")).Return(nil)
+
+	inactive := false
+	result, err := typeService.UpdateAchievementType(ctx, "other", model.UpdateAchievementTypeRequest{IsActive: &inactive})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsActive)
+	assert.Equal(t, "Lainnya", result.NameID)
+	assert.Equal(t, 5, result.DefaultPoints)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAchievementTypeService_DeleteAchievementType(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Type in use cannot be deleted", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementTypeRepository)
+		mockAchievementRepo := new(mocks.MockAchievementRepository)
+		typeService := service.NewAchievementTypeService(mockRepo, mockAchievementRepo)
+
+		mockRepo.On("GetAchievementTypeByCode", ctx, "competition").Return(&model.AchievementType{Code: "competition"}, nil)
+		mockAchievementRepo.On("CountAchievementsByType", ctx, "competition").Return(int64(3), nil)
+
+		err := typeService.DeleteAchievementType(ctx, "competition")
+
+		assert.EqualError(t, err, "achievement type is in use; deactivate it instead")
+		mockRepo.AssertNotCalled(t, "DeleteAchievementType", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unused type is deleted", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementTypeRepository)
+		mockAchievementRepo := new(mocks.MockAchievementRepository)
+		typeService := service.NewAchievementTypeService(mockRepo, mockAchievementRepo)
+
+		mockRepo.On("GetAchievementTypeByCode", ctx, "hackathon").Return(&model.AchievementType{Code: "hackathon"}, nil)
+		mockAchievementRepo.On("CountAchievementsByType", ctx, "hackathon").Return(int64(0), nil)
+		mockRepo.On("DeleteAchievementType", ctx, "hackathon").Return(nil)
+
+		err := typeService.DeleteAchievementType(ctx, "hackathon")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockAchievementRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAchievementService_GetAchievementVersions(t *testing.T) {
+	ctx := context.Background()
+
+	userID := uuid.New()
+	studentID := uuid.New()
+	achievementID := uuid.New()
+
+	t.Run("Owner sees version list", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		versions := []model.AchievementVersion{
+			{ID: uuid.New(), AchievementID: achievementID, Version: 1, ChangedBy: &userID, CreatedAt: time.Now()},
+			{ID: uuid.New(), AchievementID: achievementID, Version: 2, ChangedBy: &userID, CreatedAt: time.Now()},
+		}
+
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{ID: achievementID, StudentID: studentID, Status: "draft"}, nil)
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(&model.Student{ID: studentID, UserID: userID}, nil)
+		mockRepo.On("GetAchievementVersions", ctx, achievementID).Return(versions, nil)
+
+		result, err := achievementService.GetAchievementVersions(ctx, userID, false, achievementID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, achievementID, result.AchievementID)
+		assert.Len(t, result.Versions, 2)
+		assert.Equal(t, 2, result.Versions[1].Version)
+	})
+
+	t.Run("Deleted achievement is not found", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{ID: achievementID, StudentID: studentID, Status: "deleted"}, nil)
+
+		result, err := achievementService.GetAchievementVersions(ctx, userID, true, achievementID)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "achievement not found")
+	})
+}
+
+func TestAchievementService_DiffAchievementVersions(t *testing.T) {
+	ctx := context.Background()
+
+	achievementID := uuid.New()
+	adminID := uuid.New()
+	ref := &model.AchievementReference{ID: achievementID, StudentID: uuid.New(), Status: "submitted"}
+
+	level := "national"
+	newLevel := "international"
+	v1 := &mongodb.Achievement{
+		AchievementType: "competition",
+		Title:           "Lomba",
+		Details:         mongodb.AchievementDetails{CompetitionLevel: &level},
+		Tags:            []string{"ai"},
+		Attachments:     []mongodb.Attachment{{ID: "att-1", FileName: "sertifikat.pdf"}},
+		Points:          10,
+		UpdatedAt:       time.Now().Add(-time.Hour),
+	}
+	v2 := &mongodb.Achievement{
+		AchievementType: "competition",
+		Title:           "Lomba",
+		Details:         mongodb.AchievementDetails{CompetitionLevel: &newLevel},
+		Tags:            []string{"ai", "robotics"},
+		Attachments: []mongodb.Attachment{
+			{ID: "att-1", FileName: "sertifikat.pdf"},
+			{ID: "att-2", FileName: "foto.png"},
+		},
+		UpdatedAt: time.Now(),
+	}
+
+	t.Run("Changed fields between two versions", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil)
+		mockRepo.On("GetAchievementVersion", ctx, achievementID, 1).Return(&model.AchievementVersion{Version: 1, Snapshot: v1}, nil)
+		mockRepo.On("GetAchievementVersion", ctx, achievementID, 2).Return(&model.AchievementVersion{Version: 2, Snapshot: v2}, nil)
+
+		result, err := achievementService.DiffAchievementVersions(ctx, adminID, true, achievementID, 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.FromVersion)
+		assert.Equal(t, 2, result.ToVersion)
+
+		// points dan updatedAt bukan isi yang diisi mahasiswa
+		fields := make([]string, 0, len(result.Changes))
+		for _, change := range result.Changes {
+			fields = append(fields, change.Field)
+		}
+		assert.Equal(t, []string{"attachments.att-2", "details.competitionLevel", "tags"}, fields)
+		assert.Nil(t, result.Changes[0].Before)
+		assert.Equal(t, "national", result.Changes[1].Before)
+		assert.Equal(t, "international", result.Changes[1].After)
+	})
+
+	t.Run("Unknown version", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil)
+		mockRepo.On("GetAchievementVersion", ctx, achievementID, 1).Return(&model.AchievementVersion{Version: 1, Snapshot: v1}, nil)
+		mockRepo.On("GetAchievementVersion", ctx, achievementID, 5).Return(nil, nil)
+
+		result, err := achievementService.DiffAchievementVersions(ctx, adminID, true, achievementID, 1, 5)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "achievement version not found")
+	})
+}
+
+func TestAchievementService_BackfillBaselineVersions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Pages through references after the last id", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		first := []model.AchievementReference{{ID: uuid.New(), MongoAchievementID: "m1"}, {ID: uuid.New(), MongoAchievementID: "m2"}}
+		second := []model.AchievementReference{{ID: uuid.New(), MongoAchievementID: "m3"}}
+		mockRepo.On("GetAchievementsWithoutVersions", ctx, uuid.Nil, 500).Return(first, nil)
+		// m2 tidak punya dokumen sehingga tidak disimpan, tetapi tetap dilewati pada batch berikutnya
+		mockRepo.On("SaveBaselineVersionsFromDocuments", ctx, first).Return(1, nil)
+		mockRepo.On("GetAchievementsWithoutVersions", ctx, first[1].ID, 500).Return(second, nil)
+		mockRepo.On("SaveBaselineVersionsFromDocuments", ctx, second).Return(1, nil)
+		mockRepo.On("GetAchievementsWithoutVersions", ctx, second[0].ID, 500).Return([]model.AchievementReference{}, nil)
+
+		saved, err := achievementService.BackfillBaselineVersions(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, saved)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - saving a batch fails", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		refs := []model.AchievementReference{{ID: uuid.New(), MongoAchievementID: "m1"}}
+		mockRepo.On("GetAchievementsWithoutVersions", ctx, uuid.Nil, 500).Return(refs, nil)
+		mockRepo.On("SaveBaselineVersionsFromDocuments", ctx, refs).Return(0, errors.New("db error"))
+
+		saved, err := achievementService.BackfillBaselineVersions(ctx)
+
+		assert.EqualError(t, err, "failed to save baseline achievement versions")
+		assert.Equal(t, 0, saved)
+	})
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAchievementService_WithdrawAchievement(t *testing.T) {
+	ctx := context.Background()
+
+	userID := uuid.New()
+	studentID := uuid.New()
+	achievementID := uuid.New()
+	student := &model.Student{ID: studentID, UserID: userID, StudentID: "2110511001"}
+
+	setup := func(status string) (*mocks.MockAchievementRepository, service.AchievementService) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(student, nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+			ID: achievementID, StudentID: studentID, MongoAchievementID: "mongo_id", Status: status,
+		}, nil).Once()
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(&mongodb.Achievement{Title: "Juara 1 Hackathon"}, nil)
+		return mockRepo, service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+	}
+
+	t.Run("Success - logged and advisor notified", func(t *testing.T) {
+		mockRepo, achievementService := setup("submitted")
+
+		mockRepo.On("UpdateAchievementStatusToWithdrawn", ctx, achievementID, userID,
+			"Withdrawn by student: salah unggah sertifikat",
+			`Student 2110511001 withdrew "Juara 1 Hackathon" from verification: salah unggah sertifikat`).Return(nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{ID: achievementID, Status: "draft"}, nil).Once()
+
+		result, err := achievementService.WithdrawAchievement(ctx, userID, achievementID, " salah unggah sertifikat ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "draft", result.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - not submitted", func(t *testing.T) {
+		mockRepo, achievementService := setup("verified")
+
+		_, err := achievementService.WithdrawAchievement(ctx, userID, achievementID, "")
+
+		assert.EqualError(t, err, "achievement must be in 'submitted' status to withdraw")
+		mockRepo.AssertNotCalled(t, "UpdateAchievementStatusToWithdrawn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - decided concurrently", func(t *testing.T) {
+		mockRepo, achievementService := setup("submitted")
+
+		mockRepo.On("UpdateAchievementStatusToWithdrawn", ctx, achievementID, userID, "Withdrawn by student", mock.Anything).
+			Return(repository.ErrAchievementNotSubmitted)
+
+		_, err := achievementService.WithdrawAchievement(ctx, userID, achievementID, "")
+
+		assert.EqualError(t, err, "achievement must be in 'submitted' status to withdraw")
+	})
+
+	t.Run("Error - not the owner", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		achievementService := service.NewAchievementService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		mockRepo.On("GetStudentByUserID", ctx, userID).Return(student, nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+			ID: achievementID, StudentID: uuid.New(), Status: "submitted",
+		}, nil)
+
+		_, err := achievementService.WithdrawAchievement(ctx, userID, achievementID, "")
+
+		assert.EqualError(t, err, "unauthorized: achievement does not belong to this student")
+	})
+}
+
+func TestNotificationService(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("List includes unread count", func(t *testing.T) {
+		mockRepo := new(mocks.MockNotificationRepository)
+		notificationService := service.NewNotificationService(mockRepo)
+
+		mockRepo.On("GetNotifications", ctx, userID, true, 50).Return([]model.Notification{
+			{ID: uuid.New(), UserID: userID, Type: model.NotificationAchievementWithdrawn},
+		}, nil)
+		mockRepo.On("CountUnreadNotifications", ctx, userID).Return(1, nil)
+
+		result, err := notificationService.GetMyNotifications(ctx, userID, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Unread)
+		assert.Len(t, result.Notifications, 1)
+	})
+
+	t.Run("Error - mark read on someone else's notification", func(t *testing.T) {
+		mockRepo := new(mocks.MockNotificationRepository)
+		notificationService := service.NewNotificationService(mockRepo)
+
+		notificationID := uuid.New()
+		mockRepo.On("MarkNotificationRead", ctx, userID, notificationID, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+		err := notificationService.MarkNotificationRead(ctx, userID, notificationID)
+
+		assert.EqualError(t, err, "notification not found")
+	})
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestApprovalChainService_CreateApprovalChain(t *testing.T) {
+	ctx := context.Background()
+
+	achievementType := "Competition"
+	level := "International"
+
+	t.Run("Valid chain is normalized and saved", func(t *testing.T) {
+		mockRepo := new(mocks.MockApprovalChainRepository)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		chainService := service.NewApprovalChainService(mockRepo, mockTypeRepo)
+
+		mockTypeRepo.On("GetAchievementTypeByCode", ctx, "competition").Return(&model.AchievementType{Code: "competition", IsActive: true}, nil)
+		mockRepo.On("RoleExists", ctx, "Kepala Departemen").Return(true, nil)
+		mockRepo.On("GetApprovalChainByScope", ctx, mock.Anything, mock.Anything).Return(nil, nil)
+		mockRepo.On("CreateApprovalChain", ctx, mock.AnythingOfType("*model.ApprovalChain")).Return(nil)
+
+		chain, err := chainService.CreateApprovalChain(ctx, model.ApprovalChainRequest{
+			Name:             "Lomba internasional",
+			AchievementType:  &achievementType,
+			CompetitionLevel: &level,
+			Stages: []model.ApprovalStage{
+				{Name: "Dosen Wali", ApproverRole: "Advisor"},
+				{Name: "Kepala Departemen", ApproverRole: "Kepala Departemen"},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "competition", *chain.AchievementType)
+		assert.Equal(t, "international", *chain.CompetitionLevel)
+		assert.Equal(t, model.ApproverRoleAdvisor, chain.Stages[0].ApproverRole)
+		assert.True(t, chain.IsActive)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown approver role", func(t *testing.T) {
+		mockRepo := new(mocks.MockApprovalChainRepository)
+		chainService := service.NewApprovalChainService(mockRepo, nil)
+
+		mockRepo.On("RoleExists", ctx, "Dekan").Return(false, nil)
+
+		chain, err := chainService.CreateApprovalChain(ctx, model.ApprovalChainRequest{
+			Name:   "Default",
+			Stages: []model.ApprovalStage{{Name: "Dekan", ApproverRole: "Dekan"}},
+		})
+
+		assert.Nil(t, chain)
+		assert.EqualError(t, err, "unknown approver role: Dekan")
+		mockRepo.AssertNotCalled(t, "CreateApprovalChain", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid competition level", func(t *testing.T) {
+		chainService := service.NewApprovalChainService(new(mocks.MockApprovalChainRepository), nil)
+		invalid := "galactic"
+
+		chain, err := chainService.CreateApprovalChain(ctx, model.ApprovalChainRequest{
+			Name:             "Default",
+			CompetitionLevel: &invalid,
+			Stages:           []model.ApprovalStage{{Name: "Dosen Wali", ApproverRole: "advisor"}},
+		})
+
+		assert.Nil(t, chain)
+		assert.EqualError(t, err, "competition_level must be one of: international, national, regional, local")
+	})
+
+	t.Run("Empty stages", func(t *testing.T) {
+		chainService := service.NewApprovalChainService(new(mocks.MockApprovalChainRepository), nil)
+
+		chain, err := chainService.CreateApprovalChain(ctx, model.ApprovalChainRequest{Name: "Default"})
+
+		assert.Nil(t, chain)
+		assert.EqualError(t, err, "stages must contain 1 to 10 stages")
+	})
+
+	t.Run("Duplicate scope", func(t *testing.T) {
+		mockRepo := new(mocks.MockApprovalChainRepository)
+		chainService := service.NewApprovalChainService(mockRepo, nil)
+
+		mockRepo.On("GetApprovalChainByScope", ctx, (*string)(nil), (*string)(nil)).Return(&model.ApprovalChain{ID: uuid.New()}, nil)
+
+		chain, err := chainService.CreateApprovalChain(ctx, model.ApprovalChainRequest{
+			Name:   "Default",
+			Stages: []model.ApprovalStage{{Name: "Dosen Wali", ApproverRole: "advisor"}},
+		})
+
+		assert.Nil(t, chain)
+		assert.EqualError(t, err, "approval chain already exists for this achievement type and level")
+	})
+}
+
+func TestApprovalChainService_UpdateApprovalChain(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := new(mocks.MockApprovalChainRepository)
+	chainService := service.NewApprovalChainService(mockRepo, nil)
+
+	id := uuid.New()
+	existing := &model.ApprovalChain{ID: id, Name: "Default", IsActive: true,
+		Stages: []model.ApprovalStage{{Name: "Dosen Wali", ApproverRole: "advisor"}}}
+	inactive := false
+
+	mockRepo.On("GetApprovalChainByID", ctx, id).Return(existing, nil)
+	// Scope yang sama milik chain ini sendiri bukan duplikat
+	mockRepo.On("GetApprovalChainByScope", ctx, (*string)(nil), (*string)(nil)).Return(existing, nil)
+	mockRepo.On("UpdateApprovalChain", ctx, existing).Return(nil)
+
+	chain, err := chainService.UpdateApprovalChain(ctx, id, model.ApprovalChainRequest{
+		Name:     "Default (nonaktif)",
+		Stages:   []model.ApprovalStage{{Name: "Dosen Wali", ApproverRole: "advisor"}},
+		IsActive: &inactive,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Default (nonaktif)", chain.Name)
+	assert.False(t, chain.IsActive)
+	mockRepo.AssertExpectations(t)
+}
@@ -4,8 +4,8 @@ import (
 	"testing"
 	"time"
 	model "UASBE/app/model/Postgresql"
-	"UASBE/app/repository"
 	"UASBE/app/service"
+	"UASBE/test/mocks"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -53,12 +53,9 @@ func TestAuthServiceIntegration_Login(t *testing.T) {
 
 		permissions := []string{"read", "write"}
 
-		// Create mock repo using struct
-		mockRepo := &repository.AuthRepository{}
+		mockRepo := new(mocks.MockAuthRepository)
 		authService := service.NewAuthService(mockRepo)
 
-		// Since we can't easily mock the actual repository methods without interface,
-		// we'll test the logic flow instead
 		req := &model.LoginRequest{
 			Username: "testuser",
 			Password: password,
@@ -0,0 +1,186 @@
+package test
+
+import (
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"UASBE/utils"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// refreshFixture menyiapkan refresh token aktif milik user aktif
+type refreshFixture struct {
+	mockRepo *mocks.MockAuthRepository
+	service  service.AuthService
+	raw      string
+	stored   *model.RefreshToken
+	user     *model.Users
+}
+
+func newRefreshFixture(t *testing.T) *refreshFixture {
+	raw, err := utils.GenerateRefreshToken()
+	assert.NoError(t, err)
+
+	f := &refreshFixture{
+		mockRepo: new(mocks.MockAuthRepository),
+		raw:      raw,
+		user:     &model.Users{ID: uuid.New(), Username: "budi", FullName: "Budi", RoleID: uuid.New(), ISActive: true},
+	}
+	f.service = service.NewAuthService(f.mockRepo)
+	f.stored = &model.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    f.user.ID,
+		FamilyID:  uuid.New(),
+		TokenHash: utils.HashRefreshToken(raw),
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now().Add(-time.Hour),
+	}
+
+	f.mockRepo.On("GetRefreshTokenByHash", utils.HashRefreshToken(raw)).Return(f.stored, nil)
+	return f
+}
+
+func TestAuthService_RefreshToken(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Rotates the token within the same family", func(t *testing.T) {
+		f := newRefreshFixture(t)
+
+		var rotated model.RefreshToken
+		f.mockRepo.On("FindUserByID", f.user.ID).Return(f.user, "Mahasiswa", nil)
+		f.mockRepo.On("GetPermissionsByRoleID", f.user.RoleID).Return([]string{"achievement:create"}, nil)
+		f.mockRepo.On("RotateRefreshToken", f.stored.ID, mock.MatchedBy(func(token model.RefreshToken) bool {
+			return token.UserID == f.user.ID && token.FamilyID == f.stored.FamilyID && token.ExpiresAt.After(time.Now())
+		})).Run(func(args mock.Arguments) {
+			rotated = args.Get(1).(model.RefreshToken)
+		}).Return(nil)
+
+		result, err := f.service.RefreshToken(ctx, f.raw)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.Token)
+		assert.NotEqual(t, f.raw, result.RefreshToken)
+		// Hanya hash yang disimpan; token baru cocok dengan record yang dirotasi
+		assert.Equal(t, utils.HashRefreshToken(result.RefreshToken), rotated.TokenHash)
+		assert.Equal(t, "Mahasiswa", result.User.Role)
+		assert.Equal(t, []string{"achievement:create"}, result.User.Permissions)
+		f.mockRepo.AssertNotCalled(t, "RevokeRefreshTokenFamily", mock.Anything)
+		f.mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Reusing a rotated token revokes the family", func(t *testing.T) {
+		f := newRefreshFixture(t)
+		rotatedAt := time.Now().Add(-time.Minute)
+		f.stored.RotatedAt = &rotatedAt
+		f.mockRepo.On("RevokeRefreshTokenFamily", f.stored.FamilyID).Return(nil)
+
+		result, err := f.service.RefreshToken(ctx, f.raw)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "refresh token reuse detected")
+		f.mockRepo.AssertCalled(t, "RevokeRefreshTokenFamily", f.stored.FamilyID)
+		f.mockRepo.AssertNotCalled(t, "RotateRefreshToken", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Concurrent rotation is treated as reuse", func(t *testing.T) {
+		f := newRefreshFixture(t)
+		f.mockRepo.On("FindUserByID", f.user.ID).Return(f.user, "Mahasiswa", nil)
+		f.mockRepo.On("GetPermissionsByRoleID", f.user.RoleID).Return([]string{}, nil)
+		f.mockRepo.On("RotateRefreshToken", f.stored.ID, mock.Anything).Return(repository.ErrRefreshTokenAlreadyRotated)
+		f.mockRepo.On("RevokeRefreshTokenFamily", f.stored.FamilyID).Return(nil)
+
+		result, err := f.service.RefreshToken(ctx, f.raw)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "refresh token reuse detected")
+		f.mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Revoked family cannot be refreshed", func(t *testing.T) {
+		f := newRefreshFixture(t)
+		revokedAt := time.Now().Add(-time.Minute)
+		f.stored.RevokedAt = &revokedAt
+
+		result, err := f.service.RefreshToken(ctx, f.raw)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "refresh token has been revoked")
+		f.mockRepo.AssertNotCalled(t, "RotateRefreshToken", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Expired token is rejected without revoking the family", func(t *testing.T) {
+		f := newRefreshFixture(t)
+		f.stored.ExpiresAt = time.Now().Add(-time.Second)
+
+		result, err := f.service.RefreshToken(ctx, f.raw)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "refresh token expired")
+		f.mockRepo.AssertNotCalled(t, "RevokeRefreshTokenFamily", mock.Anything)
+		f.mockRepo.AssertNotCalled(t, "FindUserByID", mock.Anything)
+	})
+
+	t.Run("Inactive user loses the whole family", func(t *testing.T) {
+		f := newRefreshFixture(t)
+		f.user.ISActive = false
+		f.mockRepo.On("FindUserByID", f.user.ID).Return(f.user, "Mahasiswa", nil)
+		f.mockRepo.On("RevokeRefreshTokenFamily", f.stored.FamilyID).Return(nil)
+
+		result, err := f.service.RefreshToken(ctx, f.raw)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "account is inactive, please contact admin")
+		f.mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown token", func(t *testing.T) {
+		mockRepo := new(mocks.MockAuthRepository)
+		authService := service.NewAuthService(mockRepo)
+		mockRepo.On("GetRefreshTokenByHash", mock.Anything).Return(nil, errors.New("no rows in result set"))
+
+		result, err := authService.RefreshToken(ctx, "not-a-token")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "invalid refresh token")
+	})
+}
+
+func TestAuthService_RevokeRefreshToken(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Revokes the family of the owner's token", func(t *testing.T) {
+		f := newRefreshFixture(t)
+		f.mockRepo.On("RevokeRefreshTokenFamily", f.stored.FamilyID).Return(nil)
+
+		err := f.service.RevokeRefreshToken(ctx, f.user.ID, f.raw)
+
+		assert.NoError(t, err)
+		f.mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Token of another user is rejected", func(t *testing.T) {
+		f := newRefreshFixture(t)
+
+		err := f.service.RevokeRefreshToken(ctx, uuid.New(), f.raw)
+
+		assert.EqualError(t, err, "unauthorized: refresh token does not belong to this user")
+		f.mockRepo.AssertNotCalled(t, "RevokeRefreshTokenFamily", mock.Anything)
+	})
+
+	t.Run("Revoke failure is reported", func(t *testing.T) {
+		f := newRefreshFixture(t)
+		f.mockRepo.On("RevokeRefreshTokenFamily", f.stored.FamilyID).Return(errors.New("connection reset"))
+
+		err := f.service.RevokeRefreshToken(ctx, f.user.ID, f.raw)
+
+		assert.EqualError(t, err, "failed to revoke refresh token")
+	})
+}
@@ -0,0 +1,287 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAchievementService_DelegatedVerification(t *testing.T) {
+	ctx := context.Background()
+
+	delegateUserID := uuid.New()
+	delegateLecturerID := uuid.New()
+	advisorID := uuid.New()
+	advisorUserID := uuid.New()
+	studentID := uuid.New()
+	achievementID := uuid.New()
+	stageID := uuid.New()
+
+	ref := &model.AchievementReference{ID: achievementID, StudentID: studentID, MongoAchievementID: "mongo_id", Status: "submitted"}
+	delegation := &model.VerificationDelegation{ID: uuid.New(), DelegatorID: advisorID, DelegatorUserID: advisorUserID, DelegateID: delegateLecturerID}
+
+	setup := func() (*mocks.MockAchievementRepository, *mocks.MockDelegationRepository, *mocks.MockScoringRepository, *mocks.MockAchievementTypeRepository, service.AchievementService) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		mockDelegations := new(mocks.MockDelegationRepository)
+		mockScoringRepo := new(mocks.MockScoringRepository)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+
+		mockRepo.On("GetAchievementApprovals", ctx, achievementID, 0).Return([]model.AchievementApproval{
+			{ID: stageID, StageOrder: 1, ApproverRole: model.ApproverRoleAdvisor, Status: "pending"},
+		}, nil)
+		mockRepo.On("GetLecturerByUserID", ctx, delegateUserID).Return(&model.Lecturers{ID: delegateLecturerID, UserID: delegateUserID}, nil)
+		mockRepo.On("GetStudentByID", ctx, studentID).Return(&model.Student{ID: studentID, AdvisorID: advisorID}, nil)
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil).Maybe()
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{}, nil).Maybe()
+
+		return mockRepo, mockDelegations, mockScoringRepo, mockTypeRepo,
+			service.NewAchievementService(mockRepo, mockTypeRepo, mockScoringRepo, nil, mockDelegations, nil, service.NewAchievementOutboxService(mockOutbox, nil))
+	}
+
+	t.Run("Delegate verifies on behalf of the advisor", func(t *testing.T) {
+		mockRepo, mockDelegations, mockScoringRepo, mockTypeRepo, achievementService := setup()
+
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil).Once()
+		mockDelegations.On("FindActiveDelegation", ctx, advisorID, delegateLecturerID, mock.Anything).Return(delegation, nil)
+		mockRepo.On("GetLatestAchievementSnapshot", ctx, achievementID).Return(&mongodb.Achievement{AchievementType: "other"}, nil)
+		mockScoringRepo.On("GetActiveScoringRuleSet", ctx).Return(&model.ScoringRuleSet{Version: 1, IsActive: true}, nil)
+		mockTypeRepo.On("GetAchievementTypeByCode", ctx, "other").Return(&model.AchievementType{Code: "other", DefaultPoints: 5}, nil)
+		mockRepo.On("UpdateAchievementStatusToVerified", ctx, achievementID, delegateLecturerID, delegateUserID, mock.AnythingOfType("model.AchievementScore"),
+			mock.MatchedBy(func(d model.ApprovalDecision) bool {
+				return d.ApprovalID == stageID && d.DecidedBy == delegateUserID &&
+					d.OnBehalfOf != nil && *d.OnBehalfOf == advisorUserID &&
+					d.DelegationID != nil && *d.DelegationID == delegation.ID
+			})).Return(nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{ID: achievementID, Status: "verified"}, nil).Once()
+
+		result, err := achievementService.VerifyAchievement(ctx, delegateUserID, achievementID, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "verified", result.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Delegate rejects on behalf of the advisor", func(t *testing.T) {
+		mockRepo, mockDelegations, _, _, achievementService := setup()
+		snapshot := &mongodb.Achievement{AchievementType: "other"}
+
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil).Once()
+		mockDelegations.On("FindActiveDelegation", ctx, advisorID, delegateLecturerID, mock.Anything).Return(delegation, nil)
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(snapshot, nil)
+		mockRepo.On("UpdateAchievementStatusToRejected", ctx, achievementID, "Lengkapi bukti", delegateUserID, *snapshot,
+			mock.MatchedBy(func(d model.ApprovalDecision) bool {
+				return d.Status == "rejected" && d.OnBehalfOf != nil && *d.OnBehalfOf == advisorUserID
+			})).Return(nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{ID: achievementID, Status: "rejected"}, nil).Once()
+
+		result, err := achievementService.RejectAchievement(ctx, delegateUserID, achievementID, "Lengkapi bukti")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "rejected", result.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Expired or revoked delegation is denied", func(t *testing.T) {
+		mockRepo, mockDelegations, _, _, achievementService := setup()
+
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(ref, nil)
+		mockDelegations.On("FindActiveDelegation", ctx, advisorID, delegateLecturerID, mock.Anything).Return(nil, nil)
+
+		result, err := achievementService.RejectAchievement(ctx, delegateUserID, achievementID, "Lengkapi bukti")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "unauthorized: you can only reject achievements of your advisees")
+		mockRepo.AssertNotCalled(t, "UpdateAchievementStatusToRejected", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Pending approvals include delegated advisor stages", func(t *testing.T) {
+		mockRepo, mockDelegations, _, _, achievementService := setup()
+
+		delegated := []model.AchievementApproval{{ID: stageID, AchievementID: achievementID, ApproverRole: model.ApproverRoleAdvisor, Status: "pending"}}
+		mockRepo.On("GetUserRoleName", ctx, delegateUserID).Return("Dosen Wali", nil)
+		mockRepo.On("GetPendingApprovalsByRole", ctx, "Dosen Wali", mock.Anything).Return([]model.AchievementApproval{}, nil)
+		mockDelegations.On("GetDelegatedPendingApprovals", ctx, delegateLecturerID, mock.Anything).Return(delegated, nil)
+
+		result, err := achievementService.GetPendingApprovals(ctx, delegateUserID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, delegated, result)
+	})
+}
+
+func TestDelegationService_DelegateVerification(t *testing.T) {
+	ctx := context.Background()
+
+	userID := uuid.New()
+	lecturerID := uuid.New()
+	delegateID := uuid.New()
+	today := time.Now().Format("2006-01-02")
+	nextWeek := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+
+	setup := func() (*mocks.MockDelegationRepository, service.DelegationService) {
+		mockRepo := new(mocks.MockDelegationRepository)
+		mockAchievementRepo := new(mocks.MockAchievementRepository)
+		mockAchievementRepo.On("GetLecturerByUserID", ctx, userID).Return(&model.Lecturers{ID: lecturerID, UserID: userID}, nil)
+		return mockRepo, service.NewDelegationService(mockRepo, mockAchievementRepo)
+	}
+
+	t.Run("Lecturer delegates for a date range", func(t *testing.T) {
+		mockRepo, delegationService := setup()
+		reason := "  Cuti  "
+
+		mockRepo.On("LecturerExists", ctx, delegateID).Return(true, nil)
+		mockRepo.On("HasOverlappingDelegation", ctx, lecturerID, delegateID, mock.Anything, mock.Anything).Return(false, nil)
+		mockRepo.On("CreateDelegation", ctx, mock.AnythingOfType("*model.VerificationDelegation")).Return(nil)
+
+		delegation, err := delegationService.DelegateVerification(ctx, userID, model.VerificationDelegationRequest{
+			DelegateID: delegateID, StartDate: today, EndDate: nextWeek, Reason: &reason,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, lecturerID, delegation.DelegatorID)
+		assert.Equal(t, model.DelegationKindDelegation, delegation.Kind)
+		assert.Equal(t, "Cuti", *delegation.Reason)
+		assert.Equal(t, nextWeek, delegation.EndDate.Format("2006-01-02"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Cannot delegate to self", func(t *testing.T) {
+		_, delegationService := setup()
+
+		delegation, err := delegationService.DelegateVerification(ctx, userID, model.VerificationDelegationRequest{
+			DelegateID: lecturerID, StartDate: today, EndDate: nextWeek,
+		})
+
+		assert.Nil(t, delegation)
+		assert.EqualError(t, err, "delegate must be a different lecturer")
+	})
+
+	t.Run("Invalid date range", func(t *testing.T) {
+		_, delegationService := setup()
+
+		_, err := delegationService.DelegateVerification(ctx, userID, model.VerificationDelegationRequest{
+			DelegateID: delegateID, StartDate: nextWeek, EndDate: today,
+		})
+		assert.EqualError(t, err, "end_date must not be before start_date")
+
+		_, err = delegationService.DelegateVerification(ctx, userID, model.VerificationDelegationRequest{
+			DelegateID: delegateID, StartDate: "2020-01-01", EndDate: "2020-01-31",
+		})
+		assert.EqualError(t, err, "end_date must not be in the past")
+
+		_, err = delegationService.DelegateVerification(ctx, userID, model.VerificationDelegationRequest{
+			DelegateID: delegateID, StartDate: "01/02/2026", EndDate: nextWeek,
+		})
+		assert.EqualError(t, err, "start_date and end_date must use YYYY-MM-DD format")
+	})
+
+	t.Run("Overlapping delegation", func(t *testing.T) {
+		mockRepo, delegationService := setup()
+
+		mockRepo.On("LecturerExists", ctx, delegateID).Return(true, nil)
+		mockRepo.On("HasOverlappingDelegation", ctx, lecturerID, delegateID, mock.Anything, mock.Anything).Return(true, nil)
+
+		delegation, err := delegationService.DelegateVerification(ctx, userID, model.VerificationDelegationRequest{
+			DelegateID: delegateID, StartDate: today, EndDate: nextWeek,
+		})
+
+		assert.Nil(t, delegation)
+		assert.EqualError(t, err, "delegation to this lecturer already exists for an overlapping period")
+		mockRepo.AssertNotCalled(t, "CreateDelegation", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDelegationService_AssignSubstituteReviewer(t *testing.T) {
+	ctx := context.Background()
+
+	adminID := uuid.New()
+	advisorID := uuid.New()
+	substituteID := uuid.New()
+	today := time.Now().Format("2006-01-02")
+
+	t.Run("Admin assigns substitute", func(t *testing.T) {
+		mockRepo := new(mocks.MockDelegationRepository)
+		delegationService := service.NewDelegationService(mockRepo, nil)
+
+		mockRepo.On("LecturerExists", ctx, advisorID).Return(true, nil)
+		mockRepo.On("LecturerExists", ctx, substituteID).Return(true, nil)
+		mockRepo.On("HasOverlappingDelegation", ctx, advisorID, substituteID, mock.Anything, mock.Anything).Return(false, nil)
+		mockRepo.On("CreateDelegation", ctx, mock.AnythingOfType("*model.VerificationDelegation")).Return(nil)
+
+		delegation, err := delegationService.AssignSubstituteReviewer(ctx, adminID, model.VerificationDelegationRequest{
+			DelegatorID: &advisorID, DelegateID: substituteID, StartDate: today, EndDate: today,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.DelegationKindSubstitute, delegation.Kind)
+		assert.Equal(t, adminID, *delegation.CreatedBy)
+	})
+
+	t.Run("Delegator is required", func(t *testing.T) {
+		delegationService := service.NewDelegationService(new(mocks.MockDelegationRepository), nil)
+
+		delegation, err := delegationService.AssignSubstituteReviewer(ctx, adminID, model.VerificationDelegationRequest{
+			DelegateID: substituteID, StartDate: today, EndDate: today,
+		})
+
+		assert.Nil(t, delegation)
+		assert.EqualError(t, err, "delegator_id is required")
+	})
+}
+
+func TestDelegationService_RevokeDelegation(t *testing.T) {
+	ctx := context.Background()
+
+	userID := uuid.New()
+	lecturerID := uuid.New()
+	delegationID := uuid.New()
+
+	setup := func(kind string) (*mocks.MockDelegationRepository, service.DelegationService) {
+		mockRepo := new(mocks.MockDelegationRepository)
+		mockAchievementRepo := new(mocks.MockAchievementRepository)
+		mockAchievementRepo.On("GetLecturerByUserID", ctx, userID).Return(&model.Lecturers{ID: lecturerID, UserID: userID}, nil)
+		mockRepo.On("GetDelegationByID", ctx, delegationID).Return(&model.VerificationDelegation{ID: delegationID, DelegatorID: lecturerID, Kind: kind}, nil)
+		return mockRepo, service.NewDelegationService(mockRepo, mockAchievementRepo)
+	}
+
+	t.Run("Lecturer revokes own delegation", func(t *testing.T) {
+		mockRepo, delegationService := setup(model.DelegationKindDelegation)
+		mockRepo.On("RevokeDelegation", ctx, delegationID, mock.Anything).Return(nil)
+
+		err := delegationService.RevokeDelegation(ctx, userID, false, delegationID)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Lecturer cannot revoke substitute assigned by admin", func(t *testing.T) {
+		mockRepo, delegationService := setup(model.DelegationKindSubstitute)
+
+		err := delegationService.RevokeDelegation(ctx, userID, false, delegationID)
+
+		assert.EqualError(t, err, "unauthorized: substitute reviewers can only be revoked by admins")
+		mockRepo.AssertNotCalled(t, "RevokeDelegation", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Other lecturers do not see the delegation", func(t *testing.T) {
+		otherUserID := uuid.New()
+		mockRepo := new(mocks.MockDelegationRepository)
+		mockAchievementRepo := new(mocks.MockAchievementRepository)
+		mockAchievementRepo.On("GetLecturerByUserID", ctx, otherUserID).Return(&model.Lecturers{ID: uuid.New(), UserID: otherUserID}, nil)
+		mockRepo.On("GetDelegationByID", ctx, delegationID).Return(&model.VerificationDelegation{ID: delegationID, DelegatorID: lecturerID, Kind: model.DelegationKindDelegation}, nil)
+		delegationService := service.NewDelegationService(mockRepo, mockAchievementRepo)
+
+		err := delegationService.RevokeDelegation(ctx, otherUserID, false, delegationID)
+
+		assert.EqualError(t, err, "delegation not found")
+	})
+}
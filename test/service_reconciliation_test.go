@@ -0,0 +1,227 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/storage"
+	"UASBE/test/mocks"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// reconciliationFixture berisi satu contoh untuk setiap jenis drift, plus data yang sinkron
+type reconciliationFixture struct {
+	mockRepo            *mocks.MockReconciliationRepository
+	mockAchievementRepo *mocks.MockAchievementRepository
+	mockOutbox          *mocks.MockAchievementOutboxRepository
+
+	inSync, missingWithVersion, missingNoVersion, deletedDoc, undeletedDoc, mismatch model.ReconciliationReference
+	orphanDocID, otherStudentID                                                      uuid.UUID
+	orphanMongoID                                                                    string
+	latestSnapshot                                                                   *mongodb.Achievement
+}
+
+func newReconciliationFixture(ctx context.Context) *reconciliationFixture {
+	f := &reconciliationFixture{
+		mockRepo:            new(mocks.MockReconciliationRepository),
+		mockAchievementRepo: new(mocks.MockAchievementRepository),
+		mockOutbox:          new(mocks.MockAchievementOutboxRepository),
+		otherStudentID:      uuid.New(),
+		orphanMongoID:       primitive.NewObjectID().Hex(),
+	}
+	studentID := uuid.New()
+	ref := func(status string) model.ReconciliationReference {
+		return model.ReconciliationReference{ID: uuid.New(), StudentID: studentID, MongoAchievementID: primitive.NewObjectID().Hex(), Status: status}
+	}
+	f.inSync = ref("verified")
+	f.missingWithVersion = ref("draft")
+	f.missingNoVersion = ref("submitted")
+	f.deletedDoc = ref("draft")
+	f.undeletedDoc = ref("deleted")
+	f.mismatch = ref("verified")
+	pending := ref("draft")
+	pending.HasPendingOutbox = true
+	trashedAndPurged := ref("deleted")
+
+	refs := []model.ReconciliationReference{f.inSync, f.missingWithVersion, f.missingNoVersion, f.deletedDoc, f.undeletedDoc, f.mismatch, pending, trashedAndPurged}
+	old := time.Now().Add(-24 * time.Hour)
+	docs := map[string]model.ReconciliationDocument{
+		f.inSync.MongoAchievementID:       {MongoAchievementID: f.inSync.MongoAchievementID, StudentID: studentID, CreatedAt: old},
+		f.deletedDoc.MongoAchievementID:   {MongoAchievementID: f.deletedDoc.MongoAchievementID, StudentID: studentID, Deleted: true, CreatedAt: old},
+		f.undeletedDoc.MongoAchievementID: {MongoAchievementID: f.undeletedDoc.MongoAchievementID, StudentID: studentID, CreatedAt: old},
+		f.mismatch.MongoAchievementID:     {MongoAchievementID: f.mismatch.MongoAchievementID, StudentID: f.otherStudentID, CreatedAt: old},
+	}
+
+	f.mockRepo.On("GetReferencesForReconciliation", ctx, uuid.Nil, 500).Return(refs, nil)
+	f.mockRepo.On("GetReferencesForReconciliation", ctx, trashedAndPurged.ID, 500).Return([]model.ReconciliationReference{}, nil)
+	f.mockRepo.On("GetDocumentsByMongoIDs", ctx, mock.AnythingOfType("[]string")).Return(docs, nil)
+	f.latestSnapshot = &mongodb.Achievement{
+		Title:       "Juara 2 Hackathon",
+		Attachments: []mongodb.Attachment{{ID: "att-1", FileName: "sertifikat.pdf"}},
+	}
+	f.mockRepo.On("GetLatestAchievementSnapshot", ctx, f.missingWithVersion.ID).Return(f.latestSnapshot, nil)
+	f.mockRepo.On("GetLatestAchievementSnapshot", ctx, f.missingNoVersion.ID).Return(nil, nil)
+
+	// Dokumen: empat yang punya reference, satu orphan lama, satu dokumen baru (masih dalam grace period)
+	recentMongoID := primitive.NewObjectID().Hex()
+	scanned := []model.ReconciliationDocument{
+		docs[f.inSync.MongoAchievementID], docs[f.deletedDoc.MongoAchievementID],
+		docs[f.undeletedDoc.MongoAchievementID], docs[f.mismatch.MongoAchievementID],
+		{MongoAchievementID: f.orphanMongoID, StudentID: studentID, CreatedAt: old},
+		{MongoAchievementID: recentMongoID, StudentID: studentID, CreatedAt: time.Now()},
+	}
+	f.mockRepo.On("GetDocumentsForReconciliation", ctx, "", 500).Return(scanned, nil)
+	f.mockRepo.On("GetDocumentsForReconciliation", ctx, recentMongoID, 500).Return([]model.ReconciliationDocument{}, nil)
+	f.mockRepo.On("GetReferencedMongoIDs", ctx, mock.AnythingOfType("[]string")).Return(map[string]bool{
+		f.inSync.MongoAchievementID: true, f.deletedDoc.MongoAchievementID: true,
+		f.undeletedDoc.MongoAchievementID: true, f.mismatch.MongoAchievementID: true,
+	}, nil)
+
+	return f
+}
+
+func TestReconciliationService_DryRun(t *testing.T) {
+	ctx := context.Background()
+	f := newReconciliationFixture(ctx)
+	reconciliationService := service.NewReconciliationService(f.mockRepo, f.mockAchievementRepo, service.NewAchievementOutboxService(f.mockOutbox, nil), nil)
+
+	report, err := reconciliationService.Reconcile(ctx, false)
+
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 8, report.ReferencesScanned)
+	assert.Equal(t, 6, report.DocumentsScanned)
+	assert.Equal(t, 1, report.SkippedPending)
+	assert.Equal(t, map[string]int{
+		model.DriftMissingDocument:   2,
+		model.DriftDeletedDocument:   1,
+		model.DriftUndeletedDocument: 1,
+		model.DriftStudentMismatch:   1,
+		model.DriftOrphanDocument:    1,
+	}, report.Summary)
+
+	repairs := map[string]string{}
+	for _, issue := range report.Issues {
+		assert.False(t, issue.Repaired)
+		if issue.AchievementID != nil {
+			repairs[issue.AchievementID.String()] = issue.Repair
+		} else {
+			repairs[issue.MongoAchievementID] = issue.Repair
+			assert.Equal(t, f.orphanMongoID, issue.MongoAchievementID)
+		}
+		if issue.Type == model.DriftStudentMismatch {
+			assert.Equal(t, f.otherStudentID, *issue.DocumentStudentID)
+			assert.Equal(t, f.mismatch.StudentID, *issue.ReferenceStudentID)
+		}
+	}
+	assert.Equal(t, map[string]string{
+		f.missingWithVersion.ID.String(): model.RepairRecreateDocument,
+		f.missingNoVersion.ID.String():   model.RepairMoveReferenceToTrash,
+		f.deletedDoc.ID.String():         model.RepairRestoreDocument,
+		f.undeletedDoc.ID.String():       model.RepairSoftDeleteDocument,
+		f.mismatch.ID.String():           model.RepairSetDocumentStudent,
+		f.orphanMongoID:                  model.RepairDeleteDocument,
+	}, repairs)
+
+	// Dry-run tidak boleh mengubah apa pun
+	f.mockRepo.AssertNotCalled(t, "EnqueueDocumentUpsert", mock.Anything, mock.Anything, mock.Anything)
+	f.mockRepo.AssertNotCalled(t, "EnqueueDocumentOperation", mock.Anything, mock.Anything, mock.Anything)
+	f.mockRepo.AssertNotCalled(t, "MoveReferenceToTrash", mock.Anything, mock.Anything, mock.Anything)
+	f.mockRepo.AssertNotCalled(t, "DeleteOrphanDocument", mock.Anything, mock.Anything)
+	f.mockOutbox.AssertNotCalled(t, "GetPendingOutboxEventsByAchievement", mock.Anything, mock.Anything)
+}
+
+func TestReconciliationService_Repair(t *testing.T) {
+	ctx := context.Background()
+	f := newReconciliationFixture(ctx)
+
+	store, err := storage.NewLocalStorage(t.TempDir())
+	assert.NoError(t, err)
+	orphanFile, err := store.Put(ctx, bytes.NewReader([]byte("orphan attachment")), "application/pdf")
+	assert.NoError(t, err)
+
+	keptFile, err := store.Put(ctx, bytes.NewReader([]byte("still stored")), "application/pdf")
+	assert.NoError(t, err)
+
+	// Versi terakhir: attachment dari versi lama tanpa storage key, satu file masih ada, satu file sudah hilang
+	f.latestSnapshot.Attachments = []mongodb.Attachment{
+		{ID: "att-1", FileName: "sertifikat.pdf"},
+		{ID: "att-2", FileName: "foto.jpg", StorageKey: keptFile.Key},
+		{ID: "att-3", FileName: "piagam.pdf", StorageKey: "ab/cd/missing"},
+	}
+
+	reconciliationService := service.NewReconciliationService(f.mockRepo, f.mockAchievementRepo, service.NewAchievementOutboxService(f.mockOutbox, nil), store)
+	f.mockOutbox.On("WithAchievementOutboxLock", ctx, mock.AnythingOfType("uuid.UUID")).Return(true, nil)
+	f.mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, mock.AnythingOfType("uuid.UUID")).Return([]model.AchievementOutboxEvent{}, nil)
+
+	// Dokumen dibuat ulang dari versi terakhir: ID dan pemilik dari reference, hanya attachment yang file-nya ada ditautkan
+	f.mockRepo.On("EnqueueDocumentUpsert", ctx, f.missingWithVersion.ID, mock.MatchedBy(func(a mongodb.Achievement) bool {
+		return a.ID.Hex() == f.missingWithVersion.MongoAchievementID && a.StudentID == f.missingWithVersion.StudentID &&
+			a.Title == "Juara 2 Hackathon" && len(a.Attachments) == 1 && a.Attachments[0].StorageKey == keptFile.Key
+	})).Return(nil)
+	f.mockRepo.On("MoveReferenceToTrash", ctx, f.missingNoVersion.ID, "Reconciliation: MongoDB document missing").Return(nil)
+	f.mockRepo.On("EnqueueDocumentOperation", ctx, f.deletedDoc.ID, model.OutboxOpRestoreDocument).Return(nil)
+	f.mockRepo.On("EnqueueDocumentOperation", ctx, f.undeletedDoc.ID, model.OutboxOpSoftDeleteDocument).Return(errors.New("connection reset"))
+
+	f.mockAchievementRepo.On("GetAchievementDetailFromMongo", ctx, f.mismatch.MongoAchievementID).Return(&mongodb.Achievement{
+		StudentID:   f.otherStudentID,
+		Attachments: []mongodb.Attachment{{ID: "att-9", StorageKey: "kept"}},
+	}, nil)
+	f.mockRepo.On("EnqueueDocumentUpsert", ctx, f.mismatch.ID, mock.MatchedBy(func(a mongodb.Achievement) bool {
+		return a.StudentID == f.mismatch.StudentID && a.Attachments[0].StorageKey == "kept"
+	})).Return(nil)
+
+	f.mockAchievementRepo.On("GetAchievementDetailFromMongo", ctx, f.orphanMongoID).Return(&mongodb.Achievement{
+		Attachments: []mongodb.Attachment{{StorageKey: orphanFile.Key}},
+	}, nil)
+	f.mockRepo.On("DeleteOrphanDocument", ctx, f.orphanMongoID).Return(nil)
+	f.mockAchievementRepo.On("CountAttachmentsByStorageKey", ctx, orphanFile.Key).Return(int64(0), nil)
+
+	report, err := reconciliationService.Reconcile(ctx, true)
+
+	assert.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, 5, report.Repaired)
+	assert.Equal(t, 1, report.RepairFailed)
+	assert.Equal(t, 2, report.AttachmentsLost)
+	for _, issue := range report.Issues {
+		if issue.Repair == model.RepairRecreateDocument {
+			assert.Equal(t, []model.LostAttachment{
+				{AttachmentID: "att-1", FileName: "sertifikat.pdf"},
+				{AttachmentID: "att-3", FileName: "piagam.pdf", StorageKey: "ab/cd/missing"},
+			}, issue.LostAttachments)
+		}
+		if issue.Type == model.DriftUndeletedDocument {
+			assert.False(t, issue.Repaired)
+			assert.Equal(t, "connection reset", *issue.Error)
+		} else {
+			assert.True(t, issue.Repaired, issue.Type)
+		}
+	}
+
+	_, _, err = store.Open(ctx, orphanFile.Key)
+	assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+	f.mockRepo.AssertExpectations(t)
+	f.mockAchievementRepo.AssertExpectations(t)
+}
+
+func TestReconciliationService_ScanFailure(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.MockReconciliationRepository)
+	reconciliationService := service.NewReconciliationService(mockRepo, nil, nil, nil)
+
+	mockRepo.On("GetReferencesForReconciliation", ctx, uuid.Nil, 500).Return(nil, errors.New("connection refused"))
+
+	_, err := reconciliationService.Reconcile(ctx, false)
+
+	assert.EqualError(t, err, "failed to scan achievement references")
+}
@@ -0,0 +1,192 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReviewSLAService_SetReviewSLA(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - level normalized", func(t *testing.T) {
+		mockRepo := new(mocks.MockReviewSLARepository)
+		mockRoleRepo := new(mocks.MockApprovalChainRepository)
+		slaService := service.NewReviewSLAService(mockRepo, mockRoleRepo)
+
+		mockRoleRepo.On("RoleExists", ctx, "Admin").Return(true, nil)
+		mockRepo.On("UpsertReviewSLA", ctx, mock.MatchedBy(func(sla *model.ReviewSLA) bool {
+			return sla.Level == "international" && sla.DeadlineHours == 48 && sla.EscalateToRole == "Admin"
+		})).Return(nil)
+
+		sla, err := slaService.SetReviewSLA(ctx, " International ", model.ReviewSLARequest{DeadlineHours: 48, EscalateToRole: " Admin "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "international", sla.Level)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - unknown level", func(t *testing.T) {
+		mockRepo := new(mocks.MockReviewSLARepository)
+		slaService := service.NewReviewSLAService(mockRepo, new(mocks.MockApprovalChainRepository))
+
+		sla, err := slaService.SetReviewSLA(ctx, "galactic", model.ReviewSLARequest{DeadlineHours: 48, EscalateToRole: "Admin"})
+
+		assert.Error(t, err)
+		assert.Nil(t, sla)
+		assert.Contains(t, err.Error(), "level must be one of")
+		mockRepo.AssertNotCalled(t, "UpsertReviewSLA", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - deadline out of range", func(t *testing.T) {
+		slaService := service.NewReviewSLAService(new(mocks.MockReviewSLARepository), new(mocks.MockApprovalChainRepository))
+
+		_, err := slaService.SetReviewSLA(ctx, "default", model.ReviewSLARequest{DeadlineHours: 0, EscalateToRole: "Admin"})
+
+		assert.EqualError(t, err, "deadline_hours must be between 1 and 2160")
+	})
+
+	t.Run("Error - unknown escalation role", func(t *testing.T) {
+		mockRoleRepo := new(mocks.MockApprovalChainRepository)
+		slaService := service.NewReviewSLAService(new(mocks.MockReviewSLARepository), mockRoleRepo)
+
+		mockRoleRepo.On("RoleExists", ctx, "Rektor").Return(false, nil)
+
+		_, err := slaService.SetReviewSLA(ctx, "default", model.ReviewSLARequest{DeadlineHours: 24, EscalateToRole: "Rektor"})
+
+		assert.EqualError(t, err, "unknown escalation role: Rektor")
+	})
+}
+
+func TestReviewSLAService_DeleteReviewSLA(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Error - default cannot be deleted", func(t *testing.T) {
+		mockRepo := new(mocks.MockReviewSLARepository)
+		slaService := service.NewReviewSLAService(mockRepo, new(mocks.MockApprovalChainRepository))
+
+		err := slaService.DeleteReviewSLA(ctx, "default")
+
+		assert.EqualError(t, err, "default review SLA cannot be deleted")
+		mockRepo.AssertNotCalled(t, "DeleteReviewSLA", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - not found", func(t *testing.T) {
+		mockRepo := new(mocks.MockReviewSLARepository)
+		slaService := service.NewReviewSLAService(mockRepo, new(mocks.MockApprovalChainRepository))
+
+		mockRepo.On("GetReviewSLA", ctx, "national").Return(nil, nil)
+
+		err := slaService.DeleteReviewSLA(ctx, "national")
+
+		assert.EqualError(t, err, "review SLA not found")
+	})
+}
+
+func TestReviewSLAService_EscalateOverdueAchievements(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(mocks.MockReviewSLARepository)
+		slaService := service.NewReviewSLAService(mockRepo, new(mocks.MockApprovalChainRepository))
+
+		escalations := []model.AchievementEscalation{{AchievementID: uuid.New(), EscalatedTo: "Admin", ReviewDueAt: time.Now().Add(-time.Hour)}}
+		mockRepo.On("EscalateOverdueAchievements", ctx, mock.AnythingOfType("time.Time")).Return(escalations, nil)
+
+		result, err := slaService.EscalateOverdueAchievements(ctx)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+	})
+
+	t.Run("Error - repository failure", func(t *testing.T) {
+		mockRepo := new(mocks.MockReviewSLARepository)
+		slaService := service.NewReviewSLAService(mockRepo, new(mocks.MockApprovalChainRepository))
+
+		mockRepo.On("EscalateOverdueAchievements", ctx, mock.AnythingOfType("time.Time")).Return(nil, errors.New("db down"))
+
+		result, err := slaService.EscalateOverdueAchievements(ctx)
+
+		assert.EqualError(t, err, "failed to escalate overdue achievements")
+		assert.Nil(t, result)
+	})
+}
+
+func TestReviewSLAService_GetReviewerThroughput(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - date_to is inclusive", func(t *testing.T) {
+		mockRepo := new(mocks.MockReviewSLARepository)
+		slaService := service.NewReviewSLAService(mockRepo, new(mocks.MockApprovalChainRepository))
+
+		mockRepo.On("GetReviewerThroughput", ctx, mock.MatchedBy(func(from *time.Time) bool {
+			return from != nil && from.Format("2006-01-02") == "2026-01-01"
+		}), mock.MatchedBy(func(to *time.Time) bool {
+			return to != nil && to.Format("2006-01-02") == "2026-02-01"
+		})).Return([]model.ReviewerThroughput{{ReviewerName: "Dr. Budi", Decisions: 3}}, nil)
+
+		result, err := slaService.GetReviewerThroughput(ctx, "2026-01-01", "2026-01-31")
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+	})
+
+	t.Run("Error - invalid date", func(t *testing.T) {
+		slaService := service.NewReviewSLAService(new(mocks.MockReviewSLARepository), new(mocks.MockApprovalChainRepository))
+
+		_, err := slaService.GetReviewerThroughput(ctx, "01-01-2026", "")
+
+		assert.EqualError(t, err, "invalid date_from format, use YYYY-MM-DD")
+	})
+}
+
+func TestAchievementService_VerifyEscalatedAchievement(t *testing.T) {
+	ctx := context.Background()
+
+	userID := uuid.New()
+	studentID := uuid.New()
+	achievementID := uuid.New()
+	stageID := uuid.New()
+	escalatedTo := "Admin"
+
+	mockRepo := new(mocks.MockAchievementRepository)
+	mockScoringRepo := new(mocks.MockScoringRepository)
+	mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+	mockOutbox := new(mocks.MockAchievementOutboxRepository)
+	achievementService := service.NewAchievementService(mockRepo, mockTypeRepo, mockScoringRepo, nil, new(mocks.MockDelegationRepository), nil, service.NewAchievementOutboxService(mockOutbox, nil))
+
+	mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+		ID: achievementID, StudentID: studentID, MongoAchievementID: "mongo_id", Status: "submitted", EscalatedTo: &escalatedTo,
+	}, nil).Once()
+	mockRepo.On("GetAchievementApprovals", ctx, achievementID, 0).Return([]model.AchievementApproval{
+		{ID: stageID, StageOrder: 1, ApproverRole: model.ApproverRoleAdvisor, Status: "pending"},
+	}, nil)
+	mockRepo.On("GetUserRoleName", ctx, userID).Return("admin", nil)
+	mockRepo.On("GetLecturerByUserID", ctx, userID).Return(nil, errors.New("not found"))
+	mockRepo.On("GetLatestAchievementSnapshot", ctx, achievementID).Return(&mongodb.Achievement{AchievementType: "other"}, nil)
+	mockScoringRepo.On("GetActiveScoringRuleSet", ctx).Return(&model.ScoringRuleSet{Version: 1, IsActive: true}, nil)
+	mockTypeRepo.On("GetAchievementTypeByCode", ctx, "other").Return(&model.AchievementType{Code: "other", DefaultPoints: 5}, nil)
+	mockRepo.On("UpdateAchievementStatusToVerified", ctx, achievementID, userID, userID, mock.AnythingOfType("model.AchievementScore"),
+		mock.MatchedBy(func(d model.ApprovalDecision) bool {
+			return d.ApprovalID == stageID && d.DecidedBy == userID && d.OnBehalfOf == nil
+		})).Return(nil)
+	mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+	mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{}, nil)
+	mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{ID: achievementID, Status: "verified"}, nil).Once()
+
+	result, err := achievementService.VerifyAchievement(ctx, userID, achievementID, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "verified", result.Status)
+	mockRepo.AssertNotCalled(t, "GetStudentByID", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,185 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScoringService_CreateScoringRuleSet(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("Keys are normalized and rule set is activated", func(t *testing.T) {
+		mockRepo := new(mocks.MockScoringRepository)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		scoringService := service.NewScoringService(mockRepo, nil, mockTypeRepo, nil)
+
+		// Poin dasar katalog ikut disimpan di versi baru; override dari request tidak ditimpa
+		mockTypeRepo.On("GetAllAchievementTypes", ctx, false).Return([]model.AchievementType{
+			{Code: "competition", DefaultPoints: 20},
+			{Code: "publication", DefaultPoints: 25},
+		}, nil)
+		mockRepo.On("CreateScoringRuleSet", ctx, mock.MatchedBy(func(rs *model.ScoringRuleSet) bool {
+			return rs.IsActive && rs.ActivatedAt != nil && *rs.CreatedBy == userID &&
+				rs.Rules.CompetitionLevelPoints["national"] == 35 &&
+				rs.Rules.TypePoints["competition"] == 20 && rs.Rules.TypePoints["publication"] == 30
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*model.ScoringRuleSet).Version = 2
+		}).Return(nil)
+
+		result, err := scoringService.CreateScoringRuleSet(ctx, userID, model.CreateScoringRuleSetRequest{
+			Description: "2026 rules",
+			Rules: model.ScoringRules{
+				CompetitionLevelPoints: map[string]int{" National ": 35},
+				TypePoints:             map[string]int{"Publication": 30},
+			},
+			Activate: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Version)
+		mockRepo.AssertExpectations(t)
+		mockTypeRepo.AssertExpectations(t)
+	})
+
+	t.Run("Negative points rejected", func(t *testing.T) {
+		mockRepo := new(mocks.MockScoringRepository)
+		scoringService := service.NewScoringService(mockRepo, nil, nil, nil)
+
+		result, err := scoringService.CreateScoringRuleSet(ctx, userID, model.CreateScoringRuleSetRequest{
+			Rules: model.ScoringRules{MedalTypePoints: map[string]int{"gold": -1}},
+		})
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "scoring points must not be negative")
+	})
+
+	t.Run("Invalid rank key rejected", func(t *testing.T) {
+		mockRepo := new(mocks.MockScoringRepository)
+		scoringService := service.NewScoringService(mockRepo, nil, nil, nil)
+
+		result, err := scoringService.CreateScoringRuleSet(ctx, userID, model.CreateScoringRuleSetRequest{
+			Rules: model.ScoringRules{RankPoints: map[string]int{"first": 30}},
+		})
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "rank_points keys must be positive integers")
+	})
+}
+
+func TestScoringService_RecalculateScores(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("Recalculates verified achievements with a historical version", func(t *testing.T) {
+		mockRepo := new(mocks.MockScoringRepository)
+		mockAchievementRepo := new(mocks.MockAchievementRepository)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		scoringService := service.NewScoringService(mockRepo, mockAchievementRepo, mockTypeRepo, service.NewAchievementOutboxService(mockOutbox, nil))
+
+		okID, brokenID := uuid.New(), uuid.New()
+		refs := []model.AchievementReference{
+			{ID: okID, MongoAchievementID: "507f1f77bcf86cd799439011", Status: "verified"},
+			{ID: brokenID, MongoAchievementID: "507f1f77bcf86cd799439012", Status: "verified"},
+		}
+		ruleSet := &model.ScoringRuleSet{
+			Version: 1,
+			Rules:   model.ScoringRules{PublicationTypePoints: map[string]int{"journal": 40}},
+		}
+
+		mockRepo.On("GetScoringRuleSetByVersion", ctx, 1).Return(ruleSet, nil)
+		mockAchievementRepo.On("GetAchievementReferencesByStatus", ctx, "verified").Return(refs, nil)
+		mockAchievementRepo.On("GetLatestAchievementSnapshot", ctx, okID).Return(&mongodb.Achievement{
+			AchievementType: "publication",
+			Details:         mongodb.AchievementDetails{PublicationType: strPtr("journal")},
+		}, nil)
+		mockAchievementRepo.On("GetLatestAchievementSnapshot", ctx, brokenID).Return(nil, nil)
+		mockAchievementRepo.On("GetAchievementDetailFromMongo", ctx, refs[1].MongoAchievementID).Return(nil, errors.New("mongo: no documents in result"))
+		mockTypeRepo.On("GetAchievementTypeByCode", ctx, "publication").Return(&model.AchievementType{Code: "publication", DefaultPoints: 25}, nil)
+		mockRepo.On("SaveAchievementScore", ctx, mock.MatchedBy(func(score model.AchievementScore) bool {
+			return score.AchievementID == okID && score.RuleVersion == 1 && score.Points == 65
+		})).Return(nil)
+		// Poin di MongoDB ditulis lewat outbox setelah skor tersimpan
+		mockOutbox.On("WithAchievementOutboxLock", ctx, okID).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, okID).Return([]model.AchievementOutboxEvent{}, nil)
+
+		version := 1
+		result, err := scoringService.RecalculateScores(ctx, userID, &version)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.RecalculateScoresResult{
+			RuleVersion: 1,
+			Processed:   2,
+			Updated:     1,
+			Failed:      1,
+			FailedIDs:   []uuid.UUID{brokenID},
+		}, result)
+
+		mockRepo.AssertExpectations(t)
+		mockAchievementRepo.AssertExpectations(t)
+		mockTypeRepo.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
+	})
+
+	t.Run("Base points come from the rule set version, not the current catalog", func(t *testing.T) {
+		mockRepo := new(mocks.MockScoringRepository)
+		mockAchievementRepo := new(mocks.MockAchievementRepository)
+		mockTypeRepo := new(mocks.MockAchievementTypeRepository)
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		scoringService := service.NewScoringService(mockRepo, mockAchievementRepo, mockTypeRepo, service.NewAchievementOutboxService(mockOutbox, nil))
+
+		achievementID := uuid.New()
+		ref := model.AchievementReference{ID: achievementID, MongoAchievementID: "507f1f77bcf86cd799439011", Status: "verified"}
+		ruleSet := &model.ScoringRuleSet{
+			Version: 1,
+			Rules: model.ScoringRules{
+				TypePoints:            map[string]int{"publication": 25},
+				PublicationTypePoints: map[string]int{"journal": 40},
+			},
+		}
+
+		mockRepo.On("GetScoringRuleSetByVersion", ctx, 1).Return(ruleSet, nil)
+		mockAchievementRepo.On("GetAchievementReferencesByStatus", ctx, "verified").Return([]model.AchievementReference{ref}, nil)
+		mockAchievementRepo.On("GetLatestAchievementSnapshot", ctx, achievementID).Return(nil, nil)
+		mockAchievementRepo.On("GetAchievementDetailFromMongo", ctx, ref.MongoAchievementID).Return(&mongodb.Achievement{
+			AchievementType: "publication",
+			Details:         mongodb.AchievementDetails{PublicationType: strPtr("journal")},
+		}, nil)
+		mockRepo.On("SaveAchievementScore", ctx, mock.MatchedBy(func(score model.AchievementScore) bool {
+			return score.AchievementID == achievementID && score.Points == 65
+		})).Return(nil)
+		mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{}, nil)
+
+		version := 1
+		result, err := scoringService.RecalculateScores(ctx, userID, &version)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Updated)
+		mockTypeRepo.AssertNotCalled(t, "GetAchievementTypeByCode", mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown version", func(t *testing.T) {
+		mockRepo := new(mocks.MockScoringRepository)
+		scoringService := service.NewScoringService(mockRepo, nil, nil, nil)
+
+		mockRepo.On("GetScoringRuleSetByVersion", ctx, 9).Return(nil, nil)
+
+		version := 9
+		result, err := scoringService.RecalculateScores(ctx, userID, &version)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "scoring rule set not found")
+	})
+}
@@ -0,0 +1,170 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/app/repository"
+	"UASBE/app/service"
+	"UASBE/storage"
+	"UASBE/test/mocks"
+	"UASBE/utils"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestTrashService_RestoreAchievement(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+	achievementID := uuid.New()
+
+	t.Run("Success - reference then document restored to draft", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		mockOutbox := new(mocks.MockAchievementOutboxRepository)
+		trashService := service.NewTrashService(mockRepo, service.NewAchievementOutboxService(mockOutbox, nil), nil)
+
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+			ID: achievementID, MongoAchievementID: "mongo_id", Status: "deleted",
+		}, nil).Once()
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(&mongodb.Achievement{}, nil)
+		mockRepo.On("UpdateAchievementReferenceToRestored", ctx, achievementID, adminID).Return(nil)
+		mockOutbox.On("WithAchievementOutboxLock", ctx, achievementID).Return(true, nil)
+		mockOutbox.On("GetPendingOutboxEventsByAchievement", ctx, achievementID).Return([]model.AchievementOutboxEvent{}, nil)
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{ID: achievementID, Status: "draft"}, nil).Once()
+
+		result, err := trashService.RestoreAchievement(ctx, adminID, achievementID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "draft", result.Status)
+		mockRepo.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
+	})
+
+	t.Run("Error - not in trash", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		trashService := service.NewTrashService(mockRepo, nil, nil)
+
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{ID: achievementID, Status: "draft"}, nil)
+
+		_, err := trashService.RestoreAchievement(ctx, adminID, achievementID)
+
+		assert.EqualError(t, err, "only deleted achievements can be restored")
+		mockRepo.AssertNotCalled(t, "UpdateAchievementReferenceToRestored", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - restored concurrently", func(t *testing.T) {
+		mockRepo := new(mocks.MockAchievementRepository)
+		trashService := service.NewTrashService(mockRepo, nil, nil)
+
+		mockRepo.On("GetAchievementReferenceByID", ctx, achievementID).Return(&model.AchievementReference{
+			ID: achievementID, MongoAchievementID: "mongo_id", Status: "deleted",
+		}, nil)
+		mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(&mongodb.Achievement{}, nil)
+		mockRepo.On("UpdateAchievementReferenceToRestored", ctx, achievementID, adminID).Return(repository.ErrAchievementNotDeleted)
+
+		_, err := trashService.RestoreAchievement(ctx, adminID, achievementID)
+
+		assert.EqualError(t, err, "only deleted achievements can be restored")
+	})
+}
+
+func TestTrashService_GetTrash(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := new(mocks.MockAchievementRepository)
+	trashService := service.NewTrashService(mockRepo, nil, nil)
+
+	deletedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	item := model.TrashedAchievement{DeletedAt: deletedAt}
+	item.MongoAchievementID = "mongo_id"
+	mockRepo.On("GetDeletedAchievements", ctx, 1, 10).Return([]model.TrashedAchievement{item}, 1, nil)
+	mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_id").Return(&mongodb.Achievement{Title: "Lomba"}, nil)
+
+	result, err := trashService.GetTrash(ctx, 0, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, utils.TrashRetentionDays, result.RetentionDays)
+	assert.Equal(t, deletedAt.AddDate(0, 0, utils.TrashRetentionDays), result.Achievements[0].PurgeAfter)
+	assert.Equal(t, "Lomba", result.Achievements[0].Details.Title)
+}
+
+func TestTrashService_PurgeExpiredAchievements(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := storage.NewLocalStorage(t.TempDir())
+	assert.NoError(t, err)
+	unique, err := store.Put(ctx, bytes.NewReader([]byte("only used here")), "application/pdf")
+	assert.NoError(t, err)
+	shared, err := store.Put(ctx, bytes.NewReader([]byte("also attached elsewhere")), "application/pdf")
+	assert.NoError(t, err)
+	restoredFile, err := store.Put(ctx, bytes.NewReader([]byte("restored achievement")), "application/pdf")
+	assert.NoError(t, err)
+
+	mockRepo := new(mocks.MockAchievementRepository)
+	trashService := service.NewTrashService(mockRepo, nil, store)
+
+	expired := model.AchievementReference{ID: uuid.New(), MongoAchievementID: "mongo_expired", Status: "deleted"}
+	orphan := model.AchievementReference{ID: uuid.New(), MongoAchievementID: "mongo_gone", Status: "deleted"}
+	broken := model.AchievementReference{ID: uuid.New(), MongoAchievementID: "mongo_broken", Status: "deleted"}
+	pgFailed := model.AchievementReference{ID: uuid.New(), MongoAchievementID: "mongo_pg_failed", Status: "deleted"}
+	restored := model.AchievementReference{ID: uuid.New(), MongoAchievementID: "mongo_restored", Status: "deleted"}
+
+	mockRepo.On("GetAchievementsDeletedBefore", ctx, mock.MatchedBy(func(before time.Time) bool {
+		cutoff := time.Now().AddDate(0, 0, -utils.TrashRetentionDays)
+		return before.Sub(cutoff) < time.Minute && cutoff.Sub(before) < time.Minute
+	}), 200).Return([]model.AchievementReference{expired, orphan, broken, pgFailed, restored}, nil)
+
+	// Prestasi dengan dua attachment: satu file unik, satu file dipakai prestasi lain
+	mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_expired").Return(&mongodb.Achievement{
+		Attachments: []mongodb.Attachment{{StorageKey: unique.Key}, {StorageKey: shared.Key}},
+	}, nil)
+	mockRepo.On("PurgeAchievementMongo", ctx, "mongo_expired").Return(nil)
+	mockRepo.On("PurgeAchievementReference", ctx, expired.ID).Return(nil)
+	mockRepo.On("CountAttachmentsByStorageKey", ctx, unique.Key).Return(int64(0), nil)
+	mockRepo.On("CountAttachmentsByStorageKey", ctx, shared.Key).Return(int64(1), nil)
+
+	// Dokumen sudah tidak ada (jalan sebelumnya terputus): reference tetap dihapus
+	mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_gone").Return(nil, mongo.ErrNoDocuments)
+	mockRepo.On("PurgeAchievementReference", ctx, orphan.ID).Return(nil)
+
+	// MongoDB gagal: item dilewati dan dicoba lagi di jalan berikutnya
+	mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_broken").Return(nil, errors.New("connection reset"))
+
+	// Reference gagal dihapus: dokumen MongoDB tidak disentuh agar reference tidak menunjuk dokumen yang hilang
+	mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_pg_failed").Return(&mongodb.Achievement{}, nil)
+	mockRepo.On("PurgeAchievementReference", ctx, pgFailed.ID).Return(errors.New("db error"))
+
+	// Dipulihkan admin setelah terpilih: dokumen (masih deleted_at karena outbox belum jalan) dan file tetap utuh
+	mockRepo.On("GetAchievementDetailFromMongo", ctx, "mongo_restored").Return(&mongodb.Achievement{
+		Attachments: []mongodb.Attachment{{StorageKey: restoredFile.Key}},
+	}, nil)
+	mockRepo.On("PurgeAchievementReference", ctx, restored.ID).Return(repository.ErrAchievementNotDeleted)
+
+	result, err := trashService.PurgeExpiredAchievements(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Purged)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, 1, result.FilesDeleted)
+	mockRepo.AssertNotCalled(t, "PurgeAchievementReference", ctx, broken.ID)
+	mockRepo.AssertNotCalled(t, "PurgeAchievementMongo", ctx, "mongo_pg_failed")
+	mockRepo.AssertNotCalled(t, "PurgeAchievementMongo", ctx, "mongo_restored")
+	mockRepo.AssertNotCalled(t, "CountAttachmentsByStorageKey", ctx, restoredFile.Key)
+
+	_, _, err = store.Open(ctx, unique.Key)
+	assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+	reader, _, err := store.Open(ctx, shared.Key)
+	assert.NoError(t, err)
+	reader.Close()
+	reader, _, err = store.Open(ctx, restoredFile.Key)
+	assert.NoError(t, err)
+	reader.Close()
+}
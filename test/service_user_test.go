@@ -177,7 +177,7 @@ func TestUserService_GetUsers(t *testing.T) {
 		mockRepo := new(mocks.MockUserRepository)
 		userService := service.NewUserService(mockRepo)
 
-		users := []*model.Users{
+		users := []model.Users{
 			{ID: uuid.New(), Username: "user1", FullName: "User One"},
 			{ID: uuid.New(), Username: "user2", FullName: "User Two"},
 		}
@@ -201,7 +201,7 @@ func TestUserService_GetUsers(t *testing.T) {
 		mockRepo := new(mocks.MockUserRepository)
 		userService := service.NewUserService(mockRepo)
 
-		users := []*model.Users{}
+		users := []model.Users{}
 		roleNames := []string{}
 
 		mockRepo.On("GetAllUsers", ctx, 1, 10).Return(users, roleNames, 0, nil)
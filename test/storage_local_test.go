@@ -0,0 +1,80 @@
+package test
+
+import (
+	"UASBE/storage"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	store, err := storage.NewLocalStorage(root)
+	assert.NoError(t, err)
+
+	content := "%PDF-1.4 sertifikat lomba"
+	sum := sha256.Sum256([]byte(content))
+	checksum := hex.EncodeToString(sum[:])
+
+	t.Run("Put stores content-addressed file", func(t *testing.T) {
+		obj, err := store.Put(ctx, strings.NewReader(content), "application/pdf")
+		assert.NoError(t, err)
+		assert.Equal(t, checksum, obj.Checksum)
+		assert.Equal(t, int64(len(content)), obj.Size)
+		assert.Equal(t, checksum[0:2]+"/"+checksum[2:4]+"/"+checksum, obj.Key)
+		assert.Equal(t, "local://"+obj.Key, obj.URI)
+
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(obj.Key)))
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(data))
+
+		// Tidak ada file temp yang tertinggal
+		tmpFiles, _ := os.ReadDir(filepath.Join(root, ".tmp"))
+		assert.Empty(t, tmpFiles)
+	})
+
+	t.Run("Identical content is stored once", func(t *testing.T) {
+		obj1, err := store.Put(ctx, strings.NewReader(content), "application/pdf")
+		assert.NoError(t, err)
+		obj2, err := store.Put(ctx, strings.NewReader(content), "application/pdf")
+		assert.NoError(t, err)
+		assert.Equal(t, obj1.Key, obj2.Key)
+	})
+
+	t.Run("Open reads back content", func(t *testing.T) {
+		obj, _ := store.Put(ctx, strings.NewReader(content), "application/pdf")
+
+		reader, info, err := store.Open(ctx, obj.Key)
+		assert.NoError(t, err)
+		defer reader.Close()
+
+		data, _ := io.ReadAll(reader)
+		assert.Equal(t, content, string(data))
+		assert.Equal(t, int64(len(content)), info.Size)
+	})
+
+	t.Run("Open rejects invalid keys", func(t *testing.T) {
+		_, _, err := store.Open(ctx, "../../etc/passwd")
+		assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+	})
+
+	t.Run("Delete removes file", func(t *testing.T) {
+		obj, _ := store.Put(ctx, strings.NewReader("to be deleted"), "text/plain")
+
+		assert.NoError(t, store.Delete(ctx, obj.Key))
+		_, _, err := store.Open(ctx, obj.Key)
+		assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+
+		// Menghapus key yang sudah tidak ada bukan error
+		assert.NoError(t, store.Delete(ctx, obj.Key))
+	})
+}
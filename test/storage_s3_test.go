@@ -0,0 +1,53 @@
+package test
+
+import (
+	"UASBE/storage"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestS3Storage berjalan terhadap MinIO lokal, contoh:
+//
+//	docker run -p 9000:9000 minio/minio server /data
+//	S3_TEST_ENDPOINT=localhost:9000 S3_TEST_ACCESS_KEY=minioadmin S3_TEST_SECRET_KEY=minioadmin go test ./test -run TestS3Storage
+func TestS3Storage(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT not set")
+	}
+
+	ctx := context.Background()
+	store, err := storage.NewS3Storage(storage.S3Options{
+		Endpoint:  endpoint,
+		AccessKey: os.Getenv("S3_TEST_ACCESS_KEY"),
+		SecretKey: os.Getenv("S3_TEST_SECRET_KEY"),
+		Bucket:    "uasbe-test",
+	})
+	assert.NoError(t, err)
+
+	content := "%PDF-1.4 sertifikat lomba s3"
+
+	obj, err := store.Put(ctx, strings.NewReader(content), "application/pdf")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), obj.Size)
+
+	again, err := store.Put(ctx, strings.NewReader(content), "application/pdf")
+	assert.NoError(t, err)
+	assert.Equal(t, obj.Key, again.Key)
+
+	reader, info, err := store.Open(ctx, obj.Key)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	assert.Equal(t, content, string(data))
+	assert.Equal(t, "application/pdf", info.ContentType)
+
+	assert.NoError(t, store.Delete(ctx, obj.Key))
+	_, _, err = store.Open(ctx, obj.Key)
+	assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+}
@@ -0,0 +1,60 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	"UASBE/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAchievementFieldPath_AcceptsDiffPaths(t *testing.T) {
+	level, medal := "national", "gold"
+	before := mongodb.Achievement{Title: "Lomba"}
+	after := mongodb.Achievement{
+		AchievementType: "organization",
+		Title:           "Ketua Himpunan",
+		Description:     "Periode kepengurusan",
+		Details: mongodb.AchievementDetails{
+			CompetitionLevel: &level,
+			MedalType:        &medal,
+			Authors:          []string{"Budi"},
+			Period:           &mongodb.Period{Start: time.Now(), End: time.Now().AddDate(1, 0, 0)},
+		},
+		CustomFields: map[string]interface{}{"jurusan": "TI", "sk": map[string]interface{}{"nomor": "12/2024"}},
+		Attachments:  []mongodb.Attachment{{ID: "6650f1c2-aa01-4b8e-9c1d-2f3e4a5b6c7d", FileName: "sk.pdf"}},
+		Tags:         []string{"organisasi"},
+	}
+
+	changes, err := utils.DiffAchievements(before, after)
+	assert.NoError(t, err)
+
+	paths := []string{}
+	for _, change := range changes {
+		paths = append(paths, change.Field)
+		assert.True(t, utils.IsAchievementFieldPath(change.Field), change.Field)
+	}
+	assert.Contains(t, paths, "details.period.start")
+	assert.Contains(t, paths, "attachments.6650f1c2-aa01-4b8e-9c1d-2f3e4a5b6c7d")
+	assert.Contains(t, paths, "customFields.sk.nomor")
+}
+
+func TestIsAchievementFieldPath_Rejects(t *testing.T) {
+	for _, path := range []string{
+		"",
+		"points",
+		"studentId",
+		"createdAt",
+		"unknown",
+		"title.sub",
+		"tags.0",
+		"attachments",
+		"attachments.a.fileName",
+		"details.",
+		"details..period",
+		"details.period start",
+	} {
+		assert.False(t, utils.IsAchievementFieldPath(path), path)
+	}
+}
@@ -0,0 +1,161 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	"UASBE/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestValidateAchievement(t *testing.T) {
+	t.Run("Valid competition", func(t *testing.T) {
+		errs := utils.ValidateAchievement(mongodb.Achievement{
+			AchievementType: "competition",
+			Title:           "Juara 1 Gemastik",
+			Details: mongodb.AchievementDetails{
+				CompetitionName:  strPtr("Gemastik"),
+				CompetitionLevel: strPtr("national"),
+				Location:         strPtr("Surabaya"),
+			},
+		})
+
+		assert.Empty(t, errs)
+	})
+
+	t.Run("Competition without competitionLevel", func(t *testing.T) {
+		errs := utils.ValidateAchievement(mongodb.Achievement{
+			AchievementType: "competition",
+			Title:           "Juara 1 Gemastik",
+			Details: mongodb.AchievementDetails{
+				CompetitionName: strPtr("Gemastik"),
+			},
+		})
+
+		assert.Equal(t, []utils.FieldError{{Field: "details.competitionLevel", Message: "is required"}}, errs)
+	})
+
+	t.Run("Invalid competitionLevel value", func(t *testing.T) {
+		errs := utils.ValidateAchievement(mongodb.Achievement{
+			AchievementType: "competition",
+			Title:           "Juara 1 Gemastik",
+			Details: mongodb.AchievementDetails{
+				CompetitionName:  strPtr("Gemastik"),
+				CompetitionLevel: strPtr("galaxy"),
+			},
+		})
+
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "details.competitionLevel", errs[0].Field)
+	})
+
+	t.Run("Fields of another type are not allowed", func(t *testing.T) {
+		errs := utils.ValidateAchievement(mongodb.Achievement{
+			AchievementType: "certification",
+			Title:           "AWS Certified",
+			Details: mongodb.AchievementDetails{
+				CertificationName: strPtr("AWS Solutions Architect"),
+				IssuedBy:          strPtr("Amazon"),
+				Publisher:         strPtr("IEEE"),
+			},
+		})
+
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "details.publisher", errs[0].Field)
+	})
+
+	t.Run("Organization period must be ordered", func(t *testing.T) {
+		now := time.Now()
+		errs := utils.ValidateAchievement(mongodb.Achievement{
+			AchievementType: "organization",
+			Title:           "Ketua BEM",
+			Details: mongodb.AchievementDetails{
+				OrganizationName: strPtr("BEM"),
+				Position:         strPtr("Ketua"),
+				Period:           &mongodb.Period{Start: now, End: now.AddDate(0, -1, 0)},
+			},
+		})
+
+		assert.Equal(t, []utils.FieldError{{Field: "details.period", Message: "end must not be before start"}}, errs)
+	})
+
+	t.Run("Missing title and type", func(t *testing.T) {
+		errs := utils.ValidateAchievement(mongodb.Achievement{})
+
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "title", errs[0].Field)
+		assert.Equal(t, "achievementType", errs[1].Field)
+	})
+
+	t.Run("Catalog type without detail rules is not constrained", func(t *testing.T) {
+		errs := utils.ValidateAchievement(mongodb.Achievement{
+			AchievementType: "community_service",
+			Title:           "Pengabdian Masyarakat",
+			Details:         mongodb.AchievementDetails{OrganizationName: strPtr("Karang Taruna")},
+		})
+
+		assert.Empty(t, errs)
+	})
+
+	t.Run("Custom fields are validated against the type schema", func(t *testing.T) {
+		errs := utils.ValidateAchievement(mongodb.Achievement{
+			AchievementType: "academic",
+			Title:           "IPK Terbaik",
+			CustomFields: map[string]interface{}{
+				"gpa":      4.5,
+				"semester": 2.5,
+			},
+		})
+
+		assert.Equal(t, []utils.FieldError{
+			{Field: "customFields.gpa", Message: "must be <= 4"},
+			{Field: "customFields.semester", Message: "must be of type integer"},
+		}, errs)
+	})
+}
+
+func TestJSONSchema_Validate(t *testing.T) {
+	schema := utils.MustParseJSONSchema(`{
+		"type": "object",
+		"required": ["team"],
+		"additionalProperties": false,
+		"properties": {
+			"team": {"type": "array", "items": {"type": "string", "minLength": 1}},
+			"category": {"enum": ["A", "B"]}
+		}
+	}`)
+
+	t.Run("Valid document", func(t *testing.T) {
+		errs := schema.Validate("customFields", map[string]interface{}{
+			"team":     []interface{}{"Andi", "Budi"},
+			"category": "A",
+		})
+		assert.Empty(t, errs)
+	})
+
+	t.Run("Field level errors", func(t *testing.T) {
+		errs := schema.Validate("customFields", map[string]interface{}{
+			"category": "C",
+			"extra":    true,
+		})
+
+		assert.Equal(t, []utils.FieldError{
+			{Field: "customFields.team", Message: "is required"},
+			{Field: "customFields.category", Message: "must be one of [A B]"},
+			{Field: "customFields.extra", Message: "is not allowed"},
+		}, errs)
+	})
+
+	t.Run("Invalid schema", func(t *testing.T) {
+		_, err := utils.ParseJSONSchema([]byte(`{"pattern": "("}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestNormalizeAchievementTags(t *testing.T) {
+	assert.Equal(t, []string{"ai", "machine learning"}, utils.NormalizeAchievementTags([]string{" AI", "Machine Learning ", "ai", "  "}))
+	assert.Equal(t, []string{}, utils.NormalizeAchievementTags(nil))
+}
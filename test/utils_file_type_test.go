@@ -0,0 +1,50 @@
+package test
+
+import (
+	"UASBE/utils"
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectFileType(t *testing.T) {
+	t.Run("PDF", func(t *testing.T) {
+		assert.Equal(t, "application/pdf", utils.DetectFileType([]byte("%PDF-1.7\n...")))
+	})
+
+	t.Run("PNG", func(t *testing.T) {
+		assert.Equal(t, "image/png", utils.DetectFileType([]byte("\x89PNG\r\n\x1a\n\x00\x00")))
+	})
+
+	t.Run("DOCX", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, _ := zw.Create("[Content_Types].xml")
+		w.Write([]byte("<Types/>"))
+		zw.Close()
+
+		assert.Equal(t, "application/vnd.openxmlformats-officedocument.wordprocessingml.document", utils.DetectFileType(buf.Bytes()))
+	})
+
+	t.Run("Plain ZIP is not a document", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, _ := zw.Create("payload.bin")
+		w.Write([]byte("data"))
+		zw.Close()
+
+		assert.Equal(t, "application/zip", utils.DetectFileType(buf.Bytes()))
+	})
+
+	t.Run("Executable is detected regardless of file name", func(t *testing.T) {
+		fileType := utils.DetectFileType([]byte("MZ\x90\x00\x03\x00"))
+		assert.Equal(t, "application/x-msdownload", fileType)
+		assert.False(t, utils.AllowedAttachmentTypes[fileType])
+	})
+
+	t.Run("Unknown content", func(t *testing.T) {
+		assert.Equal(t, "application/octet-stream", utils.DetectFileType([]byte("hello")))
+	})
+}
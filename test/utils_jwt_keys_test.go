@@ -0,0 +1,196 @@
+package test
+
+import (
+	"UASBE/config"
+	"UASBE/utils"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const testUserID = "123e4567-e89b-12d3-a456-426614174000"
+
+func writeRSAKeyPair(t *testing.T, dir, kid string) (privatePath string, key *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	privatePath = filepath.Join(dir, kid+".key")
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	assert.NoError(t, os.WriteFile(privatePath, privPEM, 0600))
+
+	return privatePath, key
+}
+
+func writePublicKey(t *testing.T, dir, kid string, pub interface{}) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), pubPEM, 0644))
+}
+
+func TestLoadKeySet(t *testing.T) {
+	previous, previousSecret := utils.CurrentKeySet(), utils.JWTSecretKey
+	defer func() {
+		utils.SetKeySet(previous)
+		utils.JWTSecretKey = previousSecret
+	}()
+
+	t.Run("HS256 requires a secret", func(t *testing.T) {
+		_, err := utils.LoadKeySet(config.Config{})
+		assert.Error(t, err)
+	})
+
+	t.Run("HS256 from config signs with kid header", func(t *testing.T) {
+		err := utils.InitJWTKeys(config.Config{JWTSecret: "config-secret", JWTKeyID: "hs-1"})
+		assert.NoError(t, err)
+
+		token, err := utils.GenerateJWT(testUserID, "testuser", "student", nil)
+		assert.NoError(t, err)
+
+		parsed, _, _ := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+		assert.Equal(t, "hs-1", parsed.Header["kid"])
+		assert.Equal(t, "HS256", parsed.Header["alg"])
+
+		_, err = utils.ValidateToken(token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Unsupported algorithm", func(t *testing.T) {
+		_, err := utils.LoadKeySet(config.Config{JWTAlgorithm: "none"})
+		assert.Error(t, err)
+	})
+
+	t.Run("RS256 with rotated verification keys", func(t *testing.T) {
+		dir := t.TempDir()
+		oldPath, _ := writeRSAKeyPair(t, dir, "rsa-old")
+		newPath, newKey := writeRSAKeyPair(t, dir, "rsa-new")
+
+		// Token lama ditandatangani dengan key lama
+		err := utils.InitJWTKeys(config.Config{JWTAlgorithm: "RS256", JWTKeyID: "rsa-old", JWTPrivateKeyFile: oldPath})
+		assert.NoError(t, err)
+		oldToken, err := utils.GenerateJWT(testUserID, "testuser", "student", nil)
+		assert.NoError(t, err)
+
+		// Rotasi: key baru aktif, key lama hanya untuk verifikasi
+		oldSet := utils.CurrentKeySet()
+		oldPub := oldSet.JWKS().Keys[0]
+		assert.Equal(t, "rsa-old", oldPub.Kid)
+
+		oldPriv, _ := os.ReadFile(oldPath)
+		oldSigning, _ := utils.ParsePrivateKeyPEM("rsa-old", oldPriv)
+		writePublicKey(t, dir, "rsa-old", oldSigning.PublicKey)
+		writePublicKey(t, dir, "rsa-new", &newKey.PublicKey)
+
+		err = utils.InitJWTKeys(config.Config{
+			JWTAlgorithm:      "RS256",
+			JWTKeyID:          "rsa-new",
+			JWTPrivateKeyFile: newPath,
+			JWTVerifyKeysDir:  dir,
+		})
+		assert.NoError(t, err)
+
+		_, err = utils.ValidateToken(oldToken)
+		assert.NoError(t, err)
+
+		newToken, err := utils.GenerateJWT(testUserID, "testuser", "student", nil)
+		assert.NoError(t, err)
+		parsed, _, _ := new(jwt.Parser).ParseUnverified(newToken, jwt.MapClaims{})
+		assert.Equal(t, "rsa-new", parsed.Header["kid"])
+
+		jwks := utils.CurrentKeySet().JWKS()
+		assert.Len(t, jwks.Keys, 2)
+		for _, key := range jwks.Keys {
+			assert.Equal(t, "RSA", key.Kty)
+			assert.Equal(t, "RS256", key.Alg)
+			assert.NotEmpty(t, key.N)
+			assert.Equal(t, "AQAB", key.E)
+		}
+
+		// Key lama dihapus dari direktori verifikasi -> token lama ditolak
+		assert.NoError(t, os.Remove(filepath.Join(dir, "rsa-old.pem")))
+		err = utils.InitJWTKeys(config.Config{
+			JWTAlgorithm:      "RS256",
+			JWTKeyID:          "rsa-new",
+			JWTPrivateKeyFile: newPath,
+			JWTVerifyKeysDir:  dir,
+		})
+		assert.NoError(t, err)
+
+		_, err = utils.ValidateToken(oldToken)
+		assert.Error(t, err)
+	})
+}
+
+func TestKeySet_Validation(t *testing.T) {
+	previous := utils.CurrentKeySet()
+	defer utils.SetKeySet(previous)
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	edSigning, err := utils.ParsePrivateKeyPEM("ed-1", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	assert.NoError(t, err)
+
+	ks, err := utils.NewKeySet(edSigning)
+	assert.NoError(t, err)
+	utils.SetKeySet(ks)
+
+	claims := jwt.MapClaims{"jti": "abc", "user_id": testUserID, "exp": time.Now().Add(time.Hour).Unix()}
+
+	t.Run("EdDSA token is valid", func(t *testing.T) {
+		token, err := utils.GenerateJWT(testUserID, "testuser", "student", nil)
+		assert.NoError(t, err)
+
+		result, err := utils.ValidateToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, testUserID, result["user_id"])
+	})
+
+	t.Run("JWKS publishes Ed25519 key", func(t *testing.T) {
+		jwks := ks.JWKS()
+		assert.Len(t, jwks.Keys, 1)
+		assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+		assert.Equal(t, "Ed25519", jwks.Keys[0].Crv)
+		assert.Equal(t, "ed-1", jwks.Keys[0].Kid)
+	})
+
+	t.Run("Token without kid is rejected", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+		signed, _ := token.SignedString(edKey)
+
+		_, err := utils.ValidateToken(signed)
+		assert.Error(t, err)
+	})
+
+	t.Run("Token with unknown kid is rejected", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+		token.Header["kid"] = "unknown"
+		signed, _ := token.SignedString(edKey)
+
+		_, err := utils.ValidateToken(signed)
+		assert.Error(t, err)
+	})
+
+	t.Run("HS256 token forged with a known kid is rejected", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		token.Header["kid"] = "ed-1"
+		signed, _ := token.SignedString([]byte("attacker-secret"))
+
+		_, err := utils.ValidateToken(signed)
+		assert.Error(t, err)
+	})
+
+	t.Run("HMAC keys are never published", func(t *testing.T) {
+		hmacSet, err := utils.NewKeySet(utils.NewHMACKey("hs-1", []byte("secret")))
+		assert.NoError(t, err)
+		assert.Empty(t, hmacSet.JWKS().Keys)
+	})
+}
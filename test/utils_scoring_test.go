@@ -0,0 +1,83 @@
+package test
+
+import (
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+	"UASBE/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateAchievementScore(t *testing.T) {
+	rules := model.ScoringRules{
+		TypePoints:             map[string]int{"other": 2},
+		CompetitionLevelPoints: map[string]int{"international": 50, "national": 30},
+		RankPoints:             map[string]int{"1": 30, "2": 20},
+		MedalTypePoints:        map[string]int{"gold": 35, "bronze": 10},
+		PublicationTypePoints:  map[string]int{"journal": 40},
+		PositionPoints:         map[string]int{"ketua": 20},
+	}
+
+	t.Run("Competition uses level and best placement", func(t *testing.T) {
+		rank := 1
+		achievement := mongodb.Achievement{
+			AchievementType: "competition",
+			Details: mongodb.AchievementDetails{
+				CompetitionLevel: strPtr("National"),
+				Rank:             &rank,
+				MedalType:        strPtr("gold"),
+			},
+		}
+
+		points, breakdown := utils.CalculateAchievementScore(achievement, 20, rules)
+
+		assert.Equal(t, 85, points)
+		assert.Equal(t, []model.ScoreComponent{
+			{Component: "type", Value: "competition", Points: 20},
+			{Component: "competitionLevel", Value: "national", Points: 30},
+			{Component: "medalType", Value: "gold", Points: 35},
+		}, breakdown)
+	})
+
+	t.Run("Rank wins when worth more than medal", func(t *testing.T) {
+		rank := 2
+		achievement := mongodb.Achievement{
+			AchievementType: "competition",
+			Details:         mongodb.AchievementDetails{Rank: &rank, MedalType: strPtr("bronze")},
+		}
+
+		points, breakdown := utils.CalculateAchievementScore(achievement, 20, rules)
+
+		assert.Equal(t, 40, points)
+		assert.Equal(t, model.ScoreComponent{Component: "rank", Value: "2", Points: 20}, breakdown[1])
+	})
+
+	t.Run("Publication and organization", func(t *testing.T) {
+		publication := mongodb.Achievement{
+			AchievementType: "publication",
+			Details:         mongodb.AchievementDetails{PublicationType: strPtr("journal")},
+		}
+		points, _ := utils.CalculateAchievementScore(publication, 25, rules)
+		assert.Equal(t, 65, points)
+
+		organization := mongodb.Achievement{
+			AchievementType: "organization",
+			Details:         mongodb.AchievementDetails{Position: strPtr(" Ketua ")},
+		}
+		points, _ = utils.CalculateAchievementScore(organization, 10, rules)
+		assert.Equal(t, 30, points)
+	})
+
+	t.Run("Type points override catalog default and unknown values add nothing", func(t *testing.T) {
+		achievement := mongodb.Achievement{
+			AchievementType: "other",
+			Details:         mongodb.AchievementDetails{Position: strPtr("ketua")},
+		}
+
+		points, breakdown := utils.CalculateAchievementScore(achievement, 5, rules)
+
+		assert.Equal(t, 2, points)
+		assert.Len(t, breakdown, 1)
+	})
+}
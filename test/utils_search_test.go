@@ -0,0 +1,75 @@
+package test
+
+import (
+	"UASBE/utils"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"juara", "gemastik"}, utils.SearchTerms("Juara  gemastik JUARA"))
+	assert.Equal(t, []string{"lomba", "data mining"}, utils.SearchTerms(`lomba "Data Mining" -nasional`))
+	assert.Empty(t, utils.SearchTerms("   "))
+}
+
+func TestSearchHighlighter_Snippet(t *testing.T) {
+	t.Run("Marks every whole-word match", func(t *testing.T) {
+		snippet, ok := utils.NewSearchHighlighter("juara gemastik").Snippet("Juara 1 Gemastik, juara umum")
+
+		assert.True(t, ok)
+		assert.Equal(t, "<mark>Juara</mark> 1 <mark>Gemastik</mark>, <mark>juara</mark> umum", snippet)
+	})
+
+	t.Run("Does not match inside words", func(t *testing.T) {
+		_, ok := utils.NewSearchHighlighter("ai").Snippet("Kejuaraan tingkat nasional")
+
+		assert.False(t, ok)
+	})
+
+	t.Run("Terms with symbols match as whole words", func(t *testing.T) {
+		snippet, ok := utils.NewSearchHighlighter("c++ .net").Snippet("Sertifikasi C++ dan .NET, bukan c++11 atau asp.net")
+
+		assert.True(t, ok)
+		assert.Equal(t, "Sertifikasi <mark>C++</mark> dan <mark>.NET</mark>, bukan c++11 atau asp.net", snippet)
+	})
+
+	t.Run("Longer term wins over its prefix", func(t *testing.T) {
+		snippet, ok := utils.NewSearchHighlighter("c c++").Snippet("bahasa c++ dan c")
+
+		assert.True(t, ok)
+		assert.Equal(t, "bahasa <mark>c++</mark> dan <mark>c</mark>", snippet)
+	})
+
+	t.Run("Matches non-ASCII words", func(t *testing.T) {
+		snippet, ok := utils.NewSearchHighlighter("café").Snippet("Lomba café-latte, bukan cafés")
+
+		assert.True(t, ok)
+		assert.Equal(t, "Lomba <mark>café</mark>-latte, bukan cafés", snippet)
+	})
+
+	t.Run("Cuts long text around the first match", func(t *testing.T) {
+		text := strings.Repeat("awal ", 40) + "kompetisi robotik" + strings.Repeat(" akhir", 40)
+		snippet, ok := utils.NewSearchHighlighter("robotik").Snippet(text)
+
+		assert.True(t, ok)
+		assert.True(t, strings.HasPrefix(snippet, "…"))
+		assert.True(t, strings.HasSuffix(snippet, "…"))
+		assert.Contains(t, snippet, "kompetisi <mark>robotik</mark>")
+		assert.Less(t, len([]rune(snippet)), len([]rune(text)))
+	})
+
+	t.Run("Escapes HTML in the document text", func(t *testing.T) {
+		snippet, ok := utils.NewSearchHighlighter("lomba").Snippet(`<script>x</script> lomba`)
+
+		assert.True(t, ok)
+		assert.Equal(t, "&lt;script&gt;x&lt;/script&gt; <mark>lomba</mark>", snippet)
+	})
+
+	t.Run("Negated-only query never matches", func(t *testing.T) {
+		_, ok := utils.NewSearchHighlighter("-lomba").Snippet("lomba")
+
+		assert.False(t, ok)
+	})
+}
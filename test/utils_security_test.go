@@ -53,6 +53,18 @@ func TestGenerateJWT(t *testing.T) {
 		assert.Equal(t, userID, claims["user_id"])
 		assert.Equal(t, username, claims["username"])
 		assert.Equal(t, role, claims["role"])
+		assert.NotEmpty(t, claims["jti"])
+	})
+
+	t.Run("Each JWT gets a unique token ID", func(t *testing.T) {
+		token1, _ := utils.GenerateJWT(userID, username, role, permissions)
+		token2, _ := utils.GenerateJWT(userID, username, role, permissions)
+
+		jti1, err := utils.GetTokenID(token1)
+		assert.NoError(t, err)
+		jti2, err := utils.GetTokenID(token2)
+		assert.NoError(t, err)
+		assert.NotEqual(t, jti1, jti2)
 	})
 
 	t.Run("Generate JWT with empty permissions", func(t *testing.T) {
@@ -167,3 +179,41 @@ func TestGetTokenIssuedAt(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestGenerateRefreshToken(t *testing.T) {
+	t.Run("Generates unique opaque tokens", func(t *testing.T) {
+		token1, err := utils.GenerateRefreshToken()
+		assert.NoError(t, err)
+		token2, err := utils.GenerateRefreshToken()
+		assert.NoError(t, err)
+
+		assert.NotEmpty(t, token1)
+		assert.NotEqual(t, token1, token2)
+		// Refresh token bukan JWT
+		assert.NotContains(t, token1, ".")
+	})
+
+	t.Run("Access token is short-lived", func(t *testing.T) {
+		token, _ := utils.GenerateJWT("123e4567-e89b-12d3-a456-426614174000", "testuser", "student", []string{"read"})
+		expiresAt, err := utils.GetTokenExpiration(token)
+		assert.NoError(t, err)
+		assert.True(t, expiresAt.Before(time.Now().Add(utils.AccessTokenTTL+time.Minute)))
+	})
+}
+
+func TestHashRefreshToken(t *testing.T) {
+	t.Run("Hash is deterministic", func(t *testing.T) {
+		assert.Equal(t, utils.HashRefreshToken("abc"), utils.HashRefreshToken("abc"))
+	})
+
+	t.Run("Hash differs from raw token", func(t *testing.T) {
+		token, _ := utils.GenerateRefreshToken()
+		hash := utils.HashRefreshToken(token)
+		assert.NotEqual(t, token, hash)
+		assert.Len(t, hash, 64)
+	})
+
+	t.Run("Different tokens produce different hashes", func(t *testing.T) {
+		assert.NotEqual(t, utils.HashRefreshToken("token-a"), utils.HashRefreshToken("token-b"))
+	})
+}
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"UASBE/utils"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignURL(t *testing.T) {
+	path := "/api/v1/public/attachments/abc/att-1"
+
+	t.Run("Valid signature", func(t *testing.T) {
+		signed, expiresAt := utils.SignURL(path, time.Minute)
+		assert.True(t, strings.HasPrefix(signed, path+"?"))
+		assert.True(t, expiresAt.After(time.Now()))
+
+		parsed, _ := url.Parse(signed)
+		err := utils.VerifySignedURL(path, parsed.Query().Get("expires"), parsed.Query().Get("signature"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Expired URL", func(t *testing.T) {
+		signed, _ := utils.SignURL(path, -time.Minute)
+
+		parsed, _ := url.Parse(signed)
+		err := utils.VerifySignedURL(path, parsed.Query().Get("expires"), parsed.Query().Get("signature"))
+		assert.ErrorIs(t, err, utils.ErrSignedURLExpired)
+	})
+
+	t.Run("Extended expiry invalidates signature", func(t *testing.T) {
+		signed, expiresAt := utils.SignURL(path, time.Minute)
+
+		parsed, _ := url.Parse(signed)
+		later := strconv.FormatInt(expiresAt.Add(time.Hour).Unix(), 10)
+		err := utils.VerifySignedURL(path, later, parsed.Query().Get("signature"))
+		assert.ErrorIs(t, err, utils.ErrSignedURLSignature)
+	})
+
+	t.Run("Different key invalidates signature", func(t *testing.T) {
+		signed, _ := utils.SignURL(path, time.Minute)
+		parsed, _ := url.Parse(signed)
+
+		previous := utils.URLSigningKey
+		defer utils.SetURLSigningKey(previous)
+		utils.SetURLSigningKey([]byte("another-key"))
+
+		err := utils.VerifySignedURL(path, parsed.Query().Get("expires"), parsed.Query().Get("signature"))
+		assert.ErrorIs(t, err, utils.ErrSignedURLSignature)
+	})
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"testing"
 	"time"
 	"UASBE/utils"
@@ -105,3 +106,32 @@ func TestTokenBlacklistConcurrency(t *testing.T) {
 	// Should have 10 tokens
 	assert.Equal(t, 10, manager.GetBlacklistSize())
 }
+
+func TestTokenBlacklistStore_InMemory(t *testing.T) {
+	ctx := context.Background()
+	var store utils.TokenBlacklistStore = utils.NewTokenBlacklistManager()
+
+	t.Run("Revoked token ID is reported", func(t *testing.T) {
+		err := store.Revoke(ctx, "jti-1", time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+
+		revoked, err := store.IsRevoked(ctx, "jti-1")
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("Unknown token ID is not revoked", func(t *testing.T) {
+		revoked, err := store.IsRevoked(ctx, "jti-unknown")
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("Expired revocation is ignored", func(t *testing.T) {
+		err := store.Revoke(ctx, "jti-expired", time.Now().Add(-time.Minute))
+		assert.NoError(t, err)
+
+		revoked, err := store.IsRevoked(ctx, "jti-expired")
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+}
@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+)
+
+// diffIgnoredFields tidak diisi oleh mahasiswa sehingga tidak dianggap sebagai perubahan isi
+var diffIgnoredFields = map[string]bool{
+	"id":        true,
+	"studentId": true,
+	"points":    true,
+	"createdAt": true,
+	"updatedAt": true,
+}
+
+// achievementFieldPathDepth menyimpan jumlah segmen yang boleh mengikuti tiap field teratas pada path diff:
+// 0 untuk nilai tunggal (termasuk array seperti tags), 1 untuk attachment per ID, -1 untuk object yang
+// ditelusuri per field. Disusun dari tag JSON mongodb.Achievement agar sama dengan hasil flattenAchievement.
+var achievementFieldPathDepth = buildAchievementFieldPathDepth()
+
+// fieldPathSegmentPattern membatasi karakter tiap segmen path (nama field, ID attachment, key custom field)
+var fieldPathSegmentPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func buildAchievementFieldPathDepth() map[string]int {
+	depth := make(map[string]int)
+	t := reflect.TypeOf(mongodb.Achievement{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || diffIgnoredFields[name] {
+			continue
+		}
+		switch {
+		case name == "attachments":
+			depth[name] = 1
+		case field.Type.Kind() == reflect.Struct || field.Type.Kind() == reflect.Map:
+			depth[name] = -1
+		default:
+			depth[name] = 0
+		}
+	}
+	return depth
+}
+
+// IsAchievementFieldPath memeriksa apakah path memakai format field diff versi, mis. "title",
+// "details.period.start", "customFields.jurusan", atau "attachments.<id>"
+func IsAchievementFieldPath(path string) bool {
+	segments := strings.Split(path, ".")
+	depth, ok := achievementFieldPathDepth[segments[0]]
+	if !ok {
+		return false
+	}
+	rest := segments[1:]
+	if depth >= 0 && len(rest) != depth {
+		return false
+	}
+	for _, segment := range rest {
+		if !fieldPathSegmentPattern.MatchString(segment) {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffAchievements membandingkan dua versi isi prestasi dan mengembalikan field yang berubah,
+// terurut berdasarkan path. Object ditelusuri per field; attachment dibandingkan per ID
+// ("attachments.<id>"); array lain (mis. tags) dibandingkan sebagai satu nilai.
+func DiffAchievements(before, after mongodb.Achievement) ([]model.FieldChange, error) {
+	beforeFields, err := flattenAchievement(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := flattenAchievement(after)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+	for path := range beforeFields {
+		paths[path] = true
+	}
+	for path := range afterFields {
+		paths[path] = true
+	}
+
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	changes := []model.FieldChange{}
+	for _, path := range sorted {
+		oldValue, newValue := beforeFields[path], afterFields[path]
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, model.FieldChange{Field: path, Before: oldValue, After: newValue})
+		}
+	}
+
+	return changes, nil
+}
+
+func flattenAchievement(achievement mongodb.Achievement) (map[string]interface{}, error) {
+	raw, err := json.Marshal(achievement)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+	for key, value := range doc {
+		if diffIgnoredFields[key] {
+			continue
+		}
+		if items, ok := value.([]interface{}); ok && key == "attachments" {
+			for i, item := range items {
+				fields["attachments."+attachmentDiffKey(item, i)] = item
+			}
+			continue
+		}
+		flattenValue(key, value, fields)
+	}
+	return fields, nil
+}
+
+// attachmentDiffKey memakai ID attachment; attachment lama tanpa ID memakai posisinya
+func attachmentDiffKey(item interface{}, index int) string {
+	if attachment, ok := item.(map[string]interface{}); ok {
+		if id, ok := attachment["id"].(string); ok && id != "" {
+			return id
+		}
+	}
+	return strconv.Itoa(index)
+}
+
+func flattenValue(path string, value interface{}, fields map[string]interface{}) {
+	if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+		for key, v := range nested {
+			flattenValue(path+"."+key, v, fields)
+		}
+		return
+	}
+	// Nilai kosong (null, "", [], {}) dianggap sama dengan field yang tidak diisi
+	if isEmptyJSONValue(value) {
+		return
+	}
+	fields[path] = value
+}
+
+func isEmptyJSONValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
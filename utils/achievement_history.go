@@ -8,6 +8,9 @@ import (
 )
 
 // AchievementHistoryManager manages achievement history in memory
+//
+// Deprecated: status history is persisted in achievement_status_logs by the
+// achievement repository; this in-memory store is lost on restart.
 type AchievementHistoryManager struct {
 	history map[uuid.UUID][]model.AchievementStatusLog // achievement_id -> history entries
 	mu      sync.RWMutex
@@ -15,6 +18,8 @@ type AchievementHistoryManager struct {
 
 var (
 	// Global instance
+	//
+	// Deprecated: use AchievementRepository.GetAchievementStatusHistory.
 	HistoryManager *AchievementHistoryManager
 )
 
@@ -0,0 +1,203 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	mongodb "UASBE/app/model/MongoDB"
+)
+
+// AchievementTypeDefinition mendefinisikan field details yang wajib/opsional untuk satu tipe prestasi
+type AchievementTypeDefinition struct {
+	Code           string
+	RequiredFields []string
+	OptionalFields []string
+	// AllowedValues membatasi nilai field string tertentu (mis. competitionLevel)
+	AllowedValues map[string][]string
+	// CustomFieldsSchema bersifat opsional; nil berarti customFields tidak divalidasi
+	CustomFieldsSchema *JSONSchema
+}
+
+// generalDetailFields boleh diisi untuk semua tipe prestasi
+var generalDetailFields = []string{"eventDate", "location", "organizer", "score"}
+
+// AchievementTypes adalah registry aturan details per tipe prestasi, key = achievementType.
+// Keberadaan & status aktif tipe dicek terhadap katalog achievement_types di PostgreSQL;
+// tipe di katalog yang tidak punya definisi di sini tidak dibatasi field details-nya.
+var AchievementTypes = map[string]AchievementTypeDefinition{
+	"competition": {
+		Code:           "competition",
+		RequiredFields: []string{"competitionName", "competitionLevel"},
+		OptionalFields: []string{"rank", "medalType"},
+		AllowedValues: map[string][]string{
+			"competitionLevel": {"international", "national", "regional", "local"},
+		},
+	},
+	"publication": {
+		Code:           "publication",
+		RequiredFields: []string{"publicationType", "publicationTitle", "authors"},
+		OptionalFields: []string{"publisher", "issn"},
+		AllowedValues: map[string][]string{
+			"publicationType": {"journal", "conference", "book"},
+		},
+	},
+	"organization": {
+		Code:           "organization",
+		RequiredFields: []string{"organizationName", "position", "period"},
+	},
+	"certification": {
+		Code:           "certification",
+		RequiredFields: []string{"certificationName", "issuedBy"},
+		OptionalFields: []string{"certificationNumber", "validUntil"},
+	},
+	"academic": {
+		Code: "academic",
+		CustomFieldsSchema: MustParseJSONSchema(`{
+			"type": "object",
+			"properties": {
+				"gpa": {"type": "number", "minimum": 0, "maximum": 4},
+				"semester": {"type": "integer", "minimum": 1}
+			}
+		}`),
+	},
+	"other": {
+		Code: "other",
+	},
+}
+
+// GetAchievementTypeDefinition mengambil definisi tipe prestasi dari registry
+func GetAchievementTypeDefinition(code string) (AchievementTypeDefinition, bool) {
+	def, ok := AchievementTypes[code]
+	return def, ok
+}
+
+// NormalizeAchievementType menyeragamkan code tipe prestasi ("Competition " -> "competition")
+func NormalizeAchievementType(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// NormalizeAchievementTags menyeragamkan tag prestasi (lowercase, tanpa spasi di tepi);
+// tag kosong dan duplikat dibuang dengan urutan tetap
+func NormalizeAchievementTags(tags []string) []string {
+	normalized := []string{}
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// ValidateAchievement memvalidasi details dan customFields sesuai definisi tipe prestasi.
+// Mengembalikan daftar error per field; slice kosong berarti valid.
+func ValidateAchievement(achievement mongodb.Achievement) []FieldError {
+	var errs []FieldError
+
+	if strings.TrimSpace(achievement.Title) == "" {
+		errs = append(errs, FieldError{Field: "title", Message: "is required"})
+	}
+
+	if achievement.AchievementType == "" {
+		return append(errs, FieldError{Field: "achievementType", Message: "is required"})
+	}
+
+	def, ok := GetAchievementTypeDefinition(achievement.AchievementType)
+	if !ok {
+		return errs
+	}
+
+	errs = append(errs, validateDetails(def, achievement.Details)...)
+
+	if def.CustomFieldsSchema != nil {
+		customFields := achievement.CustomFields
+		if customFields == nil {
+			customFields = map[string]interface{}{}
+		}
+		errs = append(errs, def.CustomFieldsSchema.Validate("customFields", customFields)...)
+	}
+
+	return errs
+}
+
+func validateDetails(def AchievementTypeDefinition, details mongodb.AchievementDetails) []FieldError {
+	// Serialisasi ke map agar field yang terisi bisa dicek berdasarkan nama JSON-nya (omitempty)
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return []FieldError{{Field: "details", Message: "is invalid"}}
+	}
+	var present map[string]interface{}
+	if err := json.Unmarshal(raw, &present); err != nil {
+		return []FieldError{{Field: "details", Message: "is invalid"}}
+	}
+
+	var errs []FieldError
+
+	for _, field := range def.RequiredFields {
+		value, ok := present[field]
+		if !ok || isBlankDetailValue(value) {
+			errs = append(errs, FieldError{Field: "details." + field, Message: "is required"})
+		}
+	}
+
+	allowed := make(map[string]bool)
+	for _, fields := range [][]string{def.RequiredFields, def.OptionalFields, generalDetailFields} {
+		for _, field := range fields {
+			allowed[field] = true
+		}
+	}
+
+	fields := make([]string, 0, len(present))
+	for field := range present {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if !allowed[field] {
+			errs = append(errs, FieldError{Field: "details." + field, Message: fmt.Sprintf("is not allowed for achievement type %q", def.Code)})
+			continue
+		}
+
+		values, restricted := def.AllowedValues[field]
+		str, isString := present[field].(string)
+		if restricted && isString && str != "" && !containsString(values, str) {
+			errs = append(errs, FieldError{Field: "details." + field, Message: fmt.Sprintf("must be one of %s", strings.Join(values, ", "))})
+		}
+	}
+
+	if details.Rank != nil && *details.Rank < 1 {
+		errs = append(errs, FieldError{Field: "details.rank", Message: "must be >= 1"})
+	}
+	if details.Period != nil && details.Period.End.Before(details.Period.Start) {
+		errs = append(errs, FieldError{Field: "details.period", Message: "end must not be before start"})
+	}
+
+	return errs
+}
+
+func isBlankDetailValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+)
+
+// SniffLength is the number of leading bytes needed by DetectFileType
+const SniffLength = 512
+
+// AllowedAttachmentTypes are the MIME types accepted for achievement attachments
+var AllowedAttachmentTypes = map[string]bool{
+	"image/jpeg":         true,
+	"image/png":          true,
+	"image/gif":          true,
+	"application/pdf":    true,
+	"application/msword": true,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
+}
+
+// DetectFileType menentukan MIME type dari magic bytes, bukan dari header Content-Type client.
+// Mengembalikan "application/octet-stream" jika tipe tidak dikenali.
+func DetectFileType(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return "application/pdf"
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")):
+		return "image/png"
+	case bytes.HasPrefix(head, []byte("\xff\xd8\xff")):
+		return "image/jpeg"
+	case bytes.HasPrefix(head, []byte("GIF87a")), bytes.HasPrefix(head, []byte("GIF89a")):
+		return "image/gif"
+	case bytes.HasPrefix(head, []byte("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1")):
+		// OLE2 compound file (Word 97-2003)
+		return "application/msword"
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		if isOOXMLWordDocument(head) {
+			return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+		}
+		return "application/zip"
+	case bytes.HasPrefix(head, []byte("MZ")):
+		return "application/x-msdownload"
+	case bytes.HasPrefix(head, []byte("\x7fELF")):
+		return "application/x-executable"
+	}
+	return "application/octet-stream"
+}
+
+// isOOXMLWordDocument mengecek nama entry pertama di ZIP (.docx selalu diawali
+// [Content_Types].xml, _rels/, docProps/ atau word/)
+func isOOXMLWordDocument(head []byte) bool {
+	// Local file header: nama file dimulai di offset 30, panjangnya di offset 26
+	if len(head) < 30 {
+		return false
+	}
+	nameLen := int(binary.LittleEndian.Uint16(head[26:28]))
+	if len(head) < 30+nameLen {
+		return false
+	}
+	name := string(head[30 : 30+nameLen])
+
+	return name == "[Content_Types].xml" ||
+		strings.HasPrefix(name, "_rels/") ||
+		strings.HasPrefix(name, "docProps/") ||
+		strings.HasPrefix(name, "word/")
+}
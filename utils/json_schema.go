@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FieldError is a validation error for a single field (path uses dot notation, e.g. "details.rank")
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// JSONSchema adalah subset JSON Schema (draft-07) yang dipakai untuk validasi customFields.
+// Keyword yang didukung: type, properties, required, additionalProperties, items,
+// enum, minimum, maximum, minLength, maxLength, pattern.
+type JSONSchema struct {
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// ParseJSONSchema mem-parse schema dan meng-compile pattern di dalamnya
+func ParseJSONSchema(raw []byte) (*JSONSchema, error) {
+	var schema JSONSchema
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	if err := schema.compile(); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// MustParseJSONSchema seperti ParseJSONSchema tetapi panic jika schema tidak valid
+func MustParseJSONSchema(raw string) *JSONSchema {
+	schema, err := ParseJSONSchema([]byte(raw))
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+func (s *JSONSchema) compile() error {
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+	for _, prop := range s.Properties {
+		if err := prop.compile(); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile()
+	}
+	return nil
+}
+
+// Validate memvalidasi value terhadap schema; path adalah prefix nama field untuk pesan error
+func (s *JSONSchema) Validate(path string, value interface{}) []FieldError {
+	value = normalizeJSONValue(value)
+	var errs []FieldError
+
+	if s.Type != "" && !matchesJSONType(s.Type, value) {
+		return []FieldError{{Field: path, Message: fmt.Sprintf("must be of type %s", s.Type)}}
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, allowed := range s.Enum {
+			if reflect.DeepEqual(normalizeJSONValue(allowed), value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, FieldError{Field: path, Message: fmt.Sprintf("must be one of %v", s.Enum)})
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := len([]rune(v))
+		if s.MinLength != nil && length < *s.MinLength {
+			errs = append(errs, FieldError{Field: path, Message: fmt.Sprintf("must be at least %d characters", *s.MinLength)})
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			errs = append(errs, FieldError{Field: path, Message: fmt.Sprintf("must be at most %d characters", *s.MaxLength)})
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			errs = append(errs, FieldError{Field: path, Message: fmt.Sprintf("must match pattern %s", s.Pattern)})
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			errs = append(errs, FieldError{Field: path, Message: fmt.Sprintf("must be >= %v", *s.Minimum)})
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			errs = append(errs, FieldError{Field: path, Message: fmt.Sprintf("must be <= %v", *s.Maximum)})
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				errs = append(errs, s.Items.Validate(fmt.Sprintf("%s[%d]", path, i), item)...)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				errs = append(errs, FieldError{Field: joinFieldPath(path, name), Message: "is required"})
+			}
+		}
+
+		// Urutkan key agar urutan error deterministik
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			prop, ok := s.Properties[key]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					errs = append(errs, FieldError{Field: joinFieldPath(path, key), Message: "is not allowed"})
+				}
+				continue
+			}
+			errs = append(errs, prop.Validate(joinFieldPath(path, key), v[key])...)
+		}
+	}
+
+	return errs
+}
+
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func matchesJSONType(typ string, value interface{}) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == float64(int64(f))
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+// normalizeJSONValue menyeragamkan value dari JSON body maupun hasil decode BSON
+// (primitive.D/M/A, int32/int64) ke bentuk encoding/json
+func normalizeJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case primitive.D:
+		m := make(map[string]interface{}, len(v))
+		for _, e := range v {
+			m[e.Key] = normalizeJSONValue(e.Value)
+		}
+		return m
+	case primitive.M:
+		return normalizeJSONValue(map[string]interface{}(v))
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = normalizeJSONValue(item)
+		}
+		return m
+	case primitive.A:
+		return normalizeJSONValue([]interface{}(v))
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = normalizeJSONValue(item)
+		}
+		return items
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	}
+	return value
+}
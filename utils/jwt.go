@@ -46,3 +46,28 @@ func GetTokenIssuedAt(tokenString string) (time.Time, error) {
 
 	return time.Unix(int64(iat), 0), nil
 }
+
+// GetTokenID mengambil token ID (jti) dari JWT
+func GetTokenID(tokenString string) (string, error) {
+	token, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return "", err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", errors.New("invalid token claims")
+	}
+
+	return GetTokenIDFromClaims(claims)
+}
+
+// GetTokenIDFromClaims mengambil token ID (jti) dari claims yang sudah divalidasi
+func GetTokenIDFromClaims(claims jwt.MapClaims) (string, error) {
+	jti, ok := claims["jti"].(string)
+	if !ok || jti == "" {
+		return "", errors.New("jti not found in token")
+	}
+
+	return jti, nil
+}
@@ -0,0 +1,360 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"UASBE/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is a JWT key identified by its `kid`.
+// PrivateKey is nil for keys that are only accepted for verification.
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey interface{}
+	PublicKey  interface{}
+}
+
+// KeySet holds the key used to sign new tokens plus every key accepted for verification.
+// Keeping retired keys in the verification set allows rotation without logging users out.
+type KeySet struct {
+	signing      *SigningKey
+	verification map[string]*SigningKey
+}
+
+// JWK is a single JSON Web Key as published on the JWKS endpoint
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+const defaultKeyID = "default"
+
+var (
+	activeKeySet *KeySet
+	keySetMu     sync.RWMutex
+)
+
+func init() {
+	// Secret acak sampai konfigurasi dimuat lewat InitJWTKeys; tidak ada secret hard-coded
+	JWTSecretKey = make([]byte, 32)
+	if _, err := rand.Read(JWTSecretKey); err != nil {
+		panic(err)
+	}
+	activeKeySet, _ = NewKeySet(NewHMACKey(defaultKeyID, JWTSecretKey))
+}
+
+// NewKeySet builds a key set from a signing key and optional verification-only keys
+func NewKeySet(signing SigningKey, verifyOnly ...SigningKey) (*KeySet, error) {
+	if signing.ID == "" {
+		return nil, errors.New("signing key must have a kid")
+	}
+	if signing.PrivateKey == nil {
+		return nil, errors.New("signing key must have a private key")
+	}
+
+	ks := &KeySet{
+		signing:      &signing,
+		verification: map[string]*SigningKey{signing.ID: &signing},
+	}
+
+	for i := range verifyOnly {
+		key := verifyOnly[i]
+		if key.ID == "" {
+			return nil, errors.New("verification key must have a kid")
+		}
+		if _, exists := ks.verification[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate kid %q", key.ID)
+		}
+		ks.verification[key.ID] = &key
+	}
+
+	return ks, nil
+}
+
+// NewHMACKey creates an HS256 key; the secret is used for both signing and verification
+func NewHMACKey(kid string, secret []byte) SigningKey {
+	return SigningKey{ID: kid, Method: jwt.SigningMethodHS256, PrivateKey: secret, PublicKey: secret}
+}
+
+// ParsePrivateKeyPEM parses an RSA (PKCS#1/PKCS#8) or Ed25519 (PKCS#8) private key
+func ParsePrivateKeyPEM(kid string, data []byte) (SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return SigningKey{}, errors.New("invalid PEM data")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return SigningKey{ID: kid, Method: jwt.SigningMethodRS256, PrivateKey: key, PublicKey: &key.PublicKey}, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("unsupported private key: %w", err)
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return SigningKey{ID: kid, Method: jwt.SigningMethodRS256, PrivateKey: key, PublicKey: &key.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return SigningKey{ID: kid, Method: jwt.SigningMethodEdDSA, PrivateKey: key, PublicKey: key.Public()}, nil
+	default:
+		return SigningKey{}, errors.New("unsupported private key type")
+	}
+}
+
+// ParsePublicKeyPEM parses an RSA or Ed25519 public key used only for verification
+func ParsePublicKeyPEM(kid string, data []byte) (SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return SigningKey{}, errors.New("invalid PEM data")
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return SigningKey{ID: kid, Method: jwt.SigningMethodRS256, PublicKey: key}, nil
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("unsupported public key: %w", err)
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PublicKey:
+		return SigningKey{ID: kid, Method: jwt.SigningMethodRS256, PublicKey: key}, nil
+	case ed25519.PublicKey:
+		return SigningKey{ID: kid, Method: jwt.SigningMethodEdDSA, PublicKey: key}, nil
+	default:
+		return SigningKey{}, errors.New("unsupported public key type")
+	}
+}
+
+// SigningKeyID returns the kid of the key used for new tokens
+func (ks *KeySet) SigningKeyID() string {
+	return ks.signing.ID
+}
+
+// Sign signs claims with the active signing key and sets the kid header
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.PrivateKey)
+}
+
+// Keyfunc resolves the verification key from the token's kid header
+func (ks *KeySet) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, ok := t.Header["kid"].(string)
+	if !ok || kid == "" {
+		return nil, errors.New("missing kid header")
+	}
+
+	key, exists := ks.verification[kid]
+	if !exists {
+		return nil, errors.New("unknown signing key")
+	}
+
+	// Algoritma token harus sama dengan algoritma key (mencegah algorithm confusion)
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("invalid signing method")
+	}
+
+	return key.PublicKey, nil
+}
+
+// ValidMethods returns the algorithms accepted by this key set
+func (ks *KeySet) ValidMethods() []string {
+	seen := map[string]bool{}
+	var methods []string
+	for _, key := range ks.verification {
+		if !seen[key.Method.Alg()] {
+			seen[key.Method.Alg()] = true
+			methods = append(methods, key.Method.Alg())
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// JWKS returns the public verification keys. HMAC secrets are never published.
+func (ks *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+
+	for _, key := range ks.verification {
+		switch pub := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool { return jwks.Keys[i].Kid < jwks.Keys[j].Kid })
+	return jwks
+}
+
+// CurrentKeySet returns the key set used for signing and validating tokens
+func CurrentKeySet() *KeySet {
+	keySetMu.RLock()
+	defer keySetMu.RUnlock()
+	return activeKeySet
+}
+
+// SetKeySet replaces the active key set
+func SetKeySet(ks *KeySet) {
+	keySetMu.Lock()
+	defer keySetMu.Unlock()
+	activeKeySet = ks
+}
+
+// InitJWTKeys loads the signing and verification keys from config and activates them
+func InitJWTKeys(cfg config.Config) error {
+	ks, err := LoadKeySet(cfg)
+	if err != nil {
+		return err
+	}
+	SetKeySet(ks)
+
+	if secret, ok := ks.signing.PrivateKey.([]byte); ok {
+		JWTSecretKey = secret
+	}
+	return nil
+}
+
+// LoadKeySet builds a key set from config.
+//
+//   - HS256 (default): JWT_SECRET, optional JWT_PREVIOUS_SECRETS="kid:secret,..."
+//   - RS256 / EdDSA:   JWT_PRIVATE_KEY_FILE, optional JWT_VERIFY_KEYS_DIR with <kid>.pem public keys
+func LoadKeySet(cfg config.Config) (*KeySet, error) {
+	kid := cfg.JWTKeyID
+	if kid == "" {
+		kid = defaultKeyID
+	}
+
+	algorithm := strings.ToUpper(cfg.JWTAlgorithm)
+	if algorithm == "" {
+		algorithm = "HS256"
+	}
+
+	switch algorithm {
+	case "HS256":
+		if cfg.JWTSecret == "" {
+			return nil, errors.New("JWT_SECRET is required for HS256")
+		}
+
+		var previous []SigningKey
+		for _, entry := range strings.Split(cfg.JWTPreviousSecrets, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			parts := strings.SplitN(entry, ":", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return nil, errors.New("JWT_PREVIOUS_SECRETS must be formatted as kid:secret")
+			}
+			previous = append(previous, NewHMACKey(parts[0], []byte(parts[1])))
+		}
+
+		return NewKeySet(NewHMACKey(kid, []byte(cfg.JWTSecret)), previous...)
+
+	case "RS256", "EDDSA":
+		if cfg.JWTPrivateKeyFile == "" {
+			return nil, fmt.Errorf("JWT_PRIVATE_KEY_FILE is required for %s", cfg.JWTAlgorithm)
+		}
+
+		data, err := os.ReadFile(cfg.JWTPrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading private key: %w", err)
+		}
+
+		signing, err := ParsePrivateKeyPEM(kid, data)
+		if err != nil {
+			return nil, err
+		}
+		if strings.ToUpper(signing.Method.Alg()) != algorithm {
+			return nil, fmt.Errorf("private key does not match JWT_ALGORITHM %s", cfg.JWTAlgorithm)
+		}
+
+		verifyOnly, err := loadVerificationKeys(cfg.JWTVerifyKeysDir, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewKeySet(signing, verifyOnly...)
+
+	default:
+		return nil, fmt.Errorf("unsupported JWT_ALGORITHM %q", cfg.JWTAlgorithm)
+	}
+}
+
+// loadVerificationKeys membaca semua <kid>.pem dari direktori sebagai key verifikasi tambahan
+func loadVerificationKeys(dir, signingKeyID string) ([]SigningKey, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []SigningKey
+	for _, file := range files {
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+		if kid == signingKeyID {
+			continue
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading verification key %s: %w", file, err)
+		}
+
+		key, err := ParsePublicKeyPEM(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid verification key %s: %w", file, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+
+	"UASBE/config"
+)
+
+// ReconciliationIntervalHours adalah jeda antar rekonsiliasi terjadwal PostgreSQL-MongoDB
+var ReconciliationIntervalHours = 24
+
+// ReconciliationAutoRepair menentukan apakah rekonsiliasi terjadwal memperbaiki drift atau hanya melaporkan (dry-run)
+var ReconciliationAutoRepair = false
+
+// InitReconciliation membaca jadwal dan mode rekonsiliasi dari config; nilai kosong memakai default
+func InitReconciliation(cfg config.Config) error {
+	if cfg.ReconciliationIntervalHours != "" {
+		v, err := strconv.Atoi(cfg.ReconciliationIntervalHours)
+		if err != nil || v <= 0 {
+			return errors.New("RECONCILIATION_INTERVAL_HOURS must be a positive integer")
+		}
+		ReconciliationIntervalHours = v
+	}
+	if cfg.ReconciliationAutoRepair != "" {
+		v, err := strconv.ParseBool(cfg.ReconciliationAutoRepair)
+		if err != nil {
+			return errors.New("RECONCILIATION_AUTO_REPAIR must be true or false")
+		}
+		ReconciliationAutoRepair = v
+	}
+	return nil
+}
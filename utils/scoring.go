@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	mongodb "UASBE/app/model/MongoDB"
+	model "UASBE/app/model/Postgresql"
+)
+
+// CalculateAchievementScore menghitung poin prestasi dari aturan skor.
+// basePoints adalah default_points tipe prestasi di katalog (bisa di-override rules.TypePoints).
+// Komponen yang tidak ada di rules tidak menambah poin dan tidak muncul di breakdown.
+func CalculateAchievementScore(achievement mongodb.Achievement, basePoints int, rules model.ScoringRules) (int, []model.ScoreComponent) {
+	achievementType := NormalizeAchievementType(achievement.AchievementType)
+	details := achievement.Details
+
+	if points, ok := rules.TypePoints[achievementType]; ok {
+		basePoints = points
+	}
+	breakdown := []model.ScoreComponent{{Component: "type", Value: achievementType, Points: basePoints}}
+
+	switch achievementType {
+	case "competition":
+		if c, ok := lookupScore(rules.CompetitionLevelPoints, "competitionLevel", details.CompetitionLevel); ok {
+			breakdown = append(breakdown, c)
+		}
+
+		// Peringkat dan medali menggambarkan hal yang sama; ambil yang poinnya paling besar
+		var placement *model.ScoreComponent
+		if details.Rank != nil {
+			rank := strconv.Itoa(*details.Rank)
+			if c, ok := lookupScore(rules.RankPoints, "rank", &rank); ok {
+				placement = &c
+			}
+		}
+		if c, ok := lookupScore(rules.MedalTypePoints, "medalType", details.MedalType); ok {
+			if placement == nil || c.Points > placement.Points {
+				placement = &c
+			}
+		}
+		if placement != nil {
+			breakdown = append(breakdown, *placement)
+		}
+	case "publication":
+		if c, ok := lookupScore(rules.PublicationTypePoints, "publicationType", details.PublicationType); ok {
+			breakdown = append(breakdown, c)
+		}
+	case "organization":
+		if c, ok := lookupScore(rules.PositionPoints, "position", details.Position); ok {
+			breakdown = append(breakdown, c)
+		}
+	}
+
+	total := 0
+	for _, c := range breakdown {
+		total += c.Points
+	}
+	return total, breakdown
+}
+
+func lookupScore(table map[string]int, component string, value *string) (model.ScoreComponent, bool) {
+	if value == nil {
+		return model.ScoreComponent{}, false
+	}
+	key := strings.ToLower(strings.TrimSpace(*value))
+	points, ok := table[key]
+	if !ok {
+		return model.ScoreComponent{}, false
+	}
+	return model.ScoreComponent{Component: component, Value: key, Points: points}, true
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// searchSnippetRadius adalah jumlah karakter yang ditampilkan di kiri dan kanan kata pertama yang cocok
+const searchSnippetRadius = 60
+
+// SearchTerms memecah query pencarian menjadi kata/frasa untuk highlight, mengikuti sintaks $text MongoDB:
+// frasa dalam tanda kutip dihitung satu term dan kata berawalan '-' (negasi) dilewati
+func SearchTerms(query string) []string {
+	var terms []string
+	seen := map[string]bool{}
+	add := func(term string) {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term != "" && !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+
+	parts := strings.Split(query, `"`)
+	for i, part := range parts {
+		// Bagian ganjil berada di dalam tanda kutip
+		if i%2 == 1 {
+			add(part)
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			if !strings.HasPrefix(word, "-") {
+				add(word)
+			}
+		}
+	}
+
+	return terms
+}
+
+// SearchHighlighter membuat cuplikan teks dengan term pencarian ditandai <mark>
+type SearchHighlighter struct {
+	pattern *regexp.Regexp
+}
+
+// NewSearchHighlighter menyiapkan highlighter untuk query; query tanpa term menghasilkan highlighter yang tidak pernah cocok
+func NewSearchHighlighter(query string) *SearchHighlighter {
+	terms := SearchTerms(query)
+	if len(terms) == 0 {
+		return &SearchHighlighter{}
+	}
+
+	// Term terpanjang dicoba lebih dulu agar "c++" tidak terpotong menjadi "c"
+	sort.SliceStable(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	return &SearchHighlighter{pattern: regexp.MustCompile(`(?i)(?:` + strings.Join(quoted, "|") + `)`)}
+}
+
+// isSearchWordRune: huruf dan angka (Unicode) dianggap bagian dari kata
+func isSearchWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+// matches mencari semua term yang berdiri sendiri, yaitu diapit awal/akhir teks atau karakter selain huruf/angka.
+// Batas dicek manual (bukan \b) agar term yang diawali/diakhiri simbol seperti "c++" dan ".net" tetap cocok.
+func (h *SearchHighlighter) matches(text string) [][2]int {
+	var result [][2]int
+	for pos := 0; pos < len(text); {
+		loc := h.pattern.FindStringIndex(text[pos:])
+		if loc == nil {
+			break
+		}
+		start, end := pos+loc[0], pos+loc[1]
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isSearchWordRune(before)) && (end == len(text) || !isSearchWordRune(after)) && end > start {
+			result = append(result, [2]int{start, end})
+			pos = end
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(text[start:])
+		pos = start + size
+	}
+	return result
+}
+
+// Snippet memotong text di sekitar kecocokan pertama dan menandai semua kecocokan di dalamnya.
+// Teks di-escape sebagai HTML sehingga hanya tag <mark> yang berasal dari highlighter.
+func (h *SearchHighlighter) Snippet(text string) (string, bool) {
+	if h.pattern == nil {
+		return "", false
+	}
+	found := h.matches(text)
+	if len(found) == 0 {
+		return "", false
+	}
+
+	runes := []rune(text)
+	start := utf8.RuneCountInString(text[:found[0][0]]) - searchSnippetRadius
+	end := utf8.RuneCountInString(text[:found[0][1]]) + searchSnippetRadius
+	prefix, suffix := "…", "…"
+	if start <= 0 {
+		start, prefix = 0, ""
+	}
+	if end >= len(runes) {
+		end, suffix = len(runes), ""
+	}
+	// Batas jendela dalam byte; kecocokan dicari di teks utuh agar potongan kata di tepi jendela tidak ikut ditandai
+	windowStart := len(string(runes[:start]))
+	windowEnd := len(string(runes[:end]))
+
+	var b strings.Builder
+	b.WriteString(prefix)
+	last := windowStart
+	for _, match := range found {
+		if match[0] < windowStart || match[1] > windowEnd {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[last:match[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[match[0]:match[1]]))
+		b.WriteString("</mark>")
+		last = match[1]
+	}
+	b.WriteString(html.EscapeString(text[last:windowEnd]))
+	b.WriteString(suffix)
+
+	return b.String(), true
+}
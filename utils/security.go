@@ -1,15 +1,29 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var JWTSecretKey = []byte("rahasia_super_aman_uas_backend")
+// JWTSecretKey is the HS256 secret of the active key set.
+// Diisi dari JWT_SECRET oleh InitJWTKeys; sebelum itu berisi secret acak.
+var JWTSecretKey []byte
+
+var (
+	// AccessTokenTTL is the lifetime of a JWT access token
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL is the lifetime of an opaque refresh token
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
 
 func CheckPasswordHash(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
@@ -18,16 +32,16 @@ func CheckPasswordHash(password, hash string) bool {
 
 func GenerateJWT(userID, username, role string, permissions []string) (string, error) {
 	claims := jwt.MapClaims{
+		"jti":         uuid.New().String(),
 		"user_id":     userID,
 		"username":    username,
 		"role":        role,
 		"permissions": permissions,
 		"iat":         time.Now().Unix(),
-		"exp":         time.Now().Add(time.Hour * 24).Unix(),
+		"exp":         time.Now().Add(AccessTokenTTL).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(JWTSecretKey)
+	return CurrentKeySet().Sign(claims)
 }
 
 func ExtractToken(authHeader string) string {
@@ -38,12 +52,8 @@ func ExtractToken(authHeader string) string {
 }
 
 func ValidateToken(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-		return JWTSecretKey, nil
-	})
+	keys := CurrentKeySet()
+	token, err := jwt.Parse(tokenString, keys.Keyfunc, jwt.WithValidMethods(keys.ValidMethods()))
 
 	if err != nil {
 		return nil, err
@@ -66,3 +76,18 @@ func ValidateToken(tokenString string) (jwt.MapClaims, error) {
 
 	return claims, nil
 }
+
+// GenerateRefreshToken membuat refresh token opaque (random 256-bit, base64url)
+func GenerateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashRefreshToken menghasilkan hash SHA-256 (hex) dari refresh token untuk disimpan di database
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,27 +1,48 @@
 package utils
 
 import (
+	"context"
 	"sync"
 	"time"
 )
 
+// TokenBlacklistStore is the storage contract for revoked tokens.
+// Tokens are identified by their `jti` claim, never by the raw token string,
+// so the same store can be shared by several API instances.
+type TokenBlacklistStore interface {
+	// Revoke marks a token ID as revoked until expiresAt
+	Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error
+	// IsRevoked reports whether a token ID has been revoked and is not yet expired
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
 // TokenBlacklistManager manages blacklisted tokens in memory
 type TokenBlacklistManager struct {
-	blacklist map[string]time.Time // token -> expiration time
+	blacklist map[string]time.Time // token ID (jti) -> expiration time
 	mu        sync.RWMutex
 }
 
 var (
 	// Global instance
 	BlacklistManager *TokenBlacklistManager
+
+	// TokenBlacklist is the store used by the middleware and logout flow.
+	// Defaults to the in-memory manager; replaced by a persistent store at startup.
+	TokenBlacklist TokenBlacklistStore
 )
 
 func init() {
 	BlacklistManager = NewTokenBlacklistManager()
+	TokenBlacklist = BlacklistManager
 	// Start cleanup goroutine
 	go BlacklistManager.StartCleanup()
 }
 
+// SetTokenBlacklistStore replaces the store used for token revocation
+func SetTokenBlacklistStore(store TokenBlacklistStore) {
+	TokenBlacklist = store
+}
+
 // NewTokenBlacklistManager creates a new blacklist manager
 func NewTokenBlacklistManager() *TokenBlacklistManager {
 	return &TokenBlacklistManager{
@@ -29,19 +50,19 @@ func NewTokenBlacklistManager() *TokenBlacklistManager {
 	}
 }
 
-// AddToken adds a token to blacklist
-func (m *TokenBlacklistManager) AddToken(token string, expiresAt time.Time) {
+// AddToken adds a token ID to blacklist
+func (m *TokenBlacklistManager) AddToken(tokenID string, expiresAt time.Time) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.blacklist[token] = expiresAt
+	m.blacklist[tokenID] = expiresAt
 }
 
-// IsBlacklisted checks if token is blacklisted
-func (m *TokenBlacklistManager) IsBlacklisted(token string) bool {
+// IsBlacklisted checks if token ID is blacklisted
+func (m *TokenBlacklistManager) IsBlacklisted(tokenID string) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	expiresAt, exists := m.blacklist[token]
+	expiresAt, exists := m.blacklist[tokenID]
 	if !exists {
 		return false
 	}
@@ -49,18 +70,29 @@ func (m *TokenBlacklistManager) IsBlacklisted(token string) bool {
 	// Check if token is still in blacklist (not expired)
 	if time.Now().After(expiresAt) {
 		// Token expired, remove from blacklist
-		go m.removeToken(token)
+		go m.removeToken(tokenID)
 		return false
 	}
 
 	return true
 }
 
+// Revoke implements TokenBlacklistStore
+func (m *TokenBlacklistManager) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	m.AddToken(tokenID, expiresAt)
+	return nil
+}
+
+// IsRevoked implements TokenBlacklistStore
+func (m *TokenBlacklistManager) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return m.IsBlacklisted(tokenID), nil
+}
+
 // removeToken removes a token from blacklist (internal use)
-func (m *TokenBlacklistManager) removeToken(token string) {
+func (m *TokenBlacklistManager) removeToken(tokenID string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	delete(m.blacklist, token)
+	delete(m.blacklist, tokenID)
 }
 
 // StartCleanup periodically removes expired tokens
@@ -79,9 +111,9 @@ func (m *TokenBlacklistManager) cleanup() {
 	defer m.mu.Unlock()
 
 	now := time.Now()
-	for token, expiresAt := range m.blacklist {
+	for tokenID, expiresAt := range m.blacklist {
 		if now.After(expiresAt) {
-			delete(m.blacklist, token)
+			delete(m.blacklist, tokenID)
 		}
 	}
 }
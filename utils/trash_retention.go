@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+
+	"UASBE/config"
+)
+
+// TrashRetentionDays adalah lama prestasi yang dihapus (status 'deleted') disimpan sebelum dihapus permanen
+var TrashRetentionDays = 30
+
+// InitTrashRetention membaca masa simpan trash dari config; nilai kosong memakai default
+func InitTrashRetention(cfg config.Config) error {
+	if cfg.TrashRetentionDays == "" {
+		return nil
+	}
+	v, err := strconv.Atoi(cfg.TrashRetentionDays)
+	if err != nil || v <= 0 {
+		return errors.New("TRASH_RETENTION_DAYS must be a positive integer")
+	}
+	TrashRetentionDays = v
+	return nil
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+
+	"UASBE/config"
+)
+
+var (
+	// MaxAttachmentSize is the maximum size of a single attachment
+	MaxAttachmentSize int64 = 10 * 1024 * 1024
+	// MaxAttachmentsPerAchievement is the maximum number of attachments on one achievement
+	MaxAttachmentsPerAchievement = 10
+	// MaxAttachmentBytesPerAchievement is the total attachment size allowed on one achievement
+	MaxAttachmentBytesPerAchievement int64 = 25 * 1024 * 1024
+	// DefaultStudentStorageQuota is used when a student has no quota override
+	DefaultStudentStorageQuota int64 = 100 * 1024 * 1024
+)
+
+// InitUploadLimits membaca batas upload dari config; nilai kosong memakai default
+func InitUploadLimits(cfg config.Config) error {
+	limits := []struct {
+		env   string
+		value string
+		apply func(int64)
+	}{
+		{"ATTACHMENT_MAX_FILE_BYTES", cfg.AttachmentMaxFileBytes, func(v int64) { MaxAttachmentSize = v }},
+		{"ATTACHMENT_MAX_PER_ACHIEVEMENT", cfg.AttachmentMaxPerAchievement, func(v int64) { MaxAttachmentsPerAchievement = int(v) }},
+		{"ATTACHMENT_MAX_BYTES_PER_ACHIEVEMENT", cfg.AttachmentMaxBytesPerAchievement, func(v int64) { MaxAttachmentBytesPerAchievement = v }},
+		{"STUDENT_STORAGE_QUOTA_BYTES", cfg.StudentStorageQuotaBytes, func(v int64) { DefaultStudentStorageQuota = v }},
+	}
+
+	for _, limit := range limits {
+		if limit.value == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(limit.value, 10, 64)
+		if err != nil || v <= 0 {
+			return fmt.Errorf("%s must be a positive integer", limit.env)
+		}
+		limit.apply(v)
+	}
+
+	return nil
+}